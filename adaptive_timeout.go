@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Command classes of the adaptive timeouts
+const (
+	CommandClassRead   = "read"   // Commands that do not modify data
+	CommandClassScan   = "scan"   // Commands that walk the keyspace or a collection (IE: KEYS, SCAN)
+	CommandClassScript = "script" // Lua scripts (IE: EVAL, EVALSHA)
+	CommandClassWrite  = "write"  // Commands that modify data
+)
+
+// Default adaptive timeout settings
+const (
+	defaultAdaptiveFactor     = 3
+	defaultAdaptiveFloor      = 10 * time.Millisecond
+	defaultAdaptiveMinSamples = 100
+	defaultAdaptivePercentile = 0.99
+	defaultAdaptiveWindow     = 1000
+)
+
+// AdaptiveTimeoutConfig is the configuration for the adaptive timeouts (see: SetAdaptiveTimeout())
+type AdaptiveTimeoutConfig struct {
+	Ceiling    time.Duration // Max timeout, used until there are enough samples (required)
+	Factor     float64       // Multiplier of the latency percentile (default: 3)
+	Floor      time.Duration // Min timeout (default: 10ms)
+	MinSamples int           // Samples of a command class before its timeout adapts (default: 100)
+	Percentile float64       // Percentile of the observed latency, between 0 and 1 (default: 0.99)
+	Window     int           // Latest samples kept per command class (default: 1000)
+}
+
+// adaptiveTimeout derives the timeouts from the observed latency of each command class
+type adaptiveTimeout struct {
+	config  AdaptiveTimeoutConfig
+	windows map[string]*latencyWindow
+}
+
+// latencyWindow is the rolling latency of a command class
+type latencyWindow struct {
+	count   int
+	mu      sync.Mutex
+	next    int
+	samples []time.Duration
+	timeout time.Duration
+}
+
+// SetAdaptiveTimeout derives the timeout of each command from the observed latency of its command
+// class (reads, writes, scripts and scans), nil removes the adaptive timeouts
+//
+// The timeout is the percentile of the latest samples times the factor, between the floor and the
+// ceiling, so a degraded redis produces quick, consistent failures instead of max-timeout stalls
+// Flushes (FLUSHALL, FLUSHDB) always use the ceiling, commands with a timeout from the context
+// (see: WithCommandTimeout()) and pipelined commands use their own timeouts
+func (c *Client) SetAdaptiveTimeout(config *AdaptiveTimeoutConfig) error {
+	var adaptive *adaptiveTimeout
+	if config != nil {
+		if config.Ceiling <= 0 {
+			return errors.New("missing required parameter: ceiling")
+		} else if config.Percentile < 0 || config.Percentile > 1 {
+			return errors.New("percentile must be between 0 and 1")
+		}
+		adaptive = &adaptiveTimeout{config: *config}
+		if adaptive.config.Factor <= 0 {
+			adaptive.config.Factor = defaultAdaptiveFactor
+		}
+		if adaptive.config.Floor <= 0 {
+			adaptive.config.Floor = defaultAdaptiveFloor
+		}
+		if adaptive.config.Floor > adaptive.config.Ceiling {
+			adaptive.config.Floor = adaptive.config.Ceiling
+		}
+		if adaptive.config.MinSamples <= 0 {
+			adaptive.config.MinSamples = defaultAdaptiveMinSamples
+		}
+		if adaptive.config.Percentile == 0 {
+			adaptive.config.Percentile = defaultAdaptivePercentile
+		}
+		if adaptive.config.Window <= 0 {
+			adaptive.config.Window = defaultAdaptiveWindow
+		}
+		adaptive.windows = map[string]*latencyWindow{
+			CommandClassRead:   {samples: make([]time.Duration, adaptive.config.Window)},
+			CommandClassScan:   {samples: make([]time.Duration, adaptive.config.Window)},
+			CommandClassScript: {samples: make([]time.Duration, adaptive.config.Window)},
+			CommandClassWrite:  {samples: make([]time.Duration, adaptive.config.Window)},
+		}
+	}
+	c.mu.Lock()
+	c.adaptiveTimeout = adaptive
+	c.mu.Unlock()
+	return nil
+}
+
+// AdaptiveTimeouts returns the current timeout of each command class (empty if not set)
+func (c *Client) AdaptiveTimeouts() map[string]time.Duration {
+	c.mu.RLock()
+	adaptive := c.adaptiveTimeout
+	c.mu.RUnlock()
+
+	timeouts := make(map[string]time.Duration)
+	if adaptive != nil {
+		for class := range adaptive.windows {
+			timeouts[class] = adaptive.timeout(class)
+		}
+	}
+	return timeouts
+}
+
+// commandClass returns the command class of the command (empty for the flushes, which are not adapted)
+func commandClass(commandName string) string {
+	switch strings.ToUpper(commandName) {
+	case FlushAllCommand, FlushDBCommand:
+		return ""
+	case EvalCommand, "EVAL", ScriptCommand:
+		return CommandClassScript
+	case KeysCommand, ScanCommand, SetScanCommand, "HSCAN", "ZSCAN":
+		return CommandClassScan
+	}
+	if isWriteCommand(commandName) {
+		return CommandClassWrite
+	}
+	return CommandClassRead
+}
+
+// timeout returns the current timeout of the command class (the ceiling until there are enough samples)
+func (a *adaptiveTimeout) timeout(class string) time.Duration {
+	window := a.windows[class]
+	window.mu.Lock()
+	defer window.mu.Unlock()
+	if window.count < a.config.MinSamples {
+		return a.config.Ceiling
+	}
+	return window.timeout
+}
+
+// record adds the latency of a command, the timeout is derived again every tenth of the window
+func (a *adaptiveTimeout) record(class string, latency time.Duration) {
+	window := a.windows[class]
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	window.samples[window.next] = latency
+	window.next = (window.next + 1) % len(window.samples)
+	window.count++
+
+	every := len(window.samples) / 10
+	if every == 0 {
+		every = 1
+	}
+	if window.count < a.config.MinSamples || (window.count-a.config.MinSamples)%every != 0 {
+		return
+	}
+
+	size := window.count
+	if size > len(window.samples) {
+		size = len(window.samples)
+	}
+	sorted := make([]time.Duration, size)
+	copy(sorted, window.samples[:size])
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	timeout := time.Duration(float64(sorted[int(float64(size-1)*a.config.Percentile)]) * a.config.Factor)
+	if timeout < a.config.Floor {
+		timeout = a.config.Floor
+	} else if timeout > a.config.Ceiling {
+		timeout = a.config.Ceiling
+	}
+	window.timeout = timeout
+}
+
+// adaptiveConn runs each command with the adaptive timeout of its command class
+type adaptiveConn struct {
+	redis.Conn
+	adaptive *adaptiveTimeout
+	clock    Clock
+}
+
+// unwrap returns the wrapped connection
+func (c *adaptiveConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs the command with the adaptive timeout and records its latency
+func (c *adaptiveConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if len(commandName) == 0 {
+		return c.Conn.Do(commandName, args...) // Flush of pipelined commands
+	}
+	class := commandClass(commandName)
+	if len(class) == 0 {
+		return redis.DoWithTimeout(c.Conn, c.adaptive.config.Ceiling, commandName, args...)
+	}
+	start := c.clock.Now()
+	reply, err := redis.DoWithTimeout(c.Conn, c.adaptive.timeout(class), commandName, args...)
+	c.adaptive.record(class, c.clock.Now().Sub(start))
+	return reply, err
+}
+
+// DoWithTimeout runs the command with the timeout
+func (c *adaptiveConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *adaptiveConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
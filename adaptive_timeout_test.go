@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetAdaptiveTimeout tests the method SetAdaptiveTimeout()
+func TestClient_SetAdaptiveTimeout(t *testing.T) {
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Error(t, client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{}))
+		assert.Error(t, client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{Ceiling: time.Second, Percentile: 2}))
+		assert.NoError(t, client.SetAdaptiveTimeout(nil))
+		assert.Equal(t, 0, len(client.AdaptiveTimeouts()))
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.NoError(t, client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{Ceiling: time.Millisecond}))
+		config := client.adaptiveTimeout.config
+		assert.Equal(t, float64(defaultAdaptiveFactor), config.Factor)
+		assert.Equal(t, time.Millisecond, config.Floor)
+		assert.Equal(t, defaultAdaptiveMinSamples, config.MinSamples)
+		assert.Equal(t, defaultAdaptivePercentile, config.Percentile)
+		assert.Equal(t, defaultAdaptiveWindow, config.Window)
+	})
+
+	t.Run("derived from the percentile", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.NoError(t, client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{
+			Ceiling:    time.Second,
+			Factor:     2,
+			Floor:      time.Millisecond,
+			MinSamples: 10,
+			Percentile: 0.5,
+			Window:     10,
+		}))
+		adaptive := client.adaptiveTimeout
+
+		// The ceiling is used until there are enough samples
+		for i := 1; i <= 9; i++ {
+			adaptive.record(CommandClassRead, time.Duration(i)*time.Millisecond)
+		}
+		assert.Equal(t, time.Second, adaptive.timeout(CommandClassRead))
+
+		adaptive.record(CommandClassRead, 10*time.Millisecond)
+		assert.Equal(t, 10*time.Millisecond, adaptive.timeout(CommandClassRead))
+
+		// Each command class has its own samples
+		assert.Equal(t, map[string]time.Duration{
+			CommandClassRead:   10 * time.Millisecond,
+			CommandClassScan:   time.Second,
+			CommandClassScript: time.Second,
+			CommandClassWrite:  time.Second,
+		}, client.AdaptiveTimeouts())
+
+		// A degraded redis raises the timeout up to the ceiling
+		for i := 0; i < 10; i++ {
+			adaptive.record(CommandClassRead, 2*time.Second)
+		}
+		assert.Equal(t, time.Second, adaptive.timeout(CommandClassRead))
+
+		// A fast redis lowers the timeout down to the floor
+		for i := 0; i < 10; i++ {
+			adaptive.record(CommandClassRead, time.Microsecond)
+		}
+		assert.Equal(t, time.Millisecond, adaptive.timeout(CommandClassRead))
+	})
+
+	t.Run("commands using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{
+			Ceiling:    time.Second,
+			MinSamples: 1,
+			Window:     1,
+		}))
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		val, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+
+		timeouts := client.AdaptiveTimeouts()
+		assert.Equal(t, defaultAdaptiveFloor, timeouts[CommandClassRead])
+		assert.Equal(t, time.Second, timeouts[CommandClassWrite])
+
+		// A timeout from the context is not adapted
+		conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+		err = Set(WithCommandTimeout(context.Background(), time.Second), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, client.AdaptiveTimeouts()[CommandClassWrite])
+
+		// Scripts and scans are not limited by the learned read timeout, flushes are not adapted
+		conn.Command(KeysCommand, "*").Expect([]interface{}{})
+		_, err = GetAllKeys(context.Background(), client)
+		assert.NoError(t, err)
+		conn.Command(FlushAllCommand).Expect("OK")
+		err = DestroyCache(context.Background(), client)
+		assert.NoError(t, err)
+		timeouts = client.AdaptiveTimeouts()
+		assert.Equal(t, defaultAdaptiveFloor, timeouts[CommandClassScan])
+		assert.Equal(t, time.Second, timeouts[CommandClassScript])
+		assert.Len(t, timeouts, 4)
+	})
+
+	t.Run("command classes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, CommandClassRead, commandClass(GetCommand))
+		assert.Equal(t, CommandClassWrite, commandClass(SetCommand))
+		assert.Equal(t, CommandClassScript, commandClass(EvalCommand))
+		assert.Equal(t, CommandClassScript, commandClass("eval"))
+		assert.Equal(t, CommandClassScan, commandClass(KeysCommand))
+		assert.Equal(t, CommandClassScan, commandClass(ScanCommand))
+		assert.Equal(t, "", commandClass(FlushAllCommand))
+		assert.Equal(t, "", commandClass(FlushDBCommand))
+	})
+
+	t.Run("commands using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{Ceiling: time.Second, MinSamples: 5})
+		assert.NoError(t, err)
+
+		for i := 0; i < 5; i++ {
+			err = Set(context.Background(), client, testKey, testStringValue)
+			assert.NoError(t, err)
+		}
+		timeout := client.AdaptiveTimeouts()[CommandClassWrite]
+		assert.GreaterOrEqual(t, timeout, defaultAdaptiveFloor)
+		assert.Less(t, timeout, time.Second)
+	})
+}
+
+// ExampleClient_SetAdaptiveTimeout is an example of the method SetAdaptiveTimeout()
+func ExampleClient_SetAdaptiveTimeout() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Time out at 3x the p99 latency, between 10ms and 500ms
+	_ = client.SetAdaptiveTimeout(&AdaptiveTimeoutConfig{Ceiling: 500 * time.Millisecond})
+
+	// The ceiling is used until there are enough samples
+	fmt.Printf("read timeout: %s", client.AdaptiveTimeouts()[CommandClassRead])
+	// Output:read timeout: 500ms
+}
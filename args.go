@@ -0,0 +1,33 @@
+package cache
+
+import "sync"
+
+// maxPooledArgs is the largest argument slice kept for reuse (large writes allocate their own)
+const maxPooledArgs = 64
+
+// argsPool holds argument slices for reuse by the hot write paths (Set, HashMapSet, SetAddMany)
+var argsPool = sync.Pool{
+	New: func() interface{} {
+		args := make([]interface{}, 0, 8)
+		return &args
+	},
+}
+
+// getArgs returns an empty argument slice from the pool (release with putArgs())
+func getArgs() *[]interface{} {
+	return argsPool.Get().(*[]interface{})
+}
+
+// putArgs clears the argument slice and returns it to the pool
+//
+// The slice must not be used after the commands using it are flushed
+func putArgs(args *[]interface{}) {
+	if cap(*args) > maxPooledArgs {
+		return
+	}
+	for i := range *args {
+		(*args)[i] = nil
+	}
+	*args = (*args)[:0]
+	argsPool.Put(args)
+}
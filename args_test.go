@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// discardConn is a connection that discards all commands (for benchmarks)
+type discardConn struct{}
+
+func (discardConn) Close() error                                   { return nil }
+func (discardConn) Err() error                                     { return nil }
+func (discardConn) Do(string, ...interface{}) (interface{}, error) { return "OK", nil }
+func (discardConn) Send(string, ...interface{}) error              { return nil }
+func (discardConn) Flush() error                                   { return nil }
+func (discardConn) Receive() (interface{}, error)                  { return "OK", nil }
+
+// TestArgsPool tests the pooled argument slices
+func TestArgsPool(t *testing.T) {
+
+	t.Run("released slices are cleared", func(t *testing.T) {
+		args := getArgs()
+		assert.Len(t, *args, 0)
+		*args = append(*args, testKey, testStringValue)
+		putArgs(args)
+
+		assert.Len(t, *args, 0)
+		assert.Nil(t, (*args)[:2][0])
+		assert.Nil(t, (*args)[:2][1])
+	})
+
+	t.Run("large slices are not kept", func(t *testing.T) {
+		large := make([]interface{}, maxPooledArgs+1)
+		large[0] = testKey
+		putArgs(&large)
+
+		// Not cleared (dropped)
+		assert.Equal(t, testKey, large[0])
+	})
+
+	t.Run("pooled writes using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(HashMapSetCommand, testHashName, "field-1", "a", "field-2", "b")
+		conn.Command(AddToSetCommand, testKey, "member-1", "member-2")
+
+		// Repeated writes reuse the arguments
+		for i := 0; i < 3; i++ {
+			err := HashMapSetRaw(conn, testHashName, [][2]interface{}{{"field-1", "a"}, {"field-2", "b"}})
+			assert.NoError(t, err)
+			err = SetAddManyRaw(conn, testKey, "member-1", "member-2")
+			assert.NoError(t, err)
+		}
+	})
+}
+
+// BenchmarkSetRaw benchmarks the method SetRaw()
+func BenchmarkSetRaw(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = SetRaw(discardConn{}, testKey, testStringValue)
+	}
+}
+
+// BenchmarkHashMapSetRaw benchmarks the method HashMapSetRaw()
+func BenchmarkHashMapSetRaw(b *testing.B) {
+	pairs := [][2]interface{}{{"field-1", testStringValue}, {"field-2", testStringValue}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = HashMapSetRaw(discardConn{}, testHashName, pairs)
+	}
+}
+
+// BenchmarkSetAddManyRaw benchmarks the method SetAddManyRaw()
+func BenchmarkSetAddManyRaw(b *testing.B) {
+	members := []interface{}{"member-1", "member-2", "member-3"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = SetAddManyRaw(discardConn{}, testKey, members...)
+	}
+}
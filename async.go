@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default async writer settings
+const (
+	defaultAsyncQueueSize = 1000
+	defaultAsyncTimeout   = 5 * time.Second
+	defaultAsyncWorkers   = 10
+)
+
+// Async writer errors
+var (
+	ErrAsyncQueueFull        = errors.New("async write queue is full")
+	ErrAsyncWriterNotStarted = errors.New("async writer is not started")
+	ErrAsyncWriterStarted    = errors.New("async writer is already started")
+)
+
+// AsyncWriteError is the error for a failed async write
+type AsyncWriteError struct {
+	Err error  // Error from redis
+	Key string // Key that failed to be written
+}
+
+// Error returns the error message
+func (e *AsyncWriteError) Error() string {
+	return "async write failed for key " + e.Key + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *AsyncWriteError) Unwrap() error {
+	return e.Err
+}
+
+// AsyncWriterConfig is the configuration for the async writer
+type AsyncWriterConfig struct {
+	ErrorHandler func(err *AsyncWriteError) // Fired for each failed write (optional)
+	Errors       chan<- *AsyncWriteError    // Receives each failed write, dropped if full (optional)
+	QueueSize    int                        // Max pending writes (default: 1000)
+	Timeout      time.Duration              // Timeout for each write (default: 5s)
+	Workers      int                        // Concurrent writers (default: 10)
+}
+
+// AsyncWriterStats are the running totals for the async writer
+type AsyncWriterStats struct {
+	Dropped uint64 // Errors dropped because the error channel was full
+	Failed  uint64 // Writes that failed
+	Pending int    // Writes waiting in the queue
+	Written uint64 // Writes that succeeded
+}
+
+// asyncWrite is a single pending write
+type asyncWrite struct {
+	dependencies []string
+	key          string
+	value        interface{}
+}
+
+// asyncWriter runs the async write workers for a client
+type asyncWriter struct {
+	config  AsyncWriterConfig
+	queue   chan *asyncWrite
+	stats   AsyncWriterStats
+	workers sync.WaitGroup
+}
+
+// StartAsyncWriter starts the worker pool used by SetAsync()
+func (c *Client) StartAsyncWriter(config *AsyncWriterConfig) error {
+	writer := &asyncWriter{}
+	if config != nil {
+		writer.config = *config
+	}
+	if writer.config.QueueSize <= 0 {
+		writer.config.QueueSize = defaultAsyncQueueSize
+	}
+	if writer.config.Timeout <= 0 {
+		writer.config.Timeout = defaultAsyncTimeout
+	}
+	if writer.config.Workers <= 0 {
+		writer.config.Workers = defaultAsyncWorkers
+	}
+	writer.queue = make(chan *asyncWrite, writer.config.QueueSize)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.async != nil {
+		return ErrAsyncWriterStarted
+	}
+	c.async = writer
+
+	for i := 0; i < writer.config.Workers; i++ {
+		writer.workers.Add(1)
+		go writer.run(c)
+	}
+	return nil
+}
+
+// StopAsyncWriter stops accepting async writes and waits for the pending writes to finish
+// or the context to be done (pending writes continue in the background)
+func (c *Client) StopAsyncWriter(ctx context.Context) error {
+	c.mu.Lock()
+	writer := c.async
+	c.async = nil
+	if writer != nil {
+		close(writer.queue)
+	}
+	c.mu.Unlock()
+
+	if writer == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		writer.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AsyncWriterStats returns the running totals for the async writer
+func (c *Client) AsyncWriterStats() AsyncWriterStats {
+	c.mu.RLock()
+	writer := c.async
+	c.mu.RUnlock()
+	if writer == nil {
+		return AsyncWriterStats{}
+	}
+	return AsyncWriterStats{
+		Dropped: atomic.LoadUint64(&writer.stats.Dropped),
+		Failed:  atomic.LoadUint64(&writer.stats.Failed),
+		Pending: len(writer.queue),
+		Written: atomic.LoadUint64(&writer.stats.Written),
+	}
+}
+
+// SetAsync will queue the key to be set in redis (with dependencies) and return immediately
+// value can be both a string or []byte
+// Errors are delivered to the ErrorHandler and/or Errors channel of the async writer
+//
+// Requires: StartAsyncWriter()
+func SetAsync(_ context.Context, client *Client, key string,
+	value interface{}, dependencies ...string) error {
+
+	client.mu.RLock()
+	defer client.mu.RUnlock()
+	if client.async == nil {
+		return ErrAsyncWriterNotStarted
+	}
+
+	select {
+	case client.async.queue <- &asyncWrite{dependencies: dependencies, key: key, value: value}:
+		return nil
+	default:
+		return ErrAsyncQueueFull
+	}
+}
+
+// run processes the queued writes until the queue is closed
+func (w *asyncWriter) run(client *Client) {
+	defer w.workers.Done()
+	for write := range w.queue {
+		ctx, cancel := context.WithTimeout(withBackground(context.Background()), w.config.Timeout)
+		err := Set(ctx, client, write.key, write.value, write.dependencies...)
+		cancel()
+
+		if err == nil {
+			atomic.AddUint64(&w.stats.Written, 1)
+			continue
+		}
+		atomic.AddUint64(&w.stats.Failed, 1)
+		w.report(&AsyncWriteError{Err: err, Key: write.key})
+	}
+}
+
+// report delivers the error to the handler and channel
+func (w *asyncWriter) report(err *AsyncWriteError) {
+	if w.config.ErrorHandler != nil {
+		w.config.ErrorHandler(err)
+	}
+	if w.config.Errors != nil {
+		select {
+		case w.config.Errors <- err:
+		default:
+			atomic.AddUint64(&w.stats.Dropped, 1)
+		}
+	}
+}
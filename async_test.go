@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetAsync tests the method SetAsync()
+func TestSetAsync(t *testing.T) {
+
+	t.Run("writer not started", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrAsyncWriterNotStarted)
+		assert.NoError(t, client.StopAsyncWriter(context.Background()))
+	})
+
+	t.Run("writer already started", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.NoError(t, client.StartAsyncWriter(nil))
+		assert.ErrorIs(t, client.StartAsyncWriter(nil), ErrAsyncWriterStarted)
+		assert.NoError(t, client.StopAsyncWriter(context.Background()))
+	})
+
+	t.Run("async write with dependencies", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		err := client.StartAsyncWriter(&AsyncWriterConfig{Workers: 1})
+		assert.NoError(t, err)
+
+		err = SetAsync(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+
+		// Drain the pending writes
+		assert.NoError(t, client.StopAsyncWriter(context.Background()))
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+
+		err = SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrAsyncWriterNotStarted)
+	})
+
+	t.Run("errors are delivered to the handler and channel", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, testKey, testStringValue).ExpectError(errors.New("write failed"))
+
+		var handled []*AsyncWriteError
+		errs := make(chan *AsyncWriteError, 1)
+		err := client.StartAsyncWriter(&AsyncWriterConfig{
+			ErrorHandler: func(err *AsyncWriteError) {
+				handled = append(handled, err)
+			},
+			Errors:  errs,
+			Workers: 1,
+		})
+		assert.NoError(t, err)
+
+		err = SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		writeErr := <-errs
+		assert.Equal(t, testKey, writeErr.Key)
+		assert.EqualError(t, errors.Unwrap(writeErr), "write failed")
+		assert.Contains(t, writeErr.Error(), testKey)
+		assert.Equal(t, uint64(1), client.AsyncWriterStats().Failed)
+
+		assert.NoError(t, client.StopAsyncWriter(context.Background()))
+		assert.Equal(t, 1, len(handled))
+	})
+
+	t.Run("queue is full", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// A writer without workers never consumes the queue
+		writer := &asyncWriter{queue: make(chan *asyncWrite, 1)}
+		client.async = writer
+
+		err := SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		err = SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrAsyncQueueFull)
+		assert.Equal(t, 1, client.AsyncWriterStats().Pending)
+
+		client.async = nil
+	})
+}
+
+// ExampleSetAsync is an example of the method SetAsync()
+func ExampleSetAsync() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections (and drain the async writer) at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command
+	conn.Command(SetCommand, testKey, testStringValue)
+
+	// Start the async writer
+	_ = client.StartAsyncWriter(&AsyncWriterConfig{
+		ErrorHandler: func(err *AsyncWriteError) {
+			fmt.Printf("async write failed: %s", err.Error())
+		},
+	})
+
+	// Queue the write
+	_ = SetAsync(context.Background(), client, testKey, testStringValue)
+	fmt.Printf("queued key: %s", testKey)
+	// Output:queued key: test-key-name
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// CommandRecord is a single command in the audit trail (see: SetCommandAudit())
+type CommandRecord struct {
+	Command  string        `json:"command"`         // Command name (IE: GET)
+	Duration time.Duration `json:"duration"`        // Round trip of the command (0 for pipelined commands)
+	Error    string        `json:"error,omitempty"` // Error of the command (if any)
+	Key      string        `json:"key,omitempty"`   // First key of the command (if any)
+	Time     time.Time     `json:"time"`            // Start of the command
+}
+
+// commandAudit is a ring buffer of the last commands
+type commandAudit struct {
+	mu      sync.Mutex
+	next    int
+	records []CommandRecord
+	size    int
+}
+
+// add records the command, replacing the oldest record once the buffer is full
+func (a *commandAudit) add(record CommandRecord) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.records) < a.size {
+		a.records = append(a.records, record)
+		return
+	}
+	a.records[a.next] = record
+	a.next = (a.next + 1) % a.size
+}
+
+// recent returns a copy of the records (oldest first)
+func (a *commandAudit) recent() []CommandRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	records := make([]CommandRecord, 0, len(a.records))
+	records = append(records, a.records[a.next:]...)
+	return append(records, a.records[:a.next]...)
+}
+
+// SetCommandAudit keeps the last commands (up to the size) of the client in memory for diagnostics
+// (IE: a crash dump includes the recent cache activity), a size of 0 switches the audit trail off
+//
+// Only the command name and its first key are kept, values are never recorded
+func (c *Client) SetCommandAudit(size int) {
+	var audit *commandAudit
+	if size > 0 {
+		audit = &commandAudit{records: make([]CommandRecord, 0, size), size: size}
+	}
+	c.mu.Lock()
+	c.audit = audit
+	c.mu.Unlock()
+}
+
+// RecentCommands returns the last commands of the client (oldest first)
+// Returns nil if the audit trail is off (see: SetCommandAudit())
+func (c *Client) RecentCommands() []CommandRecord {
+	c.mu.RLock()
+	audit := c.audit
+	c.mu.RUnlock()
+	if audit == nil {
+		return nil
+	}
+	return audit.recent()
+}
+
+// newCommandRecord creates the record of a command
+func newCommandRecord(start time.Time, duration time.Duration, commandName string,
+	args []interface{}, err error) CommandRecord {
+	record := CommandRecord{
+		Command:  commandName,
+		Duration: duration,
+		Key:      commandKey(commandName, args),
+		Time:     start,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// commandKey returns the first key of the command (empty if the command has no key)
+func commandKey(commandName string, args []interface{}) string {
+	switch commandName {
+	case EvalCommand, "EVAL":
+		// script, number of keys, keys...
+		if len(args) < 3 {
+			return ""
+		}
+		args = args[2:]
+	case MultiCommand, ExecuteCommand, PingCommand, InfoCommand, ScriptCommand, ScanCommand, KeysCommand:
+		return ""
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	switch key := args[0].(type) {
+	case string:
+		return key
+	case []byte:
+		return string(key)
+	}
+	return ""
+}
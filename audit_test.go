@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetCommandAudit tests the method SetCommandAudit()
+func TestClient_SetCommandAudit(t *testing.T) {
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		_, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Nil(t, client.RecentCommands())
+	})
+
+	t.Run("records the last commands using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.GenericCommand(GetCommand).Expect(testStringValue)
+		conn.Command(GetCommand, "broken").ExpectError(errors.New("connection lost"))
+		conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(MultiCommand)
+		conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		client.SetCommandAudit(3)
+
+		ctx := context.Background()
+		for i := 0; i < 3; i++ {
+			_, _ = Get(ctx, client, fmt.Sprintf("key-%d", i))
+		}
+		_, err := Get(ctx, client, "broken")
+		assert.Error(t, err)
+
+		records := client.RecentCommands()
+		assert.Len(t, records, 3)
+		assert.Equal(t, "key-1", records[0].Key)
+		assert.Equal(t, "key-2", records[1].Key)
+		assert.Equal(t, GetCommand, records[2].Command)
+		assert.Equal(t, "broken", records[2].Key)
+		assert.Equal(t, "connection lost", records[2].Error)
+		assert.False(t, records[2].Time.IsZero())
+
+		// Transactions are recorded command by command
+		err = Set(ctx, client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		records = client.RecentCommands()
+		assert.Equal(t, []string{MultiCommand, AddToSetCommand, ExecuteCommand},
+			[]string{records[0].Command, records[1].Command, records[2].Command})
+		assert.Equal(t, DependencyPrefix+testDependantKey, records[1].Key)
+		assert.Empty(t, records[2].Key)
+
+		// Switched off
+		client.SetCommandAudit(0)
+		assert.Nil(t, client.RecentCommands())
+	})
+}
+
+// TestCommandKey tests the method commandKey()
+func TestCommandKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, testKey, commandKey(GetCommand, []interface{}{testKey}))
+	assert.Equal(t, testKey, commandKey(SetCommand, []interface{}{[]byte(testKey), testStringValue}))
+	assert.Equal(t, testKey, commandKey(EvalCommand, []interface{}{"sha", 1, testKey}))
+	assert.Empty(t, commandKey(EvalCommand, []interface{}{"sha", 0}))
+	assert.Empty(t, commandKey(ExecuteCommand, nil))
+	assert.Empty(t, commandKey(ScanCommand, []interface{}{"0"}))
+}
+
+// ExampleClient_RecentCommands is an example of the method RecentCommands()
+func ExampleClient_RecentCommands() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Keep the last 100 commands
+	client.SetCommandAudit(100)
+
+	// Mock and run a command
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+	_, _ = Get(context.Background(), client, testKey)
+
+	for _, record := range client.RecentCommands() {
+		fmt.Printf("%s %s", record.Command, record.Key)
+	}
+	// Output:GET test-key-name
+}
@@ -0,0 +1,316 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Package constants (RedisBloom module commands)
+const (
+	BloomAddCommand         string = "BF.ADD"
+	BloomAddManyCommand     string = "BF.MADD"
+	BloomExistsCommand      string = "BF.EXISTS"
+	BloomExistsManyCommand  string = "BF.MEXISTS"
+	BloomReserveCommand     string = "BF.RESERVE"
+	CuckooAddCommand        string = "CF.ADD"
+	CuckooAddUniqueCommand  string = "CF.ADDNX"
+	CuckooDeleteCommand     string = "CF.DEL"
+	CuckooExistsCommand     string = "CF.EXISTS"
+	CuckooExistsManyCommand string = "CF.MEXISTS"
+	CuckooReserveCommand    string = "CF.RESERVE"
+)
+
+// BloomFilterOptions are the options of a new bloom filter
+type BloomFilterOptions struct {
+	Capacity   int64   // Number of items expected before the filter scales
+	ErrorRate  float64 // Desired false positive rate (IE: 0.001)
+	NonScaling bool    // Fail adds when the capacity is reached instead of scaling
+}
+
+// CuckooFilterOptions are the options of a new cuckoo filter
+type CuckooFilterOptions struct {
+	BucketSize int64 // Number of items in each bucket (optional)
+	Capacity   int64 // Number of items expected
+}
+
+// ReserveBloomFilter creates an empty bloom filter with the capacity and error rate (requires the RedisBloom module)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ReserveBloomFilterRaw()
+func ReserveBloomFilter(ctx context.Context, client *Client, name string, options *BloomFilterOptions) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return ReserveBloomFilterRaw(conn, name, options)
+}
+
+// ReserveBloomFilterRaw creates an empty bloom filter with the capacity and error rate (requires the RedisBloom module)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/bf.reserve
+func ReserveBloomFilterRaw(conn redis.Conn, name string, options *BloomFilterOptions) (err error) {
+	if options == nil || options.Capacity <= 0 {
+		return errors.New("missing required parameter: capacity")
+	} else if options.ErrorRate <= 0 || options.ErrorRate >= 1 {
+		return errors.New("invalid parameter: error rate must be between 0 and 1")
+	}
+	args := redis.Args{}.Add(name, options.ErrorRate, options.Capacity)
+	if options.NonScaling {
+		args = args.Add("NONSCALING")
+	}
+	_, err = conn.Do(BloomReserveCommand, args...)
+	return
+}
+
+// BloomFilterAdd adds the item to the bloom filter (created with the defaults if it does not exist)
+// Returns false if the item may have already been added
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: BloomFilterAddRaw()
+func BloomFilterAdd(ctx context.Context, client *Client, name string, item interface{}) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return BloomFilterAddRaw(conn, name, item)
+}
+
+// BloomFilterAddRaw adds the item to the bloom filter (created with the defaults if it does not exist)
+// Returns false if the item may have already been added
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/bf.add
+func BloomFilterAddRaw(conn redis.Conn, name string, item interface{}) (bool, error) {
+	return redis.Bool(conn.Do(BloomAddCommand, name, item))
+}
+
+// BloomFilterAddMany adds the items to the bloom filter (created with the defaults if it does not exist)
+// Returns false for each item that may have already been added
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: BloomFilterAddManyRaw()
+func BloomFilterAddMany(ctx context.Context, client *Client, name string, items ...interface{}) ([]bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return BloomFilterAddManyRaw(conn, name, items...)
+}
+
+// BloomFilterAddManyRaw adds the items to the bloom filter (created with the defaults if it does not exist)
+// Returns false for each item that may have already been added
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/bf.madd
+func BloomFilterAddManyRaw(conn redis.Conn, name string, items ...interface{}) ([]bool, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return boolsReply(conn.Do(BloomAddManyCommand, redis.Args{}.Add(name).Add(items...)...))
+}
+
+// BloomFilterExists checks if the item may be in the bloom filter (false positives are possible)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: BloomFilterExistsRaw()
+func BloomFilterExists(ctx context.Context, client *Client, name string, item interface{}) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return BloomFilterExistsRaw(conn, name, item)
+}
+
+// BloomFilterExistsRaw checks if the item may be in the bloom filter (false positives are possible)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/bf.exists
+func BloomFilterExistsRaw(conn redis.Conn, name string, item interface{}) (bool, error) {
+	return redis.Bool(conn.Do(BloomExistsCommand, name, item))
+}
+
+// BloomFilterExistsMany checks if each item may be in the bloom filter (false positives are possible)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: BloomFilterExistsManyRaw()
+func BloomFilterExistsMany(ctx context.Context, client *Client, name string, items ...interface{}) ([]bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return BloomFilterExistsManyRaw(conn, name, items...)
+}
+
+// BloomFilterExistsManyRaw checks if each item may be in the bloom filter (false positives are possible)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/bf.mexists
+func BloomFilterExistsManyRaw(conn redis.Conn, name string, items ...interface{}) ([]bool, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return boolsReply(conn.Do(BloomExistsManyCommand, redis.Args{}.Add(name).Add(items...)...))
+}
+
+// ReserveCuckooFilter creates an empty cuckoo filter with the capacity (requires the RedisBloom module)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ReserveCuckooFilterRaw()
+func ReserveCuckooFilter(ctx context.Context, client *Client, name string, options *CuckooFilterOptions) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return ReserveCuckooFilterRaw(conn, name, options)
+}
+
+// ReserveCuckooFilterRaw creates an empty cuckoo filter with the capacity (requires the RedisBloom module)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.reserve
+func ReserveCuckooFilterRaw(conn redis.Conn, name string, options *CuckooFilterOptions) (err error) {
+	if options == nil || options.Capacity <= 0 {
+		return errors.New("missing required parameter: capacity")
+	}
+	args := redis.Args{}.Add(name, options.Capacity)
+	if options.BucketSize > 0 {
+		args = args.Add("BUCKETSIZE", options.BucketSize)
+	}
+	_, err = conn.Do(CuckooReserveCommand, args...)
+	return
+}
+
+// CuckooFilterAdd adds the item to the cuckoo filter (an item can be added more than once)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CuckooFilterAddRaw()
+func CuckooFilterAdd(ctx context.Context, client *Client, name string, item interface{}) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return CuckooFilterAddRaw(conn, name, item)
+}
+
+// CuckooFilterAddRaw adds the item to the cuckoo filter (an item can be added more than once)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.add
+func CuckooFilterAddRaw(conn redis.Conn, name string, item interface{}) (err error) {
+	_, err = conn.Do(CuckooAddCommand, name, item)
+	return
+}
+
+// CuckooFilterAddUnique adds the item to the cuckoo filter only if it may not exist
+// Returns false if the item may have already been added
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CuckooFilterAddUniqueRaw()
+func CuckooFilterAddUnique(ctx context.Context, client *Client, name string, item interface{}) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return CuckooFilterAddUniqueRaw(conn, name, item)
+}
+
+// CuckooFilterAddUniqueRaw adds the item to the cuckoo filter only if it may not exist
+// Returns false if the item may have already been added
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.addnx
+func CuckooFilterAddUniqueRaw(conn redis.Conn, name string, item interface{}) (bool, error) {
+	return redis.Bool(conn.Do(CuckooAddUniqueCommand, name, item))
+}
+
+// CuckooFilterExists checks if the item may be in the cuckoo filter (false positives are possible)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CuckooFilterExistsRaw()
+func CuckooFilterExists(ctx context.Context, client *Client, name string, item interface{}) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return CuckooFilterExistsRaw(conn, name, item)
+}
+
+// CuckooFilterExistsRaw checks if the item may be in the cuckoo filter (false positives are possible)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.exists
+func CuckooFilterExistsRaw(conn redis.Conn, name string, item interface{}) (bool, error) {
+	return redis.Bool(conn.Do(CuckooExistsCommand, name, item))
+}
+
+// CuckooFilterExistsMany checks if each item may be in the cuckoo filter (false positives are possible)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CuckooFilterExistsManyRaw()
+func CuckooFilterExistsMany(ctx context.Context, client *Client, name string, items ...interface{}) ([]bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return CuckooFilterExistsManyRaw(conn, name, items...)
+}
+
+// CuckooFilterExistsManyRaw checks if each item may be in the cuckoo filter (false positives are possible)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.mexists
+func CuckooFilterExistsManyRaw(conn redis.Conn, name string, items ...interface{}) ([]bool, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return boolsReply(conn.Do(CuckooExistsManyCommand, redis.Args{}.Add(name).Add(items...)...))
+}
+
+// CuckooFilterDelete removes one occurrence of the item from the cuckoo filter
+// Returns false if the item was not found
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CuckooFilterDeleteRaw()
+func CuckooFilterDelete(ctx context.Context, client *Client, name string, item interface{}) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return CuckooFilterDeleteRaw(conn, name, item)
+}
+
+// CuckooFilterDeleteRaw removes one occurrence of the item from the cuckoo filter
+// Returns false if the item was not found
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/cf.del
+func CuckooFilterDeleteRaw(conn redis.Conn, name string, item interface{}) (bool, error) {
+	return redis.Bool(conn.Do(CuckooDeleteCommand, name, item))
+}
+
+// boolsReply converts an array of integer replies to booleans
+func boolsReply(reply interface{}, err error) ([]bool, error) {
+	values, err := redis.Ints(reply, err)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]bool, len(values))
+	for i, value := range values {
+		results[i] = value == 1
+	}
+	return results, nil
+}
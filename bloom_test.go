@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFilterName is the filter name used for testing
+const testFilterName = "test-filter-name"
+
+// TestReserveBloomFilter tests the method ReserveBloomFilter()
+func TestReserveBloomFilter(t *testing.T) {
+
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := ReserveBloomFilter(context.Background(), client, testFilterName, nil)
+		assert.Error(t, err)
+
+		err = ReserveBloomFilter(context.Background(), client, testFilterName, &BloomFilterOptions{Capacity: 100})
+		assert.Error(t, err)
+
+		err = ReserveBloomFilter(context.Background(), client, testFilterName,
+			&BloomFilterOptions{Capacity: 100, ErrorRate: 1.5})
+		assert.Error(t, err)
+	})
+
+	t.Run("reserve using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		reserveCmd := conn.Command(BloomReserveCommand, testFilterName, 0.001, int64(1000000), "NONSCALING").
+			Expect("OK")
+
+		err := ReserveBloomFilter(context.Background(), client, testFilterName, &BloomFilterOptions{
+			Capacity:   1000000,
+			ErrorRate:  0.001,
+			NonScaling: true,
+		})
+		assert.NoError(t, err)
+		assert.True(t, reserveCmd.Called)
+	})
+}
+
+// TestBloomFilterAdd tests the methods BloomFilterAdd() and BloomFilterAddMany()
+func TestBloomFilterAdd(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(BloomAddCommand, testFilterName, "id-1").Expect(int64(1)).Expect(int64(0))
+	conn.Command(BloomAddManyCommand, testFilterName, "id-1", "id-2").Expect([]interface{}{int64(0), int64(1)})
+
+	added, err := BloomFilterAdd(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = BloomFilterAdd(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	var results []bool
+	results, err = BloomFilterAddMany(context.Background(), client, testFilterName, "id-1", "id-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, results)
+
+	results, err = BloomFilterAddMany(context.Background(), client, testFilterName)
+	assert.NoError(t, err)
+	assert.Nil(t, results)
+}
+
+// TestBloomFilterExists tests the methods BloomFilterExists() and BloomFilterExistsMany()
+func TestBloomFilterExists(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(BloomExistsCommand, testFilterName, "id-1").Expect(int64(1))
+	conn.Command(BloomExistsManyCommand, testFilterName, "id-1", "id-9").Expect([]interface{}{int64(1), int64(0)})
+
+	found, err := BloomFilterExists(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	var results []bool
+	results, err = BloomFilterExistsMany(context.Background(), client, testFilterName, "id-1", "id-9")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, results)
+}
+
+// ExampleBloomFilterExists is an example of the method BloomFilterExists()
+func ExampleBloomFilterExists() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the exists command
+	conn.Command(BloomExistsCommand, "seen-ids", "id-1").Expect(int64(0))
+
+	// Have we seen this id?
+	seen, _ := BloomFilterExists(context.Background(), client, "seen-ids", "id-1")
+	fmt.Printf("seen: %v", seen)
+	// Output:seen: false
+}
+
+// TestReserveCuckooFilter tests the method ReserveCuckooFilter()
+func TestReserveCuckooFilter(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	err := ReserveCuckooFilter(context.Background(), client, testFilterName, &CuckooFilterOptions{})
+	assert.Error(t, err)
+
+	reserveCmd := conn.Command(CuckooReserveCommand, testFilterName, int64(1000), "BUCKETSIZE", int64(4)).
+		Expect("OK")
+
+	err = ReserveCuckooFilter(context.Background(), client, testFilterName,
+		&CuckooFilterOptions{BucketSize: 4, Capacity: 1000})
+	assert.NoError(t, err)
+	assert.True(t, reserveCmd.Called)
+}
+
+// TestCuckooFilter tests the methods of the cuckoo filter
+func TestCuckooFilter(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	addCmd := conn.Command(CuckooAddCommand, testFilterName, "id-1").Expect(int64(1))
+	conn.Command(CuckooAddUniqueCommand, testFilterName, "id-1").Expect(int64(0))
+	conn.Command(CuckooExistsCommand, testFilterName, "id-1").Expect(int64(1))
+	conn.Command(CuckooExistsManyCommand, testFilterName, "id-1", "id-2").Expect([]interface{}{int64(1), int64(0)})
+	conn.Command(CuckooDeleteCommand, testFilterName, "id-1").Expect(int64(1)).Expect(int64(0))
+
+	err := CuckooFilterAdd(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.True(t, addCmd.Called)
+
+	var ok bool
+	ok, err = CuckooFilterAddUnique(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = CuckooFilterExists(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	var results []bool
+	results, err = CuckooFilterExistsMany(context.Background(), client, testFilterName, "id-1", "id-2")
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, results)
+
+	ok, err = CuckooFilterDelete(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = CuckooFilterDelete(context.Background(), client, testFilterName, "id-1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// ExampleCuckooFilterAddUnique is an example of the method CuckooFilterAddUnique()
+func ExampleCuckooFilterAddUnique() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the add command
+	conn.Command(CuckooAddUniqueCommand, "seen-ids", "id-1").Expect(int64(1))
+
+	// Add the id if it was not seen
+	added, _ := CuckooFilterAddUnique(context.Background(), client, "seen-ids", "id-1")
+	fmt.Printf("added: %v", added)
+	// Output:added: true
+}
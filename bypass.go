@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// BypassStatus is the response from the BypassHandler()
+type BypassStatus struct {
+	Bypass bool `json:"bypass"`
+}
+
+// SetBypass switches bypass mode on or off (safe to call at any time)
+//
+// In bypass mode the cache is skipped without redeploying (IE: during a data corruption incident):
+// the string and hash readers (IE: Get(), GetBytes(), HashGet()) always miss with redis.ErrNil, the
+// list, set and hash map readers (IE: GetList(), SetMembers(), HashMapGet()) return empty values,
+// the writers (IE: Set(), SetToJSON(), SetList(), HashMapSet()) do nothing, and Memoize() and
+// CacheQuery() always call through to the loader
+func (c *Client) SetBypass(bypass bool) {
+	var value uint32
+	if bypass {
+		value = 1
+	}
+	atomic.StoreUint32(&c.bypass, value)
+}
+
+// IsBypassed returns true if the client is in bypass mode
+func (c *Client) IsBypassed() bool {
+	return atomic.LoadUint32(&c.bypass) == 1
+}
+
+// BypassHandler returns an admin handler for bypass mode
+//
+// GET returns the current status, POST or PUT with ?enabled=true|false switches bypass mode
+// The handler has no authentication, only mount it on an internal admin router
+func BypassHandler(client *Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut:
+			enabled, err := strconv.ParseBool(req.URL.Query().Get("enabled"))
+			if err != nil {
+				http.Error(w, "invalid parameter: enabled", http.StatusBadRequest)
+				return
+			}
+			client.SetBypass(enabled)
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(BypassStatus{Bypass: client.IsBypassed()})
+	})
+}
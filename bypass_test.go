@@ -0,0 +1,205 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetBypass tests the method SetBypass()
+func TestClient_SetBypass(t *testing.T) {
+
+	t.Run("reads miss and writes are skipped", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		getCmd := conn.Command(GetCommand, testKey).Expect(testStringValue)
+		hashSetCmd := conn.Command(HashKeySetCommand, testHashName, testKey, testStringValue)
+
+		client.SetBypass(true)
+		assert.True(t, client.IsBypassed())
+
+		err := Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		err = SetExp(context.Background(), client, testKey, testStringValue, time.Minute)
+		assert.NoError(t, err)
+
+		err = HashSet(context.Background(), client, testHashName, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		_, err = Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		_, err = GetBytes(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		_, err = HashGet(context.Background(), client, testHashName, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		assert.False(t, setCmd.Called)
+		assert.False(t, getCmd.Called)
+		assert.False(t, hashSetCmd.Called)
+
+		// Back to normal
+		client.SetBypass(false)
+		assert.False(t, client.IsBypassed())
+
+		var value string
+		value, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+	})
+
+	t.Run("list, json and hash map paths", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetBypass(true)
+
+		var commands []*redigomock.Cmd
+		for _, command := range []string{
+			ListRangeCommand, ListPushCommand, MultiCommand, SetCommand, SetExpirationCommand,
+			HashMapGetCommand, HashMapSetCommand, HashGetAllCommand, MembersCommand, AddToSetCommand,
+		} {
+			commands = append(commands, conn.GenericCommand(command))
+		}
+		ctx := context.Background()
+
+		// Lists
+		err := SetList(ctx, client, testKey, []string{"a", "b"})
+		assert.NoError(t, err)
+		err = SetListExp(ctx, client, testKey, []string{"a", "b"}, time.Minute)
+		assert.NoError(t, err)
+		var list []string
+		list, err = GetList(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		list, err = GetListRange(ctx, client, testKey, 0, 1)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+
+		// JSON
+		err = SetToJSON(ctx, client, testKey, map[string]string{"name": "alice"}, time.Minute)
+		assert.NoError(t, err)
+
+		// Hash maps
+		pairs := [][2]interface{}{{"field", testStringValue}}
+		err = HashMapSet(ctx, client, testHashName, pairs)
+		assert.NoError(t, err)
+		err = HashMapSetExp(ctx, client, testHashName, pairs, time.Minute)
+		assert.NoError(t, err)
+		var values []string
+		values, err = HashMapGet(ctx, client, testHashName, "field")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{""}, values)
+		var result *MultiResult
+		result, err = HashMapGetFound(ctx, client, testHashName, "field")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false}, result.Found)
+		var dest struct {
+			Field string `redis:"field"`
+		}
+		err = HashGetStruct(ctx, client, testHashName, &dest)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		// Sets
+		err = SetAdd(ctx, client, testKey, testStringValue)
+		assert.NoError(t, err)
+		var members []string
+		members, err = SetMembers(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+
+		for _, command := range commands {
+			assert.False(t, command.Called)
+		}
+	})
+
+	t.Run("loaders are always called", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetBypass(true)
+
+		var calls int
+		double := Memoize(client, "double", time.Minute, func(ctx context.Context, n int) (int, error) {
+			calls++
+			return n * 2, nil
+		})
+		for i := 0; i < 2; i++ {
+			result, err := double(context.Background(), 21)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, result)
+		}
+
+		result, err := CacheQuery(context.Background(), client, testKey, time.Minute, []string{"users"},
+			func(ctx context.Context) (int, error) {
+				calls++
+				return 7, nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, result)
+		assert.Equal(t, 3, calls)
+	})
+}
+
+// ExampleClient_SetBypass is an example of the method SetBypass()
+func ExampleClient_SetBypass() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Bypass the cache during an incident
+	client.SetBypass(true)
+
+	_, err := Get(context.Background(), client, "key")
+	fmt.Printf("miss: %v", err == redis.ErrNil)
+	// Output:miss: true
+}
+
+// TestBypassHandler tests the method BypassHandler()
+func TestBypassHandler(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+	handler := BypassHandler(client)
+
+	tests := []struct {
+		method         string
+		target         string
+		expectedStatus int
+		expectedBody   string
+		expectedBypass bool
+	}{
+		{http.MethodGet, "/bypass", http.StatusOK, "{\"bypass\":false}\n", false},
+		{http.MethodPost, "/bypass?enabled=true", http.StatusOK, "{\"bypass\":true}\n", true},
+		{http.MethodGet, "/bypass", http.StatusOK, "{\"bypass\":true}\n", true},
+		{http.MethodPut, "/bypass?enabled=false", http.StatusOK, "{\"bypass\":false}\n", false},
+		{http.MethodPost, "/bypass?enabled=maybe", http.StatusBadRequest, "invalid parameter: enabled\n", false},
+		{http.MethodDelete, "/bypass", http.StatusMethodNotAllowed, "Method Not Allowed\n", false},
+	}
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(test.method, test.target, nil))
+		assert.Equal(t, test.expectedStatus, recorder.Code, test.method+" "+test.target)
+		assert.Equal(t, test.expectedBody, recorder.Body.String(), test.method+" "+test.target)
+		assert.Equal(t, test.expectedBypass, client.IsBypassed(), test.method+" "+test.target)
+	}
+}
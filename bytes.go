@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SetBytes will set the key in redis to the bytes and keep a reference to each dependency
+// The bytes are written as is (not copied or converted, IE: protobufs, images)
+// Applies the value size guard if set (see: SetValueSizeGuard())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetBytesRaw()
+func SetBytes(ctx context.Context, client *Client, key string, value []byte, dependencies ...string) error {
+	return Set(ctx, client, key, value, dependencies...)
+}
+
+// SetBytesRaw will set the key in redis to the bytes and keep a reference to each dependency
+// The bytes are written as is (not copied or converted, IE: protobufs, images)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func SetBytesRaw(conn redis.Conn, key string, value []byte, dependencies ...string) error {
+	return SetRaw(conn, key, value, dependencies...)
+}
+
+// AppendBytes appends the bytes to the end of the key (the key is created if it does not exist)
+// The bytes are written as is (not copied or converted)
+// Returns the length of the value after the append
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: AppendBytesRaw()
+func AppendBytes(ctx context.Context, client *Client, key string, value []byte,
+	dependencies ...string) (int, error) {
+	return Append(ctx, client, key, value, dependencies...)
+}
+
+// AppendBytesRaw appends the bytes to the end of the key (the key is created if it does not exist)
+// The bytes are written as is (not copied or converted)
+// Returns the length of the value after the append
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/append
+func AppendBytesRaw(conn redis.Conn, key string, value []byte, dependencies ...string) (int, error) {
+	return AppendRaw(conn, key, value, dependencies...)
+}
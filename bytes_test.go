@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetBytes tests the method SetBytes()
+func TestSetBytes(t *testing.T) {
+
+	t.Run("set bytes using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		value := []byte{0x00, 0x01, 0xff}
+		setCmd := conn.Command(SetCommand, testKey, value)
+
+		err := SetBytes(context.Background(), client, testKey, value)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("append bytes using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		value := []byte{0x02, 0x03}
+		conn.Command(AppendCommand, testKey, value).Expect(int64(5))
+
+		length, err := AppendBytes(context.Background(), client, testKey, value)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, length)
+	})
+
+	t.Run("bytes using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		err = SetBytes(ctx, client, testKey, []byte{0x00, 0x01, 0xff}, testDependantKey)
+		assert.NoError(t, err)
+
+		var length int
+		length, err = AppendBytes(ctx, client, testKey, []byte{0x00})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, length)
+
+		var value []byte
+		value, err = GetBytes(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{0x00, 0x01, 0xff, 0x00}, value)
+
+		// Removed with the dependency
+		_, err = DeleteRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleSetBytes is an example of the method SetBytes()
+func ExampleSetBytes() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command
+	value := []byte{0x0a, 0x04}
+	conn.Command(SetCommand, testKey, value)
+
+	// Set the bytes (IE: a protobuf message)
+	err := SetBytes(context.Background(), client, testKey, value)
+	fmt.Printf("set: %v", err == nil)
+	// Output:set: true
+}
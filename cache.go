@@ -9,6 +9,8 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
@@ -17,46 +19,166 @@ import (
 // Package constants (commands)
 const (
 	AddToSetCommand      string = "SADD"
+	AppendCommand        string = "APPEND"
 	AllKeysCommand       string = "*"
 	AuthCommand          string = "AUTH"
+	ClientCommand        string = "CLIENT"
+	CommandCommand       string = "COMMAND"
 	DeleteCommand        string = "DEL"
 	DependencyPrefix     string = "depend:"
+	DumpCommand          string = "DUMP"
 	EvalCommand          string = "EVALSHA"
 	ExecuteCommand       string = "EXEC"
 	ExistsCommand        string = "EXISTS"
 	ExpireCommand        string = "EXPIRE"
+	ExpireMillisCommand  string = "PEXPIRE"
 	FlushAllCommand      string = "FLUSHALL"
+	FlushDBCommand       string = "FLUSHDB"
 	GetCommand           string = "GET"
+	GetDeleteCommand     string = "GETDEL"
+	GetRangeCommand      string = "GETRANGE"
+	HashGetAllCommand    string = "HGETALL"
 	HashGetCommand       string = "HGET"
+	HashIncrementCommand string = "HINCRBY"
 	HashKeySetCommand    string = "HSET"
 	HashMapGetCommand    string = "HMGET"
 	HashMapSetCommand    string = "HMSET"
+	HashSetNXCommand     string = "HSETNX"
+	HelloCommand         string = "HELLO"
+	IncrementByCommand   string = "INCRBY"
+	IncrementCommand     string = "INCR"
+	InfoCommand          string = "INFO"
 	IsMemberCommand      string = "SISMEMBER"
 	KeysCommand          string = "KEYS"
+	ListLengthCommand    string = "LLEN"
 	ListPushCommand      string = "RPUSH"
+	ListPushLeftCommand  string = "LPUSH"
 	ListRangeCommand     string = "LRANGE"
+	LatencyCommand       string = "LATENCY"
+	ListTrimCommand      string = "LTRIM"
 	LoadCommand          string = "LOAD"
 	MembersCommand       string = "SMEMBERS"
+	MemoryCommand        string = "MEMORY"
+	ModuleCommand        string = "MODULE"
 	MultiCommand         string = "MULTI"
+	MultiGetCommand      string = "MGET"
+	ObjectCommand        string = "OBJECT"
+	PersistCommand       string = "PERSIST"
 	PingCommand          string = "PING"
+	PublishCommand       string = "PUBLISH"
+	RandomKeyCommand     string = "RANDOMKEY"
 	RemoveMemberCommand  string = "SREM"
+	RenameCommand        string = "RENAME"
+	RestoreCommand       string = "RESTORE"
+	RoleCommand          string = "ROLE"
+	ScanCommand          string = "SCAN"
 	ScriptCommand        string = "SCRIPT"
 	SelectCommand        string = "SELECT"
 	SetCommand           string = "SET"
 	SetExpirationCommand string = "SETEX"
+	SetExpMillisCommand  string = "PSETEX"
+	SetRangeCommand      string = "SETRANGE"
+	StringLengthCommand  string = "STRLEN"
+	TimeCommand          string = "TIME"
+	TTLMillisCommand     string = "PTTL"
+	TypeCommand          string = "TYPE"
+	UnlinkCommand        string = "UNLINK"
+	UnwatchCommand       string = "UNWATCH"
+	WatchCommand         string = "WATCH"
+)
+
+// Package constants (set commands)
+const (
+	SetCardCommand           string = "SCARD"
+	SetIntersectCardCommand  string = "SINTERCARD"
+	SetIntersectCommand      string = "SINTER"
+	SetIntersectStoreCommand string = "SINTERSTORE"
+	SetMoveCommand           string = "SMOVE"
+	SetScanCommand           string = "SSCAN"
+)
+
+// Package constants (sorted set commands)
+const (
+	SortedSetAddCommand          string = "ZADD"
+	SortedSetCountCommand        string = "ZCOUNT"
+	SortedSetIncrementCommand    string = "ZINCRBY"
+	SortedSetRemoveRankCommand   string = "ZREMRANGEBYRANK"
+	SortedSetRemoveScoreCommand  string = "ZREMRANGEBYSCORE"
+	SortedSetReverseRangeCommand string = "ZREVRANGE"
+	SortedSetReverseRankCommand  string = "ZREVRANK"
+	SortedSetScoreCommand        string = "ZSCORE"
+)
+
+// Package constants (command arguments)
+const (
+	AddressArgument        string = "ADDR"
+	CountArgument          string = "COUNT"
+	DoctorArgument         string = "DOCTOR"
+	ExpireMillisArgument   string = "PX"
+	ExpireSecondsArgument  string = "EX"
+	ExistsArgument         string = "EXISTS"
+	FrequencyArgument      string = "FREQ"
+	GetArgument            string = "GET"
+	HistoryArgument        string = "HISTORY"
+	IdleTimeArgument       string = "IDLETIME"
+	KeepTTLArgument        string = "KEEPTTL"
+	KillArgument           string = "KILL"
+	LimitArgument          string = "LIMIT"
+	ListArgument           string = "LIST"
+	MatchArgument          string = "MATCH"
+	ReplaceArgument        string = "REPLACE"
+	ResetArgument          string = "RESET"
+	SetIfExistsArgument    string = "XX"
+	SetIfNotExistsArgument string = "NX"
+	SetNameArgument        string = "SETNAME"
+	WithScoresArgument     string = "WITHSCORES"
 )
 
 // Get gets a key from redis in string format
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Reads values stored by the value size guard (see: SetValueSizeGuard())
+// Misses return an empty value instead of redis.ErrNil if set (see: SetMissingAsEmpty())
+// Misses of keys matching a registered loader are loaded and stored (see: RegisterLoader())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
+// Reads are hedged with a duplicate read if set (see: WithHedgedRead())
 //
 // Custom connections use method: GetRaw()
 func Get(ctx context.Context, client *Client, key string) (string, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return "", err
+	value, err := get(ctx, client, key)
+	if errors.Is(err, redis.ErrNil) {
+		value, err = client.readThrough(ctx, key)
 	}
-	defer client.CloseConnection(conn)
-	return GetRaw(conn, key)
+	return value, client.missingError(err)
+}
+
+// get gets the value without translating a miss (see: SetMissingAsEmpty())
+func get(ctx context.Context, client *Client, key string) (value string, err error) {
+	if client.IsBypassed() {
+		return "", redis.ErrNil
+	}
+	read := func(conn redis.Conn) (readValue string, readErr error) {
+		if readErr = client.checkTombstone(conn, key); readErr != nil {
+			return
+		}
+		if readValue, readErr = GetRaw(conn, key); readErr == nil && strings.HasPrefix(readValue, encodedValuePrefix) {
+			var data []byte
+			data, readErr = decodeValue(conn, key, []byte(readValue))
+			readValue = string(data)
+		}
+		return
+	}
+	if delay := hedgeDelay(ctx); delay > 0 {
+		value, err = client.hedgedRead(ctx, delay, read)
+	} else {
+		err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+			value, readErr = read(conn)
+			return
+		})
+	}
+	client.shadowGet(key, value, err)
+	client.fireGetHooks(ctx, key, "", err)
+	return
 }
 
 // GetRaw gets a key from redis in string format
@@ -67,17 +189,45 @@ func GetRaw(conn redis.Conn, key string) (string, error) {
 	return redis.String(conn.Do(GetCommand, key))
 }
 
-// GetBytes gets a key from redis formatted in bytes
+// GetBytes gets a key from redis formatted in bytes (no string conversion, see: SetBytes())
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Reads values stored by the value size guard (see: SetValueSizeGuard())
+// Misses return an empty value instead of redis.ErrNil if set (see: SetMissingAsEmpty())
+// Misses of keys matching a registered loader are loaded and stored (see: RegisterLoader())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
 //
 // Custom connections use method: GetBytesRaw()
 func GetBytes(ctx context.Context, client *Client, key string) ([]byte, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return nil, err
+	value, err := getBytes(ctx, client, key)
+	if errors.Is(err, redis.ErrNil) {
+		var loaded string
+		if loaded, err = client.readThrough(ctx, key); err == nil {
+			value = []byte(loaded)
+		}
 	}
-	defer client.CloseConnection(conn)
-	return GetBytesRaw(conn, key)
+	return value, client.missingError(err)
+}
+
+// getBytes gets the value without translating a miss (see: SetMissingAsEmpty())
+func getBytes(ctx context.Context, client *Client, key string) (value []byte, err error) {
+	if client.IsBypassed() {
+		return nil, redis.ErrNil
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		if readErr = client.checkTombstone(conn, key); readErr != nil {
+			return
+		}
+		if value, readErr = GetBytesRaw(conn, key); readErr == nil {
+			value, readErr = decodeValue(conn, key, value)
+		}
+		return
+	})
+	if client.shadowReader() != nil {
+		client.shadowGet(key, string(value), err)
+	}
+	client.fireGetHooks(ctx, key, "", err)
+	return
 }
 
 // GetBytesRaw gets a key from redis formatted in bytes
@@ -89,16 +239,25 @@ func GetBytesRaw(conn redis.Conn, key string) ([]byte, error) {
 }
 
 // GetList returns a []string stored in redis list
+// Large lists can be fetched in pages (see: GetListRange(), NewListIterator())
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Lists with a tombstone are empty if set (see: SetInvalidationTombstones())
 //
 // Custom connections use method: GetListRaw()
-func GetList(ctx context.Context, client *Client, key string) ([]string, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return nil, err
+func GetList(ctx context.Context, client *Client, key string) (list []string, err error) {
+	if client.IsBypassed() {
+		return nil, nil
 	}
-	defer client.CloseConnection(conn)
-	return GetListRaw(conn, key)
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		var tombstone bool
+		if tombstone, readErr = client.hasTombstone(conn, key); readErr != nil || tombstone {
+			return
+		}
+		list, readErr = GetListRaw(conn, key)
+		return
+	})
+	return
 }
 
 // GetListRaw returns a []string stored in redis list
@@ -118,11 +277,14 @@ func GetListRaw(conn redis.Conn, key string) (list []string, err error) {
 	return
 }
 
-// SetList saves a slice as a redis list (appends)
+// SetList saves a slice as a redis list (appends, see: SetListReplace() to replace the list)
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: SetListRaw()
 func SetList(ctx context.Context, client *Client, key string, slice []string) error {
+	if client.IsBypassed() {
+		return nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
@@ -175,17 +337,35 @@ func GetAllKeysRaw(conn redis.Conn) (keys []string, err error) {
 
 // Set will set the key in redis and keep a reference to each dependency
 // value can be both a string or []byte
+// Applies the value size guard if set (see: SetValueSizeGuard())
+// Persists the key if the write-through is started (see: StartWriteThrough())
+// Writes of keys with a tombstone are skipped if set (see: SetInvalidationTombstones())
+// Per-call options (IE: WithTTL(), WithNX()) use method: SetWith()
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: SetRaw()
 func Set(ctx context.Context, client *Client, key string,
 	value interface{}, dependencies ...string) error {
-	conn, err := client.GetConnectionWithContext(ctx)
+	if client.IsBypassed() {
+		return client.persist(ctx, key, value)
+	}
+	original := value
+	value, chunkSize, err := client.guardValue(key, value, true)
 	if err != nil {
 		return err
 	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return err
+	}
 	defer client.CloseConnection(conn)
-	return SetRaw(conn, key, value, dependencies...)
+	var written bool
+	if written, err = client.setValue(conn, key, value, chunkSize, 0, dependencies); err != nil {
+		return err
+	} else if written {
+		client.fireHooks(ctx, KeyEvent{Dependencies: dependencies, Key: key, Operation: OperationSet})
+	}
+	return client.persist(ctx, key, original)
 }
 
 // SetRaw will set the key in redis and keep a reference to each dependency
@@ -194,53 +374,72 @@ func Set(ctx context.Context, client *Client, key string,
 //
 // Spec: https://redis.io/commands/set
 func SetRaw(conn redis.Conn, key string, value interface{}, dependencies ...string) error {
-	if _, err := conn.Do(SetCommand, key, value); err != nil {
-		return err
-	}
-
-	return linkDependencies(conn, key, dependencies...)
+	args := getArgs()
+	defer putArgs(args)
+	*args = append(*args, key, value)
+	return doLinkDependencies(conn, (*args)[0], dependencies, SetCommand, *args...)
 }
 
 // SetExp will set the key in redis and keep a reference to each dependency
 // value can be both a string or []byte
+// Applies the value size guard if set (see: SetValueSizeGuard())
+// Persists the key if the write-through is started (see: StartWriteThrough())
+// Writes of keys with a tombstone are skipped if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: SetExpRaw()
 func SetExp(ctx context.Context, client *Client, key string, value interface{},
 	ttl time.Duration, dependencies ...string) error {
-	conn, err := client.GetConnectionWithContext(ctx)
+	if client.IsBypassed() {
+		return client.persist(ctx, key, value)
+	} else if ttl < time.Millisecond {
+		return ErrInvalidTTL
+	}
+	original := value
+	value, chunkSize, err := client.guardValue(key, value, true)
 	if err != nil {
 		return err
 	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return err
+	}
 	defer client.CloseConnection(conn)
-	return SetExpRaw(conn, key, value, ttl, dependencies...)
+	var written bool
+	if written, err = client.setValue(conn, key, value, chunkSize, ttl, dependencies); err != nil {
+		return err
+	} else if written {
+		client.fireHooks(ctx, KeyEvent{Dependencies: dependencies, Key: key, Operation: OperationSet, TTL: ttl})
+	}
+	return client.persist(ctx, key, original)
 }
 
 // SetExpRaw will set the key in redis and keep a reference to each dependency
 // value can be both a string or []byte
+// The ttl uses millisecond precision if it is not a whole number of seconds
 // Uses existing connection (does not close connection)
 //
 // Spec: https://redis.io/commands/setex
+// Spec: https://redis.io/commands/psetex
 func SetExpRaw(conn redis.Conn, key string, value interface{},
 	ttl time.Duration, dependencies ...string) error {
-	if _, err := conn.Do(SetExpirationCommand, key, int64(ttl.Seconds()), value); err != nil {
-		return err
+	if isWholeSeconds(ttl) {
+		return doLinkDependencies(conn, key, dependencies, SetExpirationCommand, key, int64(ttl.Seconds()), value)
 	}
-
-	return linkDependencies(conn, key, dependencies...)
+	return doLinkDependencies(conn, key, dependencies, SetExpMillisCommand, key, ttl.Milliseconds(), value)
 }
 
 // Exists checks if a key is present or not
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
 //
 // Custom connections use method: ExistsRaw()
-func Exists(ctx context.Context, client *Client, key string) (bool, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return false, err
-	}
-	defer client.CloseConnection(conn)
-	return ExistsRaw(conn, key)
+func Exists(ctx context.Context, client *Client, key string) (found bool, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		found, readErr = ExistsRaw(conn, key)
+		return
+	})
+	return
 }
 
 // ExistsRaw checks if a key is present or not
@@ -251,28 +450,122 @@ func ExistsRaw(conn redis.Conn, key string) (bool, error) {
 	return redis.Bool(conn.Do(ExistsCommand, key))
 }
 
+// ExistsMulti checks which of the keys are present in a single round trip
+// Returns the number of keys found and the presence of each key (in order)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: ExistsMultiRaw()
+func ExistsMulti(ctx context.Context, client *Client, keys ...string) (total int, found []bool, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		total, found, readErr = ExistsMultiRaw(conn, keys...)
+		return
+	})
+	return
+}
+
+// ExistsMultiRaw checks which of the keys are present in a single round trip (pipelined)
+// Returns the number of keys found and the presence of each key (in order)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/exists
+func ExistsMultiRaw(conn redis.Conn, keys ...string) (int, []bool, error) {
+	if len(keys) == 0 {
+		return 0, nil, nil
+	}
+
+	// Pipeline a check for each key
+	for _, key := range keys {
+		if err := conn.Send(ExistsCommand, key); err != nil {
+			return 0, nil, err
+		}
+	}
+	replies, err := flushPipeline(conn)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total := 0
+	found := make([]bool, len(keys))
+	for i, reply := range replies {
+		if found[i], err = redis.Bool(reply, nil); err != nil {
+			return 0, nil, err
+		} else if found[i] {
+			total++
+		}
+	}
+	return total, found, nil
+}
+
 // Expire sets the expiration for a given key
+// Options (redis 7+) only set the expiration under a condition (IE: ExpireIfGreater())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: ExpireRaw()
-func Expire(ctx context.Context, client *Client, key string, duration time.Duration) error {
+func Expire(ctx context.Context, client *Client, key string, duration time.Duration, options ...ExpireOption) error {
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
 	}
 	defer client.CloseConnection(conn)
-	return ExpireRaw(conn, key, duration)
+	return ExpireRaw(conn, key, duration, options...)
 }
 
 // ExpireRaw sets the expiration for a given key
+// The duration uses millisecond precision if it is not a whole number of seconds
+// Options (redis 7+) only set the expiration under a condition (IE: ExpireIfGreater())
 // Uses existing connection (does not close connection)
 //
 // Spec: https://redis.io/commands/expire
-func ExpireRaw(conn redis.Conn, key string, duration time.Duration) (err error) {
-	_, err = conn.Do(ExpireCommand, key, int64(duration.Seconds()))
+// Spec: https://redis.io/commands/pexpire
+func ExpireRaw(conn redis.Conn, key string, duration time.Duration, options ...ExpireOption) (err error) {
+	command, args := expireCommand(key, duration, options...)
+	_, err = conn.Do(command, args...)
 	return
 }
 
+// ExpireWithResult sets the expiration for a given key
+// Returns false if the key does not exist or the condition of the options was not met
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ExpireWithResultRaw()
+func ExpireWithResult(ctx context.Context, client *Client, key string, duration time.Duration,
+	options ...ExpireOption) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return ExpireWithResultRaw(conn, key, duration, options...)
+}
+
+// ExpireWithResultRaw sets the expiration for a given key
+// Returns false if the key does not exist or the condition of the options was not met
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/expire
+// Spec: https://redis.io/commands/pexpire
+func ExpireWithResultRaw(conn redis.Conn, key string, duration time.Duration,
+	options ...ExpireOption) (bool, error) {
+	command, args := expireCommand(key, duration, options...)
+	return redis.Bool(conn.Do(command, args...))
+}
+
+// expireCommand returns the command and arguments to set the expiration
+// PEXPIRE is used if the duration is not a whole number of seconds
+func expireCommand(key string, duration time.Duration, options ...ExpireOption) (string, []interface{}) {
+	conditions := ExpireConditions(options...)
+	if isWholeSeconds(duration) {
+		return ExpireCommand, redis.Args{}.Add(key, int64(duration.Seconds())).AddFlat(conditions)
+	}
+	return ExpireMillisCommand, redis.Args{}.Add(key, duration.Milliseconds()).AddFlat(conditions)
+}
+
+// isWholeSeconds returns true if the duration has no sub-second part
+func isWholeSeconds(duration time.Duration) bool {
+	return duration%time.Second == 0
+}
+
 // DeleteWithoutDependency will remove keys without using dependency script
 // Creates a new connection and closes connection at end of function call
 //
@@ -283,36 +576,54 @@ func DeleteWithoutDependency(ctx context.Context, client *Client, keys ...string
 		return 0, err
 	}
 	defer client.CloseConnection(conn)
-	return DeleteWithoutDependencyRaw(conn, keys...)
+	total, err := DeleteWithoutDependencyRaw(conn, keys...)
+	client.fireRemoveHooks(ctx, OperationDelete, keys, total, err)
+	return total, err
 }
 
 // DeleteWithoutDependencyRaw will remove keys without using dependency script
+// The total is from the DEL replies (missing keys are not counted), keys that failed are
+// returned in a *DeleteError and the other keys are still removed
 // Uses existing connection (does not close connection)
 //
 // Spec: https://redis.io/commands/del
 func DeleteWithoutDependencyRaw(conn redis.Conn, keys ...string) (total int, err error) {
+	if len(keys) == 0 {
+		return
+	}
+
+	// Pipeline a DEL per key (one reply per key)
+	var failures deleteFailures
 	for _, key := range keys {
-		if _, err = conn.Do(DeleteCommand, key); err != nil {
-			return
+		if err = conn.Send(DeleteCommand, key); err != nil {
+			failures.add(err, keys...)
+			return 0, failures.error(0)
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		failures.add(err, keys...)
+		return 0, failures.error(0)
+	}
+	for _, key := range keys {
+		var deleted int
+		if deleted, err = redis.Int(conn.Receive()); err != nil {
+			failures.add(err, key)
+			continue
 		}
-		total++
+		total += deleted
 	}
 
-	return
+	return total, failures.error(total)
 }
 
 // DestroyCache will flush the entire redis server
-// It only removes keys, not scripts
+// It only removes keys, not scripts (see: DeleteByPattern() to remove a prefix)
+// A protected client requires a confirmation (see: SetFlushProtection(), WithFlushToken())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: DestroyCacheRaw()
-func DestroyCache(ctx context.Context, client *Client) error {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return err
-	}
-	defer client.CloseConnection(conn)
-	return DestroyCacheRaw(conn)
+func DestroyCache(ctx context.Context, client *Client, options ...FlushOption) error {
+	return destroyCache(ctx, client, FlushAllCommand, options)
 }
 
 // DestroyCacheRaw will flush the entire redis server
@@ -325,18 +636,53 @@ func DestroyCacheRaw(conn redis.Conn) (err error) {
 	return
 }
 
+// DestroyCacheDB will flush the selected database (other databases on the server are kept)
+// A protected client requires a confirmation (see: SetFlushProtection(), WithFlushToken())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DestroyCacheDBRaw()
+func DestroyCacheDB(ctx context.Context, client *Client, options ...FlushOption) error {
+	return destroyCache(ctx, client, FlushDBCommand, options)
+}
+
+// DestroyCacheDBRaw will flush the selected database
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/flushdb
+func DestroyCacheDBRaw(conn redis.Conn) (err error) {
+	_, err = conn.Do(FlushDBCommand)
+	return
+}
+
 // SetToJSON stores the struct data (Struct->JSON) into redis under a key
+// Writes of keys with a tombstone are skipped if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: SetToJSONRaw()
 func SetToJSON(ctx context.Context, client *Client, keyName string, modelData interface{},
 	ttl time.Duration, dependencies ...string) error {
-	conn, err := client.GetConnectionWithContext(ctx)
+	if client.IsBypassed() {
+		return nil
+	}
+	responseBytes, err := json.Marshal(&modelData)
 	if err != nil {
 		return err
 	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return err
+	}
 	defer client.CloseConnection(conn)
-	return SetToJSONRaw(conn, keyName, modelData, ttl, dependencies...)
+	if ttl < 0 {
+		ttl = 0
+	}
+	var written bool
+	if written, err = client.setValue(conn, keyName, string(responseBytes), 0, ttl, dependencies); err != nil {
+		return err
+	} else if written {
+		client.fireHooks(ctx, KeyEvent{Dependencies: dependencies, Key: keyName, Operation: OperationSet, TTL: ttl})
+	}
+	return nil
 }
 
 // SetToJSONRaw stores the struct data (Struct->JSON) into redis under a key
@@ -379,3 +725,18 @@ func PingRaw(conn redis.Conn) (err error) {
 	_, err = conn.Do(PingCommand)
 	return
 }
+
+// flushPipeline sends any pending commands and returns all the replies
+// The first redis error found in the replies is returned as the error
+func flushPipeline(conn redis.Conn) ([]interface{}, error) {
+	replies, err := redis.Values(conn.Do(""))
+	if err != nil {
+		return nil, err
+	}
+	for _, reply := range replies {
+		if replyErr, ok := reply.(redis.Error); ok {
+			return replies, replyErr
+		}
+	}
+	return replies, nil
+}
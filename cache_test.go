@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -325,6 +326,19 @@ func TestSetExp(t *testing.T) {
 		}
 	})
 
+	t.Run("set exp with millisecond precision", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetExpMillisCommand, testKey, int64(1500), testStringValue).Expect("OK")
+
+		err := SetExp(context.Background(), client, testKey, testStringValue, 1500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
 	t.Run("set exp command using real redis", func(t *testing.T) {
 		if testing.Short() {
 			t.Skip("skipping live local redis tests")
@@ -814,6 +828,82 @@ func ExampleExists() {
 	// Output:key exists
 }
 
+// TestExistsMulti is testing the method ExistsMulti()
+func TestExistsMulti(t *testing.T) {
+
+	t.Run("exists multi using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ExistsCommand, "key-1").Expect(int64(1))
+		conn.Command(ExistsCommand, "key-2").Expect(int64(0))
+		conn.Command(ExistsCommand, "key-3").Expect(int64(1))
+
+		total, found, err := ExistsMulti(context.Background(), client, "key-1", "key-2", "key-3")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, []bool{true, false, true}, found)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		total, found, err := ExistsMulti(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Nil(t, found)
+	})
+
+	t.Run("exists multi using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, "key-1", testStringValue)
+		assert.NoError(t, err)
+		err = SetRaw(conn, "key-3", testStringValue)
+		assert.NoError(t, err)
+
+		total, found, existsErr := ExistsMultiRaw(conn, "key-1", "key-2", "key-3", "key-1")
+		assert.NoError(t, existsErr)
+		assert.Equal(t, 3, total)
+		assert.Equal(t, []bool{true, false, true, true}, found)
+	})
+}
+
+// ExampleExistsMulti is an example of the method ExistsMulti()
+func ExampleExistsMulti() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the exists commands
+	conn.Command(ExistsCommand, "key-1").Expect(int64(1))
+	conn.Command(ExistsCommand, "key-2").Expect(int64(0))
+
+	// Check both keys at once
+	total, found, _ := ExistsMulti(context.Background(), client, "key-1", "key-2")
+	fmt.Printf("total: %d found: %v", total, found)
+	// Output:total: 1 found: [true false]
+}
+
 // TestExpire is testing the method Expire()
 func TestExpire(t *testing.T) {
 
@@ -849,6 +939,19 @@ func TestExpire(t *testing.T) {
 		}
 	})
 
+	t.Run("expire with millisecond precision", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		expireCmd := conn.Command(ExpireMillisCommand, testKey, int64(250)).Expect(int64(1))
+
+		err := Expire(context.Background(), client, testKey, 250*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, expireCmd.Called)
+	})
+
 	t.Run("expire command using real redis", func(t *testing.T) {
 		if testing.Short() {
 			t.Skip("skipping live local redis tests")
@@ -1267,7 +1370,7 @@ func TestDeleteWithoutDependency(t *testing.T) {
 				// The main command to test
 				var commands []*redigomock.Cmd
 				for _, key := range test.keys {
-					cmd := conn.Command(DeleteCommand, key)
+					cmd := conn.Command(DeleteCommand, key).Expect(int64(1))
 					commands = append(commands, cmd)
 				}
 
@@ -1281,6 +1384,27 @@ func TestDeleteWithoutDependency(t *testing.T) {
 		}
 	})
 
+	t.Run("partial failure using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		errTestReadOnly := errors.New("READONLY You can't write against a read only replica")
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+		conn.Command(DeleteCommand, "key-2").ExpectError(errTestReadOnly)
+		conn.Command(DeleteCommand, "key-3").Expect(int64(0))
+
+		total, err := DeleteWithoutDependency(context.Background(), client, "key-1", "key-2", "key-3")
+		assert.ErrorIs(t, err, errTestReadOnly)
+		assert.Equal(t, 1, total)
+
+		var deleteErr *DeleteError
+		assert.ErrorAs(t, err, &deleteErr)
+		assert.Equal(t, 1, deleteErr.Deleted)
+		assert.Equal(t, []string{"key-2"}, deleteErr.Failed)
+	})
+
 	t.Run("delete without using dependencies using real redis", func(t *testing.T) {
 		if testing.Short() {
 			t.Skip("skipping live local redis tests")
@@ -1300,9 +1424,9 @@ func TestDeleteWithoutDependency(t *testing.T) {
 		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
 		assert.NoError(t, err)
 
-		// Fire the command
+		// Fire the command (missing keys are not counted)
 		var total int
-		total, err = DeleteWithoutDependency(context.Background(), client, testKey)
+		total, err = DeleteWithoutDependency(context.Background(), client, testKey, "missing-key")
 		assert.NoError(t, err)
 		assert.Equal(t, 1, total)
 
@@ -0,0 +1,225 @@
+// Package cacheadmin is an optional http.Handler with read-only admin endpoints for a go-cache client
+// (key lookup, TTL, dependency members, stats and script status), so each service does not need its
+// own cache debug page
+package cacheadmin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mrz1836/go-cache"
+)
+
+// DefaultMaxValueSize is the most bytes of a value returned by the key endpoint
+const DefaultMaxValueSize = 4096
+
+// Authorizer returns if the request is allowed (IE: checks a token or the remote address)
+type Authorizer func(req *http.Request) bool
+
+// Config is the configuration of the admin handler (see: NewHandler())
+type Config struct {
+	Authorize    Authorizer // Required: every request is rejected without an authorizer (see: AllowAll())
+	MaxValueSize int        // Most bytes of a value returned by the key endpoint (default: DefaultMaxValueSize)
+}
+
+// KeyResponse is the response of the key endpoint
+type KeyResponse struct {
+	Exists    bool          `json:"exists"`          // The key is present
+	Key       string        `json:"key"`             // The key
+	TTL       time.Duration `json:"ttl"`             // Time left before the key expires (0 if it never expires)
+	Truncated bool          `json:"truncated"`       // The value is longer than the max value size
+	Type      string        `json:"type"`            // Type of the value (IE: string, hash, set)
+	Value     *string       `json:"value,omitempty"` // Value of the key (string keys only)
+}
+
+// TTLResponse is the response of the TTL endpoint
+type TTLResponse struct {
+	Exists bool          `json:"exists"` // The key is present
+	Key    string        `json:"key"`    // The key
+	TTL    time.Duration `json:"ttl"`    // Time left before the key expires (0 if it never expires)
+}
+
+// DependencyResponse is the response of the dependency endpoint
+type DependencyResponse struct {
+	Dependency string   `json:"dependency"` // The dependency
+	Members    []string `json:"members"`    // Keys linked to the dependency (across all shards)
+	Shards     int      `json:"shards"`     // Shards of the dependency set (see: cache.Client.SetDependencyShards())
+}
+
+// NewHandler returns a read-only admin handler for the client, with the endpoints (GET only):
+//
+//	/key?key=<key>                the existence, TTL, type and value (string keys) of a key
+//	/ttl?key=<key>                the TTL of a key
+//	/dependency?dependency=<name> the keys linked to a dependency
+//	/stats                        the stats of the client (see: cache.Client.Stats())
+//	/scripts                      if each registered script is loaded (see: cache.ScriptsLoaded())
+//
+// Mount it under a prefix with http.StripPrefix (IE: /debug/cache), every response is JSON
+func NewHandler(client *cache.Client, config Config) http.Handler {
+	if config.MaxValueSize <= 0 {
+		config.MaxValueSize = DefaultMaxValueSize
+	}
+	h := &handler{client: client, config: config}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/key", h.key)
+	mux.HandleFunc("/ttl", h.ttl)
+	mux.HandleFunc("/dependency", h.dependency)
+	mux.HandleFunc("/stats", h.stats)
+	mux.HandleFunc("/scripts", h.scripts)
+	h.mux = mux
+	return h
+}
+
+// AllowAll allows every request (only mount the handler on an internal admin router)
+func AllowAll() Authorizer {
+	return func(*http.Request) bool {
+		return true
+	}
+}
+
+// BearerToken allows requests with the header "Authorization: Bearer <token>"
+func BearerToken(token string) Authorizer {
+	expected := []byte("Bearer " + token)
+	return func(req *http.Request) bool {
+		return len(token) > 0 &&
+			subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), expected) == 1
+	}
+}
+
+// BasicAuth allows requests with the username and password (HTTP basic authentication)
+func BasicAuth(username, password string) Authorizer {
+	return func(req *http.Request) bool {
+		user, pass, ok := req.BasicAuth()
+		return ok && len(password) > 0 &&
+			subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1 &&
+			subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+	}
+}
+
+// handler is the admin handler
+type handler struct {
+	client *cache.Client
+	config Config
+	mux    *http.ServeMux
+}
+
+// ServeHTTP authorizes the request and routes it to the endpoint
+func (h *handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h.config.Authorize == nil || !h.config.Authorize(req) {
+		writeError(w, http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
+		return
+	} else if req.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, http.StatusText(http.StatusMethodNotAllowed))
+		return
+	}
+	h.mux.ServeHTTP(w, req)
+}
+
+// key responds with the existence, TTL, type and value of the key
+func (h *handler) key(w http.ResponseWriter, req *http.Request) {
+	key := req.URL.Query().Get("key")
+	if len(key) == 0 {
+		writeError(w, http.StatusBadRequest, "missing required parameter: key")
+		return
+	}
+	info, err := cache.KeyInfoMulti(req.Context(), h.client, key)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	response := KeyResponse{Exists: info[0].Exists, Key: key, TTL: info[0].TTL, Type: info[0].Type}
+	if info[0].Type == "string" {
+		var value []byte
+		var found bool
+		if value, found, err = cache.GetBytesWithFound(req.Context(), h.client, key); err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		} else if found {
+			if len(value) > h.config.MaxValueSize {
+				value = value[:h.config.MaxValueSize]
+				response.Truncated = true
+			}
+			text := string(value)
+			response.Value = &text
+		}
+	}
+	writeJSON(w, response)
+}
+
+// ttl responds with the TTL of the key
+func (h *handler) ttl(w http.ResponseWriter, req *http.Request) {
+	key := req.URL.Query().Get("key")
+	if len(key) == 0 {
+		writeError(w, http.StatusBadRequest, "missing required parameter: key")
+		return
+	}
+	info, err := cache.KeyInfoMulti(req.Context(), h.client, key)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, TTLResponse{Exists: info[0].Exists, Key: key, TTL: info[0].TTL})
+}
+
+// dependency responds with the keys linked to the dependency (across all shards)
+func (h *handler) dependency(w http.ResponseWriter, req *http.Request) {
+	dependency := req.URL.Query().Get("dependency")
+	if len(dependency) == 0 {
+		writeError(w, http.StatusBadRequest, "missing required parameter: dependency")
+		return
+	}
+
+	shards := h.client.DependencyShards(dependency)
+	sets := []string{cache.DependencyPrefix + dependency}
+	if shards > 1 {
+		sets = make([]string, shards)
+		for i := range sets {
+			sets[i] = cache.DependencyPrefix + dependency + ":" + strconv.Itoa(i)
+		}
+	}
+
+	response := DependencyResponse{Dependency: dependency, Members: []string{}, Shards: shards}
+	for _, set := range sets {
+		members, err := cache.SetMembers(req.Context(), h.client, set)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		response.Members = append(response.Members, members...)
+	}
+	writeJSON(w, response)
+}
+
+// stats responds with the stats of the client
+func (h *handler) stats(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, h.client.Stats())
+}
+
+// scripts responds with if each registered script is loaded (by SHA)
+func (h *handler) scripts(w http.ResponseWriter, req *http.Request) {
+	loaded, err := cache.ScriptsLoaded(req.Context(), h.client)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	writeJSON(w, loaded)
+}
+
+// writeJSON writes the response as JSON
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// writeError writes the error as JSON with the status
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,191 @@
+package cacheadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadMockClient returns a client on a mocked redis
+func loadMockClient() (*cache.Client, *redigomock.Conn) {
+	conn := redigomock.NewConn()
+	return &cache.Client{
+		Pool: &redis.Pool{
+			Dial:    func() (redis.Conn, error) { return conn, nil },
+			MaxIdle: 1,
+		},
+	}, conn
+}
+
+// get sends a GET request to the handler
+func get(handler http.Handler, target string, authorized bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if authorized {
+		req.Header.Set("Authorization", "Bearer secret")
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+// TestNewHandler tests the admin handler
+func TestNewHandler(t *testing.T) {
+
+	t.Run("unauthorized", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		handler := NewHandler(client, Config{Authorize: BearerToken("secret")})
+		w := get(handler, "/stats", false)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		// No authorizer rejects everything
+		handler = NewHandler(client, Config{})
+		w = get(handler, "/stats", true)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		handler := NewHandler(client, Config{Authorize: AllowAll()})
+		req := httptest.NewRequest(http.MethodPost, "/stats", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET", w.Header().Get("Allow"))
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		authorize := BasicAuth("admin", "password")
+
+		req := httptest.NewRequest(http.MethodGet, "/stats", nil)
+		assert.False(t, authorize(req))
+		req.SetBasicAuth("admin", "wrong")
+		assert.False(t, authorize(req))
+		req.SetBasicAuth("admin", "password")
+		assert.True(t, authorize(req))
+
+		// An empty password never matches
+		assert.False(t, BasicAuth("admin", "")(req))
+	})
+
+	t.Run("key", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		conn.Command(cache.TTLMillisCommand, "key-1").Expect(int64(60000))
+		conn.Command(cache.TypeCommand, "key-1").Expect("string")
+		conn.Command(cache.GetCommand, "key-1").Expect([]byte("hello world"))
+
+		handler := NewHandler(client, Config{Authorize: BearerToken("secret"), MaxValueSize: 5})
+		w := get(handler, "/key?key=key-1", true)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var response KeyResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Exists)
+		assert.Equal(t, time.Minute, response.TTL)
+		assert.True(t, response.Truncated)
+		assert.Equal(t, "hello", *response.Value)
+
+		// Missing parameter
+		w = get(handler, "/key", true)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		conn.Command(cache.TTLMillisCommand, "key-1").Expect(int64(-2))
+		conn.Command(cache.TypeCommand, "key-1").Expect("none")
+
+		handler := NewHandler(client, Config{Authorize: AllowAll()})
+		w := get(handler, "/ttl?key=key-1", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response TTLResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Exists)
+	})
+
+	t.Run("dependency", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyShards("user-1", 2)
+		assert.NoError(t, err)
+		conn.Command(cache.MembersCommand, cache.DependencyPrefix+"user-1:0").Expect([]interface{}{[]byte("key-1")})
+		conn.Command(cache.MembersCommand, cache.DependencyPrefix+"user-1:1").Expect([]interface{}{[]byte("key-2")})
+
+		handler := NewHandler(client, Config{Authorize: AllowAll()})
+		w := get(handler, "/dependency?dependency=user-1", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response DependencyResponse
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, response.Shards)
+		assert.Equal(t, []string{"key-1", "key-2"}, response.Members)
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		conn.Command(cache.MembersCommand, cache.DependencyPrefix+"user-1").ExpectError(fmt.Errorf("connection reset"))
+
+		handler := NewHandler(client, Config{Authorize: AllowAll()})
+		w := get(handler, "/dependency?dependency=user-1", false)
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+		assert.Contains(t, w.Body.String(), "connection reset")
+	})
+
+	t.Run("stats and scripts", func(t *testing.T) {
+		client, conn := loadMockClient()
+		defer client.CloseAll(conn)
+
+		handler := NewHandler(client, Config{Authorize: AllowAll()})
+		w := get(handler, "/stats", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"pool"`)
+
+		w = get(handler, "/scripts", false)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "{}\n", w.Body.String())
+	})
+}
+
+// ExampleNewHandler is an example of the method NewHandler()
+func ExampleNewHandler() {
+	client, conn := loadMockClient()
+	defer client.CloseAll(conn)
+
+	// Mount under a prefix on an internal admin router
+	mux := http.NewServeMux()
+	mux.Handle("/debug/cache/", http.StripPrefix("/debug/cache",
+		NewHandler(client, Config{Authorize: BearerToken("secret")})))
+
+	conn.Command(cache.TTLMillisCommand, "key-1").Expect(int64(-1))
+	conn.Command(cache.TypeCommand, "key-1").Expect("hash")
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/cache/ttl?key=key-1", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	fmt.Print(w.Body.String())
+	// Output:{"exists":true,"key":"key-1","ttl":0}
+}
@@ -0,0 +1,351 @@
+// Package cachemock is a hand-written mock of the cache.Cacher interface for unit testing
+// services built on go-cache, without redigomock plumbing or a live redis
+package cachemock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-cache"
+)
+
+// Make sure the mock implements the interface
+var _ cache.Cacher = (*Mock)(nil)
+
+// Call is a single recorded call to the mock
+type Call struct {
+	Args   []interface{} // Arguments of the call (without the context)
+	Method string        // Name of the method (IE: Get)
+}
+
+// Mock implements cache.Cacher, set the XFunc fields to control the results
+//
+// Methods without a XFunc return the zero values and a nil error, every call is recorded
+type Mock struct {
+	CloseFunc                   func()
+	DeleteFunc                  func(ctx context.Context, keys ...string) (int, error)
+	DeleteWithoutDependencyFunc func(ctx context.Context, keys ...string) (int, error)
+	DestroyCacheFunc            func(ctx context.Context) error
+	ExistsFunc                  func(ctx context.Context, key string) (bool, error)
+	ExpireFunc                  func(ctx context.Context, key string, duration time.Duration, options ...cache.ExpireOption) error
+	GetFunc                     func(ctx context.Context, key string) (string, error)
+	GetAllKeysFunc              func(ctx context.Context) ([]string, error)
+	GetBytesFunc                func(ctx context.Context, key string) ([]byte, error)
+	GetListFunc                 func(ctx context.Context, key string) ([]string, error)
+	HashGetFunc                 func(ctx context.Context, hash, key string) (string, error)
+	HashMapGetFunc              func(ctx context.Context, hashName string, keys ...interface{}) ([]string, error)
+	HashMapSetFunc              func(ctx context.Context, hashName string, pairs [][2]interface{}, dependencies ...string) error
+	HashMapSetExpFunc           func(ctx context.Context, hashName string, pairs [][2]interface{}, ttl time.Duration, dependencies ...string) error
+	HashSetFunc                 func(ctx context.Context, hashName, hashKey string, value interface{}, dependencies ...string) error
+	KillByDependencyFunc        func(ctx context.Context, keys ...string) (int, error)
+	PingFunc                    func(ctx context.Context) error
+	ReleaseLockFunc             func(ctx context.Context, name, secret string) (bool, error)
+	SetFunc                     func(ctx context.Context, key string, value interface{}, dependencies ...string) error
+	SetAddFunc                  func(ctx context.Context, setName string, member interface{}, dependencies ...string) error
+	SetAddManyFunc              func(ctx context.Context, setName string, members ...interface{}) error
+	SetExpFunc                  func(ctx context.Context, key string, value interface{}, ttl time.Duration, dependencies ...string) error
+	SetIsMemberFunc             func(ctx context.Context, set string, member interface{}) (bool, error)
+	SetListFunc                 func(ctx context.Context, key string, slice []string) error
+	SetMembersFunc              func(ctx context.Context, set string) ([]string, error)
+	SetRemoveMemberFunc         func(ctx context.Context, set string, member interface{}) error
+	SetToJSONFunc               func(ctx context.Context, keyName string, modelData interface{}, ttl time.Duration, dependencies ...string) error
+	WriteLockFunc               func(ctx context.Context, name, secret string, ttl int64) (bool, error)
+
+	calls []Call
+	mu    sync.Mutex
+}
+
+// New returns a new mock
+func New() *Mock {
+	return &Mock{}
+}
+
+// Calls returns all the recorded calls (in order)
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call{}, m.calls...)
+}
+
+// CallCount returns the number of recorded calls to the method
+func (m *Mock) CallCount(method string) (count int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, call := range m.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return
+}
+
+// Reset removes all the recorded calls
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	m.calls = nil
+	m.mu.Unlock()
+}
+
+// record adds the call to the recorded calls
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Args: args, Method: method})
+	m.mu.Unlock()
+}
+
+// Close records the call and calls CloseFunc (if set)
+func (m *Mock) Close() {
+	m.record("Close")
+	if m.CloseFunc != nil {
+		m.CloseFunc()
+	}
+}
+
+// Delete records the call and returns the result of DeleteFunc (if set)
+func (m *Mock) Delete(ctx context.Context, keys ...string) (int, error) {
+	m.record("Delete", keys)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, keys...)
+	}
+	return 0, nil
+}
+
+// DeleteWithoutDependency records the call and returns the result of DeleteWithoutDependencyFunc (if set)
+func (m *Mock) DeleteWithoutDependency(ctx context.Context, keys ...string) (int, error) {
+	m.record("DeleteWithoutDependency", keys)
+	if m.DeleteWithoutDependencyFunc != nil {
+		return m.DeleteWithoutDependencyFunc(ctx, keys...)
+	}
+	return 0, nil
+}
+
+// DestroyCache records the call and returns the result of DestroyCacheFunc (if set)
+func (m *Mock) DestroyCache(ctx context.Context) error {
+	m.record("DestroyCache")
+	if m.DestroyCacheFunc != nil {
+		return m.DestroyCacheFunc(ctx)
+	}
+	return nil
+}
+
+// Exists records the call and returns the result of ExistsFunc (if set)
+func (m *Mock) Exists(ctx context.Context, key string) (bool, error) {
+	m.record("Exists", key)
+	if m.ExistsFunc != nil {
+		return m.ExistsFunc(ctx, key)
+	}
+	return false, nil
+}
+
+// Expire records the call and returns the result of ExpireFunc (if set)
+func (m *Mock) Expire(ctx context.Context, key string, duration time.Duration, options ...cache.ExpireOption) error {
+	m.record("Expire", key, duration, options)
+	if m.ExpireFunc != nil {
+		return m.ExpireFunc(ctx, key, duration, options...)
+	}
+	return nil
+}
+
+// Get records the call and returns the result of GetFunc (if set)
+func (m *Mock) Get(ctx context.Context, key string) (string, error) {
+	m.record("Get", key)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
+	}
+	return "", nil
+}
+
+// GetAllKeys records the call and returns the result of GetAllKeysFunc (if set)
+func (m *Mock) GetAllKeys(ctx context.Context) ([]string, error) {
+	m.record("GetAllKeys")
+	if m.GetAllKeysFunc != nil {
+		return m.GetAllKeysFunc(ctx)
+	}
+	return nil, nil
+}
+
+// GetBytes records the call and returns the result of GetBytesFunc (if set)
+func (m *Mock) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	m.record("GetBytes", key)
+	if m.GetBytesFunc != nil {
+		return m.GetBytesFunc(ctx, key)
+	}
+	return nil, nil
+}
+
+// GetList records the call and returns the result of GetListFunc (if set)
+func (m *Mock) GetList(ctx context.Context, key string) ([]string, error) {
+	m.record("GetList", key)
+	if m.GetListFunc != nil {
+		return m.GetListFunc(ctx, key)
+	}
+	return nil, nil
+}
+
+// HashGet records the call and returns the result of HashGetFunc (if set)
+func (m *Mock) HashGet(ctx context.Context, hash, key string) (string, error) {
+	m.record("HashGet", hash, key)
+	if m.HashGetFunc != nil {
+		return m.HashGetFunc(ctx, hash, key)
+	}
+	return "", nil
+}
+
+// HashMapGet records the call and returns the result of HashMapGetFunc (if set)
+func (m *Mock) HashMapGet(ctx context.Context, hashName string, keys ...interface{}) ([]string, error) {
+	m.record("HashMapGet", hashName, keys)
+	if m.HashMapGetFunc != nil {
+		return m.HashMapGetFunc(ctx, hashName, keys...)
+	}
+	return nil, nil
+}
+
+// HashMapSet records the call and returns the result of HashMapSetFunc (if set)
+func (m *Mock) HashMapSet(ctx context.Context, hashName string, pairs [][2]interface{}, dependencies ...string) error {
+	m.record("HashMapSet", hashName, pairs, dependencies)
+	if m.HashMapSetFunc != nil {
+		return m.HashMapSetFunc(ctx, hashName, pairs, dependencies...)
+	}
+	return nil
+}
+
+// HashMapSetExp records the call and returns the result of HashMapSetExpFunc (if set)
+func (m *Mock) HashMapSetExp(ctx context.Context, hashName string, pairs [][2]interface{},
+	ttl time.Duration, dependencies ...string) error {
+	m.record("HashMapSetExp", hashName, pairs, ttl, dependencies)
+	if m.HashMapSetExpFunc != nil {
+		return m.HashMapSetExpFunc(ctx, hashName, pairs, ttl, dependencies...)
+	}
+	return nil
+}
+
+// HashSet records the call and returns the result of HashSetFunc (if set)
+func (m *Mock) HashSet(ctx context.Context, hashName, hashKey string,
+	value interface{}, dependencies ...string) error {
+	m.record("HashSet", hashName, hashKey, value, dependencies)
+	if m.HashSetFunc != nil {
+		return m.HashSetFunc(ctx, hashName, hashKey, value, dependencies...)
+	}
+	return nil
+}
+
+// KillByDependency records the call and returns the result of KillByDependencyFunc (if set)
+func (m *Mock) KillByDependency(ctx context.Context, keys ...string) (int, error) {
+	m.record("KillByDependency", keys)
+	if m.KillByDependencyFunc != nil {
+		return m.KillByDependencyFunc(ctx, keys...)
+	}
+	return 0, nil
+}
+
+// Ping records the call and returns the result of PingFunc (if set)
+func (m *Mock) Ping(ctx context.Context) error {
+	m.record("Ping")
+	if m.PingFunc != nil {
+		return m.PingFunc(ctx)
+	}
+	return nil
+}
+
+// ReleaseLock records the call and returns the result of ReleaseLockFunc (if set)
+func (m *Mock) ReleaseLock(ctx context.Context, name, secret string) (bool, error) {
+	m.record("ReleaseLock", name, secret)
+	if m.ReleaseLockFunc != nil {
+		return m.ReleaseLockFunc(ctx, name, secret)
+	}
+	return false, nil
+}
+
+// Set records the call and returns the result of SetFunc (if set)
+func (m *Mock) Set(ctx context.Context, key string, value interface{}, dependencies ...string) error {
+	m.record("Set", key, value, dependencies)
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, key, value, dependencies...)
+	}
+	return nil
+}
+
+// SetAdd records the call and returns the result of SetAddFunc (if set)
+func (m *Mock) SetAdd(ctx context.Context, setName string, member interface{},
+	dependencies ...string) error {
+	m.record("SetAdd", setName, member, dependencies)
+	if m.SetAddFunc != nil {
+		return m.SetAddFunc(ctx, setName, member, dependencies...)
+	}
+	return nil
+}
+
+// SetAddMany records the call and returns the result of SetAddManyFunc (if set)
+func (m *Mock) SetAddMany(ctx context.Context, setName string, members ...interface{}) error {
+	m.record("SetAddMany", setName, members)
+	if m.SetAddManyFunc != nil {
+		return m.SetAddManyFunc(ctx, setName, members...)
+	}
+	return nil
+}
+
+// SetExp records the call and returns the result of SetExpFunc (if set)
+func (m *Mock) SetExp(ctx context.Context, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	m.record("SetExp", key, value, ttl, dependencies)
+	if m.SetExpFunc != nil {
+		return m.SetExpFunc(ctx, key, value, ttl, dependencies...)
+	}
+	return nil
+}
+
+// SetIsMember records the call and returns the result of SetIsMemberFunc (if set)
+func (m *Mock) SetIsMember(ctx context.Context, set string, member interface{}) (bool, error) {
+	m.record("SetIsMember", set, member)
+	if m.SetIsMemberFunc != nil {
+		return m.SetIsMemberFunc(ctx, set, member)
+	}
+	return false, nil
+}
+
+// SetList records the call and returns the result of SetListFunc (if set)
+func (m *Mock) SetList(ctx context.Context, key string, slice []string) error {
+	m.record("SetList", key, slice)
+	if m.SetListFunc != nil {
+		return m.SetListFunc(ctx, key, slice)
+	}
+	return nil
+}
+
+// SetMembers records the call and returns the result of SetMembersFunc (if set)
+func (m *Mock) SetMembers(ctx context.Context, set string) ([]string, error) {
+	m.record("SetMembers", set)
+	if m.SetMembersFunc != nil {
+		return m.SetMembersFunc(ctx, set)
+	}
+	return nil, nil
+}
+
+// SetRemoveMember records the call and returns the result of SetRemoveMemberFunc (if set)
+func (m *Mock) SetRemoveMember(ctx context.Context, set string, member interface{}) error {
+	m.record("SetRemoveMember", set, member)
+	if m.SetRemoveMemberFunc != nil {
+		return m.SetRemoveMemberFunc(ctx, set, member)
+	}
+	return nil
+}
+
+// SetToJSON records the call and returns the result of SetToJSONFunc (if set)
+func (m *Mock) SetToJSON(ctx context.Context, keyName string, modelData interface{},
+	ttl time.Duration, dependencies ...string) error {
+	m.record("SetToJSON", keyName, modelData, ttl, dependencies)
+	if m.SetToJSONFunc != nil {
+		return m.SetToJSONFunc(ctx, keyName, modelData, ttl, dependencies...)
+	}
+	return nil
+}
+
+// WriteLock records the call and returns the result of WriteLockFunc (if set)
+func (m *Mock) WriteLock(ctx context.Context, name, secret string, ttl int64) (bool, error) {
+	m.record("WriteLock", name, secret, ttl)
+	if m.WriteLockFunc != nil {
+		return m.WriteLockFunc(ctx, name, secret, ttl)
+	}
+	return false, nil
+}
@@ -0,0 +1,84 @@
+package cachemock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMock tests the mock
+func TestMock(t *testing.T) {
+
+	t.Run("zero values without a func", func(t *testing.T) {
+		m := New()
+
+		value, err := m.Get(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "", value)
+
+		var found bool
+		found, err = m.Exists(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		err = m.Set(context.Background(), "key", "value", "dependency")
+		assert.NoError(t, err)
+		m.Close()
+
+		assert.Equal(t, []Call{
+			{Args: []interface{}{"key"}, Method: "Get"},
+			{Args: []interface{}{"key"}, Method: "Exists"},
+			{Args: []interface{}{"key", "value", []string{"dependency"}}, Method: "Set"},
+			{Args: nil, Method: "Close"},
+		}, m.Calls())
+	})
+
+	t.Run("results from the funcs", func(t *testing.T) {
+		m := New()
+		m.GetFunc = func(ctx context.Context, key string) (string, error) {
+			if key == "missing" {
+				return "", redis.ErrNil
+			}
+			return "value", nil
+		}
+		m.SetExpFunc = func(ctx context.Context, key string, value interface{},
+			ttl time.Duration, dependencies ...string) error {
+			return errors.New("set failed")
+		}
+
+		value, err := m.Get(context.Background(), "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		_, err = m.Get(context.Background(), "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		err = m.SetExp(context.Background(), "key", "value", time.Minute)
+		assert.Error(t, err)
+
+		assert.Equal(t, 2, m.CallCount("Get"))
+		assert.Equal(t, 1, m.CallCount("SetExp"))
+		assert.Equal(t, 0, m.CallCount("Set"))
+
+		m.Reset()
+		assert.Empty(t, m.Calls())
+	})
+}
+
+// ExampleMock is an example of the mock
+func ExampleMock() {
+	m := New()
+	m.GetFunc = func(ctx context.Context, key string) (string, error) {
+		return "cached-value", nil
+	}
+
+	// Pass the mock to the code under test as a cache.Cacher
+	value, _ := m.Get(context.Background(), "key")
+	fmt.Printf("value: %s calls: %d", value, m.CallCount("Get"))
+	// Output:value: cached-value calls: 1
+}
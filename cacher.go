@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cacher is the cache API as an interface, so consumers can swap the implementation in
+// unit tests without redigomock plumbing or a live redis (see: the cachemock and cachetest packages)
+//
+// Implemented by the ShardedClient, and by NewCacher() for a single client
+type Cacher interface {
+	Close()
+	Delete(ctx context.Context, keys ...string) (int, error)
+	DeleteWithoutDependency(ctx context.Context, keys ...string) (int, error)
+	DestroyCache(ctx context.Context) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Expire(ctx context.Context, key string, duration time.Duration, options ...ExpireOption) error
+	Get(ctx context.Context, key string) (string, error)
+	GetAllKeys(ctx context.Context) ([]string, error)
+	GetBytes(ctx context.Context, key string) ([]byte, error)
+	GetList(ctx context.Context, key string) ([]string, error)
+	HashGet(ctx context.Context, hash, key string) (string, error)
+	HashMapGet(ctx context.Context, hashName string, keys ...interface{}) ([]string, error)
+	HashMapSet(ctx context.Context, hashName string, pairs [][2]interface{}, dependencies ...string) error
+	HashMapSetExp(ctx context.Context, hashName string, pairs [][2]interface{},
+		ttl time.Duration, dependencies ...string) error
+	HashSet(ctx context.Context, hashName, hashKey string, value interface{}, dependencies ...string) error
+	KillByDependency(ctx context.Context, keys ...string) (int, error)
+	Ping(ctx context.Context) error
+	ReleaseLock(ctx context.Context, name, secret string) (bool, error)
+	Set(ctx context.Context, key string, value interface{}, dependencies ...string) error
+	SetAdd(ctx context.Context, setName string, member interface{}, dependencies ...string) error
+	SetAddMany(ctx context.Context, setName string, members ...interface{}) error
+	SetExp(ctx context.Context, key string, value interface{}, ttl time.Duration, dependencies ...string) error
+	SetIsMember(ctx context.Context, set string, member interface{}) (bool, error)
+	SetList(ctx context.Context, key string, slice []string) error
+	SetMembers(ctx context.Context, set string) ([]string, error)
+	SetRemoveMember(ctx context.Context, set string, member interface{}) error
+	SetToJSON(ctx context.Context, keyName string, modelData interface{},
+		ttl time.Duration, dependencies ...string) error
+	WriteLock(ctx context.Context, name, secret string, ttl int64) (bool, error)
+}
+
+// Make sure the sharded client implements the interface
+var _ Cacher = (*ShardedClient)(nil)
+
+// clientCacher implements the Cacher interface for a single client
+type clientCacher struct {
+	client *Client
+}
+
+// NewCacher returns the Cacher interface for the client
+func NewCacher(client *Client) Cacher {
+	return &clientCacher{client: client}
+}
+
+// Close closes the client (see: Client.Close())
+func (c *clientCacher) Close() {
+	c.client.Close()
+}
+
+// Delete is an alias for KillByDependency()
+func (c *clientCacher) Delete(ctx context.Context, keys ...string) (int, error) {
+	return Delete(ctx, c.client, keys...)
+}
+
+// DeleteWithoutDependency will remove keys without using dependency script
+func (c *clientCacher) DeleteWithoutDependency(ctx context.Context, keys ...string) (int, error) {
+	return DeleteWithoutDependency(ctx, c.client, keys...)
+}
+
+// DestroyCache will flush the entire redis server
+func (c *clientCacher) DestroyCache(ctx context.Context) error {
+	return DestroyCache(ctx, c.client)
+}
+
+// Exists checks if a key is present or not
+func (c *clientCacher) Exists(ctx context.Context, key string) (bool, error) {
+	return Exists(ctx, c.client, key)
+}
+
+// Expire sets the expiration for a given key
+func (c *clientCacher) Expire(ctx context.Context, key string, duration time.Duration,
+	options ...ExpireOption) error {
+	return Expire(ctx, c.client, key, duration, options...)
+}
+
+// Get gets a key from redis in string format
+func (c *clientCacher) Get(ctx context.Context, key string) (string, error) {
+	return Get(ctx, c.client, key)
+}
+
+// GetAllKeys returns a []string of keys
+func (c *clientCacher) GetAllKeys(ctx context.Context) ([]string, error) {
+	return GetAllKeys(ctx, c.client)
+}
+
+// GetBytes gets a key from redis formatted in bytes
+func (c *clientCacher) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	return GetBytes(ctx, c.client, key)
+}
+
+// GetList returns a []string stored in redis list
+func (c *clientCacher) GetList(ctx context.Context, key string) ([]string, error) {
+	return GetList(ctx, c.client, key)
+}
+
+// HashGet gets a key from redis via hash
+func (c *clientCacher) HashGet(ctx context.Context, hash, key string) (string, error) {
+	return HashGet(ctx, c.client, hash, key)
+}
+
+// HashMapGet gets values from a hash map for corresponding keys
+func (c *clientCacher) HashMapGet(ctx context.Context, hashName string, keys ...interface{}) ([]string, error) {
+	return HashMapGet(ctx, c.client, hashName, keys...)
+}
+
+// HashMapSet will set the hashKey to the value in the specified hashName
+func (c *clientCacher) HashMapSet(ctx context.Context, hashName string,
+	pairs [][2]interface{}, dependencies ...string) error {
+	return HashMapSet(ctx, c.client, hashName, pairs, dependencies...)
+}
+
+// HashMapSetExp will set the hashKey to the value in the specified hashName with an expiration
+func (c *clientCacher) HashMapSetExp(ctx context.Context, hashName string, pairs [][2]interface{},
+	ttl time.Duration, dependencies ...string) error {
+	return HashMapSetExp(ctx, c.client, hashName, pairs, ttl, dependencies...)
+}
+
+// HashSet will set the hashKey to the value in the specified hashName
+func (c *clientCacher) HashSet(ctx context.Context, hashName, hashKey string,
+	value interface{}, dependencies ...string) error {
+	return HashSet(ctx, c.client, hashName, hashKey, value, dependencies...)
+}
+
+// KillByDependency removes all keys which are listed as depending on the key(s)
+func (c *clientCacher) KillByDependency(ctx context.Context, keys ...string) (int, error) {
+	return KillByDependency(ctx, c.client, keys...)
+}
+
+// Ping will do a ping command
+func (c *clientCacher) Ping(ctx context.Context) error {
+	return Ping(ctx, c.client)
+}
+
+// ReleaseLock releases the redis lock
+func (c *clientCacher) ReleaseLock(ctx context.Context, name, secret string) (bool, error) {
+	return ReleaseLock(ctx, c.client, name, secret)
+}
+
+// Set will set the key in redis and keep a reference to each dependency
+func (c *clientCacher) Set(ctx context.Context, key string, value interface{}, dependencies ...string) error {
+	return Set(ctx, c.client, key, value, dependencies...)
+}
+
+// SetAdd will add the member to the Set and link a reference to each dependency for the entire Set
+func (c *clientCacher) SetAdd(ctx context.Context, setName string, member interface{},
+	dependencies ...string) error {
+	return SetAdd(ctx, c.client, setName, member, dependencies...)
+}
+
+// SetAddMany will add many values to an existing set
+func (c *clientCacher) SetAddMany(ctx context.Context, setName string, members ...interface{}) error {
+	return SetAddMany(ctx, c.client, setName, members...)
+}
+
+// SetExp will set the key in redis and keep a reference to each dependency
+func (c *clientCacher) SetExp(ctx context.Context, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	return SetExp(ctx, c.client, key, value, ttl, dependencies...)
+}
+
+// SetIsMember returns if the member is part of the set
+func (c *clientCacher) SetIsMember(ctx context.Context, set string, member interface{}) (bool, error) {
+	return SetIsMember(ctx, c.client, set, member)
+}
+
+// SetList saves a slice as a redis list
+func (c *clientCacher) SetList(ctx context.Context, key string, slice []string) error {
+	return SetList(ctx, c.client, key, slice)
+}
+
+// SetMembers will fetch all members in the list
+func (c *clientCacher) SetMembers(ctx context.Context, set string) ([]string, error) {
+	return SetMembers(ctx, c.client, set)
+}
+
+// SetRemoveMember removes the member from the set
+func (c *clientCacher) SetRemoveMember(ctx context.Context, set string, member interface{}) error {
+	return SetRemoveMember(ctx, c.client, set, member)
+}
+
+// SetToJSON converts the interface to JSON and sets it in redis
+func (c *clientCacher) SetToJSON(ctx context.Context, keyName string, modelData interface{},
+	ttl time.Duration, dependencies ...string) error {
+	return SetToJSON(ctx, c.client, keyName, modelData, ttl, dependencies...)
+}
+
+// WriteLock attempts to grab a redis lock
+func (c *clientCacher) WriteLock(ctx context.Context, name, secret string, ttl int64) (bool, error) {
+	return WriteLock(ctx, c.client, name, secret, ttl)
+}
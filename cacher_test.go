@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewCacher tests the method NewCacher()
+func TestNewCacher(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	setCmd := conn.Command(SetCommand, testKey, testStringValue)
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+	conn.Command(ExistsCommand, testKey).Expect(int64(1))
+	conn.Command(HashGetCommand, testHashName, testKey).Expect(testStringValue)
+
+	var cacher Cacher = NewCacher(client)
+
+	err := cacher.Set(context.Background(), testKey, testStringValue)
+	assert.NoError(t, err)
+	assert.True(t, setCmd.Called)
+
+	var value string
+	value, err = cacher.Get(context.Background(), testKey)
+	assert.NoError(t, err)
+	assert.Equal(t, testStringValue, value)
+
+	var found bool
+	found, err = cacher.Exists(context.Background(), testKey)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	value, err = cacher.HashGet(context.Background(), testHashName, testKey)
+	assert.NoError(t, err)
+	assert.Equal(t, testStringValue, value)
+}
+
+// ExampleNewCacher is an example of the method NewCacher()
+func ExampleNewCacher() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the get command
+	conn.Command(GetCommand, "key").Expect("value")
+
+	// Use the interface (swap for cachemock.Mock in unit tests)
+	cacher := NewCacher(client)
+	value, _ := cacher.Get(context.Background(), "key")
+	fmt.Printf("value: %s", value)
+	// Output:value: value
+}
@@ -0,0 +1,343 @@
+package cachetest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mrz1836/go-cache"
+)
+
+// Make sure the chaos wrapper implements the interface
+var _ cache.Cacher = (*Chaos)(nil)
+
+// ErrChaos is the default error injected by the chaos wrapper
+var ErrChaos = errors.New("cachetest: injected failure")
+
+// invalidations are the operations that can be dropped (see: ChaosConfig.DropRate)
+var invalidations = map[string]bool{
+	"Delete":                  true,
+	"DeleteWithoutDependency": true,
+	"DestroyCache":            true,
+	"KillByDependency":        true,
+}
+
+// ChaosConfig is the configuration for the faults injected by NewChaos()
+//
+// Rates are probabilities between 0 and 1, checked in order: timeout, error, dropped invalidation
+type ChaosConfig struct {
+	DropRate    float64       // Rate of invalidations (IE: Delete) that report success without running
+	Error       error         // Error to inject (default: ErrChaos)
+	ErrorRate   float64       // Rate of operations that fail with the error
+	Latency     time.Duration // Latency added to every operation
+	Jitter      time.Duration // Random latency added on top of the latency (up to the duration)
+	Operations  []string      // Names of the operations to inject into (IE: Get, SetExp), all if empty
+	Seed        int64         // Seed for the random faults (0 uses the current time)
+	TimeoutRate float64       // Rate of operations that block until the context is done
+}
+
+// Chaos wraps any cache.Cacher and injects faults for resilience testing
+type Chaos struct {
+	config     ChaosConfig
+	mu         sync.Mutex
+	next       cache.Cacher
+	operations map[string]bool
+	random     *rand.Rand
+}
+
+// NewChaos returns the cacher wrapped with the faults of the config
+func NewChaos(next cache.Cacher, config ChaosConfig) *Chaos {
+	c := &Chaos{next: next}
+	c.SetConfig(config)
+	return c
+}
+
+// SetConfig replaces the faults at runtime (IE: start failing halfway through a test)
+func (c *Chaos) SetConfig(config ChaosConfig) {
+	if config.Error == nil {
+		config.Error = ErrChaos
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	operations := make(map[string]bool, len(config.Operations))
+	for _, operation := range config.Operations {
+		operations[operation] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config = config
+	c.operations = operations
+	c.random = rand.New(rand.NewSource(seed))
+}
+
+// Close closes the wrapped cacher (faults are never injected)
+func (c *Chaos) Close() {
+	c.next.Close()
+}
+
+// Delete is an alias for KillByDependency()
+func (c *Chaos) Delete(ctx context.Context, keys ...string) (int, error) {
+	if dropped, err := c.inject(ctx, "Delete"); dropped || err != nil {
+		return 0, err
+	}
+	return c.next.Delete(ctx, keys...)
+}
+
+// DeleteWithoutDependency will remove keys without using dependency script
+func (c *Chaos) DeleteWithoutDependency(ctx context.Context, keys ...string) (int, error) {
+	if dropped, err := c.inject(ctx, "DeleteWithoutDependency"); dropped || err != nil {
+		return 0, err
+	}
+	return c.next.DeleteWithoutDependency(ctx, keys...)
+}
+
+// DestroyCache will flush the entire cache
+func (c *Chaos) DestroyCache(ctx context.Context) error {
+	if dropped, err := c.inject(ctx, "DestroyCache"); dropped || err != nil {
+		return err
+	}
+	return c.next.DestroyCache(ctx)
+}
+
+// Exists checks if a key is present or not
+func (c *Chaos) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := c.inject(ctx, "Exists"); err != nil {
+		return false, err
+	}
+	return c.next.Exists(ctx, key)
+}
+
+// Expire sets the expiration for a given key
+func (c *Chaos) Expire(ctx context.Context, key string, duration time.Duration, options ...cache.ExpireOption) error {
+	if _, err := c.inject(ctx, "Expire"); err != nil {
+		return err
+	}
+	return c.next.Expire(ctx, key, duration, options...)
+}
+
+// Get gets a key in string format
+func (c *Chaos) Get(ctx context.Context, key string) (string, error) {
+	if _, err := c.inject(ctx, "Get"); err != nil {
+		return "", err
+	}
+	return c.next.Get(ctx, key)
+}
+
+// GetAllKeys returns a []string of keys
+func (c *Chaos) GetAllKeys(ctx context.Context) ([]string, error) {
+	if _, err := c.inject(ctx, "GetAllKeys"); err != nil {
+		return nil, err
+	}
+	return c.next.GetAllKeys(ctx)
+}
+
+// GetBytes gets a key formatted in bytes
+func (c *Chaos) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	if _, err := c.inject(ctx, "GetBytes"); err != nil {
+		return nil, err
+	}
+	return c.next.GetBytes(ctx, key)
+}
+
+// GetList returns a []string stored in a list
+func (c *Chaos) GetList(ctx context.Context, key string) ([]string, error) {
+	if _, err := c.inject(ctx, "GetList"); err != nil {
+		return nil, err
+	}
+	return c.next.GetList(ctx, key)
+}
+
+// HashGet gets a key via hash
+func (c *Chaos) HashGet(ctx context.Context, hash, key string) (string, error) {
+	if _, err := c.inject(ctx, "HashGet"); err != nil {
+		return "", err
+	}
+	return c.next.HashGet(ctx, hash, key)
+}
+
+// HashMapGet gets values from a hash map for corresponding keys
+func (c *Chaos) HashMapGet(ctx context.Context, hashName string, keys ...interface{}) ([]string, error) {
+	if _, err := c.inject(ctx, "HashMapGet"); err != nil {
+		return nil, err
+	}
+	return c.next.HashMapGet(ctx, hashName, keys...)
+}
+
+// HashMapSet will set the pairs in the hash and link a reference to each dependency
+func (c *Chaos) HashMapSet(ctx context.Context, hashName string, pairs [][2]interface{},
+	dependencies ...string) error {
+	if _, err := c.inject(ctx, "HashMapSet"); err != nil {
+		return err
+	}
+	return c.next.HashMapSet(ctx, hashName, pairs, dependencies...)
+}
+
+// HashMapSetExp will set the pairs in the hash with an expiration and link a reference to each dependency
+func (c *Chaos) HashMapSetExp(ctx context.Context, hashName string, pairs [][2]interface{},
+	ttl time.Duration, dependencies ...string) error {
+	if _, err := c.inject(ctx, "HashMapSetExp"); err != nil {
+		return err
+	}
+	return c.next.HashMapSetExp(ctx, hashName, pairs, ttl, dependencies...)
+}
+
+// HashSet will set the hashKey to the value in the hash and link a reference to each dependency
+func (c *Chaos) HashSet(ctx context.Context, hashName, hashKey string, value interface{},
+	dependencies ...string) error {
+	if _, err := c.inject(ctx, "HashSet"); err != nil {
+		return err
+	}
+	return c.next.HashSet(ctx, hashName, hashKey, value, dependencies...)
+}
+
+// KillByDependency removes all keys which are listed as depending on the key(s)
+func (c *Chaos) KillByDependency(ctx context.Context, keys ...string) (int, error) {
+	if dropped, err := c.inject(ctx, "KillByDependency"); dropped || err != nil {
+		return 0, err
+	}
+	return c.next.KillByDependency(ctx, keys...)
+}
+
+// Ping is a basic Ping->Pong method to determine connection
+func (c *Chaos) Ping(ctx context.Context) error {
+	if _, err := c.inject(ctx, "Ping"); err != nil {
+		return err
+	}
+	return c.next.Ping(ctx)
+}
+
+// ReleaseLock releases the lock
+func (c *Chaos) ReleaseLock(ctx context.Context, name, secret string) (bool, error) {
+	if _, err := c.inject(ctx, "ReleaseLock"); err != nil {
+		return false, err
+	}
+	return c.next.ReleaseLock(ctx, name, secret)
+}
+
+// Set will set the key and keep a reference to each dependency
+func (c *Chaos) Set(ctx context.Context, key string, value interface{}, dependencies ...string) error {
+	if _, err := c.inject(ctx, "Set"); err != nil {
+		return err
+	}
+	return c.next.Set(ctx, key, value, dependencies...)
+}
+
+// SetAdd will add the member to the set and link a reference to each dependency
+func (c *Chaos) SetAdd(ctx context.Context, setName string, member interface{}, dependencies ...string) error {
+	if _, err := c.inject(ctx, "SetAdd"); err != nil {
+		return err
+	}
+	return c.next.SetAdd(ctx, setName, member, dependencies...)
+}
+
+// SetAddMany will add many values to a set
+func (c *Chaos) SetAddMany(ctx context.Context, setName string, members ...interface{}) error {
+	if _, err := c.inject(ctx, "SetAddMany"); err != nil {
+		return err
+	}
+	return c.next.SetAddMany(ctx, setName, members...)
+}
+
+// SetExp will set the key with an expiration and keep a reference to each dependency
+func (c *Chaos) SetExp(ctx context.Context, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	if _, err := c.inject(ctx, "SetExp"); err != nil {
+		return err
+	}
+	return c.next.SetExp(ctx, key, value, ttl, dependencies...)
+}
+
+// SetIsMember returns if the member is part of the set
+func (c *Chaos) SetIsMember(ctx context.Context, set string, member interface{}) (bool, error) {
+	if _, err := c.inject(ctx, "SetIsMember"); err != nil {
+		return false, err
+	}
+	return c.next.SetIsMember(ctx, set, member)
+}
+
+// SetList saves a slice as a list (appends)
+func (c *Chaos) SetList(ctx context.Context, key string, slice []string) error {
+	if _, err := c.inject(ctx, "SetList"); err != nil {
+		return err
+	}
+	return c.next.SetList(ctx, key, slice)
+}
+
+// SetMembers will fetch all members in the set
+func (c *Chaos) SetMembers(ctx context.Context, set string) ([]string, error) {
+	if _, err := c.inject(ctx, "SetMembers"); err != nil {
+		return nil, err
+	}
+	return c.next.SetMembers(ctx, set)
+}
+
+// SetRemoveMember removes the member from the set
+func (c *Chaos) SetRemoveMember(ctx context.Context, set string, member interface{}) error {
+	if _, err := c.inject(ctx, "SetRemoveMember"); err != nil {
+		return err
+	}
+	return c.next.SetRemoveMember(ctx, set, member)
+}
+
+// SetToJSON stores the struct data (Struct->JSON) under a key
+func (c *Chaos) SetToJSON(ctx context.Context, keyName string, modelData interface{},
+	ttl time.Duration, dependencies ...string) error {
+	if _, err := c.inject(ctx, "SetToJSON"); err != nil {
+		return err
+	}
+	return c.next.SetToJSON(ctx, keyName, modelData, ttl, dependencies...)
+}
+
+// WriteLock attempts to grab the lock
+func (c *Chaos) WriteLock(ctx context.Context, name, secret string, ttl int64) (bool, error) {
+	if _, err := c.inject(ctx, "WriteLock"); err != nil {
+		return false, err
+	}
+	return c.next.WriteLock(ctx, name, secret, ttl)
+}
+
+// inject applies the faults to the operation
+// Returns true if the operation is an invalidation that should be dropped
+func (c *Chaos) inject(ctx context.Context, operation string) (dropped bool, err error) {
+	c.mu.Lock()
+	config := c.config
+	if len(c.operations) > 0 && !c.operations[operation] {
+		c.mu.Unlock()
+		return false, nil
+	}
+	latency := config.Latency
+	if config.Jitter > 0 {
+		latency += time.Duration(c.random.Int63n(int64(config.Jitter) + 1))
+	}
+	timeout := c.random.Float64() < config.TimeoutRate
+	fail := c.random.Float64() < config.ErrorRate
+	dropped = invalidations[operation] && c.random.Float64() < config.DropRate
+	c.mu.Unlock()
+
+	// Latency (cut short by the context)
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if timeout {
+		if _, ok := ctx.Deadline(); !ok {
+			return false, context.DeadlineExceeded
+		}
+		<-ctx.Done()
+		return false, ctx.Err()
+	} else if fail {
+		return false, config.Error
+	}
+	return dropped, nil
+}
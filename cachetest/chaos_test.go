@@ -0,0 +1,141 @@
+package cachetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestChaos tests the chaos wrapper
+func TestChaos(t *testing.T) {
+
+	t.Run("no faults", func(t *testing.T) {
+		ctx := context.Background()
+		c := NewChaos(New(), ChaosConfig{})
+
+		err := c.Set(ctx, "key", "value")
+		assert.NoError(t, err)
+
+		var value string
+		value, err = c.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ctx := context.Background()
+		c := NewChaos(New(), ChaosConfig{ErrorRate: 1})
+
+		err := c.Set(ctx, "key", "value")
+		assert.ErrorIs(t, err, ErrChaos)
+
+		// Custom error
+		custom := errors.New("connection reset")
+		c.SetConfig(ChaosConfig{Error: custom, ErrorRate: 1})
+		_, err = c.Get(ctx, "key")
+		assert.ErrorIs(t, err, custom)
+	})
+
+	t.Run("only the listed operations", func(t *testing.T) {
+		ctx := context.Background()
+		c := NewChaos(New(), ChaosConfig{ErrorRate: 1, Operations: []string{"Get"}})
+
+		err := c.Set(ctx, "key", "value")
+		assert.NoError(t, err)
+
+		_, err = c.Get(ctx, "key")
+		assert.ErrorIs(t, err, ErrChaos)
+
+		var found bool
+		found, err = c.Exists(ctx, "key")
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("random errors are seeded", func(t *testing.T) {
+		ctx := context.Background()
+		run := func() (failures []bool) {
+			c := NewChaos(New(), ChaosConfig{ErrorRate: 0.5, Seed: 42})
+			for i := 0; i < 20; i++ {
+				failures = append(failures, c.Ping(ctx) != nil)
+			}
+			return
+		}
+		failures := run()
+		assert.Equal(t, failures, run())
+		assert.Contains(t, failures, true)
+		assert.Contains(t, failures, false)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		c := NewChaos(New(), ChaosConfig{Latency: 20 * time.Millisecond})
+
+		start := time.Now()
+		err := c.Ping(context.Background())
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+		// Cut short by the context
+		c.SetConfig(ChaosConfig{Latency: time.Minute})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err = c.Ping(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("timeouts", func(t *testing.T) {
+		c := NewChaos(New(), ChaosConfig{TimeoutRate: 1})
+
+		err := c.Ping(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err = c.Ping(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	})
+
+	t.Run("dropped invalidations", func(t *testing.T) {
+		ctx := context.Background()
+		c := NewChaos(New(), ChaosConfig{DropRate: 1})
+
+		err := c.Set(ctx, "key", "value", "dependency")
+		assert.NoError(t, err)
+
+		var total int
+		total, err = c.Delete(ctx, "dependency")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+
+		// Still cached
+		var value string
+		value, err = c.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		c.SetConfig(ChaosConfig{})
+		_, err = c.Delete(ctx, "dependency")
+		assert.NoError(t, err)
+		_, err = c.Get(ctx, "key")
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+}
+
+// ExampleNewChaos is an example of the method NewChaos()
+func ExampleNewChaos() {
+	// Every read fails
+	c := NewChaos(New(), ChaosConfig{ErrorRate: 1, Operations: []string{"Get"}})
+	defer c.Close()
+
+	_ = c.Set(context.Background(), "key", "value")
+	_, err := c.Get(context.Background(), "key")
+	fmt.Printf("error: %v", err)
+	// Output:error: cachetest: injected failure
+}
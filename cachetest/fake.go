@@ -0,0 +1,565 @@
+// Package cachetest is a pure-Go in-memory implementation of the cache.Cacher interface
+// (strings, hashes, sets, lists, expirations and dependencies) for running test suites of
+// services built on go-cache without a live redis, docker or miniredis
+//
+// NewChaos() wraps any cache.Cacher with injected faults for resilience testing
+package cachetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache"
+)
+
+// Make sure the fake implements the interface
+var _ cache.Cacher = (*Fake)(nil)
+
+// ErrWrongType is returned when the key holds a different kind of value (same as redis)
+var ErrWrongType = redis.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+// kind is the type of value stored under a key
+type kind int
+
+// Kinds of values
+const (
+	kindString kind = iota
+	kindHash
+	kindSet
+	kindList
+)
+
+// entry is a single key
+type entry struct {
+	expires time.Time
+	hash    map[string]string
+	kind    kind
+	list    []string
+	set     map[string]struct{}
+	value   string
+}
+
+// Fake is an in-memory cache.Cacher, safe for concurrent use
+//
+// Expirations use the clock of the fake moved forward by Advance(), so tests never sleep
+type Fake struct {
+	clock  cache.Clock
+	data   map[string]*entry
+	mu     sync.Mutex
+	offset time.Duration
+}
+
+// New returns a new empty fake using the system clock
+func New() *Fake {
+	return NewWithClock(cache.SystemClock)
+}
+
+// NewWithClock returns a new empty fake using the clock (IE: the cache.ManualClock shared with a client)
+func NewWithClock(clock cache.Clock) *Fake {
+	return &Fake{clock: clock, data: make(map[string]*entry)}
+}
+
+// Advance moves the clock of the fake forward, expiring keys with a ttl shorter than the duration
+func (f *Fake) Advance(duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.offset += duration
+}
+
+// TTL returns the time left before the key expires (false if the key does not exist or has no expiration)
+func (f *Fake) TTL(key string) (time.Duration, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e := f.lookup(key)
+	if e == nil || e.expires.IsZero() {
+		return 0, false
+	}
+	return e.expires.Sub(f.now()), true
+}
+
+// Close does nothing (there are no connections)
+func (f *Fake) Close() {}
+
+// Delete is an alias for KillByDependency()
+func (f *Fake) Delete(ctx context.Context, keys ...string) (int, error) {
+	return f.KillByDependency(ctx, keys...)
+}
+
+// DeleteWithoutDependency will remove keys without removing their dependencies
+// Returns the number of keys removed (same as cache.DeleteWithoutDependency())
+func (f *Fake) DeleteWithoutDependency(_ context.Context, keys ...string) (total int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		total += f.remove(key)
+	}
+	return total, nil
+}
+
+// DestroyCache removes all keys
+func (f *Fake) DestroyCache(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data = make(map[string]*entry)
+	return nil
+}
+
+// Exists checks if a key is present or not
+func (f *Fake) Exists(_ context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lookup(key) != nil, nil
+}
+
+// Expire sets the expiration for a given key, a duration of zero or less removes the key
+// Options only set the expiration under a condition (IE: cache.ExpireIfGreater())
+func (f *Fake) Expire(_ context.Context, key string, duration time.Duration, options ...cache.ExpireOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e := f.lookup(key)
+	if e == nil {
+		return nil
+	}
+
+	// Check the conditions (no expiration is an infinite expiration)
+	expires := f.now().Add(duration)
+	for _, condition := range cache.ExpireConditions(options...) {
+		switch condition {
+		case cache.ExpireIfNoneArgument:
+			if !e.expires.IsZero() {
+				return nil
+			}
+		case cache.ExpireIfExistsArgument:
+			if e.expires.IsZero() {
+				return nil
+			}
+		case cache.ExpireIfGreaterArgument:
+			if e.expires.IsZero() || !expires.After(e.expires) {
+				return nil
+			}
+		case cache.ExpireIfLessArgument:
+			if !e.expires.IsZero() && !expires.Before(e.expires) {
+				return nil
+			}
+		}
+	}
+
+	if duration <= 0 {
+		delete(f.data, key)
+		return nil
+	}
+	e.expires = expires
+	return nil
+}
+
+// Get gets a key in string format (redis.ErrNil if the key does not exist)
+func (f *Fake) Get(_ context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(key, kindString)
+	if err != nil {
+		return "", err
+	} else if e == nil {
+		return "", redis.ErrNil
+	}
+	return e.value, nil
+}
+
+// GetAllKeys returns all the keys (sorted), including the dependency sets
+func (f *Fake) GetAllKeys(_ context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	keys := make([]string, 0, len(f.data))
+	for key := range f.data {
+		if f.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// GetBytes gets a key formatted in bytes (redis.ErrNil if the key does not exist)
+func (f *Fake) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	value, err := f.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
+
+// GetList returns the list stored under the key
+func (f *Fake) GetList(_ context.Context, key string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(key, kindList)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	return append([]string{}, e.list...), nil
+}
+
+// HashGet gets a key via hash (redis.ErrNil if the hash or key does not exist)
+func (f *Fake) HashGet(_ context.Context, hash, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(hash, kindHash)
+	if err != nil {
+		return "", err
+	} else if e == nil {
+		return "", redis.ErrNil
+	}
+	value, ok := e.hash[key]
+	if !ok {
+		return "", redis.ErrNil
+	}
+	return value, nil
+}
+
+// HashMapGet gets values from a hash map for corresponding keys (missing keys are empty)
+func (f *Fake) HashMapGet(_ context.Context, hashName string, keys ...interface{}) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(hashName, kindHash)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, len(keys))
+	if e != nil {
+		for i, key := range keys {
+			values[i] = e.hash[toString(key)]
+		}
+	}
+	return values, nil
+}
+
+// HashMapSet will set the pairs in the hash and link a reference to each dependency for the entire hash
+func (f *Fake) HashMapSet(_ context.Context, hashName string, pairs [][2]interface{}, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hashMapSet(hashName, pairs, 0, dependencies...)
+}
+
+// HashMapSetExp will set the pairs in the hash with an expiration and link a reference to
+// each dependency for the entire hash (a ttl of zero or less removes the hash, same as redis)
+func (f *Fake) HashMapSetExp(_ context.Context, hashName string, pairs [][2]interface{},
+	ttl time.Duration, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.hashMapSet(hashName, pairs, ttl, dependencies...); err != nil {
+		return err
+	} else if ttl <= 0 {
+		delete(f.data, hashName)
+	}
+	return nil
+}
+
+// HashSet will set the hashKey to the value in the hash and link a reference to each dependency
+func (f *Fake) HashSet(_ context.Context, hashName, hashKey string, value interface{}, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hashMapSet(hashName, [][2]interface{}{{hashKey, value}}, 0, dependencies...)
+}
+
+// KillByDependency removes the keys and all keys which are listed as depending on the key(s)
+// Returns the number of keys removed (including the dependency sets)
+func (f *Fake) KillByDependency(_ context.Context, keys ...string) (total int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, key := range keys {
+		dependency := cache.DependencyPrefix + key
+		var e *entry
+		if e, err = f.get(dependency, kindSet); err != nil {
+			return 0, err
+		} else if e == nil {
+			continue
+		}
+		for member := range e.set {
+			total += f.remove(member)
+		}
+		total += f.remove(dependency)
+	}
+	for _, key := range keys {
+		total += f.remove(key)
+	}
+	return total, nil
+}
+
+// Ping always succeeds
+func (f *Fake) Ping(_ context.Context) error {
+	return nil
+}
+
+// ReleaseLock releases the lock if the secret matches (cache.ErrLockMismatch if locked by someone else)
+func (f *Fake) ReleaseLock(_ context.Context, name, secret string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(name, kindString)
+	if err != nil {
+		return false, err
+	} else if e == nil {
+		return true, nil
+	} else if e.value != secret {
+		return false, cache.ErrLockMismatch
+	}
+	delete(f.data, name)
+	return true, nil
+}
+
+// Set will set the key and keep a reference to each dependency
+func (f *Fake) Set(_ context.Context, key string, value interface{}, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = &entry{kind: kindString, value: toString(value)}
+	return f.link(key, dependencies...)
+}
+
+// SetAdd will add the member to the set and link a reference to each dependency for the entire set
+func (f *Fake) SetAdd(_ context.Context, setName string, member interface{}, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.setAdd(setName, member); err != nil {
+		return err
+	}
+	return f.link(setName, dependencies...)
+}
+
+// SetAddMany will add many values to a set
+func (f *Fake) SetAddMany(_ context.Context, setName string, members ...interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(members) == 0 {
+		return errWrongArguments("sadd")
+	}
+	return f.setAdd(setName, members...)
+}
+
+// SetExp will set the key with an expiration and keep a reference to each dependency
+func (f *Fake) SetExp(_ context.Context, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ttl <= 0 {
+		return errInvalidExpire("setex")
+	}
+	f.data[key] = &entry{expires: f.now().Add(ttl), kind: kindString, value: toString(value)}
+	return f.link(key, dependencies...)
+}
+
+// SetIsMember returns if the member is part of the set
+func (f *Fake) SetIsMember(_ context.Context, set string, member interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(set, kindSet)
+	if err != nil || e == nil {
+		return false, err
+	}
+	_, ok := e.set[toString(member)]
+	return ok, nil
+}
+
+// SetList saves a slice as a list (appends)
+func (f *Fake) SetList(_ context.Context, key string, slice []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(slice) == 0 {
+		return errWrongArguments("rpush")
+	}
+	e, err := f.get(key, kindList)
+	if err != nil {
+		return err
+	} else if e == nil {
+		e = &entry{kind: kindList}
+		f.data[key] = e
+	}
+	e.list = append(e.list, slice...)
+	return nil
+}
+
+// SetMembers will fetch all members in the set (sorted)
+func (f *Fake) SetMembers(_ context.Context, set string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(set, kindSet)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]string, 0)
+	if e != nil {
+		for member := range e.set {
+			members = append(members, member)
+		}
+		sort.Strings(members)
+	}
+	return members, nil
+}
+
+// SetRemoveMember removes the member from the set (an empty set is removed)
+func (f *Fake) SetRemoveMember(_ context.Context, set string, member interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e, err := f.get(set, kindSet)
+	if err != nil || e == nil {
+		return err
+	}
+	delete(e.set, toString(member))
+	if len(e.set) == 0 {
+		delete(f.data, set)
+	}
+	return nil
+}
+
+// SetToJSON stores the struct data (Struct->JSON) under a key
+func (f *Fake) SetToJSON(ctx context.Context, keyName string, modelData interface{},
+	ttl time.Duration, dependencies ...string) error {
+	responseBytes, err := json.Marshal(&modelData)
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return f.SetExp(ctx, keyName, string(responseBytes), ttl, dependencies...)
+	}
+	return f.Set(ctx, keyName, string(responseBytes), dependencies...)
+}
+
+// WriteLock attempts to grab the lock for the ttl in seconds
+// Returns cache.ErrLockMismatch if the lock is held with a different secret
+func (f *Fake) WriteLock(_ context.Context, name, secret string, ttl int64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ttl <= 0 {
+		return false, errInvalidExpire("set")
+	}
+	e, err := f.get(name, kindString)
+	if err != nil {
+		return false, err
+	} else if e != nil && e.value != secret {
+		return false, cache.ErrLockMismatch
+	}
+	f.data[name] = &entry{expires: f.now().Add(time.Duration(ttl) * time.Second), kind: kindString, value: secret}
+	return true, nil
+}
+
+// now returns the time of the fake clock
+func (f *Fake) now() time.Time {
+	return f.clock.Now().Add(f.offset)
+}
+
+// lookup returns the entry for the key (nil if it does not exist or expired)
+func (f *Fake) lookup(key string) *entry {
+	e, ok := f.data[key]
+	if !ok {
+		return nil
+	} else if !e.expires.IsZero() && !f.now().Before(e.expires) {
+		delete(f.data, key)
+		return nil
+	}
+	return e
+}
+
+// get returns the entry for the key if it holds the kind of value (nil if it does not exist)
+func (f *Fake) get(key string, k kind) (*entry, error) {
+	e := f.lookup(key)
+	if e != nil && e.kind != k {
+		return nil, ErrWrongType
+	}
+	return e, nil
+}
+
+// remove removes the key and returns the number of keys removed
+func (f *Fake) remove(key string) int {
+	if f.lookup(key) == nil {
+		return 0
+	}
+	delete(f.data, key)
+	return 1
+}
+
+// hashMapSet sets the pairs in the hash, the expiration (if set) and links the dependencies
+func (f *Fake) hashMapSet(hashName string, pairs [][2]interface{}, ttl time.Duration, dependencies ...string) error {
+	if len(pairs) == 0 {
+		return errWrongArguments("hset")
+	}
+	e, err := f.get(hashName, kindHash)
+	if err != nil {
+		return err
+	} else if e == nil {
+		e = &entry{hash: make(map[string]string), kind: kindHash}
+		f.data[hashName] = e
+	}
+	for _, pair := range pairs {
+		e.hash[toString(pair[0])] = toString(pair[1])
+	}
+	if ttl > 0 {
+		e.expires = f.now().Add(ttl)
+	}
+	return f.link(hashName, dependencies...)
+}
+
+// setAdd adds the members to the set
+func (f *Fake) setAdd(setName string, members ...interface{}) error {
+	e, err := f.get(setName, kindSet)
+	if err != nil {
+		return err
+	} else if e == nil {
+		e = &entry{kind: kindSet, set: make(map[string]struct{})}
+		f.data[setName] = e
+	}
+	for _, member := range members {
+		e.set[toString(member)] = struct{}{}
+	}
+	return nil
+}
+
+// link adds the key to the set of each dependency (same as the cache package)
+func (f *Fake) link(key string, dependencies ...string) error {
+	for _, dependency := range dependencies {
+		if err := f.setAdd(cache.DependencyPrefix+dependency, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toString formats the value the same way redigo sends arguments to redis
+func toString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case nil:
+		return ""
+	case redis.Argument:
+		return toString(v.RedisArg())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// errInvalidExpire returns the redis error for an invalid expiration
+func errInvalidExpire(command string) error {
+	return redis.Error(fmt.Sprintf("ERR invalid expire time in '%s' command", command))
+}
+
+// errWrongArguments returns the redis error for missing arguments
+func errWrongArguments(command string) error {
+	return redis.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", command))
+}
@@ -0,0 +1,282 @@
+package cachetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFake tests the fake
+func TestFake(t *testing.T) {
+
+	t.Run("strings", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		_, err := f.Get(ctx, "key")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		err = f.Set(ctx, "key", 123)
+		assert.NoError(t, err)
+
+		var value string
+		value, err = f.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "123", value)
+
+		var data []byte
+		data, err = f.GetBytes(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("123"), data)
+
+		err = f.SetToJSON(ctx, "json", map[string]string{"name": "go-cache"}, 0)
+		assert.NoError(t, err)
+		value, err = f.Get(ctx, "json")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"go-cache"}`, value)
+
+		var keys []string
+		keys, err = f.GetAllKeys(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"json", "key"}, keys)
+
+		err = f.DestroyCache(ctx)
+		assert.NoError(t, err)
+		keys, err = f.GetAllKeys(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 0)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		err := f.SetAdd(ctx, "set", "member")
+		assert.NoError(t, err)
+
+		_, err = f.Get(ctx, "set")
+		assert.ErrorIs(t, err, ErrWrongType)
+
+		err = f.HashSet(ctx, "set", "field", "value")
+		assert.ErrorIs(t, err, ErrWrongType)
+	})
+
+	t.Run("hashes", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		err := f.HashMapSet(ctx, "hash", [][2]interface{}{{"a", "1"}, {"b", 2}})
+		assert.NoError(t, err)
+		err = f.HashSet(ctx, "hash", "c", true)
+		assert.NoError(t, err)
+
+		var value string
+		value, err = f.HashGet(ctx, "hash", "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "2", value)
+
+		_, err = f.HashGet(ctx, "hash", "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		var values []string
+		values, err = f.HashMapGet(ctx, "hash", "a", "missing", "c")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "", "1"}, values)
+
+		err = f.HashMapSet(ctx, "hash", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("sets", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		err := f.SetAddMany(ctx, "set", "b", "a", 1)
+		assert.NoError(t, err)
+
+		var members []string
+		members, err = f.SetMembers(ctx, "set")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "a", "b"}, members)
+
+		var found bool
+		found, err = f.SetIsMember(ctx, "set", "a")
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		for _, member := range members {
+			err = f.SetRemoveMember(ctx, "set", member)
+			assert.NoError(t, err)
+		}
+		found, err = f.Exists(ctx, "set")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("lists", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		list, err := f.GetList(ctx, "list")
+		assert.NoError(t, err)
+		assert.Nil(t, list)
+
+		err = f.SetList(ctx, "list", []string{"a", "b"})
+		assert.NoError(t, err)
+		err = f.SetList(ctx, "list", []string{"c"})
+		assert.NoError(t, err)
+
+		list, err = f.GetList(ctx, "list")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b", "c"}, list)
+	})
+
+	t.Run("expirations", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		err := f.SetExp(ctx, "key", "value", time.Minute)
+		assert.NoError(t, err)
+		err = f.Set(ctx, "forever", "value")
+		assert.NoError(t, err)
+
+		ttl, ok := f.TTL("key")
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, ttl.Round(time.Second))
+
+		_, ok = f.TTL("forever")
+		assert.False(t, ok)
+
+		// Conditions
+		err = f.Expire(ctx, "key", time.Second, cache.ExpireIfGreater())
+		assert.NoError(t, err)
+		ttl, _ = f.TTL("key")
+		assert.Equal(t, time.Minute, ttl.Round(time.Second))
+
+		err = f.Expire(ctx, "forever", time.Hour, cache.ExpireIfExists())
+		assert.NoError(t, err)
+		_, ok = f.TTL("forever")
+		assert.False(t, ok)
+
+		err = f.Expire(ctx, "forever", time.Hour, cache.ExpireIfNone())
+		assert.NoError(t, err)
+		_, ok = f.TTL("forever")
+		assert.True(t, ok)
+
+		// Time moves forward
+		f.Advance(time.Minute)
+
+		_, err = f.Get(ctx, "key")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		var value string
+		value, err = f.Get(ctx, "forever")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+
+		err = f.SetExp(ctx, "key", "value", 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("shared clock", func(t *testing.T) {
+		ctx := context.Background()
+		clock := cache.NewManualClock(time.Now())
+		f := NewWithClock(clock)
+
+		err := f.SetExp(ctx, "key", "value", time.Minute)
+		assert.NoError(t, err)
+
+		clock.Advance(time.Minute)
+		var found bool
+		found, err = f.Exists(ctx, "key")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("dependencies", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		err := f.Set(ctx, "key-1", "value", "user-1")
+		assert.NoError(t, err)
+		err = f.HashSet(ctx, "hash-1", "field", "value", "user-1", "user-2")
+		assert.NoError(t, err)
+		err = f.Set(ctx, "key-2", "value", "user-2")
+		assert.NoError(t, err)
+
+		var members []string
+		members, err = f.SetMembers(ctx, cache.DependencyPrefix+"user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"hash-1", "key-1"}, members)
+
+		// The two keys and the dependency set
+		var total int
+		total, err = f.KillByDependency(ctx, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+
+		var keys []string
+		keys, err = f.GetAllKeys(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{cache.DependencyPrefix + "user-2", "key-2"}, keys)
+
+		total, err = f.DeleteWithoutDependency(ctx, "key-2")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+
+		var found bool
+		found, err = f.Exists(ctx, cache.DependencyPrefix+"user-2")
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+
+	t.Run("locks", func(t *testing.T) {
+		ctx := context.Background()
+		f := New()
+
+		locked, err := f.WriteLock(ctx, "lock", "secret", 10)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+
+		locked, err = f.WriteLock(ctx, "lock", "other", 10)
+		assert.ErrorIs(t, err, cache.ErrLockMismatch)
+		assert.False(t, locked)
+
+		var released bool
+		released, err = f.ReleaseLock(ctx, "lock", "other")
+		assert.ErrorIs(t, err, cache.ErrLockMismatch)
+		assert.False(t, released)
+
+		released, err = f.ReleaseLock(ctx, "lock", "secret")
+		assert.NoError(t, err)
+		assert.True(t, released)
+
+		// Locks expire
+		_, err = f.WriteLock(ctx, "lock", "secret", 10)
+		assert.NoError(t, err)
+		f.Advance(10 * time.Second)
+
+		locked, err = f.WriteLock(ctx, "lock", "other", 10)
+		assert.NoError(t, err)
+		assert.True(t, locked)
+	})
+}
+
+// ExampleNew is an example of the method New()
+func ExampleNew() {
+	// Use the fake in place of a client
+	var cacher cache.Cacher = New()
+	defer cacher.Close()
+
+	_ = cacher.Set(context.Background(), "key", "value", "dependency")
+	_, _ = cacher.Delete(context.Background(), "dependency")
+
+	_, err := cacher.Get(context.Background(), "key")
+	fmt.Printf("found: %v", err == nil)
+	// Output:found: false
+}
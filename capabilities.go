@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// clusterEnabledInfo is the line of INFO cluster when cluster mode is enabled
+const clusterEnabledInfo = "cluster_enabled:1"
+
+// ServerCapabilities are the optional features supported by the redis server
+type ServerCapabilities struct {
+	Bloom   bool             // RedisBloom module (BF.*, CF.*)
+	Cluster bool             // Cluster mode is enabled
+	GetDel  bool             // GETDEL command (redis 6.2+)
+	JSON    bool             // RedisJSON module (JSON.*)
+	Modules map[string]int64 // Loaded modules by name (with version)
+	RESP3   bool             // HELLO command (redis 6+)
+	Search  bool             // RediSearch module (FT.*)
+	Unlink  bool             // UNLINK command (redis 4+)
+}
+
+// Capabilities returns the optional features supported by the server
+// The features are detected on connect (or on the first call) and cached on the client
+func (c *Client) Capabilities(ctx context.Context) (*ServerCapabilities, error) {
+	c.mu.RLock()
+	capabilities := c.capabilities
+	c.mu.RUnlock()
+	if capabilities != nil {
+		return capabilities, nil
+	}
+	return c.DetectCapabilities(ctx)
+}
+
+// DetectCapabilities detects (or re-detects) the optional features supported by the server
+// and caches them on the client
+//
+// Commands used:
+// https://redis.io/commands/command-info
+// https://redis.io/commands/module-list
+// https://redis.io/commands/info
+func (c *Client) DetectCapabilities(ctx context.Context) (*ServerCapabilities, error) {
+	conn, err := c.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.CloseConnection(conn)
+
+	var capabilities *ServerCapabilities
+	if capabilities, err = DetectCapabilitiesRaw(conn); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.capabilities = capabilities
+	c.mu.Unlock()
+	return capabilities, nil
+}
+
+// DetectCapabilitiesRaw detects the optional features supported by the server
+// Commands that are unknown or not allowed (IE: MODULE LIST) are treated as not supported
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/command-info
+// https://redis.io/commands/module-list
+// https://redis.io/commands/info
+func DetectCapabilitiesRaw(conn redis.Conn) (*ServerCapabilities, error) {
+	capabilities := &ServerCapabilities{Modules: make(map[string]int64)}
+
+	// Commands (unknown commands have a nil entry)
+	commands, err := redis.Values(conn.Do(CommandCommand, "INFO", UnlinkCommand, GetDeleteCommand, HelloCommand))
+	if err != nil && !isServerError(err) {
+		return nil, err
+	}
+	for _, command := range commands {
+		info, ok := command.([]interface{})
+		if !ok || len(info) == 0 {
+			continue
+		}
+		name, _ := redis.String(info[0], nil)
+		switch strings.ToUpper(name) {
+		case UnlinkCommand:
+			capabilities.Unlink = true
+		case GetDeleteCommand:
+			capabilities.GetDel = true
+		case HelloCommand:
+			capabilities.RESP3 = true
+		}
+	}
+
+	// Modules
+	var modules []interface{}
+	if modules, err = redis.Values(conn.Do(ModuleCommand, "LIST")); err != nil && !isServerError(err) {
+		return nil, err
+	}
+	for _, module := range modules {
+		fields, ok := module.([]interface{})
+		if !ok {
+			continue
+		}
+		var name string
+		var version int64
+		for i := 0; i+1 < len(fields); i += 2 {
+			switch field, _ := redis.String(fields[i], nil); field {
+			case "name":
+				name, _ = redis.String(fields[i+1], nil)
+			case "ver":
+				version, _ = redis.Int64(fields[i+1], nil)
+			}
+		}
+		if len(name) == 0 {
+			continue
+		}
+		capabilities.Modules[name] = version
+		switch strings.ToLower(name) {
+		case "bf", "bloom":
+			capabilities.Bloom = true
+		case "rejson", "json":
+			capabilities.JSON = true
+		case "search", "ft":
+			capabilities.Search = true
+		}
+	}
+
+	// Cluster
+	var info string
+	if info, err = redis.String(conn.Do(InfoCommand, "cluster")); err != nil && !isServerError(err) {
+		return nil, err
+	}
+	capabilities.Cluster = strings.Contains(info, clusterEnabledInfo)
+
+	return capabilities, nil
+}
+
+// GetAndDelete gets the key and removes it in one step
+// Uses GETDEL if supported (see: Capabilities()), otherwise GET and DEL in a transaction
+// Creates a new connection and closes connection at end of function call
+//
+// Commands used:
+// https://redis.io/commands/getdel
+// https://redis.io/commands/get
+// https://redis.io/commands/del
+func GetAndDelete(ctx context.Context, client *Client, key string) (string, error) {
+	capabilities, err := client.Capabilities(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return "", err
+	}
+	defer client.CloseConnection(conn)
+
+	if capabilities.GetDel {
+		return redis.String(conn.Do(GetDeleteCommand, key))
+	}
+
+	// Fallback for older servers
+	if err = conn.Send(MultiCommand); err != nil {
+		return "", err
+	}
+	if err = conn.Send(GetCommand, key); err != nil {
+		return "", err
+	}
+	if err = conn.Send(DeleteCommand, key); err != nil {
+		return "", err
+	}
+	var replies []interface{}
+	if replies, err = redis.Values(conn.Do(ExecuteCommand)); err != nil {
+		return "", err
+	} else if len(replies) == 0 {
+		return "", redis.ErrNil
+	}
+	return redis.String(replies[0], nil)
+}
+
+// isServerError returns true if the error is an error reply from the server (IE: unknown command)
+func isServerError(err error) bool {
+	var serverErr redis.Error
+	return errors.As(err, &serverErr)
+}
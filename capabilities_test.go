@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Capabilities tests the method Capabilities()
+func TestClient_Capabilities(t *testing.T) {
+
+	t.Run("detect using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		commandCmd := conn.Command(CommandCommand, "INFO", UnlinkCommand, GetDeleteCommand, HelloCommand).
+			Expect([]interface{}{
+				[]interface{}{[]byte("unlink"), int64(-2)},
+				nil,
+				[]interface{}{[]byte("hello"), int64(-1)},
+			})
+		conn.Command(ModuleCommand, "LIST").Expect([]interface{}{
+			[]interface{}{[]byte("name"), []byte("ReJSON"), []byte("ver"), int64(20006)},
+			[]interface{}{[]byte("name"), []byte("search"), []byte("ver"), int64(20604)},
+		})
+		conn.Command(InfoCommand, "cluster").Expect([]byte("# Cluster\r\ncluster_enabled:0\r\n"))
+
+		capabilities, err := client.Capabilities(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, &ServerCapabilities{
+			JSON:    true,
+			Modules: map[string]int64{"ReJSON": 20006, "search": 20604},
+			RESP3:   true,
+			Search:  true,
+			Unlink:  true,
+		}, capabilities)
+
+		// Cached on the client
+		_, err = client.Capabilities(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, conn.Stats(commandCmd))
+	})
+
+	t.Run("unknown commands are not supported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(CommandCommand, "INFO", UnlinkCommand, GetDeleteCommand, HelloCommand).
+			Expect([]interface{}{[]interface{}{[]byte("unlink")}, nil, nil})
+		conn.Command(ModuleCommand, "LIST").ExpectError(redis.Error("ERR unknown command 'MODULE'"))
+		conn.Command(InfoCommand, "cluster").Expect([]byte("# Cluster\r\ncluster_enabled:1\r\n"))
+
+		capabilities, err := client.DetectCapabilities(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, capabilities.Unlink)
+		assert.True(t, capabilities.Cluster)
+		assert.False(t, capabilities.GetDel)
+		assert.False(t, capabilities.Bloom)
+		assert.Empty(t, capabilities.Modules)
+	})
+
+	t.Run("detect using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		var capabilities *ServerCapabilities
+		capabilities, err = client.Capabilities(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, capabilities)
+		assert.True(t, capabilities.Unlink)
+	})
+}
+
+// ExampleClient_Capabilities is an example of the method Capabilities()
+func ExampleClient_Capabilities() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the detection commands
+	conn.GenericCommand(CommandCommand).Expect([]interface{}{[]interface{}{[]byte("unlink")}, nil, nil})
+	conn.GenericCommand(ModuleCommand).Expect([]interface{}{})
+	conn.GenericCommand(InfoCommand).Expect([]byte("cluster_enabled:0"))
+
+	// Branch on the server features
+	capabilities, _ := client.Capabilities(context.Background())
+	fmt.Printf("unlink: %v search: %v", capabilities.Unlink, capabilities.Search)
+	// Output:unlink: true search: false
+}
+
+// TestGetAndDelete tests the method GetAndDelete()
+func TestGetAndDelete(t *testing.T) {
+
+	t.Run("uses GETDEL when supported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.capabilities = &ServerCapabilities{GetDel: true}
+
+		conn.Command(GetDeleteCommand, testKey).Expect(testStringValue)
+
+		value, err := GetAndDelete(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+	})
+
+	t.Run("falls back to a transaction", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.capabilities = &ServerCapabilities{}
+
+		conn.Command(MultiCommand)
+		conn.Command(GetCommand, testKey)
+		conn.Command(DeleteCommand, testKey)
+		conn.Command(ExecuteCommand).
+			Expect([]interface{}{[]byte(testStringValue), int64(1)}).
+			Expect([]interface{}{nil, int64(0)})
+
+		value, err := GetAndDelete(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		_, err = GetAndDelete(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("get and delete using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var value string
+		value, err = GetAndDelete(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
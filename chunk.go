@@ -0,0 +1,295 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// DefaultChunkSize is the default max size of each chunk (1MB)
+const DefaultChunkSize = 1 << 20
+
+// chunkManifestHeader starts the manifest stored under the key of a chunked value
+var chunkManifestHeader = []byte(encodedValuePrefix + "chunked\x00")
+
+// ErrIncompleteChunks is returned when a chunk of a chunked value is missing (IE: evicted)
+var ErrIncompleteChunks = errors.New("chunked value is incomplete")
+
+// ChunkKey returns the key of the chunk (IE: key:chunk:0)
+func ChunkKey(key string, index int) string {
+	return key + ":chunk:" + strconv.Itoa(index)
+}
+
+// SetChunked stores the value, splitting it across chunk keys if it is larger than the chunk size
+// The key holds a manifest of the chunks, and all chunks are linked to the same dependencies
+// A chunk size of 0 uses the DefaultChunkSize, and a ttl of 0 never expires
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetChunkedRaw()
+func SetChunked(ctx context.Context, client *Client, key string, value []byte, chunkSize int,
+	ttl time.Duration, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return SetChunkedRaw(conn, key, value, chunkSize, ttl, dependencies...)
+}
+
+// SetChunkedRaw stores the value, splitting it across chunk keys if it is larger than the chunk size
+// The key holds a manifest of the chunks, and all chunks are linked to the same dependencies
+// A chunk size of 0 uses the DefaultChunkSize, and a ttl of 0 never expires
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/get
+// https://redis.io/commands/multi
+// https://redis.io/commands/set
+// https://redis.io/commands/del
+// https://redis.io/commands/exec
+func SetChunkedRaw(conn redis.Conn, key string, value []byte, chunkSize int,
+	ttl time.Duration, dependencies ...string) error {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return err
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	// Chunks of the previous value (removed if there are more than the new value)
+	previousChunks, err := chunkCount(conn, key)
+	if err != nil {
+		return err
+	}
+
+	// Small values are stored as-is (unless they look like a manifest)
+	if len(value) <= chunkSize && !bytes.HasPrefix(value, chunkManifestHeader) {
+		if err = conn.Send(MultiCommand); err != nil {
+			return err
+		}
+		if err = sendSetChunk(conn, key, value, ttl); err != nil {
+			return err
+		}
+		if err = sendDeleteChunks(conn, key, 0, previousChunks); err != nil {
+			return err
+		}
+		if _, err = conn.Do(ExecuteCommand); err != nil {
+			return err
+		}
+		return linkDependencies(conn, key, dependencies...)
+	}
+
+	// Store each chunk and the manifest in one transaction
+	chunks := (len(value) + chunkSize - 1) / chunkSize
+	keys := make([]interface{}, 0, chunks+1)
+	keys = append(keys, key)
+	if err = conn.Send(MultiCommand); err != nil {
+		return err
+	}
+	for i := 0; i < chunks; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(value) {
+			end = len(value)
+		}
+		chunkKey := ChunkKey(key, i)
+		if err = sendSetChunk(conn, chunkKey, value[i*chunkSize:end], ttl); err != nil {
+			return err
+		}
+		keys = append(keys, chunkKey)
+	}
+	if err = sendDeleteChunks(conn, key, chunks, previousChunks); err != nil {
+		return err
+	}
+	if err = sendSetChunk(conn, key, chunkManifest(chunks, len(value)), ttl); err != nil {
+		return err
+	}
+	if _, err = conn.Do(ExecuteCommand); err != nil {
+		return err
+	}
+	return linkDependenciesMany(conn, keys, dependencies...)
+}
+
+// GetChunked gets the value, reassembling the chunks if it was chunked
+// ErrIncompleteChunks is returned if a chunk is missing
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: GetChunkedRaw()
+func GetChunked(ctx context.Context, client *Client, key string) (value []byte, err error) {
+	if client.IsBypassed() {
+		return nil, redis.ErrNil
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		if readErr = client.checkTombstone(conn, key); readErr != nil {
+			return
+		}
+		value, readErr = GetChunkedRaw(conn, key)
+		return
+	})
+	return
+}
+
+// GetChunkedRaw gets the value, reassembling the chunks if it was chunked
+// ErrIncompleteChunks is returned if a chunk is missing
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/get
+// https://redis.io/commands/mget
+func GetChunkedRaw(conn redis.Conn, key string) ([]byte, error) {
+	value, err := redis.Bytes(conn.Do(GetCommand, key))
+	if err != nil {
+		return nil, err
+	}
+	chunks, size, ok := parseChunkManifest(value)
+	if !ok {
+		return value, nil
+	}
+
+	// Read all the chunks
+	args := make([]interface{}, chunks)
+	for i := range args {
+		args[i] = ChunkKey(key, i)
+	}
+	var parts [][]byte
+	if parts, err = redis.ByteSlices(conn.Do(MultiGetCommand, args...)); err != nil {
+		return nil, err
+	}
+
+	value = make([]byte, 0, size)
+	for _, part := range parts {
+		if part == nil {
+			return nil, ErrIncompleteChunks
+		}
+		value = append(value, part...)
+	}
+	if len(value) != size {
+		return nil, ErrIncompleteChunks
+	}
+	return value, nil
+}
+
+// DeleteChunked removes the value and all of its chunks
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DeleteChunkedRaw()
+func DeleteChunked(ctx context.Context, client *Client, key string) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return DeleteChunkedRaw(conn, key)
+}
+
+// DeleteChunkedRaw removes the value and all of its chunks
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/get
+// https://redis.io/commands/del
+func DeleteChunkedRaw(conn redis.Conn, key string) error {
+	chunks, err := chunkCount(conn, key)
+	if err != nil {
+		return err
+	}
+	args := make([]interface{}, 0, chunks+1)
+	args = append(args, key)
+	for i := 0; i < chunks; i++ {
+		args = append(args, ChunkKey(key, i))
+	}
+	_, err = conn.Do(DeleteCommand, args...)
+	return err
+}
+
+// chunkManifest returns the manifest of a chunked value
+func chunkManifest(chunks, size int) []byte {
+	return append(append([]byte{}, chunkManifestHeader...), fmt.Sprintf("%d:%d", chunks, size)...)
+}
+
+// parseChunkManifest returns the number of chunks and total size if the value is a manifest
+func parseChunkManifest(value []byte) (chunks, size int, ok bool) {
+	if !bytes.HasPrefix(value, chunkManifestHeader) {
+		return 0, 0, false
+	}
+	parts := bytes.SplitN(value[len(chunkManifestHeader):], []byte(":"), 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	var err error
+	if chunks, err = strconv.Atoi(string(parts[0])); err != nil {
+		return 0, 0, false
+	}
+	if size, err = strconv.Atoi(string(parts[1])); err != nil {
+		return 0, 0, false
+	}
+	return chunks, size, true
+}
+
+// chunkCount returns the number of chunks currently stored for the key (0 if not chunked)
+func chunkCount(conn redis.Conn, key string) (int, error) {
+	value, err := redis.Bytes(conn.Do(GetCommand, key))
+	if errors.Is(err, redis.ErrNil) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	chunks, _, _ := parseChunkManifest(value)
+	return chunks, nil
+}
+
+// sendSetChunk sends the set command for the chunk (with the ttl if set)
+func sendSetChunk(conn redis.Conn, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		return conn.Send(SetCommand, key, value)
+	} else if isWholeSeconds(ttl) {
+		return conn.Send(SetExpirationCommand, key, int64(ttl.Seconds()), value)
+	}
+	return conn.Send(SetExpMillisCommand, key, ttl.Milliseconds(), value)
+}
+
+// sendDeleteChunks sends a delete for the chunks from the index up to the previous number of chunks
+func sendDeleteChunks(conn redis.Conn, key string, from, previousChunks int) error {
+	if from >= previousChunks {
+		return nil
+	}
+	args := make([]interface{}, 0, previousChunks-from)
+	for i := from; i < previousChunks; i++ {
+		args = append(args, ChunkKey(key, i))
+	}
+	return conn.Send(DeleteCommand, args...)
+}
+
+// linkDependenciesMany links each of the keys to the dependencies in one transaction
+func linkDependenciesMany(conn redis.Conn, keys []interface{}, dependencies ...string) (err error) {
+	if len(dependencies) == 0 || skipsDependencies(conn) {
+		return
+	}
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	for _, dependency := range dependencies {
+		if dependencyShards(conn, dependency) > 1 {
+			for _, key := range keys {
+				if err = conn.Send(AddToSetCommand, dependencySetKey(conn, dependency, key), key); err != nil {
+					return
+				}
+			}
+			continue
+		}
+		if err = conn.Send(AddToSetCommand, redis.Args{}.Add(DependencyPrefix+dependency).Add(keys...)...); err != nil {
+			return
+		}
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
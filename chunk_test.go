@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetChunked is testing the method SetChunked()
+func TestSetChunked(t *testing.T) {
+
+	t.Run("small value using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(nil)
+		conn.Command(MultiCommand)
+		setCmd := conn.Command(SetExpirationCommand, testKey, int64(60), []byte(testStringValue))
+		conn.Command(ExecuteCommand).Expect([]interface{}{"OK"})
+
+		err := SetChunked(context.Background(), client, testKey, []byte(testStringValue), 0, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("chunked value using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// Previous value had three chunks
+		conn.Command(GetCommand, testKey).Expect(chunkManifest(3, 12))
+		conn.Command(MultiCommand).Expect("OK")
+		firstCmd := conn.Command(SetCommand, ChunkKey(testKey, 0), []byte("abcd"))
+		secondCmd := conn.Command(SetCommand, ChunkKey(testKey, 1), []byte("ef"))
+		deleteCmd := conn.Command(DeleteCommand, ChunkKey(testKey, 2))
+		manifestCmd := conn.Command(SetCommand, testKey, chunkManifest(2, 6))
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+		depCmd := conn.Command(
+			AddToSetCommand, DependencyPrefix+testDependantKey, testKey, ChunkKey(testKey, 0), ChunkKey(testKey, 1),
+		)
+
+		err := SetChunked(context.Background(), client, testKey, []byte("abcdef"), 4, 0, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, firstCmd.Called)
+		assert.True(t, secondCmd.Called)
+		assert.True(t, deleteCmd.Called)
+		assert.True(t, manifestCmd.Called)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("chunked value using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Store a value across ten chunks
+		value := bytes.Repeat([]byte("0123456789"), 10)
+		err = SetChunkedRaw(conn, testKey, value, 10, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+
+		var stored []byte
+		stored, err = GetChunkedRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, value, stored)
+
+		// A smaller value removes the extra chunks
+		err = SetChunkedRaw(conn, testKey, value[:25], 10, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+
+		stored, err = GetChunkedRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, value[:25], stored)
+
+		var found bool
+		found, err = ExistsRaw(conn, ChunkKey(testKey, 3))
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		// A missing chunk is detected
+		_, err = DeleteWithoutDependencyRaw(conn, ChunkKey(testKey, 1))
+		assert.NoError(t, err)
+		_, err = GetChunkedRaw(conn, testKey)
+		assert.ErrorIs(t, err, ErrIncompleteChunks)
+
+		// All chunks are removed by the dependency
+		err = SetChunkedRaw(conn, testKey, value, 10, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+		_, err = KillByDependencyRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+
+		var keys []string
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+
+		// Delete the chunks directly
+		err = SetChunkedRaw(conn, testKey, value, 10, 0)
+		assert.NoError(t, err)
+		err = DeleteChunkedRaw(conn, testKey)
+		assert.NoError(t, err)
+
+		_, err = GetChunkedRaw(conn, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
+
+// ExampleSetChunked is an example of the method SetChunked()
+func ExampleSetChunked() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the commands
+	conn.Command(GetCommand, "export").Expect(nil)
+	conn.Command(MultiCommand)
+	conn.GenericCommand(SetCommand)
+	conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+	// Store the export in 1MB chunks
+	err := SetChunked(context.Background(), client, "export", make([]byte, 3*DefaultChunkSize), 0, 0)
+	fmt.Printf("stored: %v", err == nil)
+	// Output:stored: true
+}
+
+// TestGetChunked is testing the method GetChunked()
+func TestGetChunked(t *testing.T) {
+
+	t.Run("get chunked using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(chunkManifest(2, 6))
+		conn.Command(MultiGetCommand, ChunkKey(testKey, 0), ChunkKey(testKey, 1)).
+			Expect([]interface{}{[]byte("abcd"), []byte("ef")}).
+			Expect([]interface{}{[]byte("abcd"), nil})
+
+		value, err := GetChunked(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abcdef"), value)
+
+		_, err = GetChunked(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, ErrIncompleteChunks)
+	})
+
+	t.Run("value that was not chunked", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect([]byte(testStringValue))
+
+		value, err := GetChunked(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(testStringValue), value)
+	})
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidConnectionName is returned for connection names with spaces (see: SetConnectionName())
+var ErrInvalidConnectionName = errors.New("connection name cannot contain spaces")
+
+// ClientEntry is a connection to the server (see: ListClients())
+type ClientEntry struct {
+	Addr        string        // Address of the client (host:port)
+	Age         time.Duration // Time since the connection was made
+	DB          int           // Selected database
+	ID          int64         // Unique id of the connection
+	Idle        time.Duration // Time since the last command
+	LastCommand string        // Last command run by the connection (IE: get, client|list)
+	Name        string        // Name of the connection (see: SetConnectionName())
+}
+
+// SetConnectionName names each connection dialed from now on (CLIENT SETNAME), so the connections
+// of the pool can be found on the server (see: ListClients()), an empty name stops naming
+// Connections already in the pool keep their name until they are closed
+func (c *Client) SetConnectionName(name string) error {
+	if strings.ContainsAny(name, " \n") {
+		return ErrInvalidConnectionName
+	}
+	c.mu.Lock()
+	c.connectionName = name
+	c.mu.Unlock()
+	return nil
+}
+
+// ConnectionName returns the name of the dialed connections (see: SetConnectionName())
+func (c *Client) ConnectionName() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.connectionName
+}
+
+// nameConnection names a dialed connection (if set), servers that refuse the name are ignored
+//
+// Spec: https://redis.io/commands/client-setname
+func (c *Client) nameConnection(conn redis.Conn) error {
+	name := c.ConnectionName()
+	if len(name) == 0 {
+		return nil
+	}
+	if _, err := conn.Do(ClientCommand, SetNameArgument, name); err != nil && !isServerError(err) {
+		return err
+	}
+	return nil
+}
+
+// ListClients returns the connections to the server (IE: to find stuck connections by name or idle time)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ListClientsRaw()
+func ListClients(ctx context.Context, client *Client) ([]ClientEntry, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return ListClientsRaw(conn)
+}
+
+// ListClientsRaw returns the connections to the server
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/client-list
+func ListClientsRaw(conn redis.Conn) ([]ClientEntry, error) {
+	list, err := redis.String(conn.Do(ClientCommand, ListArgument))
+	if err != nil {
+		return nil, err
+	}
+
+	var clients []ClientEntry
+	for _, line := range strings.Split(list, "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			clients = append(clients, parseClientEntry(line))
+		}
+	}
+	return clients, nil
+}
+
+// KillClient closes the connection of the client address (host:port, see: ListClients())
+// Returns false if no connection has the address
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: KillClientRaw()
+func KillClient(ctx context.Context, client *Client, addr string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return KillClientRaw(conn, addr)
+}
+
+// KillClientRaw closes the connection of the client address (host:port)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/client-kill
+func KillClientRaw(conn redis.Conn, addr string) (bool, error) {
+	if len(addr) == 0 {
+		return false, errors.New("missing required parameter: addr")
+	}
+	killed, err := redis.Int(conn.Do(ClientCommand, KillArgument, AddressArgument, addr))
+	return killed > 0, err
+}
+
+// parseClientEntry parses a line of CLIENT LIST (IE: "id=3 addr=127.0.0.1:6000 name= age=10 ...")
+func parseClientEntry(line string) (entry ClientEntry) {
+	for _, field := range strings.Fields(line) {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "addr":
+			entry.Addr = value
+		case "age":
+			entry.Age = parseSeconds(value)
+		case "cmd":
+			entry.LastCommand = value
+		case "db":
+			entry.DB, _ = strconv.Atoi(value)
+		case "id":
+			entry.ID, _ = strconv.ParseInt(value, 10, 64)
+		case "idle":
+			entry.Idle = parseSeconds(value)
+		case "name":
+			entry.Name = value
+		}
+	}
+	return
+}
+
+// parseSeconds parses a number of seconds (0 if invalid)
+func parseSeconds(value string) time.Duration {
+	seconds, _ := strconv.ParseInt(value, 10, 64)
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// testClientList is a CLIENT LIST reply
+const testClientList = "id=3 addr=127.0.0.1:50188 laddr=127.0.0.1:6379 fd=8 name=api-1 age=120 idle=30 " +
+	"flags=N db=0 sub=0 psub=0 multi=-1 cmd=get user=default\n" +
+	"id=4 addr=127.0.0.1:50190 laddr=127.0.0.1:6379 fd=9 name= age=5 idle=0 " +
+	"flags=N db=2 sub=0 psub=0 multi=-1 cmd=client|list user=default\n"
+
+// TestListClients tests the method ListClients()
+func TestListClients(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(ClientCommand, ListArgument).Expect(testClientList)
+
+	clients, err := ListClients(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, []ClientEntry{
+		{
+			Addr: "127.0.0.1:50188", Age: 2 * time.Minute, ID: 3,
+			Idle: 30 * time.Second, LastCommand: "get", Name: "api-1",
+		},
+		{
+			Addr: "127.0.0.1:50190", Age: 5 * time.Second, DB: 2, ID: 4,
+			LastCommand: "client|list",
+		},
+	}, clients)
+}
+
+// TestKillClient tests the method KillClient()
+func TestKillClient(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(ClientCommand, KillArgument, AddressArgument, "127.0.0.1:50188").Expect(int64(1))
+	conn.Command(ClientCommand, KillArgument, AddressArgument, "127.0.0.1:1").Expect(int64(0))
+
+	killed, err := KillClient(context.Background(), client, "127.0.0.1:50188")
+	assert.NoError(t, err)
+	assert.True(t, killed)
+
+	killed, err = KillClient(context.Background(), client, "127.0.0.1:1")
+	assert.NoError(t, err)
+	assert.False(t, killed)
+
+	_, err = KillClient(context.Background(), client, "")
+	assert.Error(t, err)
+}
+
+// TestClient_SetConnectionName tests the method SetConnectionName()
+func TestClient_SetConnectionName(t *testing.T) {
+
+	t.Run("invalid name", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.ErrorIs(t, client.SetConnectionName("my service"), ErrInvalidConnectionName)
+		assert.Equal(t, "", client.ConnectionName())
+	})
+
+	t.Run("name dialed connections using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// Not named
+		assert.NoError(t, client.nameConnection(conn))
+
+		nameCmd := conn.Command(ClientCommand, SetNameArgument, "api-1").Expect("OK")
+		assert.NoError(t, client.SetConnectionName("api-1"))
+		assert.Equal(t, "api-1", client.ConnectionName())
+		assert.NoError(t, client.nameConnection(conn))
+		assert.Equal(t, 1, conn.Stats(nameCmd))
+
+		// Refused by the server
+		conn.Command(ClientCommand, SetNameArgument, "api-2").ExpectError(redis.Error("ERR unknown command"))
+		assert.NoError(t, client.SetConnectionName("api-2"))
+		assert.NoError(t, client.nameConnection(conn))
+	})
+
+	t.Run("name dialed connections using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = client.SetConnectionName("go-cache-test")
+		assert.NoError(t, err)
+
+		// At most one connection is idle in the pool, so one of them is dialed
+		var names []string
+		for i := 0; i < 2; i++ {
+			var named redis.Conn
+			named, err = client.GetConnectionWithContext(context.Background())
+			assert.NoError(t, err)
+			defer client.CloseConnection(named)
+
+			var name string
+			name, _ = redis.String(named.Do(ClientCommand, "GETNAME"))
+			names = append(names, name)
+		}
+		assert.Contains(t, names, "go-cache-test")
+	})
+}
+
+// ExampleListClients is an example of the method ListClients()
+func ExampleListClients() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the connections
+	conn.Command(ClientCommand, ListArgument).Expect(testClientList)
+
+	clients, _ := ListClients(context.Background(), client)
+	fmt.Printf("%s idle for %s", clients[0].Name, clients[0].Idle)
+	// Output:api-1 idle for 30s
+}
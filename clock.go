@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for the client-side time logic: periodic leaderboards, hot key refreshes,
+// endpoint discovery, the shutdown drain, warm-ups, rate limits, priority queue waits, shadow reads
+// and webhook batches
+//
+// Expirations of keys are kept by redis and are not affected by the clock
+type Clock interface {
+	After(d time.Duration) <-chan time.Time // Fires once the duration has passed
+	Now() time.Time                         // Current time
+}
+
+// systemClock is the real clock (default)
+type systemClock struct{}
+
+// SystemClock is the real clock, used by default
+var SystemClock Clock = systemClock{}
+
+// After returns time.After()
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Now returns time.Now()
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SetClock replaces the clock of the client (IE: a ManualClock in tests), nil restores the SystemClock
+// Set before starting any background workers (hot keys, discovery) and before enabling the priority
+// queue, shadow reads or webhooks (they keep the clock they were created with)
+func (c *Client) SetClock(clock Clock) {
+	c.mu.Lock()
+	c.clock = clock
+	c.mu.Unlock()
+}
+
+// Clock returns the clock of the client
+func (c *Client) Clock() Clock {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.clock == nil {
+		return SystemClock
+	}
+	return c.clock
+}
+
+// clockWaiter is a pending After() on a ManualClock
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// ManualClock is a Clock that only moves with Advance(), so tests never sleep
+type ManualClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+// NewManualClock returns a manual clock starting at the given time
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// After fires once the clock is advanced past the duration
+func (m *ManualClock) After(d time.Duration) <-chan time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- m.now
+		return ch
+	}
+	m.waiters = append(m.waiters, &clockWaiter{at: m.now.Add(d), ch: ch})
+	return ch
+}
+
+// Now returns the current time of the clock
+func (m *ManualClock) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Advance moves the clock forward and fires every After() that is due (in order)
+func (m *ManualClock) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+
+	sort.SliceStable(m.waiters, func(i, j int) bool {
+		return m.waiters[i].at.Before(m.waiters[j].at)
+	})
+	pending := m.waiters[:0]
+	for _, waiter := range m.waiters {
+		if waiter.at.After(m.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+		waiter.ch <- waiter.at
+	}
+	m.waiters = pending
+}
+
+// Waiters returns the number of pending After() calls
+// Useful to wait until a background worker is sleeping before advancing the clock
+func (m *ManualClock) Waiters() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.waiters)
+}
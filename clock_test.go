@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters waits until the manual clock has the number of pending After() calls
+func waitForWaiters(t *testing.T, clock *ManualClock, waiters int) {
+	for i := 0; i < 1000 && clock.Waiters() != waiters; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, waiters, clock.Waiters())
+}
+
+// TestManualClock tests the ManualClock
+func TestManualClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	assert.Equal(t, start, clock.Now())
+
+	// Fires right away
+	assert.Equal(t, start, <-clock.After(0))
+
+	late := clock.After(2 * time.Minute)
+	early := clock.After(time.Minute)
+	assert.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), clock.Now())
+	assert.Equal(t, start.Add(time.Minute), <-early)
+	assert.Equal(t, 0, len(late))
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(2*time.Minute), <-late)
+	assert.Equal(t, 0, clock.Waiters())
+}
+
+// TestClient_SetClock tests the method SetClock()
+func TestClient_SetClock(t *testing.T) {
+
+	t.Run("default is the system clock", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Equal(t, SystemClock, client.Clock())
+
+		clock := NewManualClock(time.Now())
+		client.SetClock(clock)
+		assert.Equal(t, clock, client.Clock())
+
+		client.SetClock(nil)
+		assert.Equal(t, SystemClock, client.Clock())
+	})
+
+	t.Run("periodic leaderboard uses the clock", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		clock := NewManualClock(time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC))
+		client.SetClock(clock)
+		board := NewPeriodicLeaderboard(client, testKey, 24*time.Hour, time.Hour)
+
+		// One hour left in the period plus the retention
+		today := board.Key(clock.Now())
+		conn.Command(SortedSetAddCommand, today, float64(1), "alice")
+		expireToday := conn.Command(ExpireCommand, today, int64(7201))
+
+		err := board.Add(context.Background(), "alice", 1)
+		assert.NoError(t, err)
+		assert.True(t, expireToday.Called)
+
+		// The next period uses a new board
+		clock.Advance(time.Hour)
+		tomorrow := board.Key(clock.Now())
+		assert.NotEqual(t, today, tomorrow)
+		conn.Command(SortedSetAddCommand, tomorrow, float64(1), "alice")
+		expireTomorrow := conn.Command(ExpireCommand, tomorrow, redigomock.NewAnyInt())
+
+		err = board.Add(context.Background(), "alice", 1)
+		assert.NoError(t, err)
+		assert.True(t, expireTomorrow.Called)
+	})
+
+	t.Run("hot key refresh uses the clock", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		clock := NewManualClock(time.Now())
+		client.SetClock(clock)
+		conn.Command(SetExpirationCommand, testKey, int64(3600), testStringValue)
+
+		loads := make(chan struct{}, 10)
+		err := client.RegisterHotKey(context.Background(), testKey, time.Hour,
+			func(ctx context.Context) (interface{}, error) {
+				loads <- struct{}{}
+				return testStringValue, nil
+			},
+		)
+		assert.NoError(t, err)
+		<-loads
+
+		// Refreshed once the clock reaches the refresh interval (no sleeping for an hour)
+		waitForWaiters(t, clock, 1)
+		clock.Advance(hotKeyRefreshInterval(time.Hour))
+		select {
+		case <-loads:
+		case <-time.After(time.Second):
+			t.Fatal("hot key was not refreshed")
+		}
+
+		client.UnregisterHotKey(testKey)
+	})
+}
+
+// ExampleNewManualClock is an example of the method NewManualClock()
+func ExampleNewManualClock() {
+	clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	fired := clock.After(time.Hour)
+
+	// Move time forward instead of sleeping
+	clock.Advance(time.Hour)
+
+	fmt.Printf("fired at: %s", (<-fired).Format(time.Kitchen))
+	// Output:fired at: 1:00AM
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes and decodes values stored in redis
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the default codec (encoding/json)
+type JSONCodec struct{}
+
+// Marshal encodes the value as JSON
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes the JSON into the value
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// MsgpackCodec encodes values with MessagePack (smaller than JSON, integers use the compact encoding)
+// Struct fields use the json tags (the same field names as the JSONCodec), and the field
+// information of each struct type is cached after the first use
+type MsgpackCodec struct{}
+
+// Marshal encodes the value as MessagePack
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(encoder)
+	encoder.Reset(&buf)
+	encoder.SetCustomStructTag("json")
+	encoder.UseCompactInts(true)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes the MessagePack into the value
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	decoder := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(decoder)
+	decoder.Reset(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+// ProtoCodec encodes values that implement proto.Message with the protobuf wire format,
+// and all other values with the fallback codec (default: JSONCodec)
+type ProtoCodec struct {
+	Fallback Codec
+}
+
+// Marshal encodes the value as protobuf (or with the fallback codec)
+func (c ProtoCodec) Marshal(v interface{}) ([]byte, error) {
+	if message, ok := v.(proto.Message); ok {
+		return proto.Marshal(message)
+	}
+	return c.fallback().Marshal(v)
+}
+
+// Unmarshal decodes the protobuf into the value (or with the fallback codec)
+func (c ProtoCodec) Unmarshal(data []byte, v interface{}) error {
+	if message, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, message)
+	}
+	return c.fallback().Unmarshal(data, v)
+}
+
+// fallback returns the codec for values that are not protobuf messages
+func (c ProtoCodec) fallback() Codec {
+	if c.Fallback == nil {
+		return JSONCodec{}
+	}
+	return c.Fallback
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// TestJSONCodec tests the JSONCodec
+func TestJSONCodec(t *testing.T) {
+	t.Parallel()
+
+	type testModel struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	var codec Codec = JSONCodec{}
+	data, err := codec.Marshal(&testModel{Name: "test", Count: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"test","count":2}`, string(data))
+
+	var model testModel
+	err = codec.Unmarshal(data, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, testModel{Name: "test", Count: 2}, model)
+
+	err = codec.Unmarshal([]byte("not-json"), &model)
+	assert.Error(t, err)
+}
+
+// TestProtoCodec tests the ProtoCodec
+func TestProtoCodec(t *testing.T) {
+
+	t.Run("protobuf messages", func(t *testing.T) {
+		t.Parallel()
+
+		var codec Codec = ProtoCodec{}
+		data, err := codec.Marshal(wrapperspb.String("go-cache"))
+		assert.NoError(t, err)
+
+		expected, _ := proto.Marshal(wrapperspb.String("go-cache"))
+		assert.Equal(t, expected, data)
+
+		message := new(wrapperspb.StringValue)
+		err = codec.Unmarshal(data, message)
+		assert.NoError(t, err)
+		assert.Equal(t, "go-cache", message.GetValue())
+
+		err = codec.Unmarshal([]byte{0xff, 0xff}, message)
+		assert.Error(t, err)
+	})
+
+	t.Run("other values use the fallback", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := ProtoCodec{}.Marshal(map[string]int{"count": 2})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"count":2}`, string(data))
+
+		var value map[string]int
+		err = ProtoCodec{}.Unmarshal(data, &value)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"count": 2}, value)
+
+		// Custom fallback
+		_, err = ProtoCodec{Fallback: failingCodec{}}.Marshal(value)
+		assert.ErrorIs(t, err, errCodecFailed)
+	})
+}
+
+// errCodecFailed is returned by the failingCodec
+var errCodecFailed = errors.New("codec failed")
+
+// failingCodec fails to encode or decode all values
+type failingCodec struct{}
+
+func (failingCodec) Marshal(interface{}) ([]byte, error) { return nil, errCodecFailed }
+func (failingCodec) Unmarshal([]byte, interface{}) error { return errCodecFailed }
+
+// TestMsgpackCodec tests the MsgpackCodec
+func TestMsgpackCodec(t *testing.T) {
+	t.Parallel()
+
+	type testModel struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+
+	var codec Codec = MsgpackCodec{}
+	data, err := codec.Marshal(&testModel{Name: "test", Count: 2})
+	assert.NoError(t, err)
+
+	// Smaller than the JSON
+	jsonData, _ := JSONCodec{}.Marshal(&testModel{Name: "test", Count: 2})
+	assert.Less(t, len(data), len(jsonData))
+
+	var model testModel
+	err = codec.Unmarshal(data, &model)
+	assert.NoError(t, err)
+	assert.Equal(t, testModel{Name: "test", Count: 2}, model)
+
+	// The json tags are the field names
+	var fields map[string]interface{}
+	err = codec.Unmarshal(data, &fields)
+	assert.NoError(t, err)
+	assert.Contains(t, fields, "name")
+	assert.Contains(t, fields, "count")
+
+	err = codec.Unmarshal([]byte{0xc1}, &model)
+	assert.Error(t, err)
+}
+
+// benchmarkModel is the struct for the codec benchmarks
+type benchmarkModel struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Active  bool              `json:"active"`
+	Scores  []float64         `json:"scores"`
+	Labels  map[string]string `json:"labels"`
+	Created int64             `json:"created"`
+}
+
+// newBenchmarkModel returns the model for the codec benchmarks
+func newBenchmarkModel() *benchmarkModel {
+	return &benchmarkModel{
+		ID:      1234567,
+		Name:    "go-cache benchmark model",
+		Active:  true,
+		Scores:  []float64{1.5, 2.25, 3.125},
+		Labels:  map[string]string{"team": "platform", "tier": "gold"},
+		Created: 1660000000,
+	}
+}
+
+// benchmarkCodec benchmarks encoding and decoding the model with the codec
+func benchmarkCodec(b *testing.B, codec Codec) {
+	model := newBenchmarkModel()
+	data, _ := codec.Marshal(model)
+	b.ReportAllocs()
+	b.ReportMetric(float64(len(data)), "bytes")
+	for i := 0; i < b.N; i++ {
+		data, _ = codec.Marshal(model)
+		var decoded benchmarkModel
+		_ = codec.Unmarshal(data, &decoded)
+	}
+}
+
+// BenchmarkJSONCodec benchmarks the JSONCodec
+func BenchmarkJSONCodec(b *testing.B) {
+	benchmarkCodec(b, JSONCodec{})
+}
+
+// BenchmarkMsgpackCodec benchmarks the MsgpackCodec
+func BenchmarkMsgpackCodec(b *testing.B) {
+	benchmarkCodec(b, MsgpackCodec{})
+}
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Environment variables read by ConfigFromEnv()
+const (
+	EnvClientName      = "REDIS_CLIENT_NAME"       // Name of the connections (IE: service:instance)
+	EnvConnectTimeout  = "REDIS_CONNECT_TIMEOUT"   // Timeout for connecting (duration IE: 5s)
+	EnvDependencyMode  = "REDIS_DEPENDENCY_MODE"   // Load the dependency scripts (bool)
+	EnvIdleTimeout     = "REDIS_IDLE_TIMEOUT"      // Close idle connections after (duration IE: 240s)
+	EnvMaxActive       = "REDIS_MAX_ACTIVE"        // Max active connections (int, 0 is unlimited)
+	EnvMaxConnLifetime = "REDIS_MAX_CONN_LIFETIME" // Close connections older than (duration IE: 60s)
+	EnvMaxIdle         = "REDIS_MAX_IDLE"          // Max idle connections (int)
+	EnvMaxWait         = "REDIS_MAX_WAIT"          // Max wait for a connection from an exhausted pool (duration IE: 500ms)
+	EnvNewRelic        = "REDIS_NEW_RELIC"         // Enable NewRelic segments (bool)
+	EnvReadTimeout     = "REDIS_READ_TIMEOUT"      // Timeout for reading a command reply (duration IE: 1s)
+	EnvTLS             = "REDIS_TLS"               // Connect using TLS (bool)
+	EnvTLSSkipVerify   = "REDIS_TLS_SKIP_VERIFY"   // Skip verifying the server certificate (bool)
+	EnvURL             = "REDIS_URL"               // Redis url (required IE: redis://localhost:6379)
+	EnvWriteTimeout    = "REDIS_WRITE_TIMEOUT"     // Timeout for writing a command (duration IE: 1s)
+)
+
+// Config is the configuration for ConnectWithConfig()
+type Config struct {
+	ClientName            string        // Name of each dialed connection (see: SetConnectionName(), ServiceClientName())
+	ConnectTimeout        time.Duration // Timeout for connecting to redis (0 is no timeout)
+	DependencyMode        bool          // Load the dependency scripts (see: RegisterScripts())
+	IdleTimeout           time.Duration // Close connections after remaining idle for this duration (0 is never)
+	MaxActiveConnections  int           // Max connections allocated by the pool at a given time (0 is unlimited)
+	MaxConnectionLifetime time.Duration // Close connections older than this duration (0 is never)
+	MaxIdleConnections    int           // Max idle connections in the pool
+	MaxWait               time.Duration // Max wait for a connection from an exhausted pool (0 is no wait)
+	NewRelicEnabled       bool          // Wrap the pool with NewRelic segments
+	ReadTimeout           time.Duration // Timeout for reading a command reply (see: WithCommandTimeout())
+	TLS                   bool          // Connect using TLS (always used for rediss:// urls)
+	TLSSkipVerify         bool          // Skip verifying the server certificate (only with TLS)
+	URL                   string        // Redis url (IE: redis://localhost:6379)
+	WriteTimeout          time.Duration // Timeout for writing a command (0 is no timeout)
+
+	onDial dialHook // Called for each dialed connection (IE: naming, see: SetConnectionName())
+}
+
+// dialHook is called for each dialed connection, an error closes the connection
+type dialHook func(conn redis.Conn) error
+
+// ConnectWithConfig creates a new connection pool using the configuration
+//
+// Format of URL: redis://localhost:6379
+func ConnectWithConfig(ctx context.Context, config Config, options ...redis.DialOption) (*Client, error) {
+	return connect(ctx, config, config.dialOptions(options...)...)
+}
+
+// dialOptions returns the dial options with the timeout and TLS options added (if set)
+func (config Config) dialOptions(options ...redis.DialOption) []redis.DialOption {
+	if config.ConnectTimeout > 0 {
+		options = append(options, redis.DialConnectTimeout(config.ConnectTimeout))
+	}
+	if config.ReadTimeout > 0 {
+		options = append(options, redis.DialReadTimeout(config.ReadTimeout))
+	}
+	if config.WriteTimeout > 0 {
+		options = append(options, redis.DialWriteTimeout(config.WriteTimeout))
+	}
+	if !config.TLS && !strings.HasPrefix(config.URL, "rediss://") {
+		return options
+	}
+	return append(
+		options,
+		redis.DialUseTLS(true),
+		redis.DialTLSSkipVerify(config.TLSSkipVerify),
+	)
+}
+
+// ConnectFromEnv creates a new connection pool using the environment variables (see: ConfigFromEnv())
+func ConnectFromEnv(ctx context.Context, options ...redis.DialOption) (*Client, error) {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return ConnectWithConfig(ctx, config, options...)
+}
+
+// clientNameOptions are the names of the options returned by WithClientName() (by option, see: dialOptionKey())
+var clientNameOptions sync.Map
+
+// clientNameOption is an option returned by WithClientName() (the option is kept so its key is never reused)
+type clientNameOption struct {
+	name   string
+	option redis.DialOption
+}
+
+// WithClientName names each connection dialed by Connect() with the service and instance (see:
+// ServiceClientName()), so server-side diagnostics can attribute load per application (see: ListClients())
+// The name is set as Config.ClientName (it replaces the configured name), so each connection is named
+// once when dialed and the name can be changed later (see: SetConnectionName())
+// The instance defaults to the host name and process id
+func WithClientName(service, instance string) redis.DialOption {
+	name := ServiceClientName(service, instance)
+	option := redis.DialClientName(name) // Used as is outside of Connect() (IE: redis.Dial())
+	clientNameOptions.Store(dialOptionKey(option), &clientNameOption{name: name, option: option})
+	return option
+}
+
+// splitClientName removes the options returned by WithClientName() from the dial options, and
+// returns the name of the last one (empty if there is none)
+func splitClientName(options []redis.DialOption) (name string, rest []redis.DialOption) {
+	for _, option := range options {
+		if named, ok := clientNameOptions.Load(dialOptionKey(option)); ok {
+			name = named.(*clientNameOption).name
+			continue
+		}
+		rest = append(rest, option)
+	}
+	return
+}
+
+// dialOptionKey returns the identity of a dial option (the address of its function, which can not be
+// compared or read outside of redigo)
+func dialOptionKey(option redis.DialOption) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&option))
+}
+
+// ServiceClientName returns a connection name for the service and instance (see: Config.ClientName,
+// WithClientName(), SetConnectionName()), so server-side diagnostics can attribute load per application (see: ListClients())
+// The instance defaults to the host name and process id (IE: "billing:host-1:4242")
+func ServiceClientName(service, instance string) string {
+	if len(instance) == 0 {
+		host, _ := os.Hostname()
+		if len(host) == 0 {
+			host = "unknown"
+		}
+		instance = host + ":" + strconv.Itoa(os.Getpid())
+	}
+	return clientName(service + ":" + instance)
+}
+
+// clientName replaces the spaces that are not allowed in connection names
+func clientName(name string) string {
+	return strings.Join(strings.Fields(name), "-")
+}
+
+// ConfigFromEnv returns the configuration from the environment variables (IE: REDIS_URL)
+// Variables that are not set are left as the zero value
+func ConfigFromEnv() (config Config, err error) {
+	config.URL = os.Getenv(EnvURL)
+	config.ClientName = os.Getenv(EnvClientName)
+	if config.MaxActiveConnections, err = envInt(EnvMaxActive); err != nil {
+		return
+	}
+	if config.MaxIdleConnections, err = envInt(EnvMaxIdle); err != nil {
+		return
+	}
+	if config.MaxConnectionLifetime, err = envDuration(EnvMaxConnLifetime); err != nil {
+		return
+	}
+	if config.IdleTimeout, err = envDuration(EnvIdleTimeout); err != nil {
+		return
+	}
+	if config.MaxWait, err = envDuration(EnvMaxWait); err != nil {
+		return
+	}
+	if config.ConnectTimeout, err = envDuration(EnvConnectTimeout); err != nil {
+		return
+	}
+	if config.ReadTimeout, err = envDuration(EnvReadTimeout); err != nil {
+		return
+	}
+	if config.WriteTimeout, err = envDuration(EnvWriteTimeout); err != nil {
+		return
+	}
+	if config.DependencyMode, err = envBool(EnvDependencyMode); err != nil {
+		return
+	}
+	if config.NewRelicEnabled, err = envBool(EnvNewRelic); err != nil {
+		return
+	}
+	if config.TLS, err = envBool(EnvTLS); err != nil {
+		return
+	}
+	config.TLSSkipVerify, err = envBool(EnvTLSSkipVerify)
+	return
+}
+
+// envInt returns the environment variable as an int (0 if not set)
+func envInt(name string) (int, error) {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return i, nil
+}
+
+// envDuration returns the environment variable as a duration (0 if not set)
+func envDuration(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return d, nil
+}
+
+// envBool returns the environment variable as a bool (false if not set)
+func envBool(name string) (bool, error) {
+	value := os.Getenv(name)
+	if len(value) == 0 {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,286 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConfigFromEnv tests the method ConfigFromEnv()
+func TestConfigFromEnv(t *testing.T) {
+
+	t.Run("all variables", func(t *testing.T) {
+		t.Setenv(EnvURL, testLocalConnectionURL)
+		t.Setenv(EnvClientName, "billing:host-1")
+		t.Setenv(EnvMaxActive, "25")
+		t.Setenv(EnvMaxIdle, "10")
+		t.Setenv(EnvMaxConnLifetime, "60s")
+		t.Setenv(EnvIdleTimeout, "4m")
+		t.Setenv(EnvMaxWait, "500ms")
+		t.Setenv(EnvConnectTimeout, "5s")
+		t.Setenv(EnvReadTimeout, "1s")
+		t.Setenv(EnvWriteTimeout, "2s")
+		t.Setenv(EnvDependencyMode, "true")
+		t.Setenv(EnvNewRelic, "1")
+		t.Setenv(EnvTLS, "true")
+		t.Setenv(EnvTLSSkipVerify, "false")
+
+		config, err := ConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			ClientName:            "billing:host-1",
+			ConnectTimeout:        5 * time.Second,
+			DependencyMode:        true,
+			IdleTimeout:           4 * time.Minute,
+			MaxActiveConnections:  25,
+			MaxConnectionLifetime: time.Minute,
+			MaxIdleConnections:    10,
+			MaxWait:               500 * time.Millisecond,
+			NewRelicEnabled:       true,
+			ReadTimeout:           time.Second,
+			TLS:                   true,
+			URL:                   testLocalConnectionURL,
+			WriteTimeout:          2 * time.Second,
+		}, config)
+	})
+
+	t.Run("no variables", func(t *testing.T) {
+		for _, name := range []string{
+			EnvURL, EnvMaxActive, EnvMaxIdle, EnvMaxConnLifetime, EnvIdleTimeout, EnvMaxWait,
+			EnvDependencyMode, EnvNewRelic, EnvTLS, EnvTLSSkipVerify,
+			EnvConnectTimeout, EnvReadTimeout, EnvWriteTimeout, EnvClientName,
+		} {
+			t.Setenv(name, "")
+		}
+
+		config, err := ConfigFromEnv()
+		assert.NoError(t, err)
+		assert.Equal(t, Config{}, config)
+	})
+
+	t.Run("invalid variables", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			value string
+		}{
+			{EnvMaxActive, "lots"},
+			{EnvMaxIdle, "1.5"},
+			{EnvMaxConnLifetime, "60"},
+			{EnvIdleTimeout, "forever"},
+			{EnvMaxWait, "1"},
+			{EnvReadTimeout, "soon"},
+			{EnvDependencyMode, "yes please"},
+			{EnvTLS, "on"},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				t.Setenv(test.name, test.value)
+				_, err := ConfigFromEnv()
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.name)
+			})
+		}
+	})
+}
+
+// TestConfig_dialOptions tests the method dialOptions()
+func TestConfig_dialOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.Len(t, Config{URL: testLocalConnectionURL}.dialOptions(), 0)
+	assert.Len(t, Config{
+		ConnectTimeout: time.Second,
+		ReadTimeout:    time.Second,
+		URL:            testLocalConnectionURL,
+		WriteTimeout:   time.Second,
+	}.dialOptions(), 3)
+	assert.Len(t, Config{URL: "rediss://localhost:6379"}.dialOptions(), 2)
+}
+
+// TestConnectWithConfig tests the method ConnectWithConfig()
+func TestConnectWithConfig(t *testing.T) {
+
+	t.Run("valid connection", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithConfig(context.Background(), Config{
+			IdleTimeout:           testIdleTimeout,
+			MaxActiveConnections:  testMaxActiveConnections,
+			MaxConnectionLifetime: testMaxConnLifetime,
+			MaxIdleConnections:    testMaxIdleConnections,
+			URL:                   testLocalConnectionURL,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.NotNil(t, client.Pool)
+		assert.Equal(t, 0, len(client.ScriptsLoaded))
+
+		// Close
+		client.Close()
+	})
+
+	t.Run("missing url", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithConfig(context.Background(), Config{})
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("invalid client name", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithConfig(context.Background(), Config{
+			ClientName: "my service",
+			URL:        testLocalConnectionURL,
+		})
+		assert.ErrorIs(t, err, ErrInvalidConnectionName)
+		assert.Nil(t, client)
+	})
+
+	t.Run("named connections", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, err := ConnectWithConfig(context.Background(), Config{
+			ClientName: "billing:host-1",
+			URL:        testLocalConnectionURL,
+		})
+		assert.NoError(t, err)
+		defer client.Close()
+		assert.Equal(t, "billing:host-1", client.ConnectionName())
+
+		var conn redis.Conn
+		conn, err = client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		defer client.CloseConnection(conn)
+
+		var name string
+		name, err = redis.String(conn.Do(ClientCommand, "GETNAME"))
+		assert.NoError(t, err)
+		assert.Equal(t, "billing:host-1", name)
+	})
+}
+
+// TestWithClientName tests the method WithClientName()
+func TestWithClientName(t *testing.T) {
+
+	t.Run("split from the dial options", func(t *testing.T) {
+		t.Parallel()
+
+		timeout := redis.DialReadTimeout(time.Second)
+		name, rest := splitClientName([]redis.DialOption{
+			WithClientName("billing", "host-1"), timeout, WithClientName("billing", "host-2"),
+		})
+		assert.Equal(t, "billing:host-2", name)
+		assert.Len(t, rest, 1)
+
+		name, rest = splitClientName([]redis.DialOption{timeout, redis.DialClientName("other")})
+		assert.Empty(t, name)
+		assert.Len(t, rest, 2)
+	})
+
+	t.Run("named connections", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, err := Connect(
+			context.Background(), testLocalConnectionURL, testMaxActiveConnections, testMaxIdleConnections,
+			testMaxConnLifetime, testIdleTimeout, false, false, WithClientName("billing", "host-1"),
+		)
+		assert.NoError(t, err)
+		defer client.Close()
+		assert.Equal(t, "billing:host-1", client.ConnectionName())
+
+		var conn redis.Conn
+		conn, err = client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		defer client.CloseConnection(conn)
+
+		var name string
+		name, err = redis.String(conn.Do(ClientCommand, "GETNAME"))
+		assert.NoError(t, err)
+		assert.Equal(t, "billing:host-1", name)
+	})
+}
+
+// TestServiceClientName tests the method ServiceClientName()
+func TestServiceClientName(t *testing.T) {
+
+	t.Run("default instance", func(t *testing.T) {
+		t.Parallel()
+
+		name := ServiceClientName("billing service", "")
+		assert.True(t, strings.HasPrefix(name, "billing-service:"))
+		assert.True(t, strings.HasSuffix(name, ":"+strconv.Itoa(os.Getpid())))
+		assert.NotContains(t, name, " ")
+	})
+
+	t.Run("named instance", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "billing:host-1", ServiceClientName("billing", "host-1"))
+		assert.Equal(t, "billing:host-1", ServiceClientName("billing", "host 1"))
+	})
+}
+
+// ExampleConnectWithConfig is an example of the method ConnectWithConfig()
+func ExampleConnectWithConfig() {
+
+	client, _ := ConnectWithConfig(context.Background(), Config{
+		IdleTimeout:           testIdleTimeout,
+		MaxActiveConnections:  testMaxActiveConnections,
+		MaxConnectionLifetime: testMaxConnLifetime,
+		MaxIdleConnections:    testMaxIdleConnections,
+		URL:                   testLocalConnectionURL,
+	})
+
+	// Close connections at end of request
+	defer client.Close()
+
+	fmt.Printf("connected")
+	// Output:connected
+}
+
+// TestConnectFromEnv tests the method ConnectFromEnv()
+func TestConnectFromEnv(t *testing.T) {
+
+	t.Run("valid connection", func(t *testing.T) {
+		t.Setenv(EnvURL, testLocalConnectionURL)
+		t.Setenv(EnvMaxIdle, "10")
+
+		client, err := ConnectFromEnv(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		assert.NotNil(t, client.Pool)
+
+		// Close
+		client.Close()
+	})
+
+	t.Run("missing url", func(t *testing.T) {
+		t.Setenv(EnvURL, "")
+
+		client, err := ConnectFromEnv(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("invalid variable", func(t *testing.T) {
+		t.Setenv(EnvURL, testLocalConnectionURL)
+		t.Setenv(EnvMaxActive, "-")
+
+		client, err := ConnectFromEnv(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+}
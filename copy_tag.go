@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// copyTagBatchSize is the number of member keys dumped and restored per round trip
+const copyTagBatchSize = 100
+
+// TagCopy is the result of copying a dependency tag (see: CopyTag())
+type TagCopy struct {
+	Keys    int // Member keys copied (with their TTLs)
+	Members int // Members of the dependency set (across all shards)
+	Missing int // Member keys that no longer exist on the source (IE: expired)
+}
+
+// CopyTag copies the dependency set of the tag (and, with copyKeys, its member keys with their TTLs)
+// from the source to the destination redis (IE: reproducing a production cache state in staging
+// while debugging invalidation issues); existing keys on the destination are replaced
+//
+// The copy is not atomic, keys expiring during the copy are counted as missing
+// The shards of the tag on the source are copied as they are (see: SetDependencyShards())
+// Creates new connections and closes connections at end of function call
+// Reads from a replica of the source if configured (see: ConnectReplicas())
+//
+// Custom connections use method: CopyTagRaw()
+func CopyTag(ctx context.Context, src, dst *Client, tag string, copyKeys bool) (result *TagCopy, err error) {
+	if dst == nil {
+		return nil, errors.New("missing required parameter: dst")
+	}
+	dstConn, err := dst.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer dst.CloseConnection(dstConn)
+
+	err = src.read(ctx, func(srcConn redis.Conn) (readErr error) {
+		result, readErr = CopyTagRaw(srcConn, dstConn, tag, copyKeys)
+		return
+	})
+	return
+}
+
+// CopyTagRaw copies the dependency set of the tag (and, with copyKeys, its member keys with their TTLs)
+// from the source to the destination connection; existing keys on the destination are replaced
+// Uses existing connections (does not close connections)
+//
+// Commands used:
+// https://redis.io/commands/smembers
+// https://redis.io/commands/sadd
+// https://redis.io/commands/pttl
+// https://redis.io/commands/dump
+// https://redis.io/commands/restore
+func CopyTagRaw(srcConn, dstConn redis.Conn, tag string, copyKeys bool) (*TagCopy, error) {
+	if len(tag) == 0 {
+		return nil, errors.New("missing required parameter: tag")
+	}
+
+	result := new(TagCopy)
+	for _, set := range dependencySetKeys(srcConn, tag) {
+		members, err := SetMembersRaw(srcConn, set)
+		if err != nil {
+			return result, err
+		} else if len(members) == 0 {
+			continue
+		}
+		result.Members += len(members)
+
+		if copyKeys {
+			for start := 0; start < len(members); start += copyTagBatchSize {
+				end := start + copyTagBatchSize
+				if end > len(members) {
+					end = len(members)
+				}
+				if err = copyKeysRaw(srcConn, dstConn, members[start:end], result); err != nil {
+					return result, err
+				}
+			}
+		}
+		if _, err = dstConn.Do(AddToSetCommand, redis.Args{}.Add(set).AddFlat(members)...); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// copyKeysRaw dumps the keys (with their TTLs) from the source and restores them on the destination
+func copyKeysRaw(srcConn, dstConn redis.Conn, keys []string, result *TagCopy) error {
+	for _, key := range keys {
+		if err := srcConn.Send(TTLMillisCommand, key); err != nil {
+			return err
+		}
+		if err := srcConn.Send(DumpCommand, key); err != nil {
+			return err
+		}
+	}
+	replies, err := flushPipeline(srcConn)
+	if err != nil {
+		return err
+	}
+
+	restored := 0
+	for i, key := range keys {
+		var ttl int64
+		if ttl, err = redis.Int64(replies[i*2], nil); err != nil {
+			return err
+		}
+		var dump []byte
+		if dump, err = redis.Bytes(replies[i*2+1], nil); errors.Is(err, redis.ErrNil) {
+			result.Missing++
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		// A TTL of 0 never expires (-1 is a key without an expiration)
+		if ttl < 0 {
+			ttl = 0
+		}
+		if err = dstConn.Send(RestoreCommand, key, ttl, dump, ReplaceArgument); err != nil {
+			return err
+		}
+		restored++
+	}
+	if restored == 0 {
+		return nil
+	}
+	if _, err = flushPipeline(dstConn); err != nil {
+		return err
+	}
+	result.Keys += restored
+	return nil
+}
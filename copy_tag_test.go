@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCopyTag tests the method CopyTag()
+func TestCopyTag(t *testing.T) {
+
+	t.Run("missing parameters", func(t *testing.T) {
+		t.Parallel()
+
+		src, srcConn := loadMockRedis()
+		defer src.CloseAll(srcConn)
+
+		_, err := CopyTag(context.Background(), src, nil, testDependantKey, true)
+		assert.Error(t, err)
+
+		_, err = CopyTag(context.Background(), src, src, "", true)
+		assert.Error(t, err)
+	})
+
+	t.Run("copy using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		src, srcConn := loadMockRedis()
+		defer src.CloseAll(srcConn)
+		dst, dstConn := loadMockRedis()
+		defer dst.CloseAll(dstConn)
+
+		srcConn.Command(MembersCommand, DependencyPrefix+testDependantKey).Expect([]interface{}{
+			[]byte("key-1"), []byte("key-2"), []byte("key-3"),
+		})
+		srcConn.Command(TTLMillisCommand, "key-1").Expect(int64(60000))
+		srcConn.Command(DumpCommand, "key-1").Expect([]byte("dump-1"))
+		srcConn.Command(TTLMillisCommand, "key-2").Expect(int64(-1))
+		srcConn.Command(DumpCommand, "key-2").Expect([]byte("dump-2"))
+		srcConn.Command(TTLMillisCommand, "key-3").Expect(int64(-2))
+		srcConn.Command(DumpCommand, "key-3").Expect(nil)
+
+		restore1 := dstConn.Command(RestoreCommand, "key-1", int64(60000), []byte("dump-1"), ReplaceArgument).Expect("OK")
+		restore2 := dstConn.Command(RestoreCommand, "key-2", int64(0), []byte("dump-2"), ReplaceArgument).Expect("OK")
+		addCmd := dstConn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, "key-1", "key-2", "key-3").
+			Expect(int64(3))
+
+		result, err := CopyTag(context.Background(), src, dst, testDependantKey, true)
+		assert.NoError(t, err)
+		assert.Equal(t, &TagCopy{Keys: 2, Members: 3, Missing: 1}, result)
+		assert.True(t, restore1.Called)
+		assert.True(t, restore2.Called)
+		assert.True(t, addCmd.Called)
+	})
+
+	t.Run("copy set only using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		src, srcConn := loadMockRedis()
+		defer src.CloseAll(srcConn)
+		dst, dstConn := loadMockRedis()
+		defer dst.CloseAll(dstConn)
+
+		srcConn.Command(MembersCommand, DependencyPrefix+testDependantKey).Expect([]interface{}{[]byte("key-1")})
+		dumpCmd := srcConn.Command(DumpCommand, "key-1").Expect([]byte("dump-1"))
+		addCmd := dstConn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, "key-1").Expect(int64(1))
+
+		result, err := CopyTag(context.Background(), src, dst, testDependantKey, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &TagCopy{Members: 1}, result)
+		assert.False(t, dumpCmd.Called)
+		assert.True(t, addCmd.Called)
+	})
+
+	t.Run("copy using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		src, srcConn, err := loadRealRedis()
+		assert.NotNil(t, src)
+		assert.NoError(t, err)
+		defer src.CloseAll(srcConn)
+
+		err = clearRealRedis(srcConn)
+		assert.NoError(t, err)
+
+		// Another database as the destination
+		var dst *Client
+		dst, err = Connect(context.Background(), testLocalConnectionURL+"/1", testMaxActiveConnections,
+			testMaxIdleConnections, testMaxConnLifetime, testIdleTimeout, false, false)
+		assert.NoError(t, err)
+		var dstConn redis.Conn
+		dstConn, err = dst.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		defer dst.CloseAll(dstConn)
+		err = clearRealRedis(dstConn)
+		assert.NoError(t, err)
+
+		err = SetExpRaw(srcConn, "key-1", testStringValue, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+		err = SetRaw(srcConn, "key-2", testStringValue, testDependantKey)
+		assert.NoError(t, err)
+
+		var result *TagCopy
+		result, err = CopyTag(context.Background(), src, dst, testDependantKey, true)
+		assert.NoError(t, err)
+		assert.Equal(t, &TagCopy{Keys: 2, Members: 2}, result)
+
+		var value string
+		value, err = GetRaw(dstConn, "key-2")
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var info []KeyInfo
+		info, err = KeyInfoMultiRaw(dstConn, "key-1")
+		assert.NoError(t, err)
+		assert.Greater(t, info[0].TTL, 50*time.Second)
+
+		var members []string
+		members, err = SetMembersRaw(dstConn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"key-1", "key-2"}, members)
+	})
+}
+
+// ExampleCopyTag is an example of the method CopyTag()
+func ExampleCopyTag() {
+	// Load mocked redis servers for testing/examples
+	src, srcConn := loadMockRedis()
+	defer src.CloseAll(srcConn)
+	dst, dstConn := loadMockRedis()
+	defer dst.CloseAll(dstConn)
+
+	// Mock the dependency set on the source
+	srcConn.Command(MembersCommand, DependencyPrefix+"user-1").Expect([]interface{}{[]byte("key-1")})
+	dstConn.Command(AddToSetCommand, DependencyPrefix+"user-1", "key-1").Expect(int64(1))
+
+	result, _ := CopyTag(context.Background(), src, dst, "user-1", false)
+	fmt.Printf("copied %d members", result.Members)
+	// Output:copied 1 members
+}
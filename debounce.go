@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Package constants (debounce and signal key prefixes)
+const (
+	DebouncePrefix = "debounce:"
+	SignalPrefix   = "signal:"
+)
+
+// Debounce returns true only for the first call for the key within the window, all the other calls
+// return false until the window ends (IE: collapse a burst of rebuilds triggered by invalidations)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DebounceRaw()
+func Debounce(ctx context.Context, client *Client, key string, window time.Duration) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return DebounceRaw(conn, key, window)
+}
+
+// DebounceRaw returns true only for the first call for the key within the window
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func DebounceRaw(conn redis.Conn, key string, window time.Duration) (bool, error) {
+	if window < time.Millisecond {
+		return false, ErrInvalidTTL
+	}
+	_, err := redis.String(conn.Do(
+		SetCommand, DebouncePrefix+key, 1,
+		SetIfNotExistsArgument, ExpireMillisArgument, window.Milliseconds(),
+	))
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CoalesceSignal records pending work for the key, returns true if no work was pending already
+// Any number of signals before the work is taken (see: TakeSignal()) are coalesced into one
+// (IE: many invalidations trigger a single rebuild by a worker)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CoalesceSignalRaw()
+func CoalesceSignal(ctx context.Context, client *Client, key string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return CoalesceSignalRaw(conn, key)
+}
+
+// CoalesceSignalRaw records pending work for the key, returns true if no work was pending already
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func CoalesceSignalRaw(conn redis.Conn, key string) (bool, error) {
+	_, err := redis.String(conn.Do(SetCommand, SignalPrefix+key, 1, SetIfNotExistsArgument))
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TakeSignal takes the pending work for the key, returns true for only one caller per
+// coalesced signal (see: CoalesceSignal())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: TakeSignalRaw()
+func TakeSignal(ctx context.Context, client *Client, key string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return TakeSignalRaw(conn, key)
+}
+
+// TakeSignalRaw takes the pending work for the key, returns true for only one caller per
+// coalesced signal
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/del
+func TakeSignalRaw(conn redis.Conn, key string) (bool, error) {
+	removed, err := redis.Int(conn.Do(DeleteCommand, SignalPrefix+key))
+	return removed > 0, err
+}
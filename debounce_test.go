@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDebounce tests the method Debounce()
+func TestDebounce(t *testing.T) {
+
+	t.Run("invalid window", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := Debounce(context.Background(), client, testKey, 0)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("debounce using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, DebouncePrefix+testKey, 1, SetIfNotExistsArgument, ExpireMillisArgument, int64(500)).
+			Expect("OK")
+
+		first, err := Debounce(context.Background(), client, testKey, 500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, first)
+
+		conn.Command(SetCommand, DebouncePrefix+testKey, 1, SetIfNotExistsArgument, ExpireMillisArgument, int64(500)).
+			Expect(nil)
+
+		first, err = Debounce(context.Background(), client, testKey, 500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.False(t, first)
+	})
+
+	t.Run("debounce using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var first bool
+		first, err = DebounceRaw(conn, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, first)
+
+		first, err = DebounceRaw(conn, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, first)
+
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do("PTTL", DebouncePrefix+testKey))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+	})
+}
+
+// TestCoalesceSignal tests the methods CoalesceSignal() and TakeSignal()
+func TestCoalesceSignal(t *testing.T) {
+
+	t.Run("signal using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, SignalPrefix+testKey, 1, SetIfNotExistsArgument).Expect("OK")
+		conn.Command(DeleteCommand, SignalPrefix+testKey).Expect(int64(1))
+
+		pending, err := CoalesceSignal(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.True(t, pending)
+
+		var taken bool
+		taken, err = TakeSignal(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.True(t, taken)
+	})
+
+	t.Run("signals using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// A burst of signals is coalesced
+		var first int
+		for i := 0; i < 5; i++ {
+			var pending bool
+			pending, err = CoalesceSignalRaw(conn, testKey)
+			assert.NoError(t, err)
+			if pending {
+				first++
+			}
+		}
+		assert.Equal(t, 1, first)
+
+		// Taken once
+		var taken bool
+		taken, err = TakeSignalRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.True(t, taken)
+		taken, err = TakeSignalRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, taken)
+	})
+}
+
+// ExampleDebounce is an example of the method Debounce()
+func ExampleDebounce() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the first call in the window
+	conn.Command(SetCommand, DebouncePrefix+"rebuild:user-1", 1, SetIfNotExistsArgument, ExpireMillisArgument,
+		int64(1000)).Expect("OK")
+
+	// Only rebuild once per second
+	if first, _ := Debounce(context.Background(), client, "rebuild:user-1", time.Second); first {
+		fmt.Print("rebuilding")
+	}
+	// Output:rebuilding
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// defaultScanCount is the default number of keys per SCAN (and per UNLINK in DeleteByPattern())
+const defaultScanCount = 100
+
+// ErrMissingPattern is returned when DeleteByPattern() is called without a pattern
+var ErrMissingPattern = errors.New("missing required parameter: pattern (use DestroyCache() to remove all keys)")
+
+// ErrMaxKeysReached is returned when DeleteByPattern() stopped at the max keys
+var ErrMaxKeysReached = errors.New("max keys reached, not all matching keys were deleted")
+
+// DeletePatternOptions are the options for DeleteByPattern()
+type DeletePatternOptions struct {
+	BatchSize  int               // Keys per SCAN and UNLINK (default: 100)
+	MaxKeys    int               // Stop after deleting this many keys and return ErrMaxKeysReached (0 is no limit)
+	OnProgress func(deleted int) // Fired after each batch with the total keys deleted so far (optional)
+}
+
+// DeleteByPattern removes all keys matching the pattern (IE: user:123:*) in batches
+// Keys are found with SCAN (does not block the server like KEYS) and removed with UNLINK (redis 4+)
+// Dependencies are not followed (see: KillByDependency())
+// Returns the number of keys deleted
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DeleteByPatternRaw()
+func DeleteByPattern(ctx context.Context, client *Client, pattern string, options DeletePatternOptions) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return DeleteByPatternRaw(conn, pattern, options)
+}
+
+// DeleteByPatternRaw removes all keys matching the pattern (IE: user:123:*) in batches
+// Keys are found with SCAN (does not block the server like KEYS) and removed with UNLINK (redis 4+)
+// Dependencies are not followed (see: KillByDependency())
+// Returns the number of keys deleted
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/scan
+// https://redis.io/commands/unlink
+func DeleteByPatternRaw(conn redis.Conn, pattern string, options DeletePatternOptions) (deleted int, err error) {
+	if len(pattern) == 0 {
+		return 0, ErrMissingPattern
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultScanCount
+	}
+
+	cursor := int64(0)
+	for {
+		var keys []string
+		if cursor, keys, err = scanRaw(conn, cursor, pattern, options.BatchSize); err != nil {
+			return
+		}
+
+		// Safety limit
+		limited := false
+		if options.MaxKeys > 0 && deleted+len(keys) > options.MaxKeys {
+			keys = keys[:options.MaxKeys-deleted]
+			limited = true
+		}
+
+		if len(keys) > 0 {
+			var count int
+			if count, err = redis.Int(conn.Do(UnlinkCommand, redis.Args{}.AddFlat(keys)...)); err != nil {
+				return
+			}
+			deleted += count
+			if options.OnProgress != nil {
+				options.OnProgress(deleted)
+			}
+		}
+
+		if limited {
+			return deleted, ErrMaxKeysReached
+		} else if cursor == 0 {
+			return
+		}
+	}
+}
+
+// scanRaw returns the next cursor and the keys matching the pattern
+//
+// Spec: https://redis.io/commands/scan
+func scanRaw(conn redis.Conn, cursor int64, pattern string, count int) (int64, []string, error) {
+	values, err := redis.Values(conn.Do(ScanCommand, cursor, MatchArgument, pattern, CountArgument, count))
+	if err != nil {
+		return 0, nil, err
+	} else if len(values) != 2 {
+		return 0, nil, fmt.Errorf("unexpected scan reply with %d elements", len(values))
+	}
+	var keys []string
+	if cursor, err = redis.Int64(values[0], nil); err != nil {
+		return 0, nil, err
+	}
+	if keys, err = redis.Strings(values[1], nil); err != nil {
+		return 0, nil, err
+	}
+	return cursor, keys, nil
+}
@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDeleteByPattern tests the method DeleteByPattern()
+func TestDeleteByPattern(t *testing.T) {
+
+	t.Run("missing pattern", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		deleted, err := DeleteByPattern(context.Background(), client, "", DeletePatternOptions{})
+		assert.ErrorIs(t, err, ErrMissingPattern)
+		assert.Equal(t, 0, deleted)
+	})
+
+	t.Run("delete in batches using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ScanCommand, int64(0), MatchArgument, "user:*", CountArgument, 2).
+			Expect([]interface{}{[]byte("7"), []interface{}{[]byte("user:1"), []byte("user:2")}})
+		conn.Command(ScanCommand, int64(7), MatchArgument, "user:*", CountArgument, 2).
+			Expect([]interface{}{[]byte("0"), []interface{}{[]byte("user:3")}})
+		conn.Command(UnlinkCommand, "user:1", "user:2").Expect(int64(2))
+		conn.Command(UnlinkCommand, "user:3").Expect(int64(1))
+
+		var progress []int
+		deleted, err := DeleteByPattern(context.Background(), client, "user:*", DeletePatternOptions{
+			BatchSize: 2,
+			OnProgress: func(deleted int) {
+				progress = append(progress, deleted)
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+		assert.Equal(t, []int{2, 3}, progress)
+	})
+
+	t.Run("stops at the max keys", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ScanCommand, int64(0), MatchArgument, "user:*", CountArgument, 100).
+			Expect([]interface{}{[]byte("7"), []interface{}{[]byte("user:1"), []byte("user:2")}})
+		unlinkCmd := conn.Command(UnlinkCommand, "user:1").Expect(int64(1))
+
+		deleted, err := DeleteByPattern(context.Background(), client, "user:*", DeletePatternOptions{MaxKeys: 1})
+		assert.ErrorIs(t, err, ErrMaxKeysReached)
+		assert.Equal(t, 1, deleted)
+		assert.True(t, unlinkCmd.Called)
+	})
+
+	t.Run("delete by pattern using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		for i := 0; i < 25; i++ {
+			err = SetRaw(conn, fmt.Sprintf("user:%d", i), testStringValue)
+			assert.NoError(t, err)
+		}
+		err = SetRaw(conn, "other", testStringValue)
+		assert.NoError(t, err)
+
+		var deleted int
+		deleted, err = DeleteByPatternRaw(conn, "user:*", DeletePatternOptions{BatchSize: 10})
+		assert.NoError(t, err)
+		assert.Equal(t, 25, deleted)
+
+		var keys []string
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"other"}, keys)
+	})
+}
+
+// ExampleDeleteByPattern is an example of the method DeleteByPattern()
+func ExampleDeleteByPattern() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the scan and unlink commands
+	conn.Command(ScanCommand, int64(0), MatchArgument, "user:*", CountArgument, 100).
+		Expect([]interface{}{[]byte("0"), []interface{}{[]byte("user:1"), []byte("user:2")}})
+	conn.Command(UnlinkCommand, "user:1", "user:2").Expect(int64(2))
+
+	// Remove all the keys with the prefix
+	deleted, _ := DeleteByPattern(context.Background(), client, "user:*", DeletePatternOptions{})
+	fmt.Printf("deleted: %d", deleted)
+	// Output:deleted: 2
+}
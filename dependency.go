@@ -3,11 +3,63 @@ package cache
 import (
 	"context"
 	"errors"
+	"strconv"
 
 	"github.com/gomodule/redigo/redis"
 )
 
+// DeleteError is the error for a delete where some of the keys (or dependencies) failed to be removed
+// The keys that did not fail are still removed (see: Deleted)
+type DeleteError struct {
+	Deleted int      // Keys removed (from the Redis replies)
+	Err     error    // First error from Redis
+	Failed  []string // Keys (or dependencies) that failed to be removed
+}
+
+// Error returns the error message
+func (e *DeleteError) Error() string {
+	return "failed to delete " + strconv.Itoa(len(e.Failed)) + " key(s), deleted " +
+		strconv.Itoa(e.Deleted) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *DeleteError) Unwrap() error {
+	return e.Err
+}
+
+// deleteFailures collects the keys that failed to be removed
+type deleteFailures struct {
+	err    error
+	failed []string
+	seen   map[string]struct{}
+}
+
+// add records the keys that failed (once each) and the first error
+func (f *deleteFailures) add(err error, keys ...string) {
+	if f.err == nil {
+		f.err = err
+	}
+	for _, key := range keys {
+		if f.seen == nil {
+			f.seen = make(map[string]struct{})
+		}
+		if _, ok := f.seen[key]; !ok {
+			f.seen[key] = struct{}{}
+			f.failed = append(f.failed, key)
+		}
+	}
+}
+
+// error returns a *DeleteError if any key failed, otherwise nil
+func (f *deleteFailures) error(deleted int) error {
+	if f.err == nil {
+		return nil
+	}
+	return &DeleteError{Deleted: deleted, Err: f.err, Failed: f.failed}
+}
+
 // Delete is an alias for KillByDependency()
+// Writes tombstones for the removed keys if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: DeleteRaw()
@@ -18,7 +70,13 @@ func Delete(ctx context.Context, client *Client, keys ...string) (total int, err
 		return
 	}
 	defer client.CloseConnection(conn)
-	return DeleteRaw(conn, keys...)
+	if window := client.InvalidationTombstones(); window > 0 {
+		total, err = KillByDependencyWithTombstonesRaw(conn, window, keys...)
+	} else {
+		total, err = DeleteRaw(conn, keys...)
+	}
+	client.fireRemoveHooks(ctx, OperationDelete, keys, total, err)
+	return
 }
 
 // DeleteRaw is an alias for KillByDependency()
@@ -29,7 +87,9 @@ func DeleteRaw(conn redis.Conn, keys ...string) (total int, err error) {
 
 // KillByDependency removes all keys which are listed as depending on the key(s)
 // Alias: Delete()
+// Writes tombstones for the removed keys if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
+// Huge dependency sets may need a longer timeout (see: WithCommandTimeout())
 //
 // Custom connections use method: KillByDependencyRaw()
 //
@@ -42,11 +102,20 @@ func KillByDependency(ctx context.Context, client *Client, keys ...string) (int,
 		return 0, err
 	}
 	defer client.CloseConnection(conn)
-	return KillByDependencyRaw(conn, keys...)
+	var total int
+	if window := client.InvalidationTombstones(); window > 0 {
+		total, err = KillByDependencyWithTombstonesRaw(conn, window, keys...)
+	} else {
+		total, err = KillByDependencyRaw(conn, keys...)
+	}
+	client.fireRemoveHooks(ctx, OperationInvalidate, keys, total, err)
+	return total, err
 }
 
 // KillByDependencyRaw removes all keys which are listed as depending on the key(s)
 // Alias: Delete()
+// The total is from the DEL replies, dependencies that failed are returned in a *DeleteError
+// and the other dependencies are still removed
 //
 // Commands used:
 // https://redis.io/commands/eval
@@ -58,35 +127,94 @@ func KillByDependencyRaw(conn redis.Conn, keys ...string) (total int, err error)
 		return
 	}
 
-	// Create the arguments
-	args := make([]interface{}, len(keys)+2)
+	// Create the arguments (sharded dependencies are removed one shard at a time)
+	args := make([]interface{}, 2, len(keys)+2)
 	deleteArgs := make([]interface{}, len(keys))
 
 	args[0] = killByDependencySha
 	args[1] = 0
 
 	// Loop keys
+	var scripted []string
+	var shards, shardKeys []string
 	for i, key := range keys {
-		args[i+2] = DependencyPrefix + key
+		if sets := dependencySetKeys(conn, key); len(sets) == 1 {
+			args = append(args, sets[0])
+			scripted = append(scripted, key)
+		} else {
+			for _, set := range sets {
+				shards = append(shards, set)
+				shardKeys = append(shardKeys, key)
+			}
+		}
 		deleteArgs[i] = key
 	}
 
-	// Run the script
-	if total, err = redis.Int(conn.Do(EvalCommand, args...)); err != nil {
-		return
+	// Run the script (totals are the DEL replies, a failure does not stop the other keys)
+	var failures deleteFailures
+	if len(args) > 2 {
+		var removed int
+		if removed, err = redis.Int(conn.Do(EvalCommand, args...)); err != nil {
+			failures.add(newScriptError(err, killByDependencyName, killByDependencySha, 0), scripted...)
+		}
+		total += removed
+	}
+	for i, shard := range shards {
+		var removed int
+		if removed, err = redis.Int(conn.Do(EvalCommand, killByDependencySha, 0, shard)); err != nil {
+			failures.add(newScriptError(err, killByDependencyName, killByDependencySha, 0), shardKeys[i])
+		}
+		total += removed
 	}
 
 	// Fire the delete command
 	var deleted int
 	if deleted, err = redis.Int(conn.Do(DeleteCommand, deleteArgs...)); err != nil {
-		return
+		failures.add(err, keys...)
 	}
 	total += deleted
 
-	return
+	return total, failures.error(total)
 }
 
-// linkDependencies links any dependencies
+// LinkDependencies keeps a reference to the key from each dependency, so the key is removed
+// by KillByDependency() (IE: for keys written with custom commands)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: LinkDependenciesRaw()
+func LinkDependencies(ctx context.Context, client *Client, key string, dependencies ...string) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return LinkDependenciesRaw(conn, key, dependencies...)
+}
+
+// LinkDependenciesRaw keeps a reference to the key from each dependency, so the key is removed
+// by KillByDependency() (IE: for keys written with custom commands)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func LinkDependenciesRaw(conn redis.Conn, key string, dependencies ...string) error {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return err
+	}
+	return linkDependencies(conn, key, dependencies...)
+}
+
+// SendLinkDependencies appends the dependency links for the key to a pipeline or transaction
+// composed by the caller (the commands are only sent, the caller flushes or fires EXEC)
+//
+// Spec: https://redis.io/commands/sadd
+func SendLinkDependencies(conn redis.Conn, key string, dependencies ...string) error {
+	return sendLinkDependencies(conn, key, dependencies...)
+}
+
+// linkDependencies links any dependencies (skipped if the connection skips them, see: SetSkipDependencies())
 //
 // Commands used:
 // https://redis.io/commands/multi
@@ -95,7 +223,7 @@ func KillByDependencyRaw(conn redis.Conn, keys ...string) (total int, err error)
 func linkDependencies(conn redis.Conn, key interface{}, dependencies ...string) (err error) {
 
 	// No dependencies given
-	if len(dependencies) == 0 {
+	if len(dependencies) == 0 || skipsDependencies(conn) {
 		return
 	}
 
@@ -105,10 +233,8 @@ func linkDependencies(conn redis.Conn, key interface{}, dependencies ...string)
 	}
 
 	// Add all to the set
-	for _, dependency := range dependencies {
-		if err = conn.Send(AddToSetCommand, DependencyPrefix+dependency, key); err != nil {
-			return
-		}
+	if err = sendLinkDependencies(conn, key, dependencies...); err != nil {
+		return
 	}
 
 	// Fire the exec command (ignore nil error response?)
@@ -118,3 +244,38 @@ func linkDependencies(conn redis.Conn, key interface{}, dependencies ...string)
 	}
 	return
 }
+
+// doLinkDependencies runs the write command and links any dependencies once the write succeeded
+//
+// A failed write returns its error and links nothing (the MULTI/SADD/EXEC is only sent after the reply)
+func doLinkDependencies(conn redis.Conn, key interface{}, dependencies []string,
+	commandName string, args ...interface{}) (err error) {
+
+	// Check the dependency sets before anything is sent
+	link := len(dependencies) > 0 && !skipsDependencies(conn)
+	if link {
+		if err = checkDependencyLimit(conn, dependencies...); err != nil {
+			return
+		}
+	}
+
+	// Fire the write, then link the dependencies
+	if _, err = conn.Do(commandName, args...); err != nil || !link {
+		return
+	}
+	return linkDependencies(conn, key, dependencies...)
+}
+
+// sendLinkDependencies sends the command to add the key to the set of each dependency
+// (skipped if the connection skips them, see: SetSkipDependencies())
+func sendLinkDependencies(conn redis.Conn, key interface{}, dependencies ...string) (err error) {
+	if skipsDependencies(conn) {
+		return
+	}
+	for _, dependency := range dependencies {
+		if err = conn.Send(AddToSetCommand, dependencySetKey(conn, dependency, key), key); err != nil {
+			return
+		}
+	}
+	return
+}
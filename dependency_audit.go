@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// DependencyAudit are the statistics of the dependency sets (see: AuditDependencies())
+type DependencyAudit struct {
+	DanglingMembers int // Members of a dependency set whose key no longer exists (IE: expired)
+	Members         int // Total members across all dependency sets
+	OrphanedSets    int // Dependency sets without any existing member
+	Sets            int // Total dependency sets
+}
+
+// AuditDependencies scans all dependency sets and checks if each member still exists
+// Nothing is modified, the audit only reports (huge key spaces may need a longer timeout)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: AuditDependenciesRaw()
+func AuditDependencies(ctx context.Context, client *Client) (audit *DependencyAudit, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		audit, readErr = AuditDependenciesRaw(conn)
+		return
+	})
+	return
+}
+
+// AuditDependenciesRaw scans all dependency sets and checks if each member still exists
+// Nothing is modified, the audit only reports
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/scan
+// https://redis.io/commands/smembers
+// https://redis.io/commands/exists
+func AuditDependenciesRaw(conn redis.Conn) (*DependencyAudit, error) {
+	audit := new(DependencyAudit)
+	cursor := int64(0)
+	for {
+		var sets []string
+		var err error
+		if cursor, sets, err = scanRaw(conn, cursor, DependencyPrefix+"*", defaultScanCount); err != nil {
+			return nil, err
+		}
+
+		for _, set := range sets {
+			var members []string
+			if members, err = SetMembersRaw(conn, set); err != nil {
+				return nil, err
+			}
+			var found int
+			if found, _, err = ExistsMultiRaw(conn, members...); err != nil {
+				return nil, err
+			}
+
+			audit.Sets++
+			audit.Members += len(members)
+			audit.DanglingMembers += len(members) - found
+			if found == 0 {
+				audit.OrphanedSets++
+			}
+		}
+
+		if cursor == 0 {
+			return audit, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAuditDependencies tests the method AuditDependencies()
+func TestAuditDependencies(t *testing.T) {
+
+	t.Run("audit using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ScanCommand, int64(0), MatchArgument, DependencyPrefix+"*", CountArgument, 100).
+			Expect([]interface{}{[]byte("0"), []interface{}{
+				[]byte(DependencyPrefix + "user-1"), []byte(DependencyPrefix + "user-2"),
+			}})
+		conn.Command(MembersCommand, DependencyPrefix+"user-1").
+			Expect([]interface{}{[]byte("key-1"), []byte("key-2")})
+		conn.Command(MembersCommand, DependencyPrefix+"user-2").
+			Expect([]interface{}{[]byte("key-3")})
+		conn.Command(ExistsCommand, "key-1").Expect(int64(1))
+		conn.Command(ExistsCommand, "key-2").Expect(int64(0))
+		conn.Command(ExistsCommand, "key-3").Expect(int64(0))
+
+		audit, err := AuditDependencies(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Equal(t, &DependencyAudit{
+			DanglingMembers: 2,
+			Members:         3,
+			OrphanedSets:    1,
+			Sets:            2,
+		}, audit)
+	})
+
+	t.Run("audit using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, "key-1", testStringValue, "user-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "key-2", testStringValue, "user-1", "user-2")
+		assert.NoError(t, err)
+
+		// Removed without the dependency (IE: expired)
+		_, err = DeleteWithoutDependencyRaw(conn, "key-2")
+		assert.NoError(t, err)
+
+		var audit *DependencyAudit
+		audit, err = AuditDependenciesRaw(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, &DependencyAudit{
+			DanglingMembers: 2,
+			Members:         3,
+			OrphanedSets:    1,
+			Sets:            2,
+		}, audit)
+
+		// Nothing was modified
+		var found bool
+		found, err = ExistsRaw(conn, DependencyPrefix+"user-2")
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+}
+
+// ExampleAuditDependencies is an example of the method AuditDependencies()
+func ExampleAuditDependencies() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a dependency set with an expired member
+	conn.Command(ScanCommand, int64(0), MatchArgument, DependencyPrefix+"*", CountArgument, 100).
+		Expect([]interface{}{[]byte("0"), []interface{}{[]byte(DependencyPrefix + "user-1")}})
+	conn.Command(MembersCommand, DependencyPrefix+"user-1").Expect([]interface{}{[]byte("key-1")})
+	conn.Command(ExistsCommand, "key-1").Expect(int64(0))
+
+	audit, _ := AuditDependencies(context.Background(), client)
+	fmt.Printf("sets: %d dangling: %d orphaned: %d", audit.Sets, audit.DanglingMembers, audit.OrphanedSets)
+	// Output:sets: 1 dangling: 1 orphaned: 1
+}
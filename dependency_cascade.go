@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// maxDependencyDepth is the most levels a cascading invalidation follows (see: InvalidationTrace.Truncated)
+const maxDependencyDepth = 10
+
+// InvalidationTrace is the report of a cascading invalidation (see: KillByDependencyCascade())
+type InvalidationTrace struct {
+	Duration  time.Duration       // How long the invalidation took
+	Levels    []InvalidationLevel // Each level of the traversal (level 0 is the invalidated dependencies)
+	Removed   int                 // Total keys removed (including the dependency sets)
+	Truncated bool                // The traversal stopped at the max depth with dependencies left
+}
+
+// InvalidationLevel is a single level of a cascading invalidation
+type InvalidationLevel struct {
+	Dependencies []string      // Dependencies invalidated at the level
+	Duration     time.Duration // How long the level took
+	Keys         int           // Keys removed at the level (including the dependency sets)
+}
+
+// KillByDependencyCascade removes all keys which are listed as depending on the key(s), and keeps
+// following removed keys that are dependencies themselves (IE: a user invalidates their orders
+// which invalidates each order's line items), returning a report of the traversal
+//
+// Each level is removed separately (not atomic), cycles are followed once, and the traversal stops
+// after 10 levels (see: InvalidationTrace.Truncated)
+// The invalidate hooks receive the report (see: OnInvalidate(), KeyEvent.Trace)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: KillByDependencyCascadeRaw()
+func KillByDependencyCascade(ctx context.Context, client *Client, keys ...string) (*InvalidationTrace, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+
+	var trace *InvalidationTrace
+	if trace, err = killByDependencyCascade(conn, client.Clock(), keys); err != nil {
+		return trace, err
+	}
+	for _, key := range keys {
+		client.fireHooks(ctx, KeyEvent{Key: key, Operation: OperationInvalidate, Removed: trace.Removed, Trace: trace})
+	}
+	return trace, nil
+}
+
+// KillByDependencyCascadeRaw removes all keys which are listed as depending on the key(s), and keeps
+// following removed keys that are dependencies themselves, returning a report of the traversal
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/smembers
+// https://redis.io/commands/exists
+// https://redis.io/commands/del
+func KillByDependencyCascadeRaw(conn redis.Conn, keys ...string) (*InvalidationTrace, error) {
+	return killByDependencyCascade(conn, SystemClock, keys)
+}
+
+// killByDependencyCascade removes the dependencies level by level (the trace has the completed levels on error)
+func killByDependencyCascade(conn redis.Conn, clock Clock, keys []string) (*InvalidationTrace, error) {
+	trace := &InvalidationTrace{}
+	start := clock.Now()
+	defer func() {
+		trace.Duration = clock.Now().Sub(start)
+	}()
+
+	visited := make(map[string]bool, len(keys))
+	dependencies := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if !visited[key] {
+			visited[key] = true
+			dependencies = append(dependencies, key)
+		}
+	}
+
+	for len(dependencies) > 0 {
+		if len(trace.Levels) == maxDependencyDepth {
+			trace.Truncated = true
+			break
+		}
+		levelStart := clock.Now()
+		members, next, err := dependencyMembers(conn, dependencies, visited)
+		if err != nil {
+			return trace, err
+		}
+
+		// Remove the dependencies, their sets and their members
+		args := make([]interface{}, 0, len(dependencies)*2+len(members))
+		for _, dependency := range dependencies {
+			args = append(args, dependency)
+			for _, set := range dependencySetKeys(conn, dependency) {
+				args = append(args, set)
+			}
+		}
+		for _, member := range members {
+			args = append(args, member)
+		}
+		var removed int
+		if removed, err = redis.Int(conn.Do(DeleteCommand, args...)); err != nil {
+			return trace, err
+		}
+
+		trace.Levels = append(trace.Levels, InvalidationLevel{
+			Dependencies: dependencies,
+			Duration:     clock.Now().Sub(levelStart),
+			Keys:         removed,
+		})
+		trace.Removed += removed
+		dependencies = next
+	}
+	return trace, nil
+}
+
+// dependencyMembers returns the unvisited members of the dependency sets, and the members that are
+// dependencies themselves (the next level, marked as visited)
+func dependencyMembers(conn redis.Conn, dependencies []string,
+	visited map[string]bool) (members, next []string, err error) {
+	for _, dependency := range dependencies {
+		for _, set := range dependencySetKeys(conn, dependency) {
+			if err = conn.Send(MembersCommand, set); err != nil {
+				return
+			}
+		}
+	}
+	var replies []interface{}
+	if replies, err = flushPipeline(conn); err != nil {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, reply := range replies {
+		var set []string
+		if set, err = redis.Strings(reply, nil); err != nil {
+			return
+		}
+		for _, member := range set {
+			if !visited[member] && !seen[member] {
+				seen[member] = true
+				members = append(members, member)
+			}
+		}
+	}
+
+	// Members with a dependency set (or shard) of their own
+	sets := make([]string, 0, len(members))
+	owners := make([]int, 0, len(members))
+	for i, member := range members {
+		for _, set := range dependencySetKeys(conn, member) {
+			sets = append(sets, set)
+			owners = append(owners, i)
+		}
+	}
+	var found []bool
+	if _, found, err = ExistsMultiRaw(conn, sets...); err != nil {
+		return
+	}
+	isDependency := make([]bool, len(members))
+	for i, exists := range found {
+		if exists {
+			isDependency[owners[i]] = true
+		}
+	}
+	for i, member := range members {
+		if isDependency[i] {
+			visited[member] = true
+			next = append(next, member)
+		}
+	}
+	return
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKillByDependencyCascade tests the method KillByDependencyCascade()
+func TestKillByDependencyCascade(t *testing.T) {
+
+	t.Run("cascade using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// Level 0: user-1 -> order-1, order-2 (order-1 is a dependency)
+		conn.Command(MembersCommand, DependencyPrefix+"user-1").
+			Expect([]interface{}{[]byte("order-1"), []byte("order-2")})
+		conn.Command(ExistsCommand, DependencyPrefix+"order-1").Expect(int64(1))
+		conn.Command(ExistsCommand, DependencyPrefix+"order-2").Expect(int64(0))
+		conn.Command(DeleteCommand, "user-1", DependencyPrefix+"user-1", "order-1", "order-2").
+			Expect(int64(3))
+
+		// Level 1: order-1 -> item-1
+		conn.Command(MembersCommand, DependencyPrefix+"order-1").Expect([]interface{}{[]byte("item-1")})
+		conn.Command(ExistsCommand, DependencyPrefix+"item-1").Expect(int64(0))
+		conn.Command(DeleteCommand, "order-1", DependencyPrefix+"order-1", "item-1").Expect(int64(2))
+
+		var events []KeyEvent
+		client.OnInvalidate(func(_ context.Context, event KeyEvent) {
+			events = append(events, event)
+		})
+
+		trace, err := KillByDependencyCascade(context.Background(), client, "user-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, trace.Removed)
+		assert.False(t, trace.Truncated)
+		assert.Len(t, trace.Levels, 2)
+		assert.Equal(t, []string{"user-1"}, trace.Levels[0].Dependencies)
+		assert.Equal(t, 3, trace.Levels[0].Keys)
+		assert.Equal(t, []string{"order-1"}, trace.Levels[1].Dependencies)
+		assert.Equal(t, 2, trace.Levels[1].Keys)
+
+		assert.Len(t, events, 1)
+		assert.Equal(t, "user-1", events[0].Key)
+		assert.Equal(t, trace, events[0].Trace)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		trace, err := KillByDependencyCascade(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Empty(t, trace.Levels)
+	})
+
+	t.Run("cascade using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// user-1 -> order-1 -> item-1 -> user-1 (a cycle)
+		err = SetRaw(conn, "order-1", testStringValue, "user-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "order-2", testStringValue, "user-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "item-1", testStringValue, "order-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "user-1", testStringValue, "item-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "other", testStringValue, "user-2")
+		assert.NoError(t, err)
+
+		var trace *InvalidationTrace
+		trace, err = KillByDependencyCascadeRaw(conn, "user-1")
+		assert.NoError(t, err)
+		assert.Len(t, trace.Levels, 3)
+		assert.Equal(t, []string{"item-1"}, trace.Levels[2].Dependencies)
+
+		var keys []string
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"other", DependencyPrefix + "user-2"}, keys)
+	})
+}
+
+// ExampleKillByDependencyCascade is an example of the method KillByDependencyCascade()
+func ExampleKillByDependencyCascade() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a single level of dependencies
+	conn.Command(MembersCommand, DependencyPrefix+"user-1").Expect([]interface{}{[]byte("order-1")})
+	conn.Command(ExistsCommand, DependencyPrefix+"order-1").Expect(int64(0))
+	conn.Command(DeleteCommand, "user-1", DependencyPrefix+"user-1", "order-1").Expect(int64(2))
+
+	trace, _ := KillByDependencyCascade(context.Background(), client, "user-1")
+	fmt.Printf("levels: %d removed: %d", len(trace.Levels), trace.Removed)
+	// Output:levels: 1 removed: 2
+}
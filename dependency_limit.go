@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// DependencyLimitPolicy is what happens when a write would grow a dependency set past the limit
+type DependencyLimitPolicy int
+
+// Dependency limit policies
+const (
+	DependencyLimitWarn   DependencyLimitPolicy = iota // Link the key and fire the handler
+	DependencyLimitReject                              // Fire the handler and reject the write (ErrDependencyLimit)
+)
+
+// ErrDependencyLimit is returned when a write is rejected because a dependency set is full
+var ErrDependencyLimit = errors.New("dependency set has reached the max members")
+
+// DependencyLimitConfig is the configuration for the max members per dependency set
+type DependencyLimitConfig struct {
+	MaxMembers int                                  // Max members in each dependency set (required)
+	OnExceeded func(dependency string, members int) // Fired for each write to a full dependency set (optional)
+	Policy     DependencyLimitPolicy                // Warn (default) or reject the write
+}
+
+// dependencyLimit is the max members per dependency set of the client
+type dependencyLimit struct {
+	config DependencyLimitConfig
+}
+
+// SetDependencyLimit limits the members of each dependency set for all writes using a connection
+// from the client, nil removes the limit
+//
+// Run-away dependencies with millions of members make KillByDependency() slow. Before each write
+// with dependencies the size of each dependency set is checked (one extra round trip), a full set
+// fires the handler and, with DependencyLimitReject, the write is not sent
+// Sharded dependencies are not limited (see: SetDependencyShards())
+func (c *Client) SetDependencyLimit(config *DependencyLimitConfig) error {
+	var limit *dependencyLimit
+	if config != nil {
+		if config.MaxMembers <= 0 {
+			return errors.New("missing required parameter: max members")
+		}
+		limit = &dependencyLimit{config: *config}
+	}
+	c.mu.Lock()
+	c.dependencyLimit = limit
+	c.mu.Unlock()
+	return nil
+}
+
+// checkDependencyLimit checks the size of each dependency set if the connection has a limit
+// (called before anything is sent for the write)
+//
+// Spec: https://redis.io/commands/scard
+func checkDependencyLimit(conn redis.Conn, dependencies ...string) error {
+	settings, ok := findConn[*dependencyConn](conn)
+	if !ok || settings.limit == nil {
+		return nil
+	}
+
+	// Sharded dependencies are not limited (see: SetDependencyShards())
+	var limited []string
+	for _, dependency := range dependencies {
+		if settings.shards[dependency] <= 1 {
+			limited = append(limited, dependency)
+		}
+	}
+	if len(limited) == 0 {
+		return nil
+	}
+	for _, dependency := range limited {
+		if err := conn.Send(SetCardCommand, DependencyPrefix+dependency); err != nil {
+			return err
+		}
+	}
+	replies, err := flushPipeline(conn)
+	if err != nil {
+		return err
+	}
+
+	config := settings.limit.config
+	for i, reply := range replies {
+		var members int
+		if members, err = redis.Int(reply, nil); err != nil {
+			return err
+		} else if members < config.MaxMembers {
+			continue
+		}
+		if config.OnExceeded != nil {
+			config.OnExceeded(limited[i], members)
+		}
+		if config.Policy == DependencyLimitReject {
+			return fmt.Errorf("%w: %s has %d members", ErrDependencyLimit, limited[i], members)
+		}
+	}
+	return nil
+}
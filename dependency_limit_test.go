@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetDependencyLimit tests the method SetDependencyLimit()
+func TestSetDependencyLimit(t *testing.T) {
+
+	t.Run("missing max members", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyLimit(&DependencyLimitConfig{})
+		assert.Error(t, err)
+	})
+
+	t.Run("warn using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var exceeded []string
+		err := client.SetDependencyLimit(&DependencyLimitConfig{
+			MaxMembers: 5,
+			OnExceeded: func(dependency string, members int) {
+				exceeded = append(exceeded, fmt.Sprintf("%s:%d", dependency, members))
+			},
+		})
+		assert.NoError(t, err)
+
+		conn.Command(SetCardCommand, DependencyPrefix+testDependantKey).Expect(int64(5))
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(MultiCommand)
+		conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.Equal(t, []string{testDependantKey + ":5"}, exceeded)
+	})
+
+	t.Run("reject using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyLimit(&DependencyLimitConfig{MaxMembers: 5, Policy: DependencyLimitReject})
+		assert.NoError(t, err)
+
+		conn.Command(SetCardCommand, DependencyPrefix+testDependantKey).Expect(int64(9))
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.ErrorIs(t, err, ErrDependencyLimit)
+		assert.False(t, setCmd.Called)
+
+		// No limit
+		err = client.SetDependencyLimit(nil)
+		assert.NoError(t, err)
+		conn.Command(MultiCommand)
+		conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("reject using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.SetDependencyLimit(&DependencyLimitConfig{MaxMembers: 2, Policy: DependencyLimitReject})
+		assert.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			err = Set(context.Background(), client, fmt.Sprintf("key-%d", i), testStringValue, testDependantKey)
+			assert.NoError(t, err)
+		}
+		err = HashSet(context.Background(), client, testHashName, "field", testStringValue, testDependantKey)
+		assert.ErrorIs(t, err, ErrDependencyLimit)
+
+		// Not written
+		var found bool
+		found, err = ExistsRaw(conn, testHashName)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		// Writes without dependencies are not checked
+		err = Set(context.Background(), client, "key-3", testStringValue)
+		assert.NoError(t, err)
+	})
+}
+
+// ExampleClient_SetDependencyLimit is an example of the method SetDependencyLimit()
+func ExampleClient_SetDependencyLimit() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Reject writes to dependency sets with 100,000 members or more
+	_ = client.SetDependencyLimit(&DependencyLimitConfig{MaxMembers: 100000, Policy: DependencyLimitReject})
+
+	// Mock a full dependency set
+	conn.Command(SetCardCommand, DependencyPrefix+"user-1").Expect(int64(100000))
+
+	err := Set(context.Background(), client, testKey, testStringValue, "user-1")
+	fmt.Print(err)
+	// Output:dependency set has reached the max members: user-1 has 100000 members
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// maxDependencyShards is the most sub-sets a dependency can be sharded across
+const maxDependencyShards = 1024
+
+// ErrInvalidDependencyShards is returned when the shards of a dependency are out of range
+var ErrInvalidDependencyShards = errors.New("dependency shards must be between 1 and 1024")
+
+// SetDependencyShards spreads the dependency set of a dependency across the shards
+// (depend:<dependency>:0 to depend:<dependency>:<shards-1>), for dependencies expected to
+// hold millions of keys; each key is linked in a single shard and KillByDependency() removes
+// the shards one at a time, keeping each command small. One shard removes the sharding.
+//
+// Only connections from the client use the shards (not Raw functions on custom connections),
+// set the shards before keys are linked to the dependency (keys linked before are not removed)
+func (c *Client) SetDependencyShards(dependency string, shards int) error {
+	if len(dependency) == 0 {
+		return errors.New("missing required parameter: dependency")
+	} else if shards < 1 || shards > maxDependencyShards {
+		return ErrInvalidDependencyShards
+	}
+
+	// Copy on write (connections keep the shards they were created with)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	updated := make(map[string]int, len(c.dependencyShards)+1)
+	for name, count := range c.dependencyShards {
+		updated[name] = count
+	}
+	if shards == 1 {
+		delete(updated, dependency)
+	} else {
+		updated[dependency] = shards
+	}
+	c.dependencyShards = updated
+	return nil
+}
+
+// DependencyShards returns the shards of the dependency set (1 if not sharded)
+func (c *Client) DependencyShards(dependency string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if shards := c.dependencyShards[dependency]; shards > 1 {
+		return shards
+	}
+	return 1
+}
+
+// dependencyConn is a connection with the dependency settings of the client (limit and shards)
+type dependencyConn struct {
+	redis.Conn
+	limit  *dependencyLimit
+	shards map[string]int
+}
+
+// unwrap returns the wrapped connection
+func (c *dependencyConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// DoWithTimeout runs the command with the timeout
+func (c *dependencyConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *dependencyConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// dependencyShards returns the shards of the dependency for the connection (1 if not sharded)
+func dependencyShards(conn redis.Conn, dependency string) int {
+	if settings, ok := findConn[*dependencyConn](conn); ok && settings.shards[dependency] > 1 {
+		return settings.shards[dependency]
+	}
+	return 1
+}
+
+// dependencySetKey returns the dependency set (or shard) the key is linked in
+func dependencySetKey(conn redis.Conn, dependency string, key interface{}) string {
+	shards := dependencyShards(conn, dependency)
+	if shards == 1 {
+		return DependencyPrefix + dependency
+	}
+	return dependencyShardKey(dependency, dependencyShard(key, shards))
+}
+
+// dependencySetKeys returns all the dependency sets (or shards) of the dependency
+func dependencySetKeys(conn redis.Conn, dependency string) []string {
+	shards := dependencyShards(conn, dependency)
+	if shards == 1 {
+		return []string{DependencyPrefix + dependency}
+	}
+	keys := make([]string, shards)
+	for i := range keys {
+		keys[i] = dependencyShardKey(dependency, i)
+	}
+	return keys
+}
+
+// dependencyShardKey returns the key of the shard of the dependency set
+func dependencyShardKey(dependency string, shard int) string {
+	return DependencyPrefix + dependency + ":" + strconv.Itoa(shard)
+}
+
+// dependencyShard returns the shard of the key
+func dependencyShard(key interface{}, shards int) int {
+	hash := fnv.New32a()
+	switch k := key.(type) {
+	case string:
+		_, _ = hash.Write([]byte(k))
+	case []byte:
+		_, _ = hash.Write(k)
+	default:
+		_, _ = fmt.Fprint(hash, k)
+	}
+	return int(hash.Sum32() % uint32(shards))
+}
@@ -0,0 +1,213 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetDependencyShards tests the method SetDependencyShards()
+func TestSetDependencyShards(t *testing.T) {
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyShards("", 4)
+		assert.Error(t, err)
+
+		err = client.SetDependencyShards(testDependantKey, 0)
+		assert.ErrorIs(t, err, ErrInvalidDependencyShards)
+
+		err = client.SetDependencyShards(testDependantKey, maxDependencyShards+1)
+		assert.ErrorIs(t, err, ErrInvalidDependencyShards)
+	})
+
+	t.Run("set and remove shards", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Equal(t, 1, client.DependencyShards(testDependantKey))
+
+		err := client.SetDependencyShards(testDependantKey, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, client.DependencyShards(testDependantKey))
+
+		err = client.SetDependencyShards(testDependantKey, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, client.DependencyShards(testDependantKey))
+	})
+
+	t.Run("link using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyShards(testDependantKey, 4)
+		assert.NoError(t, err)
+
+		shard := dependencyShardKey(testDependantKey, dependencyShard(testKey, 4))
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(MultiCommand)
+		addCmd := conn.Command(AddToSetCommand, shard, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.True(t, addCmd.Called)
+	})
+
+	t.Run("kill using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyShards(testDependantKey, 2)
+		assert.NoError(t, err)
+
+		conn.Command(EvalCommand, killByDependencySha, 0, dependencyShardKey(testDependantKey, 0)).Expect(int64(2))
+		conn.Command(EvalCommand, killByDependencySha, 0, dependencyShardKey(testDependantKey, 1)).Expect(int64(3))
+		conn.Command(DeleteCommand, testDependantKey).Expect(int64(0))
+
+		var total int
+		total, err = KillByDependency(context.Background(), client, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, total)
+	})
+
+	t.Run("kill while skipping dependencies using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetSkipDependencies(true)
+
+		err := client.SetDependencyShards(testDependantKey, 2)
+		assert.NoError(t, err)
+
+		// The shards are found through the skip dependencies connection
+		shard0 := conn.Command(EvalCommand, killByDependencySha, 0, dependencyShardKey(testDependantKey, 0)).
+			Expect(int64(2))
+		shard1 := conn.Command(EvalCommand, killByDependencySha, 0, dependencyShardKey(testDependantKey, 1)).
+			Expect(int64(3))
+		conn.Command(DeleteCommand, testDependantKey).Expect(int64(0))
+
+		var total int
+		total, err = KillByDependency(context.Background(), client, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 5, total)
+		assert.Equal(t, 1, conn.Stats(shard0))
+		assert.Equal(t, 1, conn.Stats(shard1))
+	})
+
+	t.Run("link and kill using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.RegisterScripts(context.Background())
+		assert.NoError(t, err)
+
+		err = client.SetDependencyShards(testDependantKey, 4)
+		assert.NoError(t, err)
+
+		for i := 0; i < 20; i++ {
+			err = Set(context.Background(), client, fmt.Sprintf("key-%d", i), testStringValue, testDependantKey)
+			assert.NoError(t, err)
+		}
+		err = SetChunked(context.Background(), client, testKey, []byte("0123456789"), 1, 0, testDependantKey)
+		assert.NoError(t, err)
+
+		// Spread across the shards (no unsharded set)
+		var found bool
+		found, err = ExistsRaw(conn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		members := 0
+		for i := 0; i < 4; i++ {
+			var shard []string
+			shard, err = SetMembersRaw(conn, dependencyShardKey(testDependantKey, i))
+			assert.NoError(t, err)
+			members += len(shard)
+		}
+		assert.Equal(t, 31, members)
+
+		var total int
+		total, err = KillByDependency(context.Background(), client, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 35, total)
+
+		var count int
+		count, _, err = ExistsMultiRaw(conn, "key-0", testKey, ChunkKey(testKey, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("cascade using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.SetDependencyShards("orders", 3)
+		assert.NoError(t, err)
+
+		// user -> orders (sharded) -> order keys
+		err = Set(context.Background(), client, "orders", testStringValue, "user")
+		assert.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			err = Set(context.Background(), client, fmt.Sprintf("order-%d", i), testStringValue, "orders")
+			assert.NoError(t, err)
+		}
+
+		var trace *InvalidationTrace
+		trace, err = KillByDependencyCascade(context.Background(), client, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(trace.Levels))
+		assert.Equal(t, []string{"orders"}, trace.Levels[1].Dependencies)
+
+		var found bool
+		found, err = ExistsRaw(conn, "order-0")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleClient_SetDependencyShards is an example of the method SetDependencyShards()
+func ExampleClient_SetDependencyShards() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Spread a dependency with millions of keys across 16 sets
+	_ = client.SetDependencyShards("all-products", 16)
+
+	fmt.Print(client.DependencyShards("all-products"))
+	// Output:16
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -98,7 +99,40 @@ func ExampleDelete() {
 // TestKillByDependency tests the method KillByDependency()
 func TestKillByDependency(t *testing.T) {
 
-	// todo: mock kill by dependency
+	t.Run("partial failure using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		errTestScript := errors.New("BUSY Redis is busy running a script")
+		conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+"dep-1", DependencyPrefix+"dep-2").
+			ExpectError(errTestScript)
+		conn.Command(DeleteCommand, "dep-1", "dep-2").Expect(int64(1))
+
+		total, err := KillByDependency(context.Background(), client, "dep-1", "dep-2")
+		assert.ErrorIs(t, err, errTestScript)
+		assert.Equal(t, 1, total)
+
+		var deleteErr *DeleteError
+		assert.ErrorAs(t, err, &deleteErr)
+		assert.Equal(t, 1, deleteErr.Deleted)
+		assert.Equal(t, []string{"dep-1", "dep-2"}, deleteErr.Failed)
+	})
+
+	t.Run("total from the replies using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+"dep-1").Expect(int64(3))
+		conn.Command(DeleteCommand, "dep-1").Expect(int64(0))
+
+		total, err := KillByDependency(context.Background(), client, "dep-1")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
 
 	t.Run("no keys - real redis", func(t *testing.T) {
 		if testing.Short() {
@@ -322,3 +356,131 @@ func TestHashMapDependencyManagement(t *testing.T) {
 		assert.Equal(t, false, found)
 	})
 }
+
+// TestLinkDependencies tests the method LinkDependencies()
+func TestLinkDependencies(t *testing.T) {
+
+	t.Run("link using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		err := LinkDependencies(context.Background(), client, testKey, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("failed writes are not linked using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue).ExpectError(errors.New("OOM command not allowed"))
+		multiCmd := conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		err := SetRaw(conn, testKey, testStringValue, testDependantKey)
+		assert.EqualError(t, err, "OOM command not allowed")
+		assert.True(t, setCmd.Called)
+		assert.False(t, multiCmd.Called)
+		assert.False(t, depCmd.Called)
+	})
+
+	t.Run("failed writes are not linked using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		// Wrong type for the hash write
+		err = HashSetRaw(conn, testKey, "field", testStringValue, testDependantKey)
+		assert.Error(t, err)
+
+		// The connection is still usable, and the key was not linked
+		var value string
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var members []string
+		members, err = SetMembersRaw(conn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.Empty(t, members)
+	})
+
+	t.Run("link inside a caller transaction using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Custom transaction with the dependency links
+		err = conn.Send(MultiCommand)
+		assert.NoError(t, err)
+		err = conn.Send("LPUSH", testKey, testStringValue)
+		assert.NoError(t, err)
+		err = SendLinkDependencies(conn, testKey, "dependent-1", "dependent-2")
+		assert.NoError(t, err)
+		_, err = conn.Do(ExecuteCommand)
+		assert.NoError(t, err)
+
+		// The key is removed with the dependency
+		var total int
+		total, err = DeleteRaw(conn, "dependent-2")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleSendLinkDependencies is an example of the method SendLinkDependencies()
+func ExampleSendLinkDependencies() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the transaction
+	conn.Command(MultiCommand)
+	conn.Command("LPUSH", testKey, testStringValue)
+	conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+	conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+	// Compose the transaction with the dependency links
+	_ = conn.Send(MultiCommand)
+	_ = conn.Send("LPUSH", testKey, testStringValue)
+	_ = SendLinkDependencies(conn, testKey, testDependantKey)
+	_, err := conn.Do(ExecuteCommand)
+
+	fmt.Printf("linked: %v", err == nil)
+	// Output:linked: true
+}
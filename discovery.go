@@ -0,0 +1,256 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+)
+
+// DefaultDiscoveryInterval is the default time between resolving the endpoints again
+const DefaultDiscoveryInterval = 30 * time.Second
+
+// ErrNoEndpoints is returned when the resolver does not return any endpoints
+var ErrNoEndpoints = errors.New("no redis endpoints were resolved")
+
+// EndpointResolver returns the current redis endpoints (IE: host:port) in order of preference
+type EndpointResolver func(ctx context.Context) ([]string, error)
+
+// DiscoveryConfig is the configuration for ConnectWithDiscovery()
+type DiscoveryConfig struct {
+	Interval time.Duration            // Time between resolving the endpoints again (default: 30s)
+	OnChange func(endpoints []string) // Fired when the endpoints change and the pool is rebuilt (optional)
+	OnError  func(err error)          // Fired when resolving or rebuilding fails, the pool is kept (optional)
+	Resolver EndpointResolver         // Returns the current endpoints (see: SRVResolver())
+}
+
+// discovery re-resolves the endpoints for a client in the background
+type discovery struct {
+	cancel    context.CancelFunc
+	config    Config
+	discovery DiscoveryConfig
+	done      chan struct{}
+	endpoints []string
+	options   []redis.DialOption
+}
+
+// lookupSRV is the DNS SRV lookup used by SRVResolver() (replaced in tests)
+var lookupSRV = net.DefaultResolver.LookupSRV
+
+// SRVResolver returns a resolver for the DNS SRV records of the service
+// (IE: SRVResolver("redis", "tcp", "cache.svc.cluster.local") looks up _redis._tcp.cache.svc.cluster.local)
+func SRVResolver(service, proto, name string) EndpointResolver {
+	return func(ctx context.Context) ([]string, error) {
+		_, records, err := lookupSRV(ctx, service, proto, name)
+		if err != nil {
+			return nil, err
+		}
+		endpoints := make([]string, 0, len(records))
+		for _, record := range records {
+			endpoints = append(endpoints, net.JoinHostPort(
+				strings.TrimSuffix(record.Target, "."), fmt.Sprintf("%d", record.Port),
+			))
+		}
+		return endpoints, nil
+	}
+}
+
+// ConnectWithDiscovery creates a new connection pool to the first endpoint returned by the resolver
+// The endpoints are resolved again in the background, and the pool is rebuilt when they change
+//
+// The config URL (if set) is used as a template: the scheme, credentials and database are kept and
+// the host is replaced by the endpoint (IE: rediss://:password@ignored/2)
+// Discovery is stopped via Close()
+func ConnectWithDiscovery(ctx context.Context, config Config, discoveryConfig DiscoveryConfig,
+	options ...redis.DialOption) (*Client, error) {
+
+	// Required param for discovery
+	if discoveryConfig.Resolver == nil {
+		return nil, errors.New("missing required parameter: resolver")
+	}
+	if discoveryConfig.Interval <= 0 {
+		discoveryConfig.Interval = DefaultDiscoveryInterval
+	}
+
+	// Resolve the first set of endpoints
+	endpoints, err := resolveEndpoints(ctx, discoveryConfig.Resolver)
+	if err != nil {
+		return nil, err
+	}
+	template := config.URL
+	if config.URL, err = endpointURL(template, endpoints[0]); err != nil {
+		return nil, err
+	}
+
+	var client *Client
+	if client, err = ConnectWithConfig(ctx, config, options...); err != nil {
+		return nil, err
+	}
+
+	// Start resolving in the background
+	config.URL = template
+	discoveryCtx, cancel := context.WithCancel(context.Background())
+	d := &discovery{
+		cancel:    cancel,
+		config:    config,
+		discovery: discoveryConfig,
+		done:      make(chan struct{}),
+		endpoints: endpoints,
+		options:   options,
+	}
+	client.mu.Lock()
+	client.discovery = d
+	client.mu.Unlock()
+
+	go client.runDiscovery(discoveryCtx, d)
+	return client, nil
+}
+
+// Endpoints returns the endpoints that were last resolved (nil if discovery is not enabled)
+func (c *Client) Endpoints() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.discovery == nil {
+		return nil
+	}
+	return append([]string{}, c.discovery.endpoints...)
+}
+
+// StopDiscovery stops resolving the endpoints in the background (the current pool is kept)
+func (c *Client) StopDiscovery() {
+	c.mu.Lock()
+	d := c.discovery
+	c.discovery = nil
+	c.mu.Unlock()
+
+	if d != nil {
+		d.cancel()
+		<-d.done
+	}
+}
+
+// runDiscovery resolves the endpoints until the context is canceled
+func (c *Client) runDiscovery(ctx context.Context, d *discovery) {
+	defer close(d.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.Clock().After(d.discovery.Interval):
+		}
+
+		if err := c.refreshEndpoints(ctx, d); err != nil && ctx.Err() == nil && d.discovery.OnError != nil {
+			d.discovery.OnError(err)
+		}
+	}
+}
+
+// refreshEndpoints resolves the endpoints and rebuilds the pool if they changed
+func (c *Client) refreshEndpoints(ctx context.Context, d *discovery) error {
+	endpoints, err := resolveEndpoints(ctx, d.discovery.Resolver)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	changed := !sameEndpoints(d.endpoints, endpoints)
+	c.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+
+	// Build the new pool before replacing the current pool
+	config := d.config
+	config.onDial = c.nameConnection
+	if config.URL, err = endpointURL(config.URL, endpoints[0]); err != nil {
+		return err
+	}
+	var pool nrredis.Pool
+	if pool, err = newPool(config, config.dialOptions(d.options...)...); err != nil {
+		return err
+	}
+
+	// The dependency script must be loaded on the new endpoint
+	if config.DependencyMode {
+		if err = loadDependencyScript(ctx, pool); err != nil {
+			_ = pool.Close()
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	previous := c.Pool
+	c.Pool = pool
+	c.capabilities = nil
+	d.endpoints = endpoints
+	c.mu.Unlock()
+
+	// Connections in use are closed when they are returned to the previous pool
+	if previous != nil {
+		_ = previous.Close()
+	}
+	if d.discovery.OnChange != nil {
+		d.discovery.OnChange(append([]string{}, endpoints...))
+	}
+	return nil
+}
+
+// loadDependencyScript loads the dependency script using a connection from the pool
+func loadDependencyScript(ctx context.Context, pool nrredis.Pool) error {
+	conn, err := pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer CloseConnection(conn)
+	_, err = conn.Do(ScriptCommand, LoadCommand, killByDependencyLua)
+	return err
+}
+
+// resolveEndpoints returns the endpoints from the resolver (at least one)
+func resolveEndpoints(ctx context.Context, resolver EndpointResolver) ([]string, error) {
+	endpoints, err := resolver(ctx)
+	if err != nil {
+		return nil, err
+	} else if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	return endpoints, nil
+}
+
+// endpointURL returns the url for the endpoint using the template url (if set)
+func endpointURL(template, endpoint string) (string, error) {
+	if len(template) == 0 {
+		return "redis://" + endpoint, nil
+	}
+	u, err := url.Parse(template)
+	if err != nil {
+		return "", err
+	}
+	u.Host = endpoint
+	return u.String(), nil
+}
+
+// sameEndpoints returns true if both sets contain the same endpoints (in any order)
+func sameEndpoints(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
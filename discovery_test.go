@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConnectWithDiscovery tests the method ConnectWithDiscovery()
+func TestConnectWithDiscovery(t *testing.T) {
+
+	t.Run("missing resolver", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithDiscovery(context.Background(), Config{}, DiscoveryConfig{})
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("resolver errors", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithDiscovery(context.Background(), Config{}, DiscoveryConfig{
+			Resolver: func(ctx context.Context) ([]string, error) {
+				return nil, errors.New("lookup failed")
+			},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, client)
+
+		client, err = ConnectWithDiscovery(context.Background(), Config{}, DiscoveryConfig{
+			Resolver: func(ctx context.Context) ([]string, error) {
+				return nil, nil
+			},
+		})
+		assert.ErrorIs(t, err, ErrNoEndpoints)
+		assert.Nil(t, client)
+	})
+
+	t.Run("pool is rebuilt when endpoints change", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		var mu sync.Mutex
+		endpoints := []string{"localhost:6379", "127.0.0.1:6379"}
+		changes := make(chan []string, 1)
+
+		client, err := ConnectWithDiscovery(context.Background(), Config{
+			MaxIdleConnections: testMaxIdleConnections,
+			URL:                "redis://ignored/0",
+		}, DiscoveryConfig{
+			Interval: 10 * time.Millisecond,
+			OnChange: func(endpoints []string) {
+				changes <- endpoints
+			},
+			Resolver: func(ctx context.Context) ([]string, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return endpoints, nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		defer client.Close()
+		assert.Equal(t, []string{"localhost:6379", "127.0.0.1:6379"}, client.Endpoints())
+
+		previous := client.primaryPool()
+
+		// Same set in a different order does not rebuild the pool
+		mu.Lock()
+		endpoints = []string{"127.0.0.1:6379", "localhost:6379"}
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, previous, client.primaryPool())
+
+		// A new endpoint rebuilds the pool
+		mu.Lock()
+		endpoints = []string{"127.0.0.1:6379"}
+		mu.Unlock()
+		select {
+		case changed := <-changes:
+			assert.Equal(t, []string{"127.0.0.1:6379"}, changed)
+		case <-time.After(time.Second):
+			t.Fatal("pool was not rebuilt")
+		}
+		assert.NotEqual(t, previous, client.primaryPool())
+		assert.Equal(t, []string{"127.0.0.1:6379"}, client.Endpoints())
+
+		// The new pool is used
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		// Stopping keeps the current pool
+		client.StopDiscovery()
+		assert.Nil(t, client.Endpoints())
+		assert.NotNil(t, client.primaryPool())
+	})
+
+	t.Run("resolve errors are reported", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		errs := make(chan error, 10)
+
+		client, err := ConnectWithDiscovery(context.Background(), Config{}, DiscoveryConfig{
+			Interval: 10 * time.Millisecond,
+			OnError: func(err error) {
+				errs <- err
+			},
+			Resolver: func(ctx context.Context) ([]string, error) {
+				if calls++; calls > 1 {
+					return nil, errors.New("lookup failed")
+				}
+				return []string{"localhost:6379"}, nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		defer client.Close()
+
+		select {
+		case err = <-errs:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("error was not reported")
+		}
+
+		// The current pool is kept
+		assert.Equal(t, []string{"localhost:6379"}, client.Endpoints())
+		assert.NotNil(t, client.primaryPool())
+	})
+}
+
+// ExampleConnectWithDiscovery is an example of the method ConnectWithDiscovery()
+func ExampleConnectWithDiscovery() {
+
+	client, _ := ConnectWithDiscovery(context.Background(), Config{
+		MaxIdleConnections: testMaxIdleConnections,
+	}, DiscoveryConfig{
+		Resolver: func(ctx context.Context) ([]string, error) {
+			return []string{"localhost:6379"}, nil
+		},
+	})
+
+	// Close connections (and stop discovery) at end of request
+	defer client.Close()
+
+	fmt.Printf("endpoints: %v", client.Endpoints())
+	// Output:endpoints: [localhost:6379]
+}
+
+// TestSRVResolver tests the method SRVResolver()
+func TestSRVResolver(t *testing.T) {
+	defer func(original func(context.Context, string, string, string) (string, []*net.SRV, error)) {
+		lookupSRV = original
+	}(lookupSRV)
+
+	t.Run("records are returned as endpoints", func(t *testing.T) {
+		lookupSRV = func(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			assert.Equal(t, "redis", service)
+			assert.Equal(t, "tcp", proto)
+			assert.Equal(t, "cache.service.consul", name)
+			return "", []*net.SRV{
+				{Target: "redis-0.cache.service.consul.", Port: 6379},
+				{Target: "redis-1.cache.service.consul.", Port: 6380},
+			}, nil
+		}
+
+		endpoints, err := SRVResolver("redis", "tcp", "cache.service.consul")(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"redis-0.cache.service.consul:6379",
+			"redis-1.cache.service.consul:6380",
+		}, endpoints)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		lookupSRV = func(context.Context, string, string, string) (string, []*net.SRV, error) {
+			return "", nil, errors.New("no such host")
+		}
+
+		endpoints, err := SRVResolver("redis", "tcp", "cache.service.consul")(context.Background())
+		assert.Error(t, err)
+		assert.Nil(t, endpoints)
+	})
+}
+
+// TestEndpointURL tests the method endpointURL()
+func TestEndpointURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{"", "redis://10.0.0.1:6379"},
+		{"redis://ignored:1234", "redis://10.0.0.1:6379"},
+		{"rediss://:password@ignored/2", "rediss://:password@10.0.0.1:6379/2"},
+	}
+	for _, test := range tests {
+		output, err := endpointURL(test.template, "10.0.0.1:6379")
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, output)
+	}
+}
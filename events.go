@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidWindow is returned if the window of the tracked events is not set
+var ErrInvalidWindow = errors.New("window must be greater than zero")
+
+// TrackEvent records the event (IE: a login attempt) at the timestamp in a sliding window and returns
+// the number of events in the window (including this one)
+// Events older than the window are trimmed and the key expires after the window without new events
+//
+// The member must be unique per event (IE: a request id), recording the same member again moves it
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: TrackEventRaw()
+func TrackEvent(ctx context.Context, client *Client, key, member string, timestamp time.Time,
+	window time.Duration) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return TrackEventRaw(conn, key, member, timestamp, window)
+}
+
+// TrackEventRaw records the event at the timestamp in a sliding window and returns
+// the number of events in the window (including this one)
+// Events are scored in milliseconds
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/zadd
+// https://redis.io/commands/zremrangebyscore
+// https://redis.io/commands/zcount
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/exec
+func TrackEventRaw(conn redis.Conn, key, member string, timestamp time.Time, window time.Duration) (int, error) {
+	if window <= 0 {
+		return 0, ErrInvalidWindow
+	}
+
+	score := timestamp.UnixMilli()
+	start := timestamp.Add(-window).UnixMilli()
+	if err := conn.Send(MultiCommand); err != nil {
+		return 0, err
+	}
+	if err := conn.Send(SortedSetAddCommand, key, score, member); err != nil {
+		return 0, err
+	}
+	if err := conn.Send(SortedSetRemoveScoreCommand, key, "-inf", "("+strconv.FormatInt(start, 10)); err != nil {
+		return 0, err
+	}
+	if err := conn.Send(SortedSetCountCommand, key, start, "+inf"); err != nil {
+		return 0, err
+	}
+	command, args := expireCommand(key, window)
+	if err := conn.Send(command, args...); err != nil {
+		return 0, err
+	}
+
+	replies, err := redis.Values(conn.Do(ExecuteCommand))
+	if err != nil {
+		return 0, err
+	} else if len(replies) < 3 {
+		return 0, redis.ErrNil
+	}
+	return redis.Int(replies[2], nil)
+}
+
+// CountEventsSince returns the number of events recorded since the time (see: TrackEvent())
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: CountEventsSinceRaw()
+func CountEventsSince(ctx context.Context, client *Client, key string, since time.Time) (count int, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		count, readErr = CountEventsSinceRaw(conn, key, since)
+		return
+	})
+	return
+}
+
+// CountEventsSinceRaw returns the number of events recorded since the time (see: TrackEvent())
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/zcount
+func CountEventsSinceRaw(conn redis.Conn, key string, since time.Time) (int, error) {
+	return redis.Int(conn.Do(SortedSetCountCommand, key, since.UnixMilli(), "+inf"))
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTrackEvent tests the method TrackEvent()
+func TestTrackEvent(t *testing.T) {
+
+	t.Run("track using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		now := time.UnixMilli(1700000000000)
+		var commands []*redigomock.Cmd
+		commands = append(commands, conn.Command(MultiCommand))
+		commands = append(commands, conn.Command(SortedSetAddCommand, testKey, int64(1700000000000), "attempt-1"))
+		commands = append(commands, conn.Command(SortedSetRemoveScoreCommand, testKey, "-inf", "(1699999940000"))
+		commands = append(commands, conn.Command(SortedSetCountCommand, testKey, int64(1699999940000), "+inf"))
+		commands = append(commands, conn.Command(ExpireCommand, testKey, int64(60)))
+		commands = append(commands, conn.Command(ExecuteCommand).
+			Expect([]interface{}{int64(1), int64(2), int64(3), int64(1)}))
+
+		count, err := TrackEvent(context.Background(), client, testKey, "attempt-1", now, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		for _, c := range commands {
+			assert.True(t, c.Called)
+		}
+
+		_, err = TrackEvent(context.Background(), client, testKey, "attempt-1", now, 0)
+		assert.ErrorIs(t, err, ErrInvalidWindow)
+	})
+
+	t.Run("count using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SortedSetCountCommand, testKey, int64(1700000000000), "+inf").Expect(int64(4))
+
+		count, err := CountEventsSince(context.Background(), client, testKey, time.UnixMilli(1700000000000))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, count)
+	})
+
+	t.Run("sliding window using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		var count int
+		for i := 0; i < 5; i++ {
+			count, err = TrackEventRaw(conn, testKey, fmt.Sprintf("attempt-%d", i),
+				start.Add(time.Duration(i)*time.Minute), 2*time.Minute)
+			assert.NoError(t, err)
+		}
+
+		// Only the events of the last two minutes remain
+		assert.Equal(t, 3, count)
+
+		count, err = CountEventsSinceRaw(conn, testKey, start)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		count, err = CountEventsSinceRaw(conn, testKey, start.Add(4*time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+// ExampleTrackEvent is an example of the method TrackEvent()
+func ExampleTrackEvent() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the transaction (third attempt in the window)
+	conn.Command(MultiCommand)
+	conn.GenericCommand(SortedSetAddCommand)
+	conn.GenericCommand(SortedSetRemoveScoreCommand)
+	conn.GenericCommand(SortedSetCountCommand)
+	conn.GenericCommand(ExpireCommand)
+	conn.Command(ExecuteCommand).Expect([]interface{}{int64(1), int64(0), int64(3), int64(1)})
+
+	// Record the login attempt and throttle after three attempts in 15 minutes
+	attempts, _ := TrackEvent(context.Background(), client, "login:user-1", "request-3", time.Now(), 15*time.Minute)
+	fmt.Printf("attempts: %d throttled: %v", attempts, attempts >= 3)
+	// Output:attempts: 3 throttled: true
+}
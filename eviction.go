@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrLFUPolicyRequired is returned for key frequencies if the maxmemory-policy of the server is not LFU
+var ErrLFUPolicyRequired = errors.New("key frequency requires an LFU maxmemory-policy")
+
+// EvictionCandidate is a sampled key with the access data used by the eviction policy
+type EvictionCandidate struct {
+	Frequency int           // Logarithmic access counter (LFU policies only, -1 otherwise)
+	Idle      time.Duration // Time since the last access (LRU policies only)
+	Key       string        // Name of the key
+}
+
+// KeyFrequency returns the logarithmic access counter of the key (OBJECT FREQ)
+// ErrLFUPolicyRequired is returned if the maxmemory-policy of the server is not LFU,
+// and redis.ErrNil if the key does not exist
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: KeyFrequencyRaw()
+func KeyFrequency(ctx context.Context, client *Client, key string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return KeyFrequencyRaw(conn, key)
+}
+
+// KeyFrequencyRaw returns the logarithmic access counter of the key (OBJECT FREQ)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/object-freq
+func KeyFrequencyRaw(conn redis.Conn, key string) (int, error) {
+	if len(key) == 0 {
+		return 0, errors.New("missing required parameter: key")
+	}
+	frequency, err := redis.Int(conn.Do(ObjectCommand, FrequencyArgument, key))
+	return frequency, frequencyError(err)
+}
+
+// SampleEvictionCandidates samples up to n random keys the way Redis samples keys to evict
+// (see: maxmemory-samples), returning them in the order the policy would evict them:
+// least frequently used first with an LFU policy, otherwise longest idle first
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SampleEvictionCandidatesRaw()
+func SampleEvictionCandidates(ctx context.Context, client *Client, n int) ([]EvictionCandidate, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return SampleEvictionCandidatesRaw(conn, n)
+}
+
+// SampleEvictionCandidatesRaw samples up to n random keys in the order the policy would evict them
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/randomkey
+// https://redis.io/commands/object-freq
+// https://redis.io/commands/object-idletime
+func SampleEvictionCandidatesRaw(conn redis.Conn, n int) ([]EvictionCandidate, error) {
+	if n < 1 {
+		return nil, errors.New("missing required parameter: n")
+	}
+	keys, err := randomKeys(conn, n)
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	// Pipeline the frequency and idle time of each key (only one is tracked by the policy)
+	for _, key := range keys {
+		if err = conn.Send(ObjectCommand, FrequencyArgument, key); err != nil {
+			return nil, err
+		}
+		if err = conn.Send(ObjectCommand, IdleTimeArgument, key); err != nil {
+			return nil, err
+		}
+	}
+	if err = conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	lfu := false
+	candidates := make([]EvictionCandidate, 0, len(keys))
+	for _, key := range keys {
+		frequency, freqErr := redis.Int(conn.Receive())
+		idle, idleErr := redis.Int64(conn.Receive())
+		if errors.Is(freqErr, redis.ErrNil) || errors.Is(idleErr, redis.ErrNil) {
+			continue // Removed since it was sampled
+		} else if freqErr != nil && !isServerError(freqErr) {
+			return nil, freqErr
+		} else if idleErr != nil && !isServerError(idleErr) {
+			return nil, idleErr
+		}
+		candidate := EvictionCandidate{Frequency: -1, Key: key}
+		if freqErr == nil {
+			candidate.Frequency, lfu = frequency, true
+		}
+		if idleErr == nil {
+			candidate.Idle = time.Duration(idle) * time.Second
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if lfu && candidates[i].Frequency != candidates[j].Frequency {
+			return candidates[i].Frequency < candidates[j].Frequency
+		}
+		return candidates[i].Idle > candidates[j].Idle
+	})
+	return candidates, nil
+}
+
+// randomKeys returns up to n distinct random keys (fewer if the database has fewer keys)
+//
+// Spec: https://redis.io/commands/randomkey
+func randomKeys(conn redis.Conn, n int) ([]string, error) {
+	for i := 0; i < n; i++ {
+		if err := conn.Send(RandomKeyCommand); err != nil {
+			return nil, err
+		}
+	}
+	if err := conn.Flush(); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, n)
+	seen := make(map[string]struct{}, n)
+	var err error
+	for i := 0; i < n; i++ {
+		key, keyErr := redis.String(conn.Receive())
+		if errors.Is(keyErr, redis.ErrNil) {
+			continue // Empty database
+		} else if keyErr != nil {
+			if err == nil {
+				err = keyErr
+			}
+			continue
+		}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+	return keys, err
+}
+
+// frequencyError returns ErrLFUPolicyRequired if the server does not track the key frequency
+func frequencyError(err error) error {
+	if isServerError(err) && strings.Contains(err.Error(), "LFU") {
+		return ErrLFUPolicyRequired
+	}
+	return err
+}
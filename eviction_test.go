@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKeyFrequency tests the method KeyFrequency()
+func TestKeyFrequency(t *testing.T) {
+
+	t.Run("key frequency using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ObjectCommand, FrequencyArgument, testKey).Expect(int64(12))
+		conn.Command(ObjectCommand, FrequencyArgument, "missing").Expect(nil)
+		conn.Command(ObjectCommand, FrequencyArgument, "lru").ExpectError(redis.Error(
+			"ERR An LFU maxmemory policy is not selected, access frequency not tracked."))
+
+		frequency, err := KeyFrequency(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 12, frequency)
+
+		_, err = KeyFrequency(context.Background(), client, "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		_, err = KeyFrequency(context.Background(), client, "lru")
+		assert.ErrorIs(t, err, ErrLFUPolicyRequired)
+
+		_, err = KeyFrequency(context.Background(), client, "")
+		assert.Error(t, err)
+	})
+}
+
+// TestSampleEvictionCandidates tests the method SampleEvictionCandidates()
+func TestSampleEvictionCandidates(t *testing.T) {
+
+	t.Run("invalid sample size", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := SampleEvictionCandidates(context.Background(), client, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("lfu policy using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		lfuErr := redis.Error("ERR An LFU maxmemory policy is selected, idle time not tracked.")
+		conn.Command(RandomKeyCommand).Expect("hot").Expect("cold").Expect("hot").Expect("gone")
+		conn.Command(ObjectCommand, FrequencyArgument, "hot").Expect(int64(200))
+		conn.Command(ObjectCommand, IdleTimeArgument, "hot").ExpectError(lfuErr)
+		conn.Command(ObjectCommand, FrequencyArgument, "cold").Expect(int64(1))
+		conn.Command(ObjectCommand, IdleTimeArgument, "cold").ExpectError(lfuErr)
+		conn.Command(ObjectCommand, FrequencyArgument, "gone").Expect(nil)
+		conn.Command(ObjectCommand, IdleTimeArgument, "gone").ExpectError(lfuErr)
+
+		candidates, err := SampleEvictionCandidates(context.Background(), client, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, []EvictionCandidate{
+			{Frequency: 1, Key: "cold"},
+			{Frequency: 200, Key: "hot"},
+		}, candidates)
+	})
+
+	t.Run("lru policy using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Empty database
+		var candidates []EvictionCandidate
+		candidates, err = SampleEvictionCandidates(context.Background(), client, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(candidates))
+
+		err = SetRaw(conn, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		candidates, err = SampleEvictionCandidates(context.Background(), client, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(candidates))
+		assert.Equal(t, testKey, candidates[0].Key)
+		assert.Equal(t, -1, candidates[0].Frequency)
+		assert.GreaterOrEqual(t, candidates[0].Idle, time.Duration(0))
+	})
+}
+
+// ExampleSampleEvictionCandidates is an example of the method SampleEvictionCandidates()
+func ExampleSampleEvictionCandidates() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a server with an LRU policy
+	lruErr := redis.Error("ERR An LFU maxmemory policy is not selected, access frequency not tracked.")
+	conn.Command(RandomKeyCommand).Expect("user:1")
+	conn.Command(ObjectCommand, FrequencyArgument, "user:1").ExpectError(lruErr)
+	conn.Command(ObjectCommand, IdleTimeArgument, "user:1").Expect(int64(90))
+
+	candidates, _ := SampleEvictionCandidates(context.Background(), client, 1)
+	fmt.Printf("evicted next: %s (idle %s)", candidates[0].Key, candidates[0].Idle)
+	// Output:evicted next: user:1 (idle 1m30s)
+}
@@ -0,0 +1,56 @@
+package cache
+
+// Package constants (expire conditions, redis 7+)
+const (
+	ExpireIfExistsArgument  string = "XX"
+	ExpireIfGreaterArgument string = "GT"
+	ExpireIfLessArgument    string = "LT"
+	ExpireIfNoneArgument    string = "NX"
+)
+
+// ExpireOption sets a condition for Expire()
+type ExpireOption func(*expireConfig)
+
+// expireConfig holds the conditions for Expire()
+type expireConfig struct {
+	conditions []string
+}
+
+// ExpireIfNone only sets the expiration if the key has no expiration (NX)
+func ExpireIfNone() ExpireOption {
+	return func(c *expireConfig) {
+		c.conditions = append(c.conditions, ExpireIfNoneArgument)
+	}
+}
+
+// ExpireIfExists only sets the expiration if the key already has an expiration (XX)
+func ExpireIfExists() ExpireOption {
+	return func(c *expireConfig) {
+		c.conditions = append(c.conditions, ExpireIfExistsArgument)
+	}
+}
+
+// ExpireIfGreater only sets the expiration if it lengthens the current expiration (GT)
+// A key without an expiration is treated as an infinite expiration
+func ExpireIfGreater() ExpireOption {
+	return func(c *expireConfig) {
+		c.conditions = append(c.conditions, ExpireIfGreaterArgument)
+	}
+}
+
+// ExpireIfLess only sets the expiration if it shortens the current expiration (LT)
+// A key without an expiration is treated as an infinite expiration
+func ExpireIfLess() ExpireOption {
+	return func(c *expireConfig) {
+		c.conditions = append(c.conditions, ExpireIfLessArgument)
+	}
+}
+
+// ExpireConditions returns the conditions set by the options (IE: [NX GT])
+func ExpireConditions(options ...ExpireOption) []string {
+	config := new(expireConfig)
+	for _, opt := range options {
+		opt(config)
+	}
+	return config.conditions
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExpireWithResult tests the method ExpireWithResult() and the expire options
+func TestExpireWithResult(t *testing.T) {
+
+	t.Run("expire options using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var tests = []struct {
+			testCase string
+			duration time.Duration
+			option   ExpireOption
+			command  string
+			args     []interface{}
+		}{
+			{"if none", time.Minute, ExpireIfNone(), ExpireCommand, []interface{}{testKey, int64(60), "NX"}},
+			{"if exists", time.Minute, ExpireIfExists(), ExpireCommand, []interface{}{testKey, int64(60), "XX"}},
+			{"if greater", time.Minute, ExpireIfGreater(), ExpireCommand, []interface{}{testKey, int64(60), "GT"}},
+			{"if less (millis)", 1500 * time.Millisecond, ExpireIfLess(), ExpireMillisCommand,
+				[]interface{}{testKey, int64(1500), "LT"}},
+		}
+		for _, test := range tests {
+			t.Run(test.testCase, func(t *testing.T) {
+				conn.Clear()
+				expireCmd := conn.Command(test.command, test.args...).Expect(int64(1))
+
+				set, err := ExpireWithResult(context.Background(), client, testKey, test.duration, test.option)
+				assert.NoError(t, err)
+				assert.True(t, set)
+				assert.True(t, expireCmd.Called)
+			})
+		}
+	})
+
+	t.Run("condition not met", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ExpireCommand, testKey, int64(60), "XX", "GT").Expect(int64(0))
+
+		set, err := ExpireWithResult(
+			context.Background(), client, testKey, time.Minute, ExpireIfExists(), ExpireIfGreater(),
+		)
+		assert.NoError(t, err)
+		assert.False(t, set)
+
+		// Expire ignores the result
+		err = Expire(context.Background(), client, testKey, time.Minute, ExpireIfExists(), ExpireIfGreater())
+		assert.NoError(t, err)
+	})
+}
+
+// ExampleExpireWithResult is an example of the method ExpireWithResult()
+func ExampleExpireWithResult() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the expire command
+	conn.Command(ExpireCommand, testKey, int64(300), "GT").Expect(int64(1))
+
+	// Only ever lengthen the expiration
+	extended, _ := ExpireWithResult(context.Background(), client, testKey, 5*time.Minute, ExpireIfGreater())
+	fmt.Printf("extended: %v", extended)
+	// Output:extended: true
+}
+
+// TestExpireConditions tests the method ExpireConditions()
+func TestExpireConditions(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ExpireConditions())
+	assert.Equal(t, []string{"NX"}, ExpireConditions(ExpireIfNone()))
+	assert.Equal(t, []string{"XX", "LT"}, ExpireConditions(ExpireIfExists(), ExpireIfLess()))
+}
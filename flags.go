@@ -0,0 +1,304 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// FlagPrefix is the prefix for all feature flag keys (a hash per flag)
+const FlagPrefix = "flag:"
+
+// FlagChannel is the pub/sub channel for the names of changed feature flags
+const FlagChannel = "go-cache:flags"
+
+// Default feature flag settings
+const (
+	defaultFlagCacheTTL  = 30 * time.Second
+	defaultFlagRetryWait = time.Second
+)
+
+// Feature flag hash fields
+const (
+	flagEnabledField    = "enabled"
+	flagPercentageField = "percentage"
+)
+
+// ErrInvalidPercentage is returned when the rollout percentage of a flag is not between 0 and 100
+var ErrInvalidPercentage = errors.New("percentage must be between 0 and 100")
+
+// Flag is the state of a feature flag
+type Flag struct {
+	Enabled    bool // The flag is on (for the rollout percentage of the subjects)
+	Percentage int  // Percentage of the subjects the flag is enabled for (0-100)
+}
+
+// FlagConfig is the configuration for a feature flag store
+type FlagConfig struct {
+	CacheTTL            time.Duration   // Max time a flag is cached locally, negative disables (default: 30s)
+	DisableSubscription bool            // Only the cache ttl bounds stale flags (no pub/sub invalidation)
+	ErrorHandler        func(err error) // Fired when the subscription fails (optional)
+	RetryWait           time.Duration   // Wait before resubscribing after a failure (default: 1s)
+}
+
+// FlagStore reads and writes feature flags, caching them locally and dropping changed flags
+// from the cache when notified on the FlagChannel
+type FlagStore struct {
+	cache  map[string]cachedFlag
+	cancel context.CancelFunc
+	client *Client
+	config FlagConfig
+	done   chan struct{}
+	mu     sync.RWMutex
+}
+
+// cachedFlag is a locally cached flag (nil if the flag does not exist)
+type cachedFlag struct {
+	expires time.Time
+	flag    *Flag
+}
+
+// NewFlagStore creates a new feature flag store and subscribes to flag changes
+// (see: FlagConfig), Close() stops the subscription
+func NewFlagStore(client *Client, config FlagConfig) *FlagStore {
+	if config.CacheTTL == 0 {
+		config.CacheTTL = defaultFlagCacheTTL
+	}
+	if config.RetryWait <= 0 {
+		config.RetryWait = defaultFlagRetryWait
+	}
+
+	store := &FlagStore{
+		cache:  make(map[string]cachedFlag),
+		client: client,
+		config: config,
+		done:   make(chan struct{}),
+	}
+	if config.CacheTTL < 0 || config.DisableSubscription {
+		close(store.done)
+		return store
+	}
+
+	var ctx context.Context
+	ctx, store.cancel = context.WithCancel(context.Background())
+	go store.run(ctx)
+	return store
+}
+
+// Close stops the subscription to flag changes
+func (s *FlagStore) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	<-s.done
+}
+
+// SetFlag will set the state of the flag and notify all the stores of the change
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/hset
+// https://redis.io/commands/publish
+// https://redis.io/commands/exec
+func (s *FlagStore) SetFlag(ctx context.Context, name string, flag Flag) error {
+	if len(name) == 0 {
+		return errors.New("missing required parameter: name")
+	} else if flag.Percentage < 0 || flag.Percentage > 100 {
+		return ErrInvalidPercentage
+	}
+	conn, err := s.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.client.CloseConnection(conn)
+
+	if err = conn.Send(MultiCommand); err != nil {
+		return err
+	}
+	if err = conn.Send(HashKeySetCommand, FlagPrefix+name, flagEnabledField, flag.Enabled,
+		flagPercentageField, flag.Percentage); err != nil {
+		return err
+	}
+	if err = conn.Send(PublishCommand, FlagChannel, name); err != nil {
+		return err
+	}
+	_, err = conn.Do(ExecuteCommand)
+	s.invalidate(name)
+	return err
+}
+
+// DeleteFlag will remove the flag (disabled for all subjects) and notify all the stores of the change
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/del
+// https://redis.io/commands/publish
+// https://redis.io/commands/exec
+func (s *FlagStore) DeleteFlag(ctx context.Context, name string) error {
+	conn, err := s.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.client.CloseConnection(conn)
+
+	if err = conn.Send(MultiCommand); err != nil {
+		return err
+	}
+	if err = conn.Send(DeleteCommand, FlagPrefix+name); err != nil {
+		return err
+	}
+	if err = conn.Send(PublishCommand, FlagChannel, name); err != nil {
+		return err
+	}
+	_, err = conn.Do(ExecuteCommand)
+	s.invalidate(name)
+	return err
+}
+
+// GetFlag returns the state of the flag (from the local cache if cached)
+// Returns redis.ErrNil if the flag does not exist
+//
+// Spec: https://redis.io/commands/hgetall
+func (s *FlagStore) GetFlag(ctx context.Context, name string) (*Flag, error) {
+	now := s.client.Clock().Now()
+	s.mu.RLock()
+	cached, ok := s.cache[name]
+	s.mu.RUnlock()
+	if !ok || !now.Before(cached.expires) {
+		var err error
+		if cached.flag, err = s.loadFlag(ctx, name); err != nil {
+			return nil, err
+		}
+		if s.config.CacheTTL > 0 {
+			s.mu.Lock()
+			s.cache[name] = cachedFlag{expires: now.Add(s.config.CacheTTL), flag: cached.flag}
+			s.mu.Unlock()
+		}
+	}
+	if cached.flag == nil {
+		return nil, redis.ErrNil
+	}
+	flag := *cached.flag
+	return &flag, nil
+}
+
+// IsEnabled returns true if the flag is enabled for the subject (IE: a user id)
+// Subjects are assigned to the rollout percentage consistently (the same subject always gets the
+// same result for the same percentage), a missing flag is disabled
+func (s *FlagStore) IsEnabled(ctx context.Context, name, subjectID string) (bool, error) {
+	flag, err := s.GetFlag(ctx, name)
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return flag.Enabled && flagBucket(name, subjectID) < flag.Percentage, nil
+}
+
+// loadFlag reads the flag from redis (nil if the flag does not exist)
+func (s *FlagStore) loadFlag(ctx context.Context, name string) (flag *Flag, err error) {
+	var values map[string]string
+	if err = s.client.read(ctx, func(conn redis.Conn) (readErr error) {
+		values, readErr = redis.StringMap(conn.Do(HashGetAllCommand, FlagPrefix+name))
+		return
+	}); err != nil || len(values) == 0 {
+		return
+	}
+
+	flag = &Flag{}
+	if flag.Enabled, err = strconv.ParseBool(values[flagEnabledField]); err != nil {
+		return nil, err
+	}
+	if flag.Percentage, err = strconv.Atoi(values[flagPercentageField]); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// invalidate removes the flag from the local cache
+func (s *FlagStore) invalidate(name string) {
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+}
+
+// run keeps the subscription to flag changes until the context is done
+func (s *FlagStore) run(ctx context.Context) {
+	defer close(s.done)
+	for {
+		err := s.subscribe(ctx)
+
+		// Changes may have been missed while not subscribed
+		s.mu.Lock()
+		s.cache = make(map[string]cachedFlag)
+		s.mu.Unlock()
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && s.config.ErrorHandler != nil {
+			s.config.ErrorHandler(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.client.Clock().After(s.config.RetryWait):
+		}
+	}
+}
+
+// subscribe drops each changed flag from the local cache until the context is done or the
+// subscription fails
+func (s *FlagStore) subscribe(ctx context.Context) error {
+	conn, err := s.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.client.CloseConnection(conn)
+
+	pubSub := redis.PubSubConn{Conn: conn}
+	if err = pubSub.Subscribe(FlagChannel); err != nil {
+		return err
+	}
+
+	// Unsubscribing ends the receive loop (finished before the connection is closed)
+	stopped := make(chan struct{})
+	unsubscribed := make(chan struct{})
+	defer func() {
+		close(stopped)
+		<-unsubscribed
+	}()
+	go func() {
+		defer close(unsubscribed)
+		select {
+		case <-ctx.Done():
+			_ = pubSub.Unsubscribe()
+		case <-stopped:
+		}
+	}()
+
+	for {
+		switch reply := pubSub.ReceiveWithTimeout(0).(type) {
+		case redis.Message:
+			s.invalidate(string(reply.Data))
+		case redis.Subscription:
+			if reply.Count == 0 {
+				return nil
+			}
+		case error:
+			return reply
+		}
+	}
+}
+
+// flagBucket returns the rollout bucket (0-99) of the subject for the flag
+func flagBucket(name, subjectID string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(name + ":" + subjectID))
+	return int(hash.Sum32() % 100)
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFlagStore tests the FlagStore
+func TestFlagStore(t *testing.T) {
+
+	t.Run("invalid flags", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		store := NewFlagStore(client, FlagConfig{DisableSubscription: true})
+		defer store.Close()
+
+		err := store.SetFlag(context.Background(), "", Flag{Enabled: true, Percentage: 100})
+		assert.Error(t, err)
+
+		err = store.SetFlag(context.Background(), "checkout", Flag{Enabled: true, Percentage: 101})
+		assert.ErrorIs(t, err, ErrInvalidPercentage)
+	})
+
+	t.Run("set flag using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		store := NewFlagStore(client, FlagConfig{DisableSubscription: true})
+		defer store.Close()
+
+		conn.Command(MultiCommand)
+		setCmd := conn.Command(HashKeySetCommand, FlagPrefix+"checkout", flagEnabledField, true,
+			flagPercentageField, 50)
+		publishCmd := conn.Command(PublishCommand, FlagChannel, "checkout")
+		conn.Command(ExecuteCommand)
+
+		err := store.SetFlag(context.Background(), "checkout", Flag{Enabled: true, Percentage: 50})
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.True(t, publishCmd.Called)
+	})
+
+	t.Run("cached flags using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		clock := NewManualClock(time.Now())
+		client.SetClock(clock)
+		store := NewFlagStore(client, FlagConfig{CacheTTL: time.Minute, DisableSubscription: true})
+		defer store.Close()
+
+		getCmd := conn.Command(HashGetAllCommand, FlagPrefix+"checkout").Expect([]interface{}{
+			[]byte(flagEnabledField), []byte("1"), []byte(flagPercentageField), []byte("100"),
+		})
+		conn.Command(HashGetAllCommand, FlagPrefix+"missing").Expect([]interface{}{})
+
+		for i := 0; i < 3; i++ {
+			enabled, err := store.IsEnabled(context.Background(), "checkout", "user-1")
+			assert.NoError(t, err)
+			assert.True(t, enabled)
+		}
+		assert.Equal(t, 1, conn.Stats(getCmd))
+
+		// Expired from the local cache
+		clock.Advance(time.Minute)
+		flag, err := store.GetFlag(context.Background(), "checkout")
+		assert.NoError(t, err)
+		assert.Equal(t, &Flag{Enabled: true, Percentage: 100}, flag)
+		assert.Equal(t, 2, conn.Stats(getCmd))
+
+		// Missing flags are disabled
+		_, err = store.GetFlag(context.Background(), "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+		var enabled bool
+		enabled, err = store.IsEnabled(context.Background(), "missing", "user-1")
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+	})
+
+	t.Run("percentage rollout", func(t *testing.T) {
+		t.Parallel()
+
+		var enabled int
+		for i := 0; i < 1000; i++ {
+			if flagBucket("checkout", fmt.Sprintf("user-%d", i)) < 25 {
+				enabled++
+			}
+		}
+		assert.InDelta(t, 250, enabled, 50)
+
+		// Consistent for the subject
+		assert.Equal(t, flagBucket("checkout", "user-1"), flagBucket("checkout", "user-1"))
+	})
+
+	t.Run("invalidation using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		writer := NewFlagStore(client, FlagConfig{DisableSubscription: true})
+		defer writer.Close()
+		reader := NewFlagStore(client, FlagConfig{CacheTTL: time.Hour})
+		defer reader.Close()
+
+		var enabled bool
+		enabled, err = reader.IsEnabled(context.Background(), "checkout", "user-1")
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+
+		// Wait for the subscription before changing the flag
+		assert.Eventually(t, func() bool {
+			count, countErr := redis.Values(conn.Do("PUBSUB", "NUMSUB", FlagChannel))
+			return countErr == nil && len(count) == 2 && count[1] == int64(1)
+		}, 2*time.Second, 10*time.Millisecond)
+
+		err = writer.SetFlag(context.Background(), "checkout", Flag{Enabled: true, Percentage: 100})
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			enabled, err = reader.IsEnabled(context.Background(), "checkout", "user-1")
+			return err == nil && enabled
+		}, 2*time.Second, 10*time.Millisecond)
+
+		err = writer.DeleteFlag(context.Background(), "checkout")
+		assert.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			enabled, err = reader.IsEnabled(context.Background(), "checkout", "user-1")
+			return err == nil && !enabled
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+}
+
+// ExampleFlagStore_IsEnabled is an example of the method IsEnabled()
+func ExampleFlagStore_IsEnabled() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Flags without pub/sub invalidation
+	store := NewFlagStore(client, FlagConfig{DisableSubscription: true})
+	defer store.Close()
+
+	// Mock a flag enabled for all subjects
+	conn.Command(HashGetAllCommand, FlagPrefix+"checkout").Expect([]interface{}{
+		[]byte(flagEnabledField), []byte("1"), []byte(flagPercentageField), []byte("100"),
+	})
+
+	enabled, _ := store.IsEnabled(context.Background(), "checkout", "user-1")
+	fmt.Printf("enabled: %v", enabled)
+	// Output:enabled: true
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+)
+
+// ErrFlushNotAllowed is returned when a flush is rejected by the flush protection
+var ErrFlushNotAllowed = errors.New("flush is not allowed (see: SetFlushProtection())")
+
+// FlushProtection guards DestroyCache() and DestroyCacheDB() (see: SetFlushProtection())
+type FlushProtection struct {
+	OnFlush func(ctx context.Context, event FlushEvent) // Audit hook, fired for every flush (allowed or rejected)
+	Token   string                                      // Token required with WithFlushToken() (empty: WithAllowFlush(true))
+}
+
+// FlushEvent is the audit record of a flush (see: FlushProtection.OnFlush)
+type FlushEvent struct {
+	Allowed bool      // The flush passed the protection
+	Command string    // Flush command (FLUSHALL or FLUSHDB)
+	Err     error     // Error of the flush (ErrFlushNotAllowed if rejected)
+	Time    time.Time // When the flush was requested
+}
+
+// FlushOption confirms a flush for DestroyCache() and DestroyCacheDB()
+type FlushOption func(*flushConfig)
+
+// flushConfig holds the confirmation of a flush
+type flushConfig struct {
+	allow bool
+	token string
+}
+
+// WithAllowFlush explicitly enables the flush (required with a protection without a token)
+func WithAllowFlush(allow bool) FlushOption {
+	return func(c *flushConfig) {
+		c.allow = allow
+	}
+}
+
+// WithFlushToken confirms the flush with the token of the protection
+func WithFlushToken(token string) FlushOption {
+	return func(c *flushConfig) {
+		c.token = token
+	}
+}
+
+// SetFlushProtection guards DestroyCache() and DestroyCacheDB() against accidental calls
+// (IE: set in production from the environment), nil removes the protection
+//
+// With a token each flush must pass WithFlushToken(token), without a token each flush must
+// pass WithAllowFlush(true); every flush (allowed or rejected) fires the audit hook
+// Only the client functions are guarded (not Raw functions on custom connections), and the
+// Cacher interface can not confirm a flush
+func (c *Client) SetFlushProtection(protection *FlushProtection) {
+	c.mu.Lock()
+	c.flushProtection = protection
+	c.mu.Unlock()
+}
+
+// checkFlush returns ErrFlushNotAllowed if the flush is not confirmed for the protection
+func checkFlush(protection *FlushProtection, options []FlushOption) error {
+	if protection == nil {
+		return nil
+	}
+	config := new(flushConfig)
+	for _, option := range options {
+		option(config)
+	}
+	if len(protection.Token) > 0 {
+		if subtle.ConstantTimeCompare([]byte(config.token), []byte(protection.Token)) != 1 {
+			return ErrFlushNotAllowed
+		}
+	} else if !config.allow {
+		return ErrFlushNotAllowed
+	}
+	return nil
+}
+
+// destroyCache runs the flush command if the protection of the client allows it, and fires the audit hook
+func destroyCache(ctx context.Context, client *Client, command string, options []FlushOption) error {
+	client.mu.RLock()
+	protection := client.flushProtection
+	client.mu.RUnlock()
+
+	now := client.Clock().Now()
+	err := checkFlush(protection, options)
+	allowed := err == nil
+	if allowed {
+		conn, connErr := client.GetConnectionWithContext(ctx)
+		if connErr != nil {
+			err = connErr
+		} else {
+			_, err = conn.Do(command)
+			client.CloseConnection(conn)
+		}
+	}
+
+	if protection != nil && protection.OnFlush != nil {
+		protection.OnFlush(ctx, FlushEvent{Allowed: allowed, Command: command, Err: err, Time: now})
+	}
+	return err
+}
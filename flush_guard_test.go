@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetFlushProtection tests the method SetFlushProtection()
+func TestSetFlushProtection(t *testing.T) {
+
+	t.Run("token required using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var events []FlushEvent
+		client.SetFlushProtection(&FlushProtection{
+			OnFlush: func(_ context.Context, event FlushEvent) {
+				events = append(events, event)
+			},
+			Token: "production",
+		})
+		flushCmd := conn.Command(FlushAllCommand)
+
+		// Not confirmed
+		err := DestroyCache(context.Background(), client)
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+		err = DestroyCache(context.Background(), client, WithAllowFlush(true))
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+		err = DestroyCache(context.Background(), client, WithFlushToken("staging"))
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+		assert.False(t, flushCmd.Called)
+
+		// Confirmed
+		err = DestroyCache(context.Background(), client, WithFlushToken("production"))
+		assert.NoError(t, err)
+		assert.True(t, flushCmd.Called)
+
+		assert.Equal(t, 4, len(events))
+		assert.False(t, events[0].Allowed)
+		assert.ErrorIs(t, events[0].Err, ErrFlushNotAllowed)
+		assert.True(t, events[3].Allowed)
+		assert.NoError(t, events[3].Err)
+		assert.Equal(t, FlushAllCommand, events[3].Command)
+	})
+
+	t.Run("allow flush required using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		client.SetFlushProtection(&FlushProtection{})
+		flushCmd := conn.Command(FlushDBCommand)
+
+		err := DestroyCacheDB(context.Background(), client)
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+		err = DestroyCacheDB(context.Background(), client, WithAllowFlush(false))
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+		assert.False(t, flushCmd.Called)
+
+		err = DestroyCacheDB(context.Background(), client, WithAllowFlush(true))
+		assert.NoError(t, err)
+		assert.True(t, flushCmd.Called)
+
+		// No protection
+		client.SetFlushProtection(nil)
+		err = DestroyCacheDB(context.Background(), client)
+		assert.NoError(t, err)
+	})
+
+	t.Run("destroy db using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		client.SetFlushProtection(&FlushProtection{Token: "production"})
+		err = DestroyCacheDB(context.Background(), client)
+		assert.ErrorIs(t, err, ErrFlushNotAllowed)
+
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		err = DestroyCacheDB(context.Background(), client, WithFlushToken("production"))
+		assert.NoError(t, err)
+
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleClient_SetFlushProtection is an example of the method SetFlushProtection()
+func ExampleClient_SetFlushProtection() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Require the token for every flush
+	client.SetFlushProtection(&FlushProtection{Token: "flush-production"})
+
+	err := DestroyCache(context.Background(), client)
+	fmt.Print(err)
+	// Output:flush is not allowed (see: SetFlushProtection())
+}
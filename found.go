@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SetMissingAsEmpty switches the translation of misses on or off (safe to call at any time)
+//
+// When on, Get(), GetBytes() and HashGet() return an empty value and no error for a missing key
+// instead of redis.ErrNil, use GetWithFound(), GetBytesWithFound() or HashGetWithFound() to tell
+// an empty value from a miss without checking driver errors
+func (c *Client) SetMissingAsEmpty(missingAsEmpty bool) {
+	var value uint32
+	if missingAsEmpty {
+		value = 1
+	}
+	atomic.StoreUint32(&c.missingAsEmpty, value)
+}
+
+// MissingAsEmpty returns true if misses return an empty value (see: SetMissingAsEmpty())
+func (c *Client) MissingAsEmpty() bool {
+	return atomic.LoadUint32(&c.missingAsEmpty) == 1
+}
+
+// missingError returns nil for a miss if misses return an empty value
+func (c *Client) missingError(err error) error {
+	if c.MissingAsEmpty() && errors.Is(err, redis.ErrNil) {
+		return nil
+	}
+	return err
+}
+
+// GetWithFound gets a key from redis in string format, found is false (with no error) if the
+// key is missing
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: GetRaw()
+func GetWithFound(ctx context.Context, client *Client, key string) (string, bool, error) {
+	value, err := get(ctx, client, key)
+	if errors.Is(err, redis.ErrNil) {
+		value, err = client.readThrough(ctx, key)
+	}
+	found, err := foundResult(err)
+	return value, found, err
+}
+
+// GetBytesWithFound gets a key from redis formatted in bytes, found is false (with no error) if
+// the key is missing
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: GetBytesRaw()
+func GetBytesWithFound(ctx context.Context, client *Client, key string) ([]byte, bool, error) {
+	value, err := getBytes(ctx, client, key)
+	if errors.Is(err, redis.ErrNil) {
+		var loaded string
+		if loaded, err = client.readThrough(ctx, key); err == nil {
+			value = []byte(loaded)
+		}
+	}
+	found, err := foundResult(err)
+	return value, found, err
+}
+
+// HashGetWithFound gets a key from redis via hash, found is false (with no error) if the hash
+// or the key is missing
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetRaw()
+func HashGetWithFound(ctx context.Context, client *Client, hash, key string) (string, bool, error) {
+	value, err := hashGet(ctx, client, hash, key)
+	found, err := foundResult(err)
+	return value, found, err
+}
+
+// foundResult translates a miss into not found
+func foundResult(err error) (bool, error) {
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetWithFound tests the methods GetWithFound(), GetBytesWithFound() and HashGetWithFound()
+func TestGetWithFound(t *testing.T) {
+
+	t.Run("found and missing using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect([]byte(""))
+		conn.Command(GetCommand, "missing").Expect(nil)
+		conn.Command(HashGetCommand, testHashName, "missing").Expect(nil)
+
+		value, found, err := GetWithFound(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, "", value)
+
+		value, found, err = GetWithFound(context.Background(), client, "missing")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, "", value)
+
+		var data []byte
+		data, found, err = GetBytesWithFound(context.Background(), client, "missing")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, data)
+
+		value, found, err = HashGetWithFound(context.Background(), client, testHashName, "missing")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.Equal(t, "", value)
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).ExpectError(errors.New("connection lost"))
+
+		_, found, err := GetWithFound(context.Background(), client, testKey)
+		assert.Error(t, err)
+		assert.False(t, found)
+	})
+}
+
+// TestSetMissingAsEmpty tests the method SetMissingAsEmpty()
+func TestSetMissingAsEmpty(t *testing.T) {
+
+	t.Run("misses are errors by default", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(nil)
+
+		assert.False(t, client.MissingAsEmpty())
+		_, err := Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("misses return empty values", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetMissingAsEmpty(true)
+
+		conn.Command(GetCommand, testKey).Expect(nil)
+		conn.Command(HashGetCommand, testHashName, testKey).Expect(nil)
+
+		value, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "", value)
+
+		var data []byte
+		data, err = GetBytes(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		value, err = HashGet(context.Background(), client, testHashName, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "", value)
+
+		// Still distinguished with found
+		var found bool
+		_, found, err = GetWithFound(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		client.SetMissingAsEmpty(false)
+		_, err = Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("bypassed misses return empty values", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetBypass(true)
+		client.SetMissingAsEmpty(true)
+
+		value, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "", value)
+	})
+}
+
+// ExampleGetWithFound is an example of the method GetWithFound()
+func ExampleGetWithFound() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a missing key
+	conn.Command(GetCommand, testKey).Expect(nil)
+
+	_, found, _ := GetWithFound(context.Background(), client, testKey)
+	fmt.Printf("found: %v", found)
+	// Output:found: false
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/gomodule/redigo/redis"
@@ -9,17 +10,35 @@ import (
 
 // HashSet will set the hashKey to the value in the specified hashName and link a
 // reference to each dependency for the entire hash
+// Applies the value size guard if set, values are never chunked (see: SetValueSizeGuard())
+// Per-call options (IE: WithTTL(), WithNX()) use method: HashSetWith()
+// Writes of hashes with a tombstone are skipped if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: HashSetRaw()
 func HashSet(ctx context.Context, client *Client, hashName, hashKey string,
 	value interface{}, dependencies ...string) error {
-	conn, err := client.GetConnectionWithContext(ctx)
+	if client.IsBypassed() {
+		return nil
+	}
+	value, _, err := client.guardValue(hashName+":"+hashKey, value, false)
 	if err != nil {
 		return err
 	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return err
+	}
 	defer client.CloseConnection(conn)
-	return HashSetRaw(conn, hashName, hashKey, value, dependencies...)
+	if tombstone, tombstoneErr := client.hasTombstone(conn, hashName); tombstoneErr != nil || tombstone {
+		return tombstoneErr
+	}
+	if err = HashSetRaw(conn, hashName, hashKey, value, dependencies...); err == nil {
+		client.fireHooks(ctx, KeyEvent{
+			Dependencies: dependencies, Field: hashKey, Key: hashName, Operation: OperationSet,
+		})
+	}
+	return err
 }
 
 // HashSetRaw will set the hashKey to the value in the specified hashName and link a
@@ -28,24 +47,41 @@ func HashSet(ctx context.Context, client *Client, hashName, hashKey string,
 //
 // Spec: https://redis.io/commands/hset
 func HashSetRaw(conn redis.Conn, hashName, hashKey string, value interface{}, dependencies ...string) error {
-	if _, err := conn.Do(HashKeySetCommand, hashName, hashKey, value); err != nil {
-		return err
-	}
-
-	return linkDependencies(conn, hashName, dependencies...)
+	return doLinkDependencies(conn, hashName, dependencies, HashKeySetCommand, hashName, hashKey, value)
 }
 
 // HashGet gets a key from redis via hash
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Reads values compressed by the value size guard (see: SetValueSizeGuard())
+// Misses return an empty value instead of redis.ErrNil if set (see: SetMissingAsEmpty())
+// Hashes with a tombstone are a miss if set (see: SetInvalidationTombstones())
 //
 // Custom connections use method: HashGetRaw()
 func HashGet(ctx context.Context, client *Client, hash, key string) (string, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return "", err
+	value, err := hashGet(ctx, client, hash, key)
+	return value, client.missingError(err)
+}
+
+// hashGet gets the value without translating a miss (see: SetMissingAsEmpty())
+func hashGet(ctx context.Context, client *Client, hash, key string) (value string, err error) {
+	if client.IsBypassed() {
+		return "", redis.ErrNil
 	}
-	defer client.CloseConnection(conn)
-	return HashGetRaw(conn, hash, key)
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		if readErr = client.checkTombstone(conn, hash); readErr != nil {
+			return
+		}
+		if value, readErr = HashGetRaw(conn, hash, key); readErr == nil &&
+			strings.HasPrefix(value, encodedValuePrefix) {
+			var data []byte
+			data, readErr = decompressValue([]byte(value))
+			value = string(data)
+		}
+		return
+	})
+	client.fireGetHooks(ctx, hash, key, err)
+	return
 }
 
 // HashGetRaw gets a key from redis via hash
@@ -57,15 +93,24 @@ func HashGetRaw(conn redis.Conn, hash, key string) (string, error) {
 }
 
 // HashMapGet gets values from a hash map for corresponding keys
+// Hashes with a tombstone are missing (empty values) if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: HashMapGetRaw()
 func HashMapGet(ctx context.Context, client *Client, hashName string, keys ...interface{}) ([]string, error) {
+	if client.IsBypassed() {
+		return make([]string, len(keys)), nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer client.CloseConnection(conn)
+	if tombstone, err := client.hasTombstone(conn, hashName); err != nil {
+		return nil, err
+	} else if tombstone {
+		return make([]string, len(keys)), nil
+	}
 	return HashMapGetRaw(conn, hashName, keys...)
 }
 
@@ -84,17 +129,27 @@ func HashMapGetRaw(conn redis.Conn, hashName string, keys ...interface{}) ([]str
 
 // HashMapSet will set the hashKey to the value in the specified hashName and link a
 // reference to each dependency for the entire hash
+// Writes of hashes with a tombstone are skipped if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: HashMapSetRaw()
 func HashMapSet(ctx context.Context, client *Client, hashName string,
 	pairs [][2]interface{}, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
 	}
 	defer client.CloseConnection(conn)
-	return HashMapSetRaw(conn, hashName, pairs, dependencies...)
+	if tombstone, tombstoneErr := client.hasTombstone(conn, hashName); tombstoneErr != nil || tombstone {
+		return tombstoneErr
+	}
+	if err = HashMapSetRaw(conn, hashName, pairs, dependencies...); err == nil {
+		client.fireHooks(ctx, KeyEvent{Dependencies: dependencies, Key: hashName, Operation: OperationSet})
+	}
+	return err
 }
 
 // HashMapSetRaw will set the hashKey to the value in the specified hashName and link a
@@ -103,17 +158,20 @@ func HashMapSet(ctx context.Context, client *Client, hashName string,
 //
 // Spec: https://redis.io/commands/hmset
 func HashMapSetRaw(conn redis.Conn, hashName string, pairs [][2]interface{}, dependencies ...string) error {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return err
+	}
 
-	// Set the arguments
-	args := make([]interface{}, 0, 2*len(pairs)+1)
-	args = append(args, hashName)
+	// Set the arguments (pooled)
+	args := getArgs()
+	defer putArgs(args)
+	*args = append(*args, hashName)
 	for _, pair := range pairs {
-		args = append(args, pair[0])
-		args = append(args, pair[1])
+		*args = append(*args, pair[0], pair[1])
 	}
 
 	// Set the hash map
-	if _, err := conn.Do(HashMapSetCommand, args...); err != nil {
+	if _, err := conn.Do(HashMapSetCommand, *args...); err != nil {
 		return err
 	}
 
@@ -123,28 +181,47 @@ func HashMapSetRaw(conn redis.Conn, hashName string, pairs [][2]interface{}, dep
 
 // HashMapSetExp will set the hashKey to the value in the specified hashName and link a
 // reference to each dependency for the entire hash
+// Writes of hashes with a tombstone are skipped if set (see: SetInvalidationTombstones())
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: HashMapSetExpRaw()
 func HashMapSetExp(ctx context.Context, client *Client, hashName string, pairs [][2]interface{},
 	ttl time.Duration, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
 	}
 	defer client.CloseConnection(conn)
-	return HashMapSetExpRaw(conn, hashName, pairs, ttl, dependencies...)
+	if tombstone, tombstoneErr := client.hasTombstone(conn, hashName); tombstoneErr != nil || tombstone {
+		return tombstoneErr
+	}
+	if err = HashMapSetExpRaw(conn, hashName, pairs, ttl, dependencies...); err == nil {
+		client.fireHooks(ctx, KeyEvent{Dependencies: dependencies, Key: hashName, Operation: OperationSet, TTL: ttl})
+	}
+	return err
 }
 
 // HashMapSetExpRaw will set the hashKey to the value in the specified hashName and link a
 // reference to each dependency for the entire hash
+// The hash, expiration and dependencies are set in one transaction (the hash is never left without a ttl)
+// The ttl uses millisecond precision if it is not a whole number of seconds
 // Uses existing connection (does not close connection)
 //
-// Commands:
+// Commands used:
+// https://redis.io/commands/multi
 // https://redis.io/commands/hmset
 // https://redis.io/commands/expire
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
 func HashMapSetExpRaw(conn redis.Conn, hashName string, pairs [][2]interface{},
-	ttl time.Duration, dependencies ...string) error {
+	ttl time.Duration, dependencies ...string) (err error) {
+	if err = checkDependencyLimit(conn, dependencies...); err != nil {
+		return
+	}
 
 	// Set the arguments
 	args := make([]interface{}, 0, 2*len(pairs)+1)
@@ -153,16 +230,95 @@ func HashMapSetExpRaw(conn redis.Conn, hashName string, pairs [][2]interface{},
 		args = append(args, pair[0], pair[1])
 	}
 
-	// Set the hash map
-	if _, err := conn.Do(HashMapSetCommand, args...); err != nil {
+	// Set the hash map and the expiration
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	if err = conn.Send(HashMapSetCommand, args...); err != nil {
+		return
+	}
+	command, expireArgs := expireCommand(hashName, ttl)
+	if err = conn.Send(command, expireArgs...); err != nil {
+		return
+	}
+
+	// Link the dependencies
+	if err = sendLinkDependencies(conn, hashName, dependencies...); err != nil {
+		return
+	}
+
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
+
+// HashSetStruct will set each exported field of the struct as a field of the hash and link a
+// reference to each dependency for the entire hash (field names use the `redis` tag)
+// Writes of hashes with a tombstone are skipped if set (see: SetInvalidationTombstones())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: HashSetStructRaw()
+func HashSetStruct(ctx context.Context, client *Client, hashName string,
+	value interface{}, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
 		return err
 	}
+	defer client.CloseConnection(conn)
+	if tombstone, tombstoneErr := client.hasTombstone(conn, hashName); tombstoneErr != nil || tombstone {
+		return tombstoneErr
+	}
+	return HashSetStructRaw(conn, hashName, value, dependencies...)
+}
 
-	// Fire the "expire" command
-	if _, err := conn.Do(ExpireCommand, hashName, int64(ttl.Seconds())); err != nil {
+// HashSetStructRaw will set each exported field of the struct as a field of the hash and link a
+// reference to each dependency for the entire hash (field names use the `redis` tag)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hmset
+func HashSetStructRaw(conn redis.Conn, hashName string, value interface{}, dependencies ...string) error {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return err
+	}
+	if _, err := conn.Do(HashMapSetCommand, redis.Args{}.Add(hashName).AddFlat(value)...); err != nil {
 		return err
 	}
 
-	// Link and return the error
 	return linkDependencies(conn, hashName, dependencies...)
 }
+
+// HashGetStruct gets all fields of the hash into the struct (field names use the `redis` tag)
+// redis.ErrNil is returned if the hash does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Hashes with a tombstone are missing if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: HashGetStructRaw()
+func HashGetStruct(ctx context.Context, client *Client, hashName string, dest interface{}) error {
+	if client.IsBypassed() {
+		return redis.ErrNil
+	}
+	return client.read(ctx, func(conn redis.Conn) error {
+		if err := client.checkTombstone(conn, hashName); err != nil {
+			return err
+		}
+		return HashGetStructRaw(conn, hashName, dest)
+	})
+}
+
+// HashGetStructRaw gets all fields of the hash into the struct (field names use the `redis` tag)
+// redis.ErrNil is returned if the hash does not exist
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hgetall
+func HashGetStructRaw(conn redis.Conn, hashName string, dest interface{}) error {
+	values, err := redis.Values(conn.Do(HashGetAllCommand, hashName))
+	if err != nil {
+		return err
+	} else if len(values) == 0 {
+		return redis.ErrNil
+	}
+	return redis.ScanStruct(values, dest)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gomodule/redigo/redis"
 	"github.com/rafaeljusto/redigomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -383,6 +384,16 @@ func TestHashMapSetExp(t *testing.T) {
 				},
 				2 * time.Second,
 			},
+			{
+				"millisecond ttl",
+				testHashName,
+				testKey,
+				[]string{testDependantKey},
+				[][2]interface{}{
+					{"pair-1", "pair-1-value"},
+				},
+				1500 * time.Millisecond,
+			},
 		}
 		for _, test := range tests {
 			t.Run(test.testCase, func(t *testing.T) {
@@ -396,24 +407,20 @@ func TestHashMapSetExp(t *testing.T) {
 
 				var commands []*redigomock.Cmd
 
-				// The main command to test
+				// The main command to test (one transaction)
+				commands = append(commands, conn.Command(MultiCommand))
 				commands = append(commands, conn.Command(HashMapSetCommand, args...))
-				commands = append(commands, conn.Command(ExpireCommand, test.hashName, int64(test.expiration.Seconds())))
+				command, expireArgs := expireCommand(test.hashName, test.expiration)
+				commands = append(commands, conn.Command(command, expireArgs...))
 
 				// Loop for each dependency
-				if len(test.dependencies) > 0 {
-					commands = append(commands, conn.Command(MultiCommand))
-					for _, dep := range test.dependencies {
-						commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+dep, test.hashName))
-					}
-					commands = append(commands, conn.Command(ExecuteCommand))
-
-					err := HashMapSetExp(context.Background(), client, test.hashName, test.pairs, test.expiration, test.dependencies...)
-					assert.NoError(t, err)
-				} else {
-					err := HashMapSetExp(context.Background(), client, test.hashName, test.pairs, test.expiration, test.dependencies...)
-					assert.NoError(t, err)
+				for _, dep := range test.dependencies {
+					commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+dep, test.hashName))
 				}
+				commands = append(commands, conn.Command(ExecuteCommand))
+
+				err := HashMapSetExp(context.Background(), client, test.hashName, test.pairs, test.expiration, test.dependencies...)
+				assert.NoError(t, err)
 
 				for _, c := range commands {
 					assert.Equal(t, true, c.Called)
@@ -497,3 +504,76 @@ func ExampleHashMapSetExp() {
 	fmt.Printf("set: %s pairs: %d dep key: %s exp: %v", testHashName, len(pairs), testDependantKey, 5*time.Second)
 	// Output:set: test-hash-name pairs: 3 dep key: test-dependant-key-name exp: 5s
 }
+
+// testHashDocument is a struct used to test the struct hash methods
+type testHashDocument struct {
+	Name  string `redis:"name"`
+	Price int    `redis:"price"`
+}
+
+// TestHashSetStruct is testing the method HashSetStruct()
+func TestHashSetStruct(t *testing.T) {
+
+	t.Run("set struct using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(HashMapSetCommand, testHashName, "name", "widget", "price", 5).Expect("OK")
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testHashName)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		err := HashSetStruct(
+			context.Background(), client, testHashName, &testHashDocument{Name: "widget", Price: 5}, testDependantKey,
+		)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("set and get struct using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var doc testHashDocument
+		err = HashGetStructRaw(conn, testHashName, &doc)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		err = HashSetStructRaw(conn, testHashName, &testHashDocument{Name: "widget", Price: 5})
+		assert.NoError(t, err)
+
+		err = HashGetStructRaw(conn, testHashName, &doc)
+		assert.NoError(t, err)
+		assert.Equal(t, testHashDocument{Name: "widget", Price: 5}, doc)
+	})
+}
+
+// ExampleHashSetStruct is an example of the method HashSetStruct()
+func ExampleHashSetStruct() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the hmset command
+	conn.GenericCommand(HashMapSetCommand).Expect("OK")
+
+	// Set the struct
+	err := HashSetStruct(context.Background(), client, "product:1", &testHashDocument{Name: "widget", Price: 5})
+	fmt.Printf("set: %v", err == nil)
+	// Output:set: true
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// HashGetInt64 gets a field of the hash as an int64
+// redis.ErrNil is returned if the field does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetInt64Raw()
+func HashGetInt64(ctx context.Context, client *Client, hash, key string) (int64, error) {
+	value, err := hashGetReply(ctx, client, hash, key)
+	return redis.Int64(value, err)
+}
+
+// HashGetInt64Raw gets a field of the hash as an int64
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hget
+func HashGetInt64Raw(conn redis.Conn, hash, key string) (int64, error) {
+	return redis.Int64(conn.Do(HashGetCommand, hash, key))
+}
+
+// HashGetFloat64 gets a field of the hash as a float64
+// redis.ErrNil is returned if the field does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetFloat64Raw()
+func HashGetFloat64(ctx context.Context, client *Client, hash, key string) (float64, error) {
+	value, err := hashGetReply(ctx, client, hash, key)
+	return redis.Float64(value, err)
+}
+
+// HashGetFloat64Raw gets a field of the hash as a float64
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hget
+func HashGetFloat64Raw(conn redis.Conn, hash, key string) (float64, error) {
+	return redis.Float64(conn.Do(HashGetCommand, hash, key))
+}
+
+// HashGetBool gets a field of the hash as a bool (IE: 1, t, true, 0, f, false)
+// redis.ErrNil is returned if the field does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetBoolRaw()
+func HashGetBool(ctx context.Context, client *Client, hash, key string) (bool, error) {
+	value, err := hashGetReply(ctx, client, hash, key)
+	return redis.Bool(value, err)
+}
+
+// HashGetBoolRaw gets a field of the hash as a bool (IE: 1, t, true, 0, f, false)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hget
+func HashGetBoolRaw(conn redis.Conn, hash, key string) (bool, error) {
+	return redis.Bool(conn.Do(HashGetCommand, hash, key))
+}
+
+// HashGetBytes gets a field of the hash formatted in bytes
+// redis.ErrNil is returned if the field does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetBytesRaw()
+func HashGetBytes(ctx context.Context, client *Client, hash, key string) ([]byte, error) {
+	return redis.Bytes(hashGetReply(ctx, client, hash, key))
+}
+
+// HashGetBytesRaw gets a field of the hash formatted in bytes
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hget
+func HashGetBytesRaw(conn redis.Conn, hash, key string) ([]byte, error) {
+	return redis.Bytes(conn.Do(HashGetCommand, hash, key))
+}
+
+// HashGetJSON gets a field of the hash and decodes the JSON into the destination
+// redis.ErrNil is returned if the field does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: HashGetJSONRaw()
+func HashGetJSON(ctx context.Context, client *Client, hash, key string, dest interface{}) error {
+	data, err := HashGetBytes(ctx, client, hash, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// HashGetJSONRaw gets a field of the hash and decodes the JSON into the destination
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hget
+func HashGetJSONRaw(conn redis.Conn, hash, key string, dest interface{}) error {
+	data, err := HashGetBytesRaw(conn, hash, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// hashGetReply gets a field of the hash (see: HashGet()) as a reply for the redis conversions
+func hashGetReply(ctx context.Context, client *Client, hash, key string) (interface{}, error) {
+	value, err := hashGet(ctx, client, hash, key)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(value), nil
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHashGetTyped tests the typed hash getters
+func TestHashGetTyped(t *testing.T) {
+
+	t.Run("typed getters using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(HashGetCommand, testHashName, "count").Expect([]byte("42"))
+		conn.Command(HashGetCommand, testHashName, "price").Expect([]byte("9.95"))
+		conn.Command(HashGetCommand, testHashName, "active").Expect([]byte("true"))
+		conn.Command(HashGetCommand, testHashName, "profile").Expect([]byte(`{"name":"go-cache"}`))
+		conn.Command(HashGetCommand, testHashName, "missing").Expect(nil)
+
+		ctx := context.Background()
+		count, err := HashGetInt64(ctx, client, testHashName, "count")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), count)
+
+		var price float64
+		price, err = HashGetFloat64(ctx, client, testHashName, "price")
+		assert.NoError(t, err)
+		assert.Equal(t, 9.95, price)
+
+		var active bool
+		active, err = HashGetBool(ctx, client, testHashName, "active")
+		assert.NoError(t, err)
+		assert.True(t, active)
+
+		var data []byte
+		data, err = HashGetBytes(ctx, client, testHashName, "count")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("42"), data)
+
+		var profile struct {
+			Name string `json:"name"`
+		}
+		err = HashGetJSON(ctx, client, testHashName, "profile", &profile)
+		assert.NoError(t, err)
+		assert.Equal(t, "go-cache", profile.Name)
+
+		// Missing fields
+		_, err = HashGetInt64(ctx, client, testHashName, "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+		err = HashGetJSON(ctx, client, testHashName, "missing", &profile)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		// Not a number
+		_, err = HashGetInt64(ctx, client, testHashName, "active")
+		assert.Error(t, err)
+	})
+
+	t.Run("typed getters using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = HashMapSetRaw(conn, testHashName, [][2]interface{}{
+			{"count", 42}, {"price", 9.95}, {"active", true}, {"tags", `["a","b"]`},
+		})
+		assert.NoError(t, err)
+
+		var count int64
+		count, err = HashGetInt64Raw(conn, testHashName, "count")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), count)
+
+		var price float64
+		price, err = HashGetFloat64Raw(conn, testHashName, "price")
+		assert.NoError(t, err)
+		assert.Equal(t, 9.95, price)
+
+		var active bool
+		active, err = HashGetBoolRaw(conn, testHashName, "active")
+		assert.NoError(t, err)
+		assert.True(t, active)
+
+		var tags []string
+		err = HashGetJSONRaw(conn, testHashName, "tags", &tags)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, tags)
+
+		_, err = HashGetBytesRaw(conn, testHashName, "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+}
+
+// ExampleHashGetInt64 is an example of the method HashGetInt64()
+func ExampleHashGetInt64() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the hash field
+	conn.Command(HashGetCommand, testHashName, "visits").Expect([]byte("42"))
+
+	// Get the field as a number
+	visits, _ := HashGetInt64(context.Background(), client, testHashName, "visits")
+	fmt.Printf("visits: %d", visits+1)
+	// Output:visits: 43
+}
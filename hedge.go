@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// hedgeDelayKey is the context key for the hedging delay
+type hedgeDelayKey struct{}
+
+// hedgedReply is the reply of one attempt of a hedged read
+type hedgedReply struct {
+	err   error
+	value string
+}
+
+// WithHedgedRead returns a context that hedges the reads of Get(): if the first read has not replied
+// within the delay, a duplicate read is sent to the next replica (or on a second connection to the
+// primary if there are no replicas) and the first reply is used
+//
+// Hedging cuts the tail latency of reads at the cost of extra commands for the slowest reads,
+// use a delay around the p95 latency of the reads
+func WithHedgedRead(ctx context.Context, delay time.Duration) context.Context {
+	return context.WithValue(ctx, hedgeDelayKey{}, delay)
+}
+
+// hedgeDelay returns the hedging delay from the context (0 if not set)
+func hedgeDelay(ctx context.Context) time.Duration {
+	delay, _ := ctx.Value(hedgeDelayKey{}).(time.Duration)
+	return delay
+}
+
+// hedgedRead runs the read, and a duplicate read if there was no reply within the delay
+//
+// The first reply that did not fail on the connection is used, the slower read is left to finish
+func (c *Client) hedgedRead(ctx context.Context, delay time.Duration,
+	fn func(conn redis.Conn) (string, error)) (string, error) {
+	replies := make(chan hedgedReply, 2)
+	attempt := func() {
+		var reply hedgedReply
+		reply.err = c.read(ctx, func(conn redis.Conn) (err error) {
+			reply.value, err = fn(conn)
+			return
+		})
+		replies <- reply
+	}
+
+	go attempt()
+	pending := 1
+	hedge := c.Clock().After(delay)
+	var reply hedgedReply
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			pending++
+			go attempt()
+			continue
+		case reply = <-replies:
+			pending--
+		}
+		if !isReplicaFailure(reply.err) {
+			return reply.value, reply.err
+		}
+	}
+	return reply.value, reply.err
+}
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithHedgedRead tests the method WithHedgedRead()
+func TestWithHedgedRead(t *testing.T) {
+
+	t.Run("first read replies in time using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		getCmd := conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		val, err := Get(WithHedgedRead(context.Background(), time.Second), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+		assert.Equal(t, 1, conn.Stats(getCmd))
+	})
+
+	t.Run("slow read is hedged using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// The first replica is slow, the duplicate read to the next replica replies right away
+		slowConn, fastConn := redigomock.NewConn(), redigomock.NewConn()
+		client.Replicas = []nrredis.Pool{
+			&redis.Pool{Dial: func() (redis.Conn, error) { return slowConn, nil }},
+			&redis.Pool{Dial: func() (redis.Conn, error) { return fastConn, nil }},
+		}
+		client.replicaIndex = 1
+		slowConn.Command(GetCommand, testKey).Handle(func(args []interface{}) (interface{}, error) {
+			time.Sleep(500 * time.Millisecond)
+			return "slow", nil
+		})
+		fastCmd := fastConn.Command(GetCommand, testKey).Expect("fast")
+
+		start := time.Now()
+		val, err := Get(WithHedgedRead(context.Background(), 10*time.Millisecond), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", val)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, 1, fastConn.Stats(fastCmd))
+	})
+
+	t.Run("miss is not hedged using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		getCmd := conn.Command(GetCommand, testKey).Expect(nil)
+
+		_, err := Get(WithHedgedRead(context.Background(), time.Second), client, testKey)
+		assert.Error(t, err)
+		assert.Equal(t, 1, conn.Stats(getCmd))
+	})
+
+	t.Run("hedged read using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var val string
+		val, err = Get(WithHedgedRead(context.Background(), time.Nanosecond), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+	})
+}
+
+// ExampleWithHedgedRead is an example of the method WithHedgedRead()
+func ExampleWithHedgedRead() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the read
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+	// Send a duplicate read if there is no reply within 5ms
+	ctx := WithHedgedRead(context.Background(), 5*time.Millisecond)
+	value, _ := Get(ctx, client, testKey)
+	fmt.Printf("got value: %s", value)
+	// Output:got value: test-string-value
+}
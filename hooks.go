@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// KeyOperation is the operation of a key event
+type KeyOperation string
+
+// Key operations (see: OnSet(), OnGet(), OnDelete(), OnInvalidate())
+const (
+	OperationDelete     KeyOperation = "delete"
+	OperationGet        KeyOperation = "get"
+	OperationInvalidate KeyOperation = "invalidate"
+	OperationSet        KeyOperation = "set"
+)
+
+// KeyEvent is passed to the hooks after a successful operation on a key
+type KeyEvent struct {
+	Dependencies []string           // Set: the dependencies linked to the key
+	Field        string             // Hash operations: the field (empty if all fields)
+	Found        bool               // Get: the key was found (false on a miss)
+	Key          string             // The key (the dependency for an invalidation)
+	Operation    KeyOperation       // The operation
+	Removed      int                // Delete and invalidate: the total keys removed by the call
+	TTL          time.Duration      // Set: the expiration (0 is no expiration)
+	Trace        *InvalidationTrace // Invalidate: the report of a cascade (see: KillByDependencyCascade())
+}
+
+// KeyHook is called after a successful operation on a key
+type KeyHook func(ctx context.Context, event KeyEvent)
+
+// keyHooks are the hooks registered for each operation
+type keyHooks map[KeyOperation][]KeyHook
+
+// OnSet registers a hook called after each key is written
+// (Set(), SetExp(), SetWith(), SetToJSON(), HashSet(), HashMapSet(), HashMapSetExp())
+//
+// Hooks run in the goroutine of the operation (keep them fast) and only for operations
+// using the client (not Raw functions on custom connections)
+func (c *Client) OnSet(hook KeyHook) {
+	c.addHook(OperationSet, hook)
+}
+
+// OnGet registers a hook called after each key is read, including misses (Get(), GetBytes(), HashGet())
+func (c *Client) OnGet(hook KeyHook) {
+	c.addHook(OperationGet, hook)
+}
+
+// OnDelete registers a hook called for each key removed (Delete(), DeleteWithoutDependency())
+func (c *Client) OnDelete(hook KeyHook) {
+	c.addHook(OperationDelete, hook)
+}
+
+// OnInvalidate registers a hook called for each dependency invalidated
+// (KillByDependency(), KillByDependencyCascade())
+func (c *Client) OnInvalidate(hook KeyHook) {
+	c.addHook(OperationInvalidate, hook)
+}
+
+// ClearHooks removes all the registered hooks
+func (c *Client) ClearHooks() {
+	c.mu.Lock()
+	c.hooks = nil
+	c.mu.Unlock()
+}
+
+// addHook registers the hook for the operation
+func (c *Client) addHook(operation KeyOperation, hook KeyHook) {
+	if hook == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hooks == nil {
+		c.hooks = make(keyHooks)
+	}
+
+	// Copied so hooks being fired are never modified
+	hooks := make([]KeyHook, 0, len(c.hooks[operation])+1)
+	c.hooks[operation] = append(append(hooks, c.hooks[operation]...), hook)
+}
+
+// fireHooks calls the hooks registered for the operation of the event (and records the per-key stats)
+func (c *Client) fireHooks(ctx context.Context, event KeyEvent) {
+	c.mu.RLock()
+	hooks := c.hooks[event.Operation]
+	keyStats := c.keyStats
+	c.mu.RUnlock()
+	if keyStats != nil {
+		keyStats.record(c, event)
+	}
+	for _, hook := range hooks {
+		hook(ctx, event)
+	}
+}
+
+// fireGetHooks calls the get hooks if the read succeeded or missed
+func (c *Client) fireGetHooks(ctx context.Context, key, field string, err error) {
+	if err == nil || errors.Is(err, redis.ErrNil) {
+		c.fireHooks(ctx, KeyEvent{Field: field, Found: err == nil, Key: key, Operation: OperationGet})
+	}
+}
+
+// fireRemoveHooks calls the hooks for each key if the removal succeeded
+func (c *Client) fireRemoveHooks(ctx context.Context, operation KeyOperation, keys []string,
+	removed int, err error) {
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		c.fireHooks(ctx, KeyEvent{Key: key, Operation: operation, Removed: removed})
+	}
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// eventRecorder records the key events of the hooks
+type eventRecorder struct {
+	sync.Mutex
+	events []KeyEvent
+}
+
+// hook records the event
+func (r *eventRecorder) hook(_ context.Context, event KeyEvent) {
+	r.Lock()
+	defer r.Unlock()
+	r.events = append(r.events, event)
+}
+
+// TestKeyHooks tests the methods OnSet(), OnGet(), OnDelete() and OnInvalidate()
+func TestKeyHooks(t *testing.T) {
+
+	t.Run("set hooks using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := new(eventRecorder)
+		client.OnSet(recorder.hook)
+		client.OnSet(nil)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(SetExpirationCommand, testKey, int64(60), testStringValue)
+		conn.Command(HashKeySetCommand, testHashName, "field", testStringValue)
+		conn.Command(MultiCommand)
+		conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand)
+
+		ctx := context.Background()
+		err := Set(ctx, client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		err = SetExp(ctx, client, testKey, testStringValue, time.Minute)
+		assert.NoError(t, err)
+		err = HashSet(ctx, client, testHashName, "field", testStringValue)
+		assert.NoError(t, err)
+
+		// Failed writes are not reported
+		conn.Command(SetCommand, "failed", testStringValue).ExpectError(errors.New("failed"))
+		err = Set(ctx, client, "failed", testStringValue)
+		assert.Error(t, err)
+
+		assert.Equal(t, []KeyEvent{
+			{Dependencies: []string{testDependantKey}, Key: testKey, Operation: OperationSet},
+			{Key: testKey, Operation: OperationSet, TTL: time.Minute},
+			{Field: "field", Key: testHashName, Operation: OperationSet},
+		}, recorder.events)
+	})
+
+	t.Run("set with options hooks using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := new(eventRecorder)
+		client.OnSet(recorder.hook)
+
+		conn.Command(SetCommand, testKey, testStringValue, SetIfNotExistsArgument).Expect(nil)
+
+		// Not written (the key exists)
+		written, err := SetWith(context.Background(), client, testKey, testStringValue, WithNX())
+		assert.NoError(t, err)
+		assert.False(t, written)
+		assert.Len(t, recorder.events, 0)
+	})
+
+	t.Run("get hooks using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := new(eventRecorder)
+		client.OnGet(recorder.hook)
+
+		conn.Command(GetCommand, testKey).Expect([]byte(testStringValue))
+		conn.Command(GetCommand, "missing").Expect(nil)
+		conn.Command(GetCommand, "failed").ExpectError(errors.New("failed"))
+		conn.Command(HashGetCommand, testHashName, "field").Expect([]byte(testStringValue))
+
+		ctx := context.Background()
+		_, err := Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		_, err = GetBytes(ctx, client, "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+		_, err = Get(ctx, client, "failed")
+		assert.Error(t, err)
+		_, err = HashGet(ctx, client, testHashName, "field")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []KeyEvent{
+			{Found: true, Key: testKey, Operation: OperationGet},
+			{Key: "missing", Operation: OperationGet},
+			{Field: "field", Found: true, Key: testHashName, Operation: OperationGet},
+		}, recorder.events)
+	})
+
+	t.Run("delete and invalidate hooks using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		deletes, invalidations := new(eventRecorder), new(eventRecorder)
+		client.OnDelete(deletes.hook)
+		client.OnInvalidate(invalidations.hook)
+
+		conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+testDependantKey).Expect(int64(2))
+		conn.Command(DeleteCommand, testDependantKey).Expect(int64(0))
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+		conn.Command(DeleteCommand, "key-2").Expect(int64(1))
+
+		ctx := context.Background()
+		_, err := KillByDependency(ctx, client, testDependantKey)
+		assert.NoError(t, err)
+		_, err = DeleteWithoutDependency(ctx, client, "key-1", "key-2")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []KeyEvent{
+			{Key: testDependantKey, Operation: OperationInvalidate, Removed: 2},
+		}, invalidations.events)
+		assert.Equal(t, []KeyEvent{
+			{Key: "key-1", Operation: OperationDelete, Removed: 2},
+			{Key: "key-2", Operation: OperationDelete, Removed: 2},
+		}, deletes.events)
+
+		// Removed hooks
+		client.ClearHooks()
+		_, err = DeleteWithoutDependency(ctx, client, "key-1", "key-2")
+		assert.NoError(t, err)
+		assert.Len(t, deletes.events, 2)
+	})
+}
+
+// ExampleClient_OnSet is an example of the method OnSet()
+func ExampleClient_OnSet() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Report each key written
+	client.OnSet(func(ctx context.Context, event KeyEvent) {
+		fmt.Printf("%s: %s", event.Operation, event.Key)
+	})
+
+	// Mock the set command
+	conn.Command(SetCommand, testKey, testStringValue)
+
+	_ = Set(context.Background(), client, testKey, testStringValue)
+	// Output:set: test-key-name
+}
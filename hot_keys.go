@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Hot key refresh settings
+const (
+	hotKeyMinTTL         = time.Second     // Smallest TTL supported for a hot key
+	hotKeyRefreshDivisor = 10              // Refresh when 1/10th of the TTL remains
+	hotKeyMaxRefreshLead = 5 * time.Second // Never refresh more than this far ahead of expiry
+)
+
+// ErrInvalidHotKeyTTL is returned when the hot key TTL is too small
+var ErrInvalidHotKeyTTL = errors.New("hot key ttl must be at least one second")
+
+// HotKeyLoader loads the current value for a hot key
+type HotKeyLoader func(ctx context.Context) (interface{}, error)
+
+// HotKeyErrorHandler is fired when a background refresh of a hot key fails
+type HotKeyErrorHandler func(key string, err error)
+
+// hotKey is a registered hot key and its background refresher
+type hotKey struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// RegisterHotKey will load the key, store it with the ttl, and keep refreshing it in the
+// background shortly before it expires, so latency-critical keys never miss
+//
+// The first load happens before returning, registering an existing hot key replaces it
+// Hot keys are stopped via UnregisterHotKey(), StopHotKeys() or Close()
+func (c *Client) RegisterHotKey(ctx context.Context, key string, ttl time.Duration,
+	loader HotKeyLoader, dependencies ...string) error {
+
+	if ttl < hotKeyMinTTL {
+		return ErrInvalidHotKeyTTL
+	}
+
+	// Load the key now (errors are returned to the caller)
+	if err := c.refreshHotKey(ctx, key, ttl, loader, dependencies...); err != nil {
+		return err
+	}
+
+	// Start the background refresher
+	refreshCtx, cancel := context.WithCancel(context.Background())
+	hot := &hotKey{cancel: cancel, done: make(chan struct{})}
+
+	c.UnregisterHotKey(key)
+	c.mu.Lock()
+	if c.hotKeys == nil {
+		c.hotKeys = make(map[string]*hotKey)
+	}
+	c.hotKeys[key] = hot
+	c.mu.Unlock()
+
+	go c.runHotKey(refreshCtx, hot, key, ttl, loader, dependencies...)
+	return nil
+}
+
+// UnregisterHotKey stops refreshing the hot key (the key remains until it expires)
+func (c *Client) UnregisterHotKey(key string) {
+	c.mu.Lock()
+	hot, ok := c.hotKeys[key]
+	delete(c.hotKeys, key)
+	c.mu.Unlock()
+
+	if ok {
+		hot.cancel()
+		<-hot.done
+	}
+}
+
+// HotKeys returns the keys that are currently being refreshed
+func (c *Client) HotKeys() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	keys := make([]string, 0, len(c.hotKeys))
+	for key := range c.hotKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// StopHotKeys stops refreshing all hot keys
+func (c *Client) StopHotKeys() {
+	for _, key := range c.HotKeys() {
+		c.UnregisterHotKey(key)
+	}
+}
+
+// OnHotKeyError sets the handler that is fired when a background refresh fails
+func (c *Client) OnHotKeyError(handler HotKeyErrorHandler) {
+	c.mu.Lock()
+	c.hotKeyErrorHandler = handler
+	c.mu.Unlock()
+}
+
+// runHotKey refreshes the hot key until the context is canceled
+func (c *Client) runHotKey(ctx context.Context, hot *hotKey, key string, ttl time.Duration,
+	loader HotKeyLoader, dependencies ...string) {
+	defer close(hot.done)
+
+	interval := hotKeyRefreshInterval(ttl)
+	next := interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.Clock().After(next):
+		}
+
+		// Retry sooner on failure (the current value has not expired yet)
+		next = interval
+		if err := c.refreshHotKey(ctx, key, ttl, loader, dependencies...); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.mu.RLock()
+			handler := c.hotKeyErrorHandler
+			c.mu.RUnlock()
+			if handler != nil {
+				handler(key, err)
+			}
+			next = (ttl - interval) / 2
+		}
+	}
+}
+
+// refreshHotKey loads and stores the hot key (stored with PriorityBackground unless a priority is set)
+func (c *Client) refreshHotKey(ctx context.Context, key string, ttl time.Duration,
+	loader HotKeyLoader, dependencies ...string) error {
+	value, err := loader(ctx)
+	if err != nil {
+		return err
+	}
+	return SetExp(backgroundPriority(ctx), c, key, value, ttl, dependencies...)
+}
+
+// hotKeyRefreshInterval returns how long to wait before refreshing a hot key
+func hotKeyRefreshInterval(ttl time.Duration) time.Duration {
+	lead := ttl / hotKeyRefreshDivisor
+	if lead > hotKeyMaxRefreshLead {
+		lead = hotKeyMaxRefreshLead
+	}
+	return ttl - lead
+}
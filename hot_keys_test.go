@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_RegisterHotKey tests the method RegisterHotKey()
+func TestClient_RegisterHotKey(t *testing.T) {
+
+	t.Run("invalid ttl", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.RegisterHotKey(context.Background(), testKey, 500*time.Millisecond,
+			func(ctx context.Context) (interface{}, error) {
+				return testStringValue, nil
+			},
+		)
+		assert.ErrorIs(t, err, ErrInvalidHotKeyTTL)
+		assert.Equal(t, 0, len(client.HotKeys()))
+	})
+
+	t.Run("loader error on register", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.RegisterHotKey(context.Background(), testKey, time.Second,
+			func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("loader failed")
+			},
+		)
+		assert.Error(t, err)
+		assert.Equal(t, 0, len(client.HotKeys()))
+	})
+
+	t.Run("hot key is loaded and refreshed", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetExpirationCommand, testKey, int64(1), testStringValue)
+
+		loads := make(chan struct{}, 10)
+		err := client.RegisterHotKey(context.Background(), testKey, time.Second,
+			func(ctx context.Context) (interface{}, error) {
+				loads <- struct{}{}
+				return testStringValue, nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testKey}, client.HotKeys())
+
+		// First load on register, second load from the background refresher
+		<-loads
+		select {
+		case <-loads:
+		case <-time.After(2 * time.Second):
+			t.Fatal("hot key was not refreshed")
+		}
+
+		client.UnregisterHotKey(testKey)
+		assert.Equal(t, 0, len(client.HotKeys()))
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("refresh errors are reported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetExpirationCommand, testKey, int64(1), testStringValue)
+
+		failures := make(chan error, 10)
+		client.OnHotKeyError(func(key string, err error) {
+			failures <- err
+		})
+
+		first := true
+		err := client.RegisterHotKey(context.Background(), testKey, time.Second,
+			func(ctx context.Context) (interface{}, error) {
+				if first {
+					first = false
+					return testStringValue, nil
+				}
+				return nil, errors.New("loader failed")
+			},
+		)
+		assert.NoError(t, err)
+
+		select {
+		case err = <-failures:
+			assert.Error(t, err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("hot key error was not reported")
+		}
+
+		// Close also stops the hot keys
+		client.Close()
+		assert.Equal(t, 0, len(client.HotKeys()))
+	})
+}
+
+// TestHotKeyRefreshInterval tests the method hotKeyRefreshInterval()
+func TestHotKeyRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 900*time.Millisecond, hotKeyRefreshInterval(time.Second))
+	assert.Equal(t, 27*time.Second, hotKeyRefreshInterval(30*time.Second))
+	assert.Equal(t, time.Hour-hotKeyMaxRefreshLead, hotKeyRefreshInterval(time.Hour))
+}
+
+// ExampleClient_RegisterHotKey is an example of the method RegisterHotKey()
+func ExampleClient_RegisterHotKey() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections (and stop hot keys) at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command
+	conn.Command(SetExpirationCommand, testKey, int64(60), testStringValue)
+
+	// Keep the key loaded
+	_ = client.RegisterHotKey(context.Background(), testKey, time.Minute,
+		func(ctx context.Context) (interface{}, error) {
+			return testStringValue, nil
+		},
+	)
+
+	fmt.Printf("hot keys: %v", client.HotKeys())
+	// Output:hot keys: [test-key-name]
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// IdempotencyPrefix is the prefix for all idempotency keys
+const IdempotencyPrefix = "idempotency:"
+
+// idempotencyResultSuffix is the suffix for the stored result of an idempotency key
+const idempotencyResultSuffix = ":result"
+
+// ErrInvalidIdempotencyTTL is returned for an idempotency ttl under a millisecond
+var ErrInvalidIdempotencyTTL = errors.New("idempotency ttl must be at least a millisecond")
+
+// ClaimIdempotencyKey atomically claims the idempotency key for the ttl
+// Returns false if the key was already claimed (IE: a duplicate request)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ClaimIdempotencyKeyRaw()
+func ClaimIdempotencyKey(ctx context.Context, client *Client, key string, ttl time.Duration) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return claimIdempotencyKey(conn, key, ttl, client.Clock().Now())
+}
+
+// ClaimIdempotencyKeyRaw atomically claims the idempotency key for the ttl
+// Returns false if the key was already claimed (IE: a duplicate request)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func ClaimIdempotencyKeyRaw(conn redis.Conn, key string, ttl time.Duration) (bool, error) {
+	return claimIdempotencyKey(conn, key, ttl, time.Now())
+}
+
+// claimIdempotencyKey claims the key with the claim time, the ttl is kept to the millisecond
+func claimIdempotencyKey(conn redis.Conn, key string, ttl time.Duration, now time.Time) (bool, error) {
+	if ttl < time.Millisecond {
+		return false, ErrInvalidIdempotencyTTL
+	}
+	_, err := redis.String(conn.Do(
+		SetCommand, IdempotencyPrefix+key, now.UTC().Unix(),
+		SetIfNotExistsArgument, ExpireMillisArgument, ttl.Milliseconds(),
+	))
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReleaseIdempotencyKey removes the claim and any stored result (IE: the request failed and can be retried)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ReleaseIdempotencyKeyRaw()
+func ReleaseIdempotencyKey(ctx context.Context, client *Client, key string) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return ReleaseIdempotencyKeyRaw(conn, key)
+}
+
+// ReleaseIdempotencyKeyRaw removes the claim and any stored result (IE: the request failed and can be retried)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/del
+func ReleaseIdempotencyKeyRaw(conn redis.Conn, key string) (err error) {
+	_, err = conn.Do(DeleteCommand, IdempotencyPrefix+key, IdempotencyPrefix+key+idempotencyResultSuffix)
+	return
+}
+
+// StoreIdempotentResult stores the result (IE: status, body) for a claimed idempotency key
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: StoreIdempotentResultRaw()
+func StoreIdempotentResult(ctx context.Context, client *Client, key string,
+	result map[string]string, ttl time.Duration) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return StoreIdempotentResultRaw(conn, key, result, ttl)
+}
+
+// StoreIdempotentResultRaw stores the result (IE: status, body) for a claimed idempotency key
+// The claim and the result are both kept for the ttl
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/hmset
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/exec
+func StoreIdempotentResultRaw(conn redis.Conn, key string, result map[string]string, ttl time.Duration) (err error) {
+	if len(result) == 0 {
+		return errors.New("missing required parameter: result")
+	} else if ttl < time.Millisecond {
+		return ErrInvalidIdempotencyTTL
+	}
+
+	// Build the hash
+	resultKey := IdempotencyPrefix + key + idempotencyResultSuffix
+	args := make([]interface{}, 0, 2*len(result)+1)
+	args = append(args, resultKey)
+	for field, value := range result {
+		args = append(args, field, value)
+	}
+
+	// Replace the result and extend the claim in one transaction
+	millis := ttl.Milliseconds()
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	if err = conn.Send(DeleteCommand, resultKey); err != nil {
+		return
+	}
+	if err = conn.Send(HashMapSetCommand, args...); err != nil {
+		return
+	}
+	if err = conn.Send(ExpireMillisCommand, resultKey, millis); err != nil {
+		return
+	}
+	if err = conn.Send(ExpireMillisCommand, IdempotencyPrefix+key, millis); err != nil {
+		return
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
+
+// GetIdempotentResult gets the stored result for an idempotency key
+// redis.ErrNil is returned if no result has been stored (yet)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: GetIdempotentResultRaw()
+func GetIdempotentResult(ctx context.Context, client *Client, key string) (map[string]string, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return GetIdempotentResultRaw(conn, key)
+}
+
+// GetIdempotentResultRaw gets the stored result for an idempotency key
+// redis.ErrNil is returned if no result has been stored (yet)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hgetall
+func GetIdempotentResultRaw(conn redis.Conn, key string) (map[string]string, error) {
+	result, err := redis.StringMap(conn.Do(HashGetAllCommand, IdempotencyPrefix+key+idempotencyResultSuffix))
+	if err != nil {
+		return nil, err
+	} else if len(result) == 0 {
+		return nil, redis.ErrNil
+	}
+	return result, nil
+}
@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClaimIdempotencyKey tests the method ClaimIdempotencyKey()
+func TestClaimIdempotencyKey(t *testing.T) {
+
+	t.Run("claim using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.GenericCommand(SetCommand).Expect("OK").Expect(nil)
+
+		claimed, err := ClaimIdempotencyKey(context.Background(), client, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+
+		claimed, err = ClaimIdempotencyKey(context.Background(), client, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+	})
+
+	t.Run("sub-second ttl using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		client.SetClock(NewManualClock(start))
+
+		// The claim time comes from the client clock
+		setCmd := conn.Command(
+			SetCommand, IdempotencyPrefix+testKey, start.Unix(),
+			SetIfNotExistsArgument, ExpireMillisArgument, int64(1500),
+		).Expect("OK")
+
+		claimed, err := ClaimIdempotencyKey(context.Background(), client, testKey, 1500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+		assert.True(t, setCmd.Called)
+
+		_, err = ClaimIdempotencyKey(context.Background(), client, testKey, time.Microsecond)
+		assert.ErrorIs(t, err, ErrInvalidIdempotencyTTL)
+	})
+
+	t.Run("idempotency flow using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// First request claims the key
+		var claimed bool
+		claimed, err = ClaimIdempotencyKeyRaw(conn, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+
+		// No result yet
+		_, err = GetIdempotentResultRaw(conn, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		// Duplicate request
+		claimed, err = ClaimIdempotencyKeyRaw(conn, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, claimed)
+
+		// Store and fetch the result
+		result := map[string]string{"status": "201", "body": `{"id":1}`}
+		err = StoreIdempotentResultRaw(conn, testKey, result, time.Hour)
+		assert.NoError(t, err)
+
+		var stored map[string]string
+		stored, err = GetIdempotentResultRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, result, stored)
+
+		// Release for a retry
+		err = ReleaseIdempotencyKeyRaw(conn, testKey)
+		assert.NoError(t, err)
+
+		claimed, err = ClaimIdempotencyKeyRaw(conn, testKey, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, claimed)
+	})
+}
+
+// ExampleClaimIdempotencyKey is an example of the method ClaimIdempotencyKey()
+func ExampleClaimIdempotencyKey() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command
+	conn.GenericCommand(SetCommand).Expect("OK")
+
+	// Claim the key
+	claimed, _ := ClaimIdempotencyKey(context.Background(), client, "request-id", 24*time.Hour)
+	fmt.Printf("claimed: %v", claimed)
+	// Output:claimed: true
+}
+
+// TestStoreIdempotentResult tests the method StoreIdempotentResult()
+func TestStoreIdempotentResult(t *testing.T) {
+
+	t.Run("missing result", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := StoreIdempotentResult(context.Background(), client, testKey, nil, time.Minute)
+		assert.Error(t, err)
+	})
+
+	t.Run("sub-second ttl using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		resultKey := IdempotencyPrefix + testKey + idempotencyResultSuffix
+		conn.Command(MultiCommand)
+		conn.Command(DeleteCommand, resultKey)
+		conn.Command(HashMapSetCommand, resultKey, "status", "200")
+		resultCmd := conn.Command(ExpireMillisCommand, resultKey, int64(500))
+		claimCmd := conn.Command(ExpireMillisCommand, IdempotencyPrefix+testKey, int64(500))
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		result := map[string]string{"status": "200"}
+		err := StoreIdempotentResult(context.Background(), client, testKey, result, 500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, resultCmd.Called)
+		assert.True(t, claimCmd.Called)
+
+		err = StoreIdempotentResult(context.Background(), client, testKey, result, time.Microsecond)
+		assert.ErrorIs(t, err, ErrInvalidIdempotencyTTL)
+	})
+
+	t.Run("store using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		resultKey := IdempotencyPrefix + testKey + idempotencyResultSuffix
+		conn.Command(MultiCommand)
+		conn.Command(DeleteCommand, resultKey)
+		setCmd := conn.Command(HashMapSetCommand, resultKey, "status", "200")
+		conn.Command(ExpireMillisCommand, resultKey, int64(60000))
+		claimCmd := conn.Command(ExpireMillisCommand, IdempotencyPrefix+testKey, int64(60000))
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		err := StoreIdempotentResult(
+			context.Background(), client, testKey, map[string]string{"status": "200"}, time.Minute,
+		)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.True(t, claimCmd.Called)
+	})
+}
+
+// TestGetIdempotentResult tests the method GetIdempotentResult()
+func TestGetIdempotentResult(t *testing.T) {
+
+	t.Run("get using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		resultKey := IdempotencyPrefix + testKey + idempotencyResultSuffix
+		conn.Command(HashGetAllCommand, resultKey).
+			ExpectStringSlice("status", "200").
+			ExpectStringSlice()
+
+		result, err := GetIdempotentResult(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"status": "200"}, result)
+
+		_, err = GetIdempotentResult(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// KeyInfo is the state of a key (see: KeyInfoMulti())
+type KeyInfo struct {
+	Exists bool          // The key is present
+	Key    string        // The key
+	TTL    time.Duration // Time left before the key expires (0 if the key never expires or is missing)
+	Type   string        // Type of the value (IE: string, hash, set) or "none" if missing
+}
+
+// KeyInfoMulti returns the existence, TTL and type of each key (in order) in a single round trip
+// (IE: inspecting the cache state for an admin page or planning a warm-up)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: KeyInfoMultiRaw()
+func KeyInfoMulti(ctx context.Context, client *Client, keys ...string) (info []KeyInfo, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		info, readErr = KeyInfoMultiRaw(conn, keys...)
+		return
+	})
+	return
+}
+
+// KeyInfoMultiRaw returns the existence, TTL and type of each key (in order) in a single round trip (pipelined)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/pttl
+// https://redis.io/commands/type
+func KeyInfoMultiRaw(conn redis.Conn, keys ...string) ([]KeyInfo, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	// Pipeline the TTL and type of each key
+	for _, key := range keys {
+		if err := conn.Send(TTLMillisCommand, key); err != nil {
+			return nil, err
+		}
+		if err := conn.Send(TypeCommand, key); err != nil {
+			return nil, err
+		}
+	}
+	replies, err := flushPipeline(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	info := make([]KeyInfo, len(keys))
+	for i, key := range keys {
+		var ttl int64
+		if ttl, err = redis.Int64(replies[i*2], nil); err != nil {
+			return nil, err
+		}
+		info[i].Key = key
+		if info[i].Type, err = redis.String(replies[i*2+1], nil); err != nil {
+			return nil, err
+		}
+
+		// -2 is a missing key, -1 is a key without an expiration
+		info[i].Exists = ttl != -2
+		if ttl > 0 {
+			info[i].TTL = time.Duration(ttl) * time.Millisecond
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKeyInfoMulti tests the method KeyInfoMulti()
+func TestKeyInfoMulti(t *testing.T) {
+
+	t.Run("key info using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(TTLMillisCommand, "key-1").Expect(int64(1500))
+		conn.Command(TypeCommand, "key-1").Expect("string")
+		conn.Command(TTLMillisCommand, "key-2").Expect(int64(-2))
+		conn.Command(TypeCommand, "key-2").Expect("none")
+		conn.Command(TTLMillisCommand, "key-3").Expect(int64(-1))
+		conn.Command(TypeCommand, "key-3").Expect("hash")
+
+		info, err := KeyInfoMulti(context.Background(), client, "key-1", "key-2", "key-3")
+		assert.NoError(t, err)
+		assert.Equal(t, []KeyInfo{
+			{Exists: true, Key: "key-1", TTL: 1500 * time.Millisecond, Type: "string"},
+			{Exists: false, Key: "key-2", Type: "none"},
+			{Exists: true, Key: "key-3", Type: "hash"},
+		}, info)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		info, err := KeyInfoMulti(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Nil(t, info)
+	})
+
+	t.Run("key info using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetExpRaw(conn, "key-1", testStringValue, time.Minute)
+		assert.NoError(t, err)
+		err = SetAddRaw(conn, "key-3", testStringValue)
+		assert.NoError(t, err)
+
+		var info []KeyInfo
+		info, err = KeyInfoMulti(context.Background(), client, "key-1", "key-2", "key-3")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(info))
+
+		assert.True(t, info[0].Exists)
+		assert.Equal(t, "string", info[0].Type)
+		assert.Greater(t, info[0].TTL, 50*time.Second)
+
+		assert.False(t, info[1].Exists)
+		assert.Equal(t, "none", info[1].Type)
+
+		assert.True(t, info[2].Exists)
+		assert.Equal(t, "set", info[2].Type)
+		assert.Equal(t, time.Duration(0), info[2].TTL)
+	})
+}
+
+// ExampleKeyInfoMulti is an example of the method KeyInfoMulti()
+func ExampleKeyInfoMulti() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the pipelined replies
+	conn.Command(TTLMillisCommand, testKey).Expect(int64(60000))
+	conn.Command(TypeCommand, testKey).Expect("string")
+
+	info, _ := KeyInfoMulti(context.Background(), client, testKey)
+	fmt.Printf("%s exists: %t type: %s ttl: %s", info[0].Key, info[0].Exists, info[0].Type, info[0].TTL)
+	// Output:test-key-name exists: true type: string ttl: 1m0s
+}
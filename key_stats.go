@@ -0,0 +1,251 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// KeyStatsPrefix is the prefix for the hashes of the per-key stats
+const KeyStatsPrefix = "keystats:"
+
+// Default per-key stats settings
+const (
+	defaultKeyStatsBatchSize     = 100
+	defaultKeyStatsFlushInterval = time.Second
+	defaultKeyStatsQueueSize     = 1000
+	defaultKeyStatsSampleRate    = 0.01
+	defaultKeyStatsTTL           = 24 * time.Hour
+)
+
+// Per-key stats hash fields
+const (
+	keyStatsLastAccessField = "last_access"
+	keyStatsReadsField      = "reads"
+	keyStatsWritesField     = "writes"
+)
+
+// ErrInvalidSampleRate is returned when the sample rate is not between 0 and 1
+var ErrInvalidSampleRate = errors.New("sample rate must be greater than 0 and at most 1")
+
+// KeyStatsConfig is the configuration for the per-key stats (see: SetKeyStats())
+type KeyStatsConfig struct {
+	BatchSize     int           // Keys per flush, a full batch is flushed right away (default: 100)
+	FlushInterval time.Duration // Max wait of a recorded operation before it is flushed (default: 1s)
+	QueueSize     int           // Max operations waiting for the flusher, dropped if full (default: 1000)
+	SampleRate    float64       // Rate of operations recorded, greater than 0 and at most 1 (default: 0.01)
+	TTL           time.Duration // Expiration of the stats of a key, renewed on each update (default: 24h)
+}
+
+// KeyStats are the stats of a key (see: KeyStats())
+type KeyStats struct {
+	Key        string    // The key
+	LastAccess time.Time // Last recorded read or write (zero if never recorded)
+	Reads      int64     // Reads that found the key (estimated if sampled)
+	Writes     int64     // Writes of the key (estimated if sampled)
+}
+
+// keyStatsRecorder records the per-key stats in batches
+type keyStatsRecorder struct {
+	config  KeyStatsConfig
+	done    chan struct{}
+	mu      sync.RWMutex
+	queue   chan keyStatsUpdate
+	stopped bool
+	weight  int64
+}
+
+// keyStatsUpdate is the recorded update of the stats of a key
+type keyStatsUpdate struct {
+	key        string
+	lastAccess int64 // Unix milliseconds
+	reads      int64
+	writes     int64
+}
+
+// SetKeyStats switches the per-key stats on (reads, writes and last access of each key in a
+// compact hash under KeyStatsPrefix) or off (nil), to decide which keys are worth their memory
+//
+// Reads that found the key and writes (the operations firing OnGet() and OnSet() hooks) are sampled,
+// only the sample rate of the operations is recorded (counts are scaled, so they are estimates)
+// The recorded operations are queued and merged per key by a background flusher, which writes a
+// batch in one round trip once it is full or after the flush interval, so the operations never wait
+// on the stats; operations past a full queue and failures to flush are ignored
+// Replacing the config or switching off flushes the pending updates (also done by Close())
+// Only the client functions are recorded (not Raw functions on custom connections)
+func (c *Client) SetKeyStats(config *KeyStatsConfig) error {
+	var recorder *keyStatsRecorder
+	if config != nil {
+		recorder = &keyStatsRecorder{config: *config, done: make(chan struct{})}
+		if recorder.config.SampleRate == 0 {
+			recorder.config.SampleRate = defaultKeyStatsSampleRate
+		} else if recorder.config.SampleRate < 0 || recorder.config.SampleRate > 1 {
+			return ErrInvalidSampleRate
+		}
+		if recorder.config.BatchSize <= 0 {
+			recorder.config.BatchSize = defaultKeyStatsBatchSize
+		}
+		if recorder.config.FlushInterval <= 0 {
+			recorder.config.FlushInterval = defaultKeyStatsFlushInterval
+		}
+		if recorder.config.QueueSize <= 0 {
+			recorder.config.QueueSize = defaultKeyStatsQueueSize
+		}
+		if recorder.config.TTL <= 0 {
+			recorder.config.TTL = defaultKeyStatsTTL
+		}
+		recorder.queue = make(chan keyStatsUpdate, recorder.config.QueueSize)
+		recorder.weight = int64(math.Round(1 / recorder.config.SampleRate))
+		go recorder.run(c)
+	}
+	c.mu.Lock()
+	previous := c.keyStats
+	c.keyStats = recorder
+	c.mu.Unlock()
+	if previous != nil {
+		previous.stop()
+	}
+	return nil
+}
+
+// KeyStats returns the recorded stats of the key (see: SetKeyStats())
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: KeyStatsRaw()
+func (c *Client) KeyStats(ctx context.Context, key string) (stats *KeyStats, err error) {
+	err = c.read(ctx, func(conn redis.Conn) (readErr error) {
+		stats, readErr = KeyStatsRaw(conn, key)
+		return
+	})
+	return
+}
+
+// KeyStatsRaw returns the recorded stats of the key (zero stats if nothing is recorded)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hmget
+func KeyStatsRaw(conn redis.Conn, key string) (*KeyStats, error) {
+	values, err := redis.Int64s(conn.Do(
+		HashMapGetCommand, KeyStatsPrefix+key, keyStatsReadsField, keyStatsWritesField, keyStatsLastAccessField,
+	))
+	if err != nil {
+		return nil, err
+	}
+	stats := &KeyStats{Key: key, Reads: values[0], Writes: values[1]}
+	if values[2] > 0 {
+		stats.LastAccess = time.UnixMilli(values[2])
+	}
+	return stats, nil
+}
+
+// record queues the read or write of the event (if sampled)
+func (r *keyStatsRecorder) record(client *Client, event KeyEvent) {
+	update := keyStatsUpdate{key: event.Key}
+	switch {
+	case event.Operation == OperationGet && event.Found:
+		update.reads = r.weight
+	case event.Operation == OperationSet:
+		update.writes = r.weight
+	default:
+		return
+	}
+	if r.config.SampleRate < 1 && rand.Float64() >= r.config.SampleRate { //nolint:gosec // sampling only
+		return
+	}
+	update.lastAccess = client.Clock().Now().UnixMilli()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.stopped {
+		return
+	}
+	select {
+	case r.queue <- update:
+	default:
+	}
+}
+
+// run merges the queued updates per key and flushes them until the queue is closed
+func (r *keyStatsRecorder) run(client *Client) {
+	defer close(r.done)
+
+	pending := make(map[string]*keyStatsUpdate)
+	var flush <-chan time.Time // Set while updates are waiting
+	for {
+		select {
+		case update, ok := <-r.queue:
+			if !ok {
+				r.flush(client, pending)
+				return
+			}
+			if merged, exists := pending[update.key]; exists {
+				merged.reads += update.reads
+				merged.writes += update.writes
+				if update.lastAccess > merged.lastAccess {
+					merged.lastAccess = update.lastAccess
+				}
+			} else {
+				pending[update.key] = &update
+			}
+			if flush == nil {
+				flush = client.Clock().After(r.config.FlushInterval)
+			}
+			if len(pending) >= r.config.BatchSize {
+				flush = nil
+				r.flush(client, pending)
+				pending = make(map[string]*keyStatsUpdate)
+			}
+		case <-flush:
+			flush = nil
+			r.flush(client, pending)
+			pending = make(map[string]*keyStatsUpdate)
+		}
+	}
+}
+
+// flush writes the updates in one round trip (failures are ignored)
+//
+// Commands used:
+// https://redis.io/commands/hincrby
+// https://redis.io/commands/hset
+// https://redis.io/commands/pexpire
+func (r *keyStatsRecorder) flush(client *Client, pending map[string]*keyStatsUpdate) {
+	if len(pending) == 0 {
+		return
+	}
+	conn, err := client.GetConnectionWithContext(withBackground(context.Background()))
+	if err != nil {
+		return
+	}
+	defer client.CloseConnection(conn)
+
+	for _, update := range pending {
+		key := KeyStatsPrefix + update.key
+		if update.reads > 0 {
+			_ = conn.Send(HashIncrementCommand, key, keyStatsReadsField, update.reads)
+		}
+		if update.writes > 0 {
+			_ = conn.Send(HashIncrementCommand, key, keyStatsWritesField, update.writes)
+		}
+		_ = conn.Send(HashKeySetCommand, key, keyStatsLastAccessField, update.lastAccess)
+		_ = conn.Send(ExpireMillisCommand, key, r.config.TTL.Milliseconds())
+	}
+	_, _ = conn.Do("")
+}
+
+// stop stops queueing updates and waits for the pending updates to be flushed
+func (r *keyStatsRecorder) stop() {
+	r.mu.Lock()
+	if !r.stopped {
+		r.stopped = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+	<-r.done
+}
@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetKeyStats tests the method SetKeyStats()
+func TestSetKeyStats(t *testing.T) {
+
+	t.Run("invalid sample rate", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetKeyStats(&KeyStatsConfig{SampleRate: 1.5})
+		assert.ErrorIs(t, err, ErrInvalidSampleRate)
+
+		err = client.SetKeyStats(&KeyStatsConfig{SampleRate: -0.5})
+		assert.ErrorIs(t, err, ErrInvalidSampleRate)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetKeyStats(&KeyStatsConfig{})
+		assert.NoError(t, err)
+		config := client.keyStats.config
+		assert.Equal(t, defaultKeyStatsBatchSize, config.BatchSize)
+		assert.Equal(t, defaultKeyStatsFlushInterval, config.FlushInterval)
+		assert.Equal(t, defaultKeyStatsQueueSize, config.QueueSize)
+		assert.Equal(t, defaultKeyStatsSampleRate, config.SampleRate)
+		assert.Equal(t, defaultKeyStatsTTL, config.TTL)
+		assert.Equal(t, int64(100), client.keyStats.weight)
+	})
+
+	t.Run("record using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		client.SetClock(clock)
+		err := client.SetKeyStats(&KeyStatsConfig{SampleRate: 1, TTL: time.Hour})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		writeCmd := conn.Command(HashIncrementCommand, KeyStatsPrefix+testKey, keyStatsWritesField, int64(2))
+		readCmd := conn.Command(HashIncrementCommand, KeyStatsPrefix+testKey, keyStatsReadsField, int64(1))
+		accessCmd := conn.Command(HashKeySetCommand, KeyStatsPrefix+testKey, keyStatsLastAccessField,
+			clock.Now().UnixMilli())
+		expireCmd := conn.Command(ExpireMillisCommand, KeyStatsPrefix+testKey, int64(3600000))
+
+		// Nothing is written until the flush
+		for i := 0; i < 2; i++ {
+			err = Set(context.Background(), client, testKey, testStringValue)
+			assert.NoError(t, err)
+		}
+		_, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+
+		// Misses are not recorded
+		conn.Command(GetCommand, "missing").Expect(nil)
+		missCmd := conn.Command(HashIncrementCommand, KeyStatsPrefix+"missing", keyStatsReadsField, int64(1))
+		_, err = Get(context.Background(), client, "missing")
+		assert.Error(t, err)
+		assert.Equal(t, 0, conn.Stats(writeCmd))
+
+		// The updates of the key are merged into one flush
+		flushed := make(chan struct{}, 1)
+		expireCmd.Handle(func([]interface{}) (interface{}, error) {
+			flushed <- struct{}{}
+			return int64(1), nil
+		})
+		waitForWaiters(t, clock, 1)
+		clock.Advance(time.Second)
+		select {
+		case <-flushed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("key stats were not flushed")
+		}
+
+		// Switching off waits for the flusher
+		err = client.SetKeyStats(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, conn.Stats(writeCmd))
+		assert.Equal(t, 1, conn.Stats(readCmd))
+		assert.Equal(t, 1, conn.Stats(accessCmd))
+		assert.Equal(t, 0, conn.Stats(missCmd))
+
+		// Off
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, conn.Stats(writeCmd))
+	})
+
+	t.Run("pending updates are flushed when switched off using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetKeyStats(&KeyStatsConfig{FlushInterval: time.Hour, SampleRate: 1})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		writeCmd := conn.Command(HashIncrementCommand, KeyStatsPrefix+testKey, keyStatsWritesField, int64(1))
+		conn.GenericCommand(HashKeySetCommand)
+		conn.GenericCommand(ExpireMillisCommand)
+
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, conn.Stats(writeCmd))
+
+		client.Close()
+		assert.Equal(t, 1, conn.Stats(writeCmd))
+	})
+
+	t.Run("full batches are flushed using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetKeyStats(&KeyStatsConfig{BatchSize: 2, FlushInterval: time.Hour, SampleRate: 1})
+		assert.NoError(t, err)
+
+		flushed := make(chan struct{}, 2)
+		writeCmd := conn.GenericCommand(HashIncrementCommand)
+		conn.GenericCommand(HashKeySetCommand)
+		conn.GenericCommand(ExpireMillisCommand).Handle(func([]interface{}) (interface{}, error) {
+			flushed <- struct{}{}
+			return int64(1), nil
+		})
+
+		client.keyStats.record(client, KeyEvent{Key: "key-1", Operation: OperationSet})
+		client.keyStats.record(client, KeyEvent{Key: "key-2", Operation: OperationSet})
+		for i := 0; i < 2; i++ {
+			select {
+			case <-flushed:
+			case <-time.After(5 * time.Second):
+				t.Fatal("key stats were not flushed")
+			}
+		}
+
+		err = client.SetKeyStats(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, conn.Stats(writeCmd))
+	})
+
+	t.Run("sampled using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetKeyStats(&KeyStatsConfig{FlushInterval: time.Hour, SampleRate: 0.25})
+		assert.NoError(t, err)
+
+		var writes int64
+		conn.Command(SetCommand, testKey, testStringValue)
+		conn.GenericCommand(HashIncrementCommand).Handle(func(args []interface{}) (interface{}, error) {
+			writes = args[2].(int64)
+			return writes, nil
+		})
+		conn.GenericCommand(HashKeySetCommand)
+		conn.GenericCommand(ExpireMillisCommand)
+		for i := 0; i < 400; i++ {
+			err = Set(context.Background(), client, testKey, testStringValue)
+			assert.NoError(t, err)
+		}
+		err = client.SetKeyStats(nil)
+		assert.NoError(t, err)
+
+		// Roughly a quarter of the writes, each counted as 4
+		assert.Greater(t, writes, int64(200))
+		assert.Less(t, writes, int64(600))
+		assert.Equal(t, int64(0), writes%4)
+	})
+
+	t.Run("key stats using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.SetKeyStats(&KeyStatsConfig{SampleRate: 1})
+		assert.NoError(t, err)
+
+		var stats *KeyStats
+		stats, err = client.KeyStats(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, &KeyStats{Key: testKey}, stats)
+
+		start := time.Now().Add(-time.Second)
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			_, err = Get(context.Background(), client, testKey)
+			assert.NoError(t, err)
+		}
+
+		// Flush the pending updates
+		err = client.SetKeyStats(nil)
+		assert.NoError(t, err)
+
+		stats, err = client.KeyStats(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), stats.Reads)
+		assert.Equal(t, int64(1), stats.Writes)
+		assert.True(t, stats.LastAccess.After(start))
+	})
+}
+
+// ExampleClient_SetKeyStats is an example of the method SetKeyStats()
+func ExampleClient_SetKeyStats() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Record 10% of the reads and writes of each key
+	_ = client.SetKeyStats(&KeyStatsConfig{SampleRate: 0.1})
+
+	// Mock the recorded stats
+	conn.Command(HashMapGetCommand, KeyStatsPrefix+testKey, keyStatsReadsField, keyStatsWritesField,
+		keyStatsLastAccessField).Expect([]interface{}{[]byte("120"), []byte("10"), []byte("1700000000000")})
+
+	stats, _ := client.KeyStats(context.Background(), testKey)
+	fmt.Printf("reads: %d writes: %d", stats.Reads, stats.Writes)
+	// Output:reads: 120 writes: 10
+}
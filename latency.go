@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// LatencySample is a latency spike of an event (see: LatencyHistory())
+type LatencySample struct {
+	Latency time.Duration // Latency of the spike (millisecond precision)
+	Time    time.Time     // Time of the spike (second precision)
+}
+
+// ServerDoctor are the human-readable reports of the server (see: DoctorReport())
+type ServerDoctor struct {
+	Latency string `json:"latency"` // LATENCY DOCTOR report
+	Memory  string `json:"memory"`  // MEMORY DOCTOR report
+}
+
+// LatencyHistory returns the latency spikes of the event (IE: "command", "fast-command")
+// Spikes are only recorded if latency-monitor-threshold is set on the server
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: LatencyHistoryRaw()
+func LatencyHistory(ctx context.Context, client *Client, event string) ([]LatencySample, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return LatencyHistoryRaw(conn, event)
+}
+
+// LatencyHistoryRaw returns the latency spikes of the event
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/latency-history
+func LatencyHistoryRaw(conn redis.Conn, event string) ([]LatencySample, error) {
+	if len(event) == 0 {
+		return nil, errors.New("missing required parameter: event")
+	}
+	reply, err := redis.Values(conn.Do(LatencyCommand, HistoryArgument, event))
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]LatencySample, 0, len(reply))
+	for _, entry := range reply {
+		var values []int64
+		if values, err = redis.Int64s(entry, nil); err != nil {
+			return nil, err
+		} else if len(values) < 2 {
+			return nil, errors.New("invalid latency history entry")
+		}
+		samples = append(samples, LatencySample{
+			Latency: time.Duration(values[1]) * time.Millisecond,
+			Time:    time.Unix(values[0], 0),
+		})
+	}
+	return samples, nil
+}
+
+// LatencyReset removes the latency spikes of the events (all events if none are given)
+// Returns the number of event time series that were reset
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: LatencyResetRaw()
+func LatencyReset(ctx context.Context, client *Client, events ...string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return LatencyResetRaw(conn, events...)
+}
+
+// LatencyResetRaw removes the latency spikes of the events (all events if none are given)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/latency-reset
+func LatencyResetRaw(conn redis.Conn, events ...string) (int, error) {
+	return redis.Int(conn.Do(LatencyCommand, redis.Args{}.Add(ResetArgument).AddFlat(events)...))
+}
+
+// DoctorReport returns the MEMORY DOCTOR and LATENCY DOCTOR reports of the server
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DoctorReportRaw()
+func DoctorReport(ctx context.Context, client *Client) (*ServerDoctor, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return DoctorReportRaw(conn)
+}
+
+// DoctorReportRaw returns the MEMORY DOCTOR and LATENCY DOCTOR reports of the server
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/memory-doctor
+// https://redis.io/commands/latency-doctor
+func DoctorReportRaw(conn redis.Conn) (report *ServerDoctor, err error) {
+	report = new(ServerDoctor)
+	if report.Memory, err = redis.String(conn.Do(MemoryCommand, DoctorArgument)); err != nil {
+		return nil, err
+	}
+	if report.Latency, err = redis.String(conn.Do(LatencyCommand, DoctorArgument)); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLatencyHistory tests the method LatencyHistory()
+func TestLatencyHistory(t *testing.T) {
+
+	t.Run("missing event", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := LatencyHistory(context.Background(), client, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("history using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(LatencyCommand, HistoryArgument, "command").Expect([]interface{}{
+			[]interface{}{int64(1700000000), int64(250)},
+			[]interface{}{int64(1700000060), int64(12)},
+		})
+		conn.Command(LatencyCommand, HistoryArgument, "broken").Expect([]interface{}{
+			[]interface{}{int64(1700000000)},
+		})
+
+		samples, err := LatencyHistory(context.Background(), client, "command")
+		assert.NoError(t, err)
+		assert.Equal(t, []LatencySample{
+			{Latency: 250 * time.Millisecond, Time: time.Unix(1700000000, 0)},
+			{Latency: 12 * time.Millisecond, Time: time.Unix(1700000060, 0)},
+		}, samples)
+
+		_, err = LatencyHistory(context.Background(), client, "broken")
+		assert.Error(t, err)
+	})
+}
+
+// TestLatencyReset tests the method LatencyReset()
+func TestLatencyReset(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(LatencyCommand, ResetArgument).Expect(int64(3))
+	conn.Command(LatencyCommand, ResetArgument, "command").Expect(int64(1))
+
+	reset, err := LatencyReset(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, reset)
+
+	reset, err = LatencyReset(context.Background(), client, "command")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reset)
+}
+
+// TestDoctorReport tests the method DoctorReport()
+func TestDoctorReport(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(MemoryCommand, DoctorArgument).Expect("Sam, I have no memory problems")
+	conn.Command(LatencyCommand, DoctorArgument).Expect("Dave, no latency spike was observed")
+
+	report, err := DoctorReport(context.Background(), client)
+	assert.NoError(t, err)
+	assert.Equal(t, &ServerDoctor{
+		Latency: "Dave, no latency spike was observed",
+		Memory:  "Sam, I have no memory problems",
+	}, report)
+}
+
+// ExampleDoctorReport is an example of the method DoctorReport()
+func ExampleDoctorReport() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the reports
+	conn.Command(MemoryCommand, DoctorArgument).Expect("Sam, I have no memory problems")
+	conn.Command(LatencyCommand, DoctorArgument).Expect("Dave, no latency spike was observed")
+
+	report, _ := DoctorReport(context.Background(), client)
+	fmt.Printf("memory: %s", report.Memory)
+	// Output:memory: Sam, I have no memory problems
+}
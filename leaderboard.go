@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Leaderboard is a ranked board of members (highest score first) built on a sorted set
+//
+// Periodic boards store each period (IE: daily) under its own key and expire automatically
+type Leaderboard struct {
+	client    *Client
+	name      string
+	period    time.Duration
+	retention time.Duration
+}
+
+// LeaderboardEntry is a single member on a leaderboard
+type LeaderboardEntry struct {
+	Member string  // Member name
+	Rank   int64   // Position on the board (1 = highest score)
+	Score  float64 // Current score
+}
+
+// NewLeaderboard creates a new all-time leaderboard stored under the name
+func NewLeaderboard(client *Client, name string) *Leaderboard {
+	return &Leaderboard{client: client, name: name}
+}
+
+// NewPeriodicLeaderboard creates a leaderboard with a board per period (IE: 24 hours)
+// Each board expires once the period has ended plus the retention
+func NewPeriodicLeaderboard(client *Client, name string, period, retention time.Duration) *Leaderboard {
+	return &Leaderboard{client: client, name: name, period: period, retention: retention}
+}
+
+// Key returns the key of the board for the given time
+func (l *Leaderboard) Key(t time.Time) string {
+	if l.period <= 0 {
+		return l.name
+	}
+	return l.name + ":" + strconv.FormatInt(t.UTC().Truncate(l.period).Unix(), 10)
+}
+
+// Add will set the score of the member on the current board
+//
+// Commands used:
+// https://redis.io/commands/zadd
+// https://redis.io/commands/expire
+func (l *Leaderboard) Add(ctx context.Context, member string, score float64) error {
+	conn, err := l.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer l.client.CloseConnection(conn)
+
+	now := l.client.Clock().Now()
+	if err = conn.Send(SortedSetAddCommand, l.Key(now), score, member); err != nil {
+		return err
+	}
+	if err = l.sendExpire(conn, now); err != nil {
+		return err
+	}
+	_, err = flushPipeline(conn)
+	return err
+}
+
+// IncrementScore will increment the score of the member on the current board
+// and return the new score
+//
+// Commands used:
+// https://redis.io/commands/zincrby
+// https://redis.io/commands/expire
+func (l *Leaderboard) IncrementScore(ctx context.Context, member string, delta float64) (float64, error) {
+	conn, err := l.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer l.client.CloseConnection(conn)
+
+	now := l.client.Clock().Now()
+	if err = conn.Send(SortedSetIncrementCommand, l.Key(now), delta, member); err != nil {
+		return 0, err
+	}
+	if err = l.sendExpire(conn, now); err != nil {
+		return 0, err
+	}
+	var replies []interface{}
+	if replies, err = flushPipeline(conn); err != nil {
+		return 0, err
+	}
+	return redis.Float64(replies[0], nil)
+}
+
+// Rank returns the entry for the member on the current board
+// redis.ErrNil is returned if the member is not on the board
+//
+// Commands used:
+// https://redis.io/commands/zrevrank
+// https://redis.io/commands/zscore
+func (l *Leaderboard) Rank(ctx context.Context, member string) (*LeaderboardEntry, error) {
+	conn, err := l.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer l.client.CloseConnection(conn)
+	return l.rank(conn, l.Key(l.client.Clock().Now()), member)
+}
+
+// TopN returns the top n entries on the current board
+//
+// Spec: https://redis.io/commands/zrevrange
+func (l *Leaderboard) TopN(ctx context.Context, n int64) ([]*LeaderboardEntry, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	conn, err := l.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer l.client.CloseConnection(conn)
+	return l.entries(conn, l.Key(l.client.Clock().Now()), 0, n-1)
+}
+
+// Around returns the member and up to n entries above and below the member on the current board
+// redis.ErrNil is returned if the member is not on the board
+//
+// Commands used:
+// https://redis.io/commands/zrevrank
+// https://redis.io/commands/zrevrange
+func (l *Leaderboard) Around(ctx context.Context, member string, n int64) ([]*LeaderboardEntry, error) {
+	conn, err := l.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer l.client.CloseConnection(conn)
+
+	key := l.Key(l.client.Clock().Now())
+	var rank int64
+	if rank, err = redis.Int64(conn.Do(SortedSetReverseRankCommand, key, member)); err != nil {
+		return nil, err
+	}
+	start := rank - n
+	if start < 0 {
+		start = 0
+	}
+	return l.entries(conn, key, start, rank+n)
+}
+
+// sendExpire sends the expire command for a periodic board
+func (l *Leaderboard) sendExpire(conn redis.Conn, now time.Time) error {
+	if l.period <= 0 {
+		return nil
+	}
+	ends := now.UTC().Truncate(l.period).Add(l.period + l.retention)
+	return conn.Send(ExpireCommand, l.Key(now), int64(ends.Sub(now).Seconds())+1)
+}
+
+// rank returns the entry for the member on the board
+func (l *Leaderboard) rank(conn redis.Conn, key, member string) (*LeaderboardEntry, error) {
+	if err := conn.Send(SortedSetReverseRankCommand, key, member); err != nil {
+		return nil, err
+	}
+	if err := conn.Send(SortedSetScoreCommand, key, member); err != nil {
+		return nil, err
+	}
+	replies, err := flushPipeline(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &LeaderboardEntry{Member: member}
+	if entry.Rank, err = redis.Int64(replies[0], nil); err != nil {
+		return nil, err
+	}
+	entry.Rank++
+	if entry.Score, err = redis.Float64(replies[1], nil); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// entries returns the entries on the board between the start and stop positions (zero-based)
+func (l *Leaderboard) entries(conn redis.Conn, key string, start, stop int64) ([]*LeaderboardEntry, error) {
+	values, err := redis.Strings(conn.Do(SortedSetReverseRangeCommand, key, start, stop, WithScoresArgument))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*LeaderboardEntry, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		entry := &LeaderboardEntry{
+			Member: values[i],
+			Rank:   start + int64(i/2) + 1,
+		}
+		if entry.Score, err = strconv.ParseFloat(values[i+1], 64); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
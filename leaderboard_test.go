@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLeaderboard_Key tests the method Key()
+func TestLeaderboard_Key(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 8, 9, 15, 30, 0, 0, time.UTC)
+	assert.Equal(t, testKey, NewLeaderboard(nil, testKey).Key(now))
+
+	daily := NewPeriodicLeaderboard(nil, testKey, 24*time.Hour, time.Hour)
+	day := time.Date(2022, 8, 9, 0, 0, 0, 0, time.UTC).Unix()
+	assert.Equal(t, testKey+":"+strconv.FormatInt(day, 10), daily.Key(now))
+	assert.Equal(t, daily.Key(now), daily.Key(now.Add(8*time.Hour)))
+	assert.NotEqual(t, daily.Key(now), daily.Key(now.Add(9*time.Hour)))
+}
+
+// TestLeaderboard tests the leaderboard methods
+func TestLeaderboard(t *testing.T) {
+
+	t.Run("leaderboard using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		board := NewLeaderboard(client, testKey)
+
+		addCmd := conn.Command(SortedSetAddCommand, testKey, float64(10), "alice")
+		err := board.Add(context.Background(), "alice", 10)
+		assert.NoError(t, err)
+		assert.True(t, addCmd.Called)
+
+		conn.Command(SortedSetIncrementCommand, testKey, float64(5), "alice").Expect([]byte("15"))
+		var score float64
+		score, err = board.IncrementScore(context.Background(), "alice", 5)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(15), score)
+
+		conn.Command(SortedSetReverseRankCommand, testKey, "alice").Expect(int64(0))
+		conn.Command(SortedSetScoreCommand, testKey, "alice").Expect([]byte("15"))
+		var entry *LeaderboardEntry
+		entry, err = board.Rank(context.Background(), "alice")
+		assert.NoError(t, err)
+		assert.Equal(t, &LeaderboardEntry{Member: "alice", Rank: 1, Score: 15}, entry)
+
+		conn.Command(SortedSetReverseRangeCommand, testKey, int64(0), int64(1), WithScoresArgument).
+			ExpectStringSlice("alice", "15", "bob", "7.5")
+		var entries []*LeaderboardEntry
+		entries, err = board.TopN(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []*LeaderboardEntry{
+			{Member: "alice", Rank: 1, Score: 15},
+			{Member: "bob", Rank: 2, Score: 7.5},
+		}, entries)
+
+		entries, err = board.TopN(context.Background(), 0)
+		assert.NoError(t, err)
+		assert.Nil(t, entries)
+	})
+
+	t.Run("periodic board sets the expiration", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		board := NewPeriodicLeaderboard(client, testKey, time.Hour, time.Hour)
+
+		key := board.Key(time.Now())
+		conn.Command(SortedSetAddCommand, key, float64(1), "alice")
+		expireCmd := conn.Command(ExpireCommand, key, redigomock.NewAnyInt())
+
+		err := board.Add(context.Background(), "alice", 1)
+		assert.NoError(t, err)
+		assert.True(t, expireCmd.Called)
+	})
+
+	t.Run("member not on the board", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		board := NewLeaderboard(client, testKey)
+
+		conn.Command(SortedSetReverseRankCommand, testKey, "nobody").Expect(nil)
+		conn.Command(SortedSetScoreCommand, testKey, "nobody").Expect(nil)
+
+		_, err := board.Rank(context.Background(), "nobody")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		_, err = board.Around(context.Background(), "nobody", 2)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("leaderboard using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		board := NewPeriodicLeaderboard(client, testKey, 24*time.Hour, time.Hour)
+		for i, member := range []string{"a", "b", "c", "d", "e"} {
+			err = board.Add(ctx, member, float64(i))
+			assert.NoError(t, err)
+		}
+
+		var score float64
+		score, err = board.IncrementScore(ctx, "a", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(10), score)
+
+		var entry *LeaderboardEntry
+		entry, err = board.Rank(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), entry.Rank)
+
+		var entries []*LeaderboardEntry
+		entries, err = board.Around(ctx, "d", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(entries))
+		assert.Equal(t, "e", entries[0].Member)
+		assert.Equal(t, "d", entries[1].Member)
+		assert.Equal(t, int64(3), entries[1].Rank)
+		assert.Equal(t, "c", entries[2].Member)
+
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do("TTL", board.Key(time.Now())))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+	})
+}
+
+// ExampleLeaderboard_TopN is an example of the method TopN()
+func ExampleLeaderboard_TopN() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the range command
+	conn.Command(SortedSetReverseRangeCommand, testKey, int64(0), int64(0), WithScoresArgument).
+		ExpectStringSlice("alice", "15")
+
+	// Fire the command
+	entries, _ := NewLeaderboard(client, testKey).TopN(context.Background(), 1)
+	fmt.Printf("leader: %s with score %.0f", entries[0].Member, entries[0].Score)
+	// Output:leader: alice with score 15
+}
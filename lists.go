@@ -0,0 +1,319 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrInvalidTTL is returned when the ttl is not positive
+var ErrInvalidTTL = errors.New("ttl must be greater than zero")
+
+// ErrInvalidMaxLength is returned when the max length of a bounded list is not positive
+var ErrInvalidMaxLength = errors.New("max length must be greater than zero")
+
+// GetListRange returns the part of the list between the start and stop indexes (both inclusive)
+// Negative indexes start from the end of the list (IE: -1 is the last element)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Lists with a tombstone are empty if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: GetListRangeRaw()
+func GetListRange(ctx context.Context, client *Client, key string, start, stop int) (list []string, err error) {
+	if client.IsBypassed() {
+		return nil, nil
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		var tombstone bool
+		if tombstone, readErr = client.hasTombstone(conn, key); readErr != nil || tombstone {
+			return
+		}
+		list, readErr = GetListRangeRaw(conn, key, start, stop)
+		return
+	})
+	return
+}
+
+// GetListRangeRaw returns the part of the list between the start and stop indexes (both inclusive)
+// Negative indexes start from the end of the list (IE: -1 is the last element)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/lrange
+func GetListRangeRaw(conn redis.Conn, key string, start, stop int) ([]string, error) {
+	return redis.Strings(conn.Do(ListRangeCommand, key, start, stop))
+}
+
+// ListLength returns the number of elements in the list (0 if the key does not exist)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: ListLengthRaw()
+func ListLength(ctx context.Context, client *Client, key string) (length int, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		length, readErr = ListLengthRaw(conn, key)
+		return
+	})
+	return
+}
+
+// ListLengthRaw returns the number of elements in the list (0 if the key does not exist)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/llen
+func ListLengthRaw(conn redis.Conn, key string) (int, error) {
+	return redis.Int(conn.Do(ListLengthCommand, key))
+}
+
+// SetListReplace saves a slice as a redis list, replacing any existing list, and keeps a reference
+// to each dependency (an empty slice removes the list)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetListReplaceRaw()
+func SetListReplace(ctx context.Context, client *Client, key string, slice []string, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return SetListReplaceRaw(conn, key, slice, dependencies...)
+}
+
+// SetListReplaceRaw saves a slice as a redis list, replacing any existing list, and keeps a reference
+// to each dependency (an empty slice removes the list)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/del
+// https://redis.io/commands/rpush
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func SetListReplaceRaw(conn redis.Conn, key string, slice []string, dependencies ...string) error {
+	return replaceList(conn, key, slice, 0, dependencies...)
+}
+
+// SetListExp saves a slice as a redis list with an expiration, replacing any existing list, and
+// keeps a reference to each dependency (an empty slice removes the list)
+// The ttl uses millisecond precision if it is not a whole number of seconds
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetListExpRaw()
+func SetListExp(ctx context.Context, client *Client, key string, slice []string,
+	ttl time.Duration, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return SetListExpRaw(conn, key, slice, ttl, dependencies...)
+}
+
+// SetListExpRaw saves a slice as a redis list with an expiration, replacing any existing list, and
+// keeps a reference to each dependency (an empty slice removes the list)
+// The ttl uses millisecond precision if it is not a whole number of seconds
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/del
+// https://redis.io/commands/rpush
+// https://redis.io/commands/expire
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func SetListExpRaw(conn redis.Conn, key string, slice []string, ttl time.Duration, dependencies ...string) error {
+	if ttl <= 0 {
+		return ErrInvalidTTL
+	}
+	return replaceList(conn, key, slice, ttl, dependencies...)
+}
+
+// replaceList replaces the list, sets the expiration (if set) and links the dependencies in one transaction
+func replaceList(conn redis.Conn, key string, slice []string, ttl time.Duration, dependencies ...string) (err error) {
+	if err = checkDependencyLimit(conn, dependencies...); err != nil {
+		return
+	}
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	if err = conn.Send(DeleteCommand, key); err != nil {
+		return
+	}
+	if len(slice) > 0 {
+		if err = conn.Send(ListPushCommand, redis.Args{}.Add(key).AddFlat(slice)...); err != nil {
+			return
+		}
+		if ttl > 0 {
+			command, args := expireCommand(key, ttl)
+			if err = conn.Send(command, args...); err != nil {
+				return
+			}
+		}
+		if err = sendLinkDependencies(conn, key, dependencies...); err != nil {
+			return
+		}
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
+
+// PushBounded appends the value to the end of the list and trims the list to the newest maxLen
+// elements (the oldest elements are removed from the start), keeping a reference to each dependency
+// The push and the trim are applied atomically (IE: a capped activity feed)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: PushBoundedRaw()
+func PushBounded(ctx context.Context, client *Client, key, value string, maxLen int,
+	dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return PushBoundedRaw(conn, key, value, maxLen, dependencies...)
+}
+
+// PushBoundedRaw appends the value to the end of the list and trims the list to the newest maxLen
+// elements (the oldest elements are removed from the start), keeping a reference to each dependency
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/rpush
+// https://redis.io/commands/ltrim
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func PushBoundedRaw(conn redis.Conn, key, value string, maxLen int, dependencies ...string) error {
+	if maxLen <= 0 {
+		return ErrInvalidMaxLength
+	}
+	return pushBounded(conn, ListPushCommand, key, value, -maxLen, -1, dependencies...)
+}
+
+// PushBoundedLeft prepends the value to the start of the list and trims the list to the newest maxLen
+// elements (the oldest elements are removed from the end), keeping a reference to each dependency
+// The push and the trim are applied atomically (IE: a newest-first activity feed)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: PushBoundedLeftRaw()
+func PushBoundedLeft(ctx context.Context, client *Client, key, value string, maxLen int,
+	dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return PushBoundedLeftRaw(conn, key, value, maxLen, dependencies...)
+}
+
+// PushBoundedLeftRaw prepends the value to the start of the list and trims the list to the newest maxLen
+// elements (the oldest elements are removed from the end), keeping a reference to each dependency
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/lpush
+// https://redis.io/commands/ltrim
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func PushBoundedLeftRaw(conn redis.Conn, key, value string, maxLen int, dependencies ...string) error {
+	if maxLen <= 0 {
+		return ErrInvalidMaxLength
+	}
+	return pushBounded(conn, ListPushLeftCommand, key, value, 0, maxLen-1, dependencies...)
+}
+
+// pushBounded pushes the value, trims the list to the range and links the dependencies in one transaction
+func pushBounded(conn redis.Conn, command, key, value string, start, stop int, dependencies ...string) (err error) {
+	if err = checkDependencyLimit(conn, dependencies...); err != nil {
+		return
+	}
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	if err = conn.Send(command, key, value); err != nil {
+		return
+	}
+	if err = conn.Send(ListTrimCommand, key, start, stop); err != nil {
+		return
+	}
+	if err = sendLinkDependencies(conn, key, dependencies...); err != nil {
+		return
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
+
+// ListIterator pages through a list in fixed-size pages (see: NewListIterator())
+//
+//	iterator := NewListIterator(client, key, 100)
+//	for iterator.Next(ctx) {
+//		page := iterator.Page()
+//	}
+//	err := iterator.Err()
+type ListIterator struct {
+	client   *Client
+	done     bool
+	err      error
+	key      string
+	next     int
+	page     []string
+	pageSize int
+}
+
+// NewListIterator returns an iterator over the list with pages of the given size (default: 100)
+// Elements added while iterating are returned if they are appended after the current page
+func NewListIterator(client *Client, key string, pageSize int) *ListIterator {
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+	return &ListIterator{client: client, key: key, pageSize: pageSize}
+}
+
+// Next fetches the next page, false is returned once the list is exhausted or on error (see: Err())
+//
+// Spec: https://redis.io/commands/lrange
+func (i *ListIterator) Next(ctx context.Context) bool {
+	if i.done {
+		return false
+	}
+	page, err := GetListRange(ctx, i.client, i.key, i.next, i.next+i.pageSize-1)
+	if err != nil {
+		i.err = err
+		i.done = true
+		return false
+	}
+	if len(page) < i.pageSize {
+		i.done = true
+	}
+	if len(page) == 0 {
+		return false
+	}
+	i.page = page
+	i.next += len(page)
+	return true
+}
+
+// Page returns the current page
+func (i *ListIterator) Page() []string {
+	return i.page
+}
+
+// Err returns the error that stopped the iteration (if any)
+func (i *ListIterator) Err() error {
+	return i.err
+}
@@ -0,0 +1,368 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetListRange tests the method GetListRange()
+func TestGetListRange(t *testing.T) {
+
+	t.Run("get list range using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ListRangeCommand, testKey, 0, 1).Expect([]interface{}{[]byte("a"), []byte("b")})
+
+		list, err := GetListRange(context.Background(), client, testKey, 0, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, list)
+	})
+
+	t.Run("list length using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ListLengthCommand, testKey).Expect(int64(3))
+
+		length, err := ListLength(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, length)
+	})
+
+	t.Run("ranges using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetListRaw(conn, testKey, []string{"a", "b", "c", "d"})
+		assert.NoError(t, err)
+
+		var length int
+		length, err = ListLengthRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, length)
+
+		var list []string
+		list, err = GetListRangeRaw(conn, testKey, 1, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b", "c"}, list)
+
+		list, err = GetListRangeRaw(conn, testKey, -2, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c", "d"}, list)
+
+		// Missing list
+		length, err = ListLengthRaw(conn, "missing")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, length)
+	})
+}
+
+// TestSetListReplace tests the method SetListReplace()
+func TestSetListReplace(t *testing.T) {
+
+	t.Run("replace using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var commands []*redigomock.Cmd
+		commands = append(commands, conn.Command(MultiCommand))
+		commands = append(commands, conn.Command(DeleteCommand, testKey))
+		commands = append(commands, conn.Command(ListPushCommand, testKey, "a", "b"))
+		commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey))
+		commands = append(commands, conn.Command(ExecuteCommand))
+
+		err := SetListReplace(context.Background(), client, testKey, []string{"a", "b"}, testDependantKey)
+		assert.NoError(t, err)
+		for _, c := range commands {
+			assert.True(t, c.Called)
+		}
+	})
+
+	t.Run("empty slice removes the list", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(MultiCommand)
+		delCmd := conn.Command(DeleteCommand, testKey)
+		conn.Command(ExecuteCommand)
+
+		err := SetListReplace(context.Background(), client, testKey, nil, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, delCmd.Called)
+	})
+
+	t.Run("replace with expiration using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(MultiCommand)
+		conn.Command(DeleteCommand, testKey)
+		conn.Command(ListPushCommand, testKey, "a")
+		expireCmd := conn.Command(ExpireMillisCommand, testKey, int64(1500))
+		conn.Command(ExecuteCommand)
+
+		err := SetListExp(context.Background(), client, testKey, []string{"a"}, 1500*time.Millisecond)
+		assert.NoError(t, err)
+		assert.True(t, expireCmd.Called)
+
+		err = SetListExp(context.Background(), client, testKey, []string{"a"}, 0)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("replace using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetListRaw(conn, testKey, []string{"old-1", "old-2"})
+		assert.NoError(t, err)
+
+		err = SetListExpRaw(conn, testKey, []string{"a", "b"}, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+
+		var list []string
+		list, err = GetListRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, list)
+
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do("TTL", testKey))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+
+		// Removed with the dependency
+		_, err = DeleteRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// TestPushBounded tests the methods PushBounded() and PushBoundedLeft()
+func TestPushBounded(t *testing.T) {
+
+	t.Run("invalid max length", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := PushBounded(context.Background(), client, testKey, "a", 0)
+		assert.ErrorIs(t, err, ErrInvalidMaxLength)
+
+		err = PushBoundedLeft(context.Background(), client, testKey, "a", -1)
+		assert.ErrorIs(t, err, ErrInvalidMaxLength)
+	})
+
+	t.Run("push bounded using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var commands []*redigomock.Cmd
+		commands = append(commands, conn.Command(MultiCommand))
+		commands = append(commands, conn.Command(ListPushCommand, testKey, "a"))
+		commands = append(commands, conn.Command(ListTrimCommand, testKey, -10, -1))
+		commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey))
+		commands = append(commands, conn.Command(ExecuteCommand))
+
+		err := PushBounded(context.Background(), client, testKey, "a", 10, testDependantKey)
+		assert.NoError(t, err)
+		for _, c := range commands {
+			assert.True(t, c.Called)
+		}
+	})
+
+	t.Run("push bounded left using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(MultiCommand)
+		pushCmd := conn.Command(ListPushLeftCommand, testKey, "a")
+		trimCmd := conn.Command(ListTrimCommand, testKey, 0, 9)
+		conn.Command(ExecuteCommand)
+
+		err := PushBoundedLeft(context.Background(), client, testKey, "a", 10)
+		assert.NoError(t, err)
+		assert.True(t, pushCmd.Called)
+		assert.True(t, trimCmd.Called)
+	})
+
+	t.Run("push bounded using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		for _, value := range []string{"a", "b", "c", "d"} {
+			err = PushBoundedRaw(conn, testKey, value, 3, testDependantKey)
+			assert.NoError(t, err)
+			err = PushBoundedLeftRaw(conn, testHashName, value, 3)
+			assert.NoError(t, err)
+		}
+
+		var list []string
+		list, err = GetListRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b", "c", "d"}, list)
+
+		list, err = GetListRaw(conn, testHashName)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"d", "c", "b"}, list)
+
+		// Removed with the dependency
+		_, err = DeleteRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExamplePushBounded is an example of the method PushBounded()
+func ExamplePushBounded() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the transaction
+	conn.Command(MultiCommand)
+	conn.Command(ListPushCommand, testKey, "viewed-item-1")
+	conn.Command(ListTrimCommand, testKey, -100, -1)
+	conn.Command(ExecuteCommand)
+
+	// Keep the newest 100 elements
+	if err := PushBounded(context.Background(), client, testKey, "viewed-item-1", 100); err != nil {
+		fmt.Printf("error occurred: %s", err.Error())
+		return
+	}
+	fmt.Print("pushed")
+	// Output:pushed
+}
+
+// TestListIterator tests the ListIterator
+func TestListIterator(t *testing.T) {
+
+	t.Run("pages using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ListRangeCommand, testKey, 0, 1).Expect([]interface{}{[]byte("a"), []byte("b")})
+		conn.Command(ListRangeCommand, testKey, 2, 3).Expect([]interface{}{[]byte("c")})
+
+		var pages [][]string
+		iterator := NewListIterator(client, testKey, 2)
+		for iterator.Next(context.Background()) {
+			pages = append(pages, iterator.Page())
+		}
+		assert.NoError(t, iterator.Err())
+		assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, pages)
+
+		// Exhausted
+		assert.False(t, iterator.Next(context.Background()))
+	})
+
+	t.Run("exact multiple of the page size", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ListRangeCommand, testKey, 0, 1).Expect([]interface{}{[]byte("a"), []byte("b")})
+		conn.Command(ListRangeCommand, testKey, 2, 3).Expect([]interface{}{})
+
+		var count int
+		iterator := NewListIterator(client, testKey, 2)
+		for iterator.Next(context.Background()) {
+			count++
+		}
+		assert.NoError(t, iterator.Err())
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("error stops the iteration", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ListRangeCommand, testKey, 0, 99).ExpectError(errors.New("connection lost"))
+
+		iterator := NewListIterator(client, testKey, 0)
+		assert.False(t, iterator.Next(context.Background()))
+		assert.Error(t, iterator.Err())
+	})
+}
+
+// ExampleNewListIterator is an example of the method NewListIterator()
+func ExampleNewListIterator() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the range commands
+	conn.Command(ListRangeCommand, testKey, 0, 1).Expect([]interface{}{[]byte("a"), []byte("b")})
+	conn.Command(ListRangeCommand, testKey, 2, 3).Expect([]interface{}{[]byte("c")})
+
+	// Page through the list two elements at a time
+	iterator := NewListIterator(client, testKey, 2)
+	for iterator.Next(context.Background()) {
+		fmt.Println(iterator.Page())
+	}
+	// Output:[a b]
+	// [c]
+}
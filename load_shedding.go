@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrOverloaded is returned for background calls past the concurrency limit (see: SetConcurrencyLimit())
+var ErrOverloaded = errors.New("redis client is overloaded")
+
+// LoadStats are the stats of the load shedding (see: SetConcurrencyLimit())
+type LoadStats struct {
+	Limit       int    `json:"limit"`       // Max outstanding operations (0 is disabled)
+	Outstanding int    `json:"outstanding"` // Operations holding a connection
+	Shed        uint64 `json:"shed"`        // Background calls rejected with ErrOverloaded
+}
+
+// loadShedder counts the outstanding operations of the client
+type loadShedder struct {
+	active int64
+	limit  int64
+	shed   uint64
+}
+
+// SetConcurrencyLimit limits the outstanding operations (connections in use from the primary, replica
+// and class pools) of the client, 0 removes the limit
+//
+// Past the limit, background calls (see: WithPriority()) fail fast with ErrOverloaded instead of
+// waiting for the pool, request-path calls are not rejected. This protects redis and the app during
+// traffic spikes, the rejected calls are reported by Stats()
+func (c *Client) SetConcurrencyLimit(limit int) error {
+	var shedder *loadShedder
+	if limit < 0 {
+		return errors.New("limit must be zero or more")
+	} else if limit > 0 {
+		shedder = &loadShedder{limit: int64(limit)}
+	}
+	c.mu.Lock()
+	c.loadShedder = shedder
+	c.mu.Unlock()
+	return nil
+}
+
+// enter counts the operation, false if it is shed
+func (s *loadShedder) enter(level Priority) bool {
+	if atomic.AddInt64(&s.active, 1) > s.limit && level == PriorityBackground {
+		atomic.AddInt64(&s.active, -1)
+		atomic.AddUint64(&s.shed, 1)
+		return false
+	}
+	return true
+}
+
+// leave removes the operation
+func (s *loadShedder) leave() {
+	atomic.AddInt64(&s.active, -1)
+}
+
+// stats returns the stats of the load shedding
+func (s *loadShedder) stats() LoadStats {
+	return LoadStats{
+		Limit:       int(s.limit),
+		Outstanding: int(atomic.LoadInt64(&s.active)),
+		Shed:        atomic.LoadUint64(&s.shed),
+	}
+}
+
+// admit applies the load shedding and the priority queue (primary pool only) before taking a connection,
+// the release (nil if there is nothing to release) must be called once the connection is closed
+func (c *Client) admit(ctx context.Context, maxWait time.Duration, primary bool) (func(), error) {
+	c.mu.RLock()
+	shedder, queue := c.loadShedder, c.priorityQueue
+	c.mu.RUnlock()
+
+	level := priority(ctx)
+	var releases []func()
+	if shedder != nil {
+		if !shedder.enter(level) {
+			return nil, ErrOverloaded
+		}
+		releases = append(releases, shedder.leave)
+	}
+
+	// Calls using a pool class or a replica are not queued
+	if queue != nil && primary {
+		release, err := queue.acquire(ctx, level, maxWait)
+		if err != nil {
+			if shedder != nil {
+				shedder.leave()
+			}
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+
+	if len(releases) == 0 {
+		return nil, nil
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			for _, release := range releases {
+				release()
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mrz1836/go-cache/nrredis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetConcurrencyLimit tests the method SetConcurrencyLimit()
+func TestClient_SetConcurrencyLimit(t *testing.T) {
+
+	t.Run("invalid limit", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Error(t, client.SetConcurrencyLimit(-1))
+		assert.NoError(t, client.SetConcurrencyLimit(0))
+		assert.Equal(t, 0, client.Stats().Load.Limit)
+	})
+
+	t.Run("shed background calls using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetConcurrencyLimit(1))
+
+		held, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, client.Stats().Load.Outstanding)
+
+		// Background calls fail fast
+		background := WithPriority(context.Background(), PriorityBackground)
+		_, err = client.GetConnectionWithContext(background)
+		assert.ErrorIs(t, err, ErrOverloaded)
+
+		// Request-path calls are not rejected
+		request, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, client.Stats().Load.Outstanding)
+		client.CloseConnection(request)
+
+		// Closing twice leaves once
+		client.CloseConnection(held)
+		client.CloseConnection(held)
+		assert.Equal(t, 0, client.Stats().Load.Outstanding)
+
+		held, err = client.GetConnectionWithContext(background)
+		assert.NoError(t, err)
+		client.CloseConnection(held)
+
+		stats := client.Stats().Load
+		assert.Equal(t, 1, stats.Limit)
+		assert.Equal(t, uint64(1), stats.Shed)
+	})
+
+	t.Run("shed replica reads using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+		assert.NoError(t, client.SetConcurrencyLimit(1))
+
+		held, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		defer client.CloseConnection(held)
+
+		// Background replica reads fail fast (no fallback to the primary)
+		getCmd := replicaConn.Command(GetCommand, testKey).Expect(testStringValue)
+		background := WithPriority(context.Background(), PriorityBackground)
+		_, err = Get(background, client, testKey)
+		assert.ErrorIs(t, err, ErrOverloaded)
+
+		_, err = client.GetReadConnectionWithContext(background)
+		assert.ErrorIs(t, err, ErrOverloaded)
+		assert.Equal(t, uint64(2), client.Stats().Load.Shed)
+
+		// Request-path replica reads are counted
+		read, err := client.GetReadConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, client.Stats().Load.Outstanding)
+		client.CloseConnection(read)
+
+		var val string
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+		assert.Equal(t, 1, replicaConn.Stats(getCmd))
+		assert.Equal(t, 1, client.Stats().Load.Outstanding)
+	})
+
+	t.Run("with the priority queue using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetConcurrencyLimit(1))
+		assert.NoError(t, client.SetPriorityQueue(1))
+
+		held, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+
+		// Canceled waits in the queue leave the load shedding
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = client.GetConnectionWithContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, client.Stats().Load.Outstanding)
+
+		client.CloseConnection(held)
+		assert.Equal(t, 0, client.Stats().Load.Outstanding)
+	})
+}
+
+// ExampleClient_SetConcurrencyLimit is an example of the method SetConcurrencyLimit()
+func ExampleClient_SetConcurrencyLimit() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Shed background calls past one outstanding operation
+	_ = client.SetConcurrencyLimit(1)
+	held, _ := client.GetConnectionWithContext(context.Background())
+	defer client.CloseConnection(held)
+
+	err := Set(WithPriority(context.Background(), PriorityBackground), client, testKey, testStringValue)
+	fmt.Printf("overloaded: %t", err == ErrOverloaded)
+	// Output:overloaded: true
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"path"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// LoaderFunc loads the value of a missing key from the source of truth (see: RegisterLoader())
+// Return redis.ErrNil if the key does not exist in the source (the miss is returned)
+type LoaderFunc func(ctx context.Context, key string) (string, error)
+
+// registeredLoader is a loader for the keys matching the pattern
+type registeredLoader struct {
+	load    LoaderFunc
+	pattern string
+	tags    []string
+	ttl     time.Duration
+}
+
+// loaderRegistry are the registered loaders (in order) and the loads in flight
+type loaderRegistry struct {
+	flight  flightGroup
+	loaders []*registeredLoader
+}
+
+// RegisterLoader registers a read-through loader: Get() and GetBytes() misses for keys matching
+// the pattern call the loader and store the value with the ttl (0 is no expiration) linked to the
+// tags, centralizing the cache policy of a key space (IE: "user:*")
+//
+// Patterns use the path.Match syntax (* does not match a "/"), the first registered pattern that
+// matches is used and registering a pattern again replaces its loader
+// Concurrent misses of a key share a single load, and the value is returned even if storing it fails
+// Only the client functions read through (not Raw functions on custom connections)
+func (c *Client) RegisterLoader(pattern string, loader LoaderFunc, ttl time.Duration, tags ...string) error {
+	if len(pattern) == 0 {
+		return errors.New("missing required parameter: pattern")
+	} else if loader == nil {
+		return errors.New("missing required parameter: loader")
+	} else if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+	registered := &registeredLoader{load: loader, pattern: pattern, tags: tags, ttl: ttl}
+
+	// Copy on write (loads in flight keep the loaders they matched)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	registry := &loaderRegistry{flight: flightGroup{client: c}, loaders: []*registeredLoader{registered}}
+	if c.loaders != nil {
+		registry.loaders = make([]*registeredLoader, 0, len(c.loaders.loaders)+1)
+		replaced := false
+		for _, existing := range c.loaders.loaders {
+			if existing.pattern == pattern {
+				existing, replaced = registered, true
+			}
+			registry.loaders = append(registry.loaders, existing)
+		}
+		if !replaced {
+			registry.loaders = append(registry.loaders, registered)
+		}
+	}
+	c.loaders = registry
+	return nil
+}
+
+// UnregisterLoader removes the loader of the pattern (see: RegisterLoader())
+func (c *Client) UnregisterLoader(pattern string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.loaders == nil {
+		return
+	}
+	registry := &loaderRegistry{
+		flight:  flightGroup{client: c},
+		loaders: make([]*registeredLoader, 0, len(c.loaders.loaders)),
+	}
+	for _, existing := range c.loaders.loaders {
+		if existing.pattern != pattern {
+			registry.loaders = append(registry.loaders, existing)
+		}
+	}
+	if len(registry.loaders) == 0 {
+		registry = nil
+	}
+	c.loaders = registry
+}
+
+// readThrough calls the loader registered for the missing key and stores the loaded value
+// (redis.ErrNil is returned if no pattern matches the key)
+func (c *Client) readThrough(ctx context.Context, key string) (string, error) {
+	c.mu.RLock()
+	registry := c.loaders
+	c.mu.RUnlock()
+	if registry == nil {
+		return "", redis.ErrNil
+	}
+
+	var matched *registeredLoader
+	for _, loader := range registry.loaders {
+		if ok, _ := path.Match(loader.pattern, key); ok {
+			matched = loader
+			break
+		}
+	}
+	if matched == nil {
+		return "", redis.ErrNil
+	}
+
+	value, err, _ := registry.flight.do(key, func() (interface{}, error) {
+		data, loadErr := matched.load(ctx, key)
+		if loadErr != nil {
+			return "", loadErr
+		}
+		_ = storeValue(ctx, c, key, []byte(data), matched.ttl, matched.tags)
+		return data, nil
+	})
+	return value.(string), err
+}
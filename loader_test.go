@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegisterLoader tests the method RegisterLoader()
+func TestRegisterLoader(t *testing.T) {
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		loader := func(context.Context, string) (string, error) { return "", nil }
+		err := client.RegisterLoader("", loader, 0)
+		assert.Error(t, err)
+
+		err = client.RegisterLoader("user:*", nil, 0)
+		assert.Error(t, err)
+
+		err = client.RegisterLoader("user:[", loader, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("read through using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var keys []string
+		err := client.RegisterLoader("user:*", func(_ context.Context, key string) (string, error) {
+			keys = append(keys, key)
+			return "loaded-" + key, nil
+		}, time.Minute, "users")
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, "user:1").Expect(nil)
+		setCmd := conn.Command(SetExpirationCommand, "user:1", int64(60), []byte("loaded-user:1"))
+		conn.Command(MultiCommand)
+		addCmd := conn.Command(AddToSetCommand, DependencyPrefix+"users", "user:1")
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		var value string
+		value, err = Get(context.Background(), client, "user:1")
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded-user:1", value)
+		assert.Equal(t, []string{"user:1"}, keys)
+		assert.True(t, setCmd.Called)
+		assert.True(t, addCmd.Called)
+
+		// Keys not matching the pattern still miss
+		conn.Command(GetCommand, "order:1").Expect(nil)
+		_, err = Get(context.Background(), client, "order:1")
+		assert.ErrorIs(t, err, redis.ErrNil)
+		assert.Equal(t, 1, len(keys))
+	})
+
+	t.Run("loader errors using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		errSource := errors.New("database is down")
+		err := client.RegisterLoader("user:*", func(context.Context, string) (string, error) {
+			return "", errSource
+		}, 0)
+		assert.NoError(t, err)
+		err = client.RegisterLoader("order:*", func(context.Context, string) (string, error) {
+			return "", redis.ErrNil
+		}, 0)
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, "user:1").Expect(nil)
+		conn.Command(GetCommand, "order:1").Expect(nil)
+
+		_, err = GetBytes(context.Background(), client, "user:1")
+		assert.ErrorIs(t, err, errSource)
+
+		var found bool
+		_, found, err = GetWithFound(context.Background(), client, "order:1")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("replace and unregister", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.RegisterLoader("user:*", func(context.Context, string) (string, error) {
+			return "first", nil
+		}, 0)
+		assert.NoError(t, err)
+		err = client.RegisterLoader("user:*", func(context.Context, string) (string, error) {
+			return "second", nil
+		}, 0)
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, "user:1").Expect(nil)
+		conn.Command(SetCommand, "user:1", []byte("second"))
+
+		var value string
+		value, err = Get(context.Background(), client, "user:1")
+		assert.NoError(t, err)
+		assert.Equal(t, "second", value)
+
+		client.UnregisterLoader("user:*")
+		_, err = Get(context.Background(), client, "user:1")
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("read through using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var loads int32
+		err = client.RegisterLoader("product:*", func(_ context.Context, key string) (string, error) {
+			atomic.AddInt32(&loads, 1)
+			time.Sleep(20 * time.Millisecond)
+			return "loaded-" + key, nil
+		}, time.Minute, "products")
+		assert.NoError(t, err)
+
+		// Concurrent misses share a single load
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, getErr := Get(context.Background(), client, "product:1")
+				assert.NoError(t, getErr)
+				assert.Equal(t, "loaded-product:1", value)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+		// Stored and linked to the tag
+		var value string
+		value, err = GetRaw(conn, "product:1")
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded-product:1", value)
+
+		var total int
+		total, err = KillByDependency(context.Background(), client, "products")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total)
+	})
+}
+
+// ExampleClient_RegisterLoader is an example of the method RegisterLoader()
+func ExampleClient_RegisterLoader() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Load missing users from the database
+	_ = client.RegisterLoader("user:*", func(_ context.Context, key string) (string, error) {
+		return `{"name":"` + key + `"}`, nil
+	}, 0)
+
+	// Mock the miss and the store
+	conn.Command(GetCommand, "user:1").Expect(nil)
+	conn.Command(SetCommand, "user:1", []byte(`{"name":"user:1"}`))
+
+	value, _ := Get(context.Background(), client, "user:1")
+	fmt.Print(value)
+	// Output:{"name":"user:1"}
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// MemoizePrefix is the prefix for all memoized result keys
+const MemoizePrefix = "memoize:"
+
+// MemoizeOption configures a memoized function
+type MemoizeOption func(*memoizeConfig)
+
+// memoizeConfig is the configuration of a memoized function
+type memoizeConfig struct {
+	codec        Codec
+	dependencies []string
+	singleflight bool
+}
+
+// WithMemoizeCodec sets the codec used to store the results (default: JSONCodec)
+func WithMemoizeCodec(codec Codec) MemoizeOption {
+	return func(c *memoizeConfig) {
+		if codec != nil {
+			c.codec = codec
+		}
+	}
+}
+
+// WithMemoizeDependencies links every stored result to the dependency keys
+func WithMemoizeDependencies(dependencies ...string) MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.dependencies = append(c.dependencies, dependencies...)
+	}
+}
+
+// WithMemoizeSingleflight lets concurrent misses for the same args share a single call of the function
+func WithMemoizeSingleflight() MemoizeOption {
+	return func(c *memoizeConfig) {
+		c.singleflight = true
+	}
+}
+
+// Memoize wraps the function and returns a wrapper that caches the results for the ttl
+//
+// The args are encoded and hashed into the key (memoize:<name>:<hash>), so args must
+// be encodable by the codec. Errors from the function are returned and never cached.
+// The cache is best-effort: if redis fails the function is called and its result returned.
+func Memoize[A any, R any](client *Client, name string, ttl time.Duration,
+	fn func(ctx context.Context, args A) (R, error), options ...MemoizeOption) func(ctx context.Context, args A) (R, error) {
+
+	config := &memoizeConfig{codec: JSONCodec{}}
+	for _, opt := range options {
+		opt(config)
+	}
+	group := &flightGroup{client: client}
+
+	// load calls the function and stores the result
+	load := func(ctx context.Context, key string, args A) (R, error) {
+		result, err := fn(ctx, args)
+		if err != nil {
+			return result, err
+		}
+		var data []byte
+		if data, err = config.codec.Marshal(result); err == nil {
+			_ = storeValue(ctx, client, key, data, ttl, config.dependencies)
+		}
+		return result, nil
+	}
+
+	return func(ctx context.Context, args A) (R, error) {
+		var result R
+		key, err := memoizeKey(config.codec, name, args)
+		if err != nil {
+			return result, err
+		}
+
+		// Cached result
+		var data []byte
+		if data, err = getBytes(ctx, client, key); err == nil {
+			if err = config.codec.Unmarshal(data, &result); err == nil {
+				return result, nil
+			}
+			result = *new(R)
+		}
+
+		if !config.singleflight {
+			return load(ctx, key, args)
+		}
+
+		var value interface{}
+		if value, err, _ = group.do(key, func() (interface{}, error) {
+			return load(ctx, key, args)
+		}); err != nil {
+			return result, err
+		}
+		result, _ = value.(R) // A nil interface result is not an R
+		return result, nil
+	}
+}
+
+// memoizeKey returns the key for the memoized function and args
+func memoizeKey(codec Codec, name string, args interface{}) (string, error) {
+	if len(name) == 0 {
+		return "", errors.New("missing required parameter: name")
+	}
+	data, err := codec.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return MemoizePrefix + name + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// storeValue stores the encoded value with the ttl (0 is no expiration) and dependencies through the
+// client writers (see: Set(), SetExp()), so the size guard, tombstones, hooks and write-through apply
+func storeValue(ctx context.Context, client *Client, key string, data []byte,
+	ttl time.Duration, dependencies []string) error {
+	if ttl > 0 {
+		return SetExp(ctx, client, key, data, ttl, dependencies...)
+	}
+	return Set(ctx, client, key, data, dependencies...)
+}
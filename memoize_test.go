@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testUserLookup is a result used to test memoization
+type testUserLookup struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestMemoize tests the method Memoize()
+func TestMemoize(t *testing.T) {
+
+	t.Run("missing name", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		lookup := Memoize(client, "", time.Minute, func(_ context.Context, id int) (string, error) {
+			return testStringValue, nil
+		})
+		_, err := lookup(context.Background(), 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("miss then hit using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		key, err := memoizeKey(JSONCodec{}, "user", 7)
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, key).Expect(nil).Expect([]byte(`{"id":7,"name":"cached"}`))
+		setCmd := conn.Command(SetExpirationCommand, key, int64(60), []byte(`{"id":7,"name":"user-7"}`)).
+			Expect("OK")
+
+		var calls int64
+		lookup := Memoize(client, "user", time.Minute, func(_ context.Context, id int) (*testUserLookup, error) {
+			atomic.AddInt64(&calls, 1)
+			return &testUserLookup{ID: id, Name: fmt.Sprintf("user-%d", id)}, nil
+		})
+
+		var user *testUserLookup
+		user, err = lookup(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-7", user.Name)
+		assert.True(t, setCmd.Called)
+
+		user, err = lookup(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, "cached", user.Name)
+		assert.Equal(t, int64(1), calls)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		key, err := memoizeKey(JSONCodec{}, "failing", "args")
+		assert.NoError(t, err)
+		conn.Command(GetCommand, key).Expect(nil)
+
+		lookup := Memoize(client, "failing", time.Minute, func(_ context.Context, _ string) (int, error) {
+			return 0, errors.New("failed")
+		})
+		_, err = lookup(context.Background(), "args")
+		assert.EqualError(t, err, "failed")
+		assert.Equal(t, 0, conn.Stats(conn.GenericCommand(SetExpirationCommand)))
+	})
+
+	t.Run("nil interface results with singleflight using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		key, err := memoizeKey(JSONCodec{}, "stringer", 1)
+		assert.NoError(t, err)
+		conn.Command(GetCommand, key).Expect(nil)
+		conn.Command(SetExpirationCommand, key, int64(60), []byte("null")).Expect("OK")
+
+		lookup := Memoize(client, "stringer", time.Minute, func(_ context.Context, _ int) (fmt.Stringer, error) {
+			return nil, nil
+		}, WithMemoizeSingleflight())
+
+		var value fmt.Stringer
+		assert.NotPanics(t, func() {
+			value, err = lookup(context.Background(), 1)
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, value)
+	})
+
+	t.Run("stored through the client using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var sets []string
+		client.OnSet(func(_ context.Context, event KeyEvent) { sets = append(sets, event.Key) })
+
+		key, err := memoizeKey(JSONCodec{}, "double", 21)
+		assert.NoError(t, err)
+		conn.Command(GetCommand, key).Expect(nil)
+		conn.Command(SetExpirationCommand, key, int64(60), []byte("42")).Expect("OK")
+
+		lookup := Memoize(client, "double", time.Minute, func(_ context.Context, id int) (int, error) {
+			return id * 2, nil
+		})
+		_, err = lookup(context.Background(), 21)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{key}, sets)
+	})
+
+	t.Run("redis failure calls the function", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.GenericCommand(GetCommand).ExpectError(errors.New("connection refused"))
+		conn.GenericCommand(SetExpirationCommand).ExpectError(errors.New("connection refused"))
+
+		lookup := Memoize(client, "user", time.Minute, func(_ context.Context, id int) (int, error) {
+			return id * 2, nil
+		})
+		value, err := lookup(context.Background(), 21)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, value)
+	})
+
+	t.Run("singleflight using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var calls int64
+		lookup := Memoize(client, "slow", time.Minute, func(_ context.Context, id int) (int, error) {
+			atomic.AddInt64(&calls, 1)
+			time.Sleep(100 * time.Millisecond)
+			return id * 2, nil
+		}, WithMemoizeSingleflight(), WithMemoizeDependencies(testDependantKey))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, lookupErr := lookup(context.Background(), 21)
+				assert.NoError(t, lookupErr)
+				assert.Equal(t, 42, value)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int64(1), calls)
+
+		// Cached until the dependency is killed
+		_, err = lookup(context.Background(), 21)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), calls)
+
+		_, err = KillByDependencyRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+
+		_, err = lookup(context.Background(), 21)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), calls)
+	})
+}
+
+// ExampleMemoize is an example of the method Memoize()
+func ExampleMemoize() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the get and set commands
+	conn.GenericCommand(GetCommand).Expect(nil)
+	conn.GenericCommand(SetExpirationCommand).Expect("OK")
+
+	// Memoize an expensive function
+	double := Memoize(client, "double", time.Minute, func(_ context.Context, n int) (int, error) {
+		return n * 2, nil
+	}, WithMemoizeSingleflight())
+
+	value, _ := double(context.Background(), 21)
+	fmt.Printf("value: %d", value)
+	// Output:value: 42
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// MultiResult is the result of reading many keys (or hash fields) at once, an empty value is
+// distinguished from a missing key by Found
+type MultiResult struct {
+	Found  []bool   // Presence of each key (in order)
+	Keys   []string // Keys that were read (in order)
+	Values []string // Value of each key (in order, empty if missing)
+}
+
+// Map returns the value of each key by key (nil if the key is missing)
+func (r *MultiResult) Map() map[string]*string {
+	values := make(map[string]*string, len(r.Keys))
+	for i, key := range r.Keys {
+		if r.Found[i] {
+			values[key] = &r.Values[i]
+		} else {
+			values[key] = nil
+		}
+	}
+	return values
+}
+
+// Missing returns the keys that were not found (in order)
+func (r *MultiResult) Missing() []string {
+	var missing []string
+	for i, key := range r.Keys {
+		if !r.Found[i] {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// GetMulti gets the keys from redis in string format in a single round trip
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Reads values stored by the value size guard (see: SetValueSizeGuard())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: GetMultiRaw()
+func GetMulti(ctx context.Context, client *Client, keys ...string) (result *MultiResult, err error) {
+	if client.IsBypassed() {
+		return newMultiResult(keys, make([]interface{}, len(keys)))
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		if result, readErr = GetMultiRaw(conn, keys...); readErr != nil {
+			return
+		} else if readErr = client.hideTombstones(conn, result); readErr != nil {
+			return
+		}
+		for i, value := range result.Values {
+			if result.Found[i] && strings.HasPrefix(value, encodedValuePrefix) {
+				var data []byte
+				if data, readErr = decodeValue(conn, keys[i], []byte(value)); readErr != nil {
+					return
+				}
+				result.Values[i] = string(data)
+			}
+		}
+		return
+	})
+	return
+}
+
+// GetMultiRaw gets the keys from redis in string format in a single round trip
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/mget
+func GetMultiRaw(conn redis.Conn, keys ...string) (*MultiResult, error) {
+	if len(keys) == 0 {
+		return &MultiResult{}, nil
+	}
+	replies, err := redis.Values(conn.Do(MultiGetCommand, redis.Args{}.AddFlat(keys)...))
+	if err != nil {
+		return nil, err
+	}
+	return newMultiResult(keys, replies)
+}
+
+// HashMapGetFound gets the fields of a hash map, an empty value is distinguished from a
+// missing field (see: HashMapGet())
+// Hashes with a tombstone are missing if set (see: SetInvalidationTombstones())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: HashMapGetFoundRaw()
+func HashMapGetFound(ctx context.Context, client *Client, hashName string, fields ...string) (*MultiResult, error) {
+	if client.IsBypassed() {
+		return newMultiResult(fields, make([]interface{}, len(fields)))
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	if tombstone, err := client.hasTombstone(conn, hashName); err != nil {
+		return nil, err
+	} else if tombstone {
+		return newMultiResult(fields, make([]interface{}, len(fields)))
+	}
+	return HashMapGetFoundRaw(conn, hashName, fields...)
+}
+
+// HashMapGetFoundRaw gets the fields of a hash map, an empty value is distinguished from a
+// missing field
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hmget
+func HashMapGetFoundRaw(conn redis.Conn, hashName string, fields ...string) (*MultiResult, error) {
+	if len(fields) == 0 {
+		return &MultiResult{}, nil
+	}
+	replies, err := redis.Values(conn.Do(HashMapGetCommand, redis.Args{}.Add(hashName).AddFlat(fields)...))
+	if err != nil {
+		return nil, err
+	}
+	return newMultiResult(fields, replies)
+}
+
+// newMultiResult converts the replies for the keys (nil replies are missing keys)
+func newMultiResult(keys []string, replies []interface{}) (*MultiResult, error) {
+	result := &MultiResult{
+		Found:  make([]bool, len(keys)),
+		Keys:   keys,
+		Values: make([]string, len(keys)),
+	}
+	for i, reply := range replies {
+		if reply == nil {
+			continue
+		}
+		value, err := redis.String(reply, nil)
+		if err != nil {
+			return nil, err
+		}
+		result.Found[i] = true
+		result.Values[i] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetMulti tests the method GetMulti()
+func TestGetMulti(t *testing.T) {
+
+	t.Run("get multi using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(MultiGetCommand, "key-1", "key-2", "key-3").
+			Expect([]interface{}{[]byte("value-1"), []byte(""), nil})
+
+		result, err := GetMulti(context.Background(), client, "key-1", "key-2", "key-3")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true, false}, result.Found)
+		assert.Equal(t, []string{"value-1", "", ""}, result.Values)
+		assert.Equal(t, []string{"key-3"}, result.Missing())
+
+		values := result.Map()
+		assert.Equal(t, "value-1", *values["key-1"])
+		assert.Equal(t, "", *values["key-2"])
+		assert.Nil(t, values["key-3"])
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		result, err := GetMulti(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Found)
+	})
+
+	t.Run("bypassed cache misses every key", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetBypass(true)
+
+		result, err := GetMulti(context.Background(), client, "key-1", "key-2")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"key-1", "key-2"}, result.Missing())
+	})
+
+	t.Run("hash map get found using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(HashMapGetCommand, testHashName, "field-1", "field-2").
+			Expect([]interface{}{[]byte(""), nil})
+
+		result, err := HashMapGetFound(context.Background(), client, testHashName, "field-1", "field-2")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, result.Found)
+	})
+
+	t.Run("get multi using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetRaw(conn, "key-1", "value-1")
+		assert.NoError(t, err)
+		err = SetRaw(conn, "key-2", "")
+		assert.NoError(t, err)
+		err = HashSetRaw(conn, testHashName, "field-1", "")
+		assert.NoError(t, err)
+
+		var result *MultiResult
+		result, err = GetMultiRaw(conn, "key-1", "key-2", "key-3")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, true, false}, result.Found)
+		assert.Equal(t, []string{"value-1", "", ""}, result.Values)
+
+		result, err = HashMapGetFoundRaw(conn, testHashName, "field-1", "field-2")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, result.Found)
+	})
+}
+
+// ExampleGetMulti is an example of the method GetMulti()
+func ExampleGetMulti() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a cached empty value and a missing key
+	conn.Command(MultiGetCommand, "key-1", "key-2").Expect([]interface{}{[]byte(""), nil})
+
+	result, _ := GetMulti(context.Background(), client, "key-1", "key-2")
+	fmt.Printf("found: %v missing: %v", result.Found, result.Missing())
+	// Output:found: [true false] missing: [key-2]
+}
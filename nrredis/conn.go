@@ -2,6 +2,7 @@ package nrredis
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -60,6 +61,26 @@ func (c *wrappedConn) Receive() (interface{}, error) {
 	return c.Conn.Receive()
 }
 
+// DoWithTimeout is a wrapper for the standard method
+func (c *wrappedConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	if c.txn != nil {
+		seg := c.createSegment(commandName)
+		seg.ParameterizedQuery = formatCommand(commandName, args)
+		defer seg.End()
+	}
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout is a wrapper for the standard method
+func (c *wrappedConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	if c.txn != nil {
+		seg := c.createSegment("receive")
+		defer seg.End()
+	}
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
 // createSegment will create a new datastore segment for NewRelic
 func (c *wrappedConn) createSegment(cmdName string) *newrelic.DatastoreSegment {
 	return &newrelic.DatastoreSegment{
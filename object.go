@@ -0,0 +1,183 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// notFoundValue is stored for objects the loader reported as missing (see: WithNegativeTTL())
+const notFoundValue = encodedValuePrefix + "not-found\x00"
+
+// ErrObjectNotFound is returned by a loader for objects that do not exist (the miss can be cached,
+// see: WithNegativeTTL()) and by FetchStruct() for those objects
+var ErrObjectNotFound = errors.New("object not found")
+
+// SetObject encodes and stores the object with the ttl (0 is no expiration) and keeps a reference
+// to each dependency
+// Objects that implement proto.Message are stored as protobuf, all others as JSON (see: ProtoCodec)
+// Applies the value size guard if set (see: SetValueSizeGuard())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetObjectRaw()
+func SetObject(ctx context.Context, client *Client, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	data, err := ProtoCodec{}.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return SetExp(ctx, client, key, data, ttl, dependencies...)
+	}
+	return Set(ctx, client, key, data, dependencies...)
+}
+
+// SetObjectRaw encodes and stores the object with the ttl (0 is no expiration) and keeps a reference
+// to each dependency
+// Objects that implement proto.Message are stored as protobuf, all others as JSON (see: ProtoCodec)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func SetObjectRaw(conn redis.Conn, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	data, err := ProtoCodec{}.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if ttl > 0 {
+		return SetExpRaw(conn, key, data, ttl, dependencies...)
+	}
+	return SetRaw(conn, key, data, dependencies...)
+}
+
+// GetObject decodes the stored object into the destination (redis.ErrNil if the key is missing)
+// Destinations that implement proto.Message are decoded as protobuf, all others as JSON (see: ProtoCodec)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: GetObjectRaw()
+func GetObject(ctx context.Context, client *Client, key string, dest interface{}) error {
+	data, err := getBytes(ctx, client, key)
+	if err != nil {
+		return err
+	}
+	return ProtoCodec{}.Unmarshal(data, dest)
+}
+
+// GetObjectRaw decodes the stored object into the destination (redis.ErrNil if the key is missing)
+// Destinations that implement proto.Message are decoded as protobuf, all others as JSON (see: ProtoCodec)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/get
+func GetObjectRaw(conn redis.Conn, key string, dest interface{}) error {
+	data, err := GetBytesRaw(conn, key)
+	if err != nil {
+		return err
+	}
+	return ProtoCodec{}.Unmarshal(data, dest)
+}
+
+// ObjectLoader loads the object on a cache miss with the ttl (0 is no expiration) and tags to store it with
+// Return ErrObjectNotFound if the object does not exist
+type ObjectLoader func(ctx context.Context) (value interface{}, ttl time.Duration, tags []string, err error)
+
+// ObjectCacheOption configures an object cache
+type ObjectCacheOption func(*ObjectCache)
+
+// WithObjectCodec sets the codec used to store the objects (default: JSONCodec, IE: ProtoCodec{})
+func WithObjectCodec(codec Codec) ObjectCacheOption {
+	return func(o *ObjectCache) {
+		if codec != nil {
+			o.codec = codec
+		}
+	}
+}
+
+// WithNegativeTTL caches objects the loader reported as missing (ErrObjectNotFound) for the ttl,
+// so repeated lookups of a missing object do not reach the loader (default: not cached)
+func WithNegativeTTL(ttl time.Duration) ObjectCacheOption {
+	return func(o *ObjectCache) {
+		o.negativeTTL = ttl
+	}
+}
+
+// ObjectCache stores domain objects encoded by a codec and tagged with dependencies
+//
+// Removing a tag (see: Delete(), KillByDependency()) removes every object stored with the tag.
+// Concurrent misses for the same key share a single call of the loader.
+type ObjectCache struct {
+	client      *Client
+	codec       Codec
+	flight      flightGroup
+	negativeTTL time.Duration
+}
+
+// NewObjectCache creates a new object cache
+func NewObjectCache(client *Client, options ...ObjectCacheOption) *ObjectCache {
+	o := &ObjectCache{client: client, codec: JSONCodec{}}
+	o.flight.client = client
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+// CacheStruct encodes and stores the object with the ttl (0 is no expiration) and links each tag
+// as a dependency
+func (o *ObjectCache) CacheStruct(ctx context.Context, key string, value interface{},
+	ttl time.Duration, tags ...string) error {
+	data, err := o.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return storeValue(ctx, o.client, key, data, ttl, tags)
+}
+
+// FetchStruct decodes the cached object into the destination, calling the loader on a miss
+// and storing the loaded object
+//
+// ErrObjectNotFound is returned if the loader reported the object as missing (or the miss is cached).
+// The cache is best-effort: if redis fails the loader is called and its object returned.
+func (o *ObjectCache) FetchStruct(ctx context.Context, key string, dest interface{}, loader ObjectLoader) error {
+
+	// Cached object (or cached miss)
+	data, err := getBytes(ctx, o.client, key)
+	if err == nil {
+		if string(data) == notFoundValue {
+			return ErrObjectNotFound
+		}
+		if err = o.codec.Unmarshal(data, dest); err == nil {
+			return nil
+		}
+	}
+
+	var value interface{}
+	if value, err, _ = o.flight.do(key, func() (interface{}, error) {
+		return o.load(ctx, key, loader)
+	}); err != nil {
+		return err
+	}
+	return o.codec.Unmarshal(value.([]byte), dest)
+}
+
+// load calls the loader and stores the encoded object (or the miss)
+func (o *ObjectCache) load(ctx context.Context, key string, loader ObjectLoader) ([]byte, error) {
+	value, ttl, tags, err := loader(ctx)
+	if errors.Is(err, ErrObjectNotFound) {
+		if o.negativeTTL > 0 {
+			_ = storeValue(ctx, o.client, key, []byte(notFoundValue), o.negativeTTL, tags)
+		}
+		return nil, ErrObjectNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if data, err = o.codec.Marshal(value); err != nil {
+		return nil, err
+	}
+	_ = storeValue(ctx, o.client, key, data, ttl, tags)
+	return data, nil
+}
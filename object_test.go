@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// testObject is an object for the object cache tests
+type testObject struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestObjectCache tests the ObjectCache
+func TestObjectCache(t *testing.T) {
+
+	t.Run("fetch cached object using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect([]byte(`{"id":1,"name":"go-cache"}`))
+
+		objects := NewObjectCache(client)
+		var object testObject
+		err := objects.FetchStruct(context.Background(), testKey, &object,
+			func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+				t.Fatal("loader should not be called")
+				return nil, 0, nil, nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, testObject{ID: 1, Name: "go-cache"}, object)
+	})
+
+	t.Run("fetch cached miss using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect([]byte(notFoundValue))
+
+		objects := NewObjectCache(client, WithNegativeTTL(time.Minute))
+		var object testObject
+		err := objects.FetchStruct(context.Background(), testKey, &object,
+			func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+				t.Fatal("loader should not be called")
+				return nil, 0, nil, nil
+			})
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+	})
+
+	t.Run("loader errors are not cached using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(nil)
+
+		objects := NewObjectCache(client)
+		var object testObject
+		err := objects.FetchStruct(context.Background(), testKey, &object,
+			func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+				return nil, 0, nil, errors.New("database is down")
+			})
+		assert.EqualError(t, err, "database is down")
+	})
+
+	t.Run("load, tag and invalidate using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		objects := NewObjectCache(client, WithNegativeTTL(time.Minute))
+
+		var calls int32
+		loader := func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(10 * time.Millisecond)
+			return &testObject{ID: 1, Name: "go-cache"}, time.Minute, []string{"user-1"}, nil
+		}
+
+		// Concurrent misses share the loader
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var object testObject
+				assert.NoError(t, objects.FetchStruct(ctx, testKey, &object, loader))
+				assert.Equal(t, "go-cache", object.Name)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		// Cached
+		var object testObject
+		err = objects.FetchStruct(ctx, testKey, &object, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		// Removing the tag removes the object
+		_, err = Delete(ctx, client, "user-1")
+		assert.NoError(t, err)
+		err = objects.FetchStruct(ctx, testKey, &object, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+		// Cached misses
+		missing := func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, 0, nil, ErrObjectNotFound
+		}
+		err = objects.FetchStruct(ctx, "missing", &object, missing)
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+		err = objects.FetchStruct(ctx, "missing", &object, missing)
+		assert.ErrorIs(t, err, ErrObjectNotFound)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+		// Stored objects replace the cached miss
+		err = objects.CacheStruct(ctx, "missing", &testObject{ID: 2, Name: "found"}, time.Minute)
+		assert.NoError(t, err)
+		err = objects.FetchStruct(ctx, "missing", &object, missing)
+		assert.NoError(t, err)
+		assert.Equal(t, testObject{ID: 2, Name: "found"}, object)
+	})
+}
+
+// TestSetObject tests the method SetObject()
+func TestSetObject(t *testing.T) {
+
+	t.Run("protobuf message using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		data, err := proto.Marshal(wrapperspb.String("go-cache"))
+		assert.NoError(t, err)
+		setCmd := conn.Command(SetExpirationCommand, testKey, int64(60), data)
+		conn.Command(GetCommand, testKey).Expect(data)
+
+		err = SetObject(context.Background(), client, testKey, wrapperspb.String("go-cache"), time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+
+		message := new(wrapperspb.StringValue)
+		err = GetObject(context.Background(), client, testKey, message)
+		assert.NoError(t, err)
+		assert.Equal(t, "go-cache", message.GetValue())
+	})
+
+	t.Run("struct using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, []byte(`{"id":1,"name":"go-cache"}`))
+
+		err := SetObject(context.Background(), client, testKey, &testObject{ID: 1, Name: "go-cache"}, 0)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("objects using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetObjectRaw(conn, testKey, wrapperspb.Int64(42), time.Minute, testDependantKey)
+		assert.NoError(t, err)
+
+		message := new(wrapperspb.Int64Value)
+		err = GetObjectRaw(conn, testKey, message)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), message.GetValue())
+
+		err = SetObjectRaw(conn, "object", &testObject{ID: 2, Name: "json"}, 0)
+		assert.NoError(t, err)
+		var object testObject
+		err = GetObjectRaw(conn, "object", &object)
+		assert.NoError(t, err)
+		assert.Equal(t, testObject{ID: 2, Name: "json"}, object)
+
+		err = GetObjectRaw(conn, "missing", &object)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+}
+
+// ExampleSetObject is an example of the method SetObject()
+func ExampleSetObject() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command (protobuf wire format)
+	data, _ := proto.Marshal(wrapperspb.String("go-cache"))
+	conn.Command(SetCommand, testKey, data)
+
+	// Store the protobuf message
+	err := SetObject(context.Background(), client, testKey, wrapperspb.String("go-cache"), 0)
+	fmt.Printf("stored %d bytes: %v", len(data), err == nil)
+	// Output:stored 10 bytes: true
+}
+
+// ExampleObjectCache_FetchStruct is an example of the method FetchStruct()
+func ExampleObjectCache_FetchStruct() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the cached object
+	conn.Command(GetCommand, "user:1").Expect([]byte(`{"id":1,"name":"go-cache"}`))
+
+	// Fetch the object, loading it from the database on a miss
+	objects := NewObjectCache(client, WithNegativeTTL(time.Minute))
+	var user testObject
+	_ = objects.FetchStruct(context.Background(), "user:1", &user,
+		func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+			return &testObject{ID: 1, Name: "from the database"}, time.Hour, []string{"user-1"}, nil
+		})
+	fmt.Printf("name: %s", user.Name)
+	// Output:name: go-cache
+}
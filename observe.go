@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// observeConn wraps the connection to record the commands (see: SetCommandAudit()), report
+// slow commands (see: SetSlowCommandThreshold()) and collect the command totals (see: SetCommandStats())
+// The connection is returned as-is if all are off
+// The pool wait is the time it took to get the connection from the pool
+func (c *Client) observeConn(conn redis.Conn, poolWait time.Duration) redis.Conn {
+	c.mu.RLock()
+	audit, slowLog, stats := c.audit, c.slowLog, c.stats
+	c.mu.RUnlock()
+	if audit == nil && slowLog == nil && stats == nil {
+		return conn
+	}
+	return &observedConn{
+		Conn:     conn,
+		audit:    audit,
+		clock:    c.Clock(),
+		poolWait: poolWait,
+		slowLog:  slowLog,
+		stats:    stats,
+	}
+}
+
+// observedConn records each command in the audit trail, reports slow commands and collects the totals
+type observedConn struct {
+	redis.Conn
+	audit    *commandAudit
+	clock    Clock
+	poolWait time.Duration // Reported with the first command on the connection
+	slowLog  *slowLog
+	stats    *commandStats
+}
+
+// unwrap returns the wrapped connection
+func (c *observedConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs and observes the command
+func (c *observedConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	start := c.clock.Now()
+	reply, err := c.Conn.Do(commandName, args...)
+	c.observe(start, c.clock.Now().Sub(start), commandName, args, err)
+	c.stats.addReply(commandName, reply, err)
+	return reply, err
+}
+
+// Send sends and observes the command (the duration of a pipelined command is unknown)
+func (c *observedConn) Send(commandName string, args ...interface{}) error {
+	start := c.clock.Now()
+	err := c.Conn.Send(commandName, args...)
+	c.observe(start, 0, commandName, args, err)
+	return err
+}
+
+// DoWithTimeout runs and observes the command with the timeout
+func (c *observedConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	start := c.clock.Now()
+	reply, err := redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+	c.observe(start, c.clock.Now().Sub(start), commandName, args, err)
+	c.stats.addReply(commandName, reply, err)
+	return reply, err
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *observedConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// observe records the command, reports it if it was slow and adds it to the totals
+func (c *observedConn) observe(start time.Time, duration time.Duration, commandName string,
+	args []interface{}, err error) {
+	if len(commandName) == 0 {
+		return // Flush of pipelined commands
+	}
+	if c.audit != nil {
+		c.audit.add(newCommandRecord(start, duration, commandName, args, err))
+	}
+	if c.stats != nil {
+		c.stats.add(commandName, duration, err)
+	}
+	poolWait := c.poolWait
+	c.poolWait = 0
+	if c.slowLog != nil && duration+poolWait >= c.slowLog.threshold {
+		c.slowLog.handler(SlowCommand{
+			Command:  commandName,
+			Duration: duration,
+			Key:      commandKey(commandName, args),
+			PoolWait: poolWait,
+		})
+	}
+}
@@ -3,11 +3,13 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/url"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,20 +17,77 @@ import (
 	"github.com/mrz1836/go-cache/nrredis"
 )
 
+// ErrPoolExhausted is returned when there are no connections available in the pool
+// (the same error as redis.ErrPoolExhausted, so both can be matched with errors.Is())
+var ErrPoolExhausted = redis.ErrPoolExhausted
+
 // Client is used to store the redis.Pool and additional fields/information
 type Client struct {
 	DependencyScriptSha string // Stored SHA of the script after loaded
 	// Pool                *redis.Pool // Redis pool for the client (get connections)
-	Pool          nrredis.Pool // Redis pool for the client (get connections)
-	ScriptsLoaded []string     // List of scripts that have been loaded
+	Pool          nrredis.Pool   // Redis pool for the client (get connections)
+	Replicas      []nrredis.Pool // Redis pools for read replicas (optional)
+	ScriptsLoaded []string       // List of scripts that have been loaded
+
+	mu                 sync.RWMutex        // Guards the optional client features below
+	adaptiveTimeout    *adaptiveTimeout    // Timeouts from the observed latency (see: SetAdaptiveTimeout())
+	async              *asyncWriter        // Async writer for SetAsync() (if started)
+	audit              *commandAudit       // Audit trail of the last commands (see: SetCommandAudit())
+	bypass             uint32              // Set by SetBypass() (reads miss and writes are skipped)
+	capabilities       *ServerCapabilities // Detected server features (see: Capabilities())
+	classPools         poolClasses         // Pools of the workload classes (see: AddPoolClass())
+	clock              Clock               // Source of time for client-side time logic (see: SetClock())
+	connectionName     string              // Name of the dialed connections (see: SetConnectionName())
+	dependencyLimit    *dependencyLimit    // Max members per dependency set (see: SetDependencyLimit())
+	dependencyShards   map[string]int      // Shards of large dependency sets (see: SetDependencyShards())
+	discovery          *discovery          // Endpoint discovery (see: ConnectWithDiscovery())
+	evalScripts        scriptCache         // Scripts run by Eval() by source (caches the SHA)
+	flushProtection    *FlushProtection    // Guard for DestroyCache() (see: SetFlushProtection())
+	hooks              keyHooks            // Key event hooks (see: OnSet(), OnGet())
+	hotKeyErrorHandler HotKeyErrorHandler  // Fired when a hot key refresh fails
+	hotKeys            map[string]*hotKey  // Hot keys refreshed in the background
+	keyStats           *keyStatsRecorder   // Per-key stats (see: SetKeyStats())
+	loaders            *loaderRegistry     // Read-through loaders by key pattern (see: RegisterLoader())
+	loadShedder        *loadShedder        // Concurrency limit of the client (see: SetConcurrencyLimit())
+	maxWait            time.Duration       // Max wait for a connection when the pool is exhausted
+	missingAsEmpty     uint32              // Set by SetMissingAsEmpty() (misses are not redis.ErrNil)
+	newClassPool       poolFactory         // Creates the pools of the workload classes (set by Connect())
+	onReplica          uint32              // Set when a READONLY error was returned (see: IsOnReplica())
+	priorityQueue      *priorityQueue      // Queue for the primary pool (see: SetPriorityQueue())
+	readOnly           uint32              // Set by SetReadOnly() (commands that modify data are rejected)
+	refuseReplicaWrite uint32              // Set by SetRefuseReplicaWrites()
+	replicaIndex       uint64              // Round-robin index for the read replicas
+	scripts            map[string]string   // Sources of the registered scripts by SHA (see: ReloadScripts())
+	shadow             *shadowReader       // Shadow-read verification (if enabled)
+	shutdown           uint32              // Set by Shutdown() (new operations are rejected)
+	sizeGuard          *valueSizeGuard     // Max value size for writes (if set)
+	skipDependencies   uint32              // Set by SetSkipDependencies() (no dependency bookkeeping)
+	slowLog            *slowLog            // Slow command threshold (see: SetSlowCommandThreshold())
+	standby            *standby            // Warm standby checking the primary (see: ConnectWithStandby())
+	stats              *commandStats       // Running totals of the commands (see: SetCommandStats())
+	tombstoneWindow    int64               // Set by SetInvalidationTombstones() (two-phase invalidation)
+	writeThrough       *writeThrough       // Write-through persistence (see: StartWriteThrough())
 }
 
-// Close closes the connection pool
+// Close stops any background workers and closes the connection pool (and any replica and class pools)
 func (c *Client) Close() {
+	c.StopHotKeys()
+	c.StopDiscovery()
+	c.StopStandby()
+	_ = c.StopAsyncWriter(context.Background())
+	_ = c.StopWriteThrough(context.Background())
+	_ = c.SetKeyStats(nil)
+
+	c.closePoolClasses()
+
+	c.mu.Lock()
 	if c.Pool != nil {
 		_ = c.Pool.Close()
 	}
 	c.Pool = nil
+	closePools(c.Replicas)
+	c.Replicas = nil
+	c.mu.Unlock()
 }
 
 // CloseAll closes the connection pool and given connection
@@ -41,16 +100,119 @@ func (c *Client) CloseAll(conn redis.Conn) redis.Conn {
 // The connection must be closed when you're finished
 // Deprecated: use GetConnectionWithContext()
 func (c *Client) GetConnection() redis.Conn {
-	return c.Pool.Get()
+	return c.primaryPool().Get()
 }
 
 // GetConnectionWithContext will return a connection from the pool. (convenience method)
 // The connection must be closed when you're finished
+// Commands use the timeout from the context if set (see: WithCommandTimeout()), or the adaptive
+// timeout (see: SetAdaptiveTimeout())
+// Commands that modify data return ErrReadOnly in read-only mode (see: SetReadOnly())
+// Connections come from the pool of the workload class if set (see: WithPoolClass())
+// Calls wait in the priority queue when the pool is saturated (see: SetPriorityQueue())
+// Background calls return ErrOverloaded past the concurrency limit (see: SetConcurrencyLimit())
+//
+// If a max wait is configured (see: Config.MaxWait) and the pool is exhausted, this waits up to
+// the max wait for a connection to be returned and then returns ErrPoolExhausted
 func (c *Client) GetConnectionWithContext(ctx context.Context) (redis.Conn, error) {
-	if c.Pool != nil {
-		return c.Pool.GetContext(ctx)
+	if err := c.acceptOperation(ctx); err != nil {
+		return nil, err
+	}
+
+	pool, err := c.contextPool(ctx)
+	if err != nil {
+		return nil, err
+	} else if pool == nil {
+		return nil, errors.New("redis pool is nil")
+	}
+
+	c.mu.RLock()
+	maxWait, limit, shards := c.maxWait, c.dependencyLimit, c.dependencyShards
+	adaptive := c.adaptiveTimeout
+	c.mu.RUnlock()
+
+	start := c.Clock().Now()
+	release, err := c.admit(ctx, maxWait, len(poolClass(ctx)) == 0)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := getPoolConnection(ctx, pool, maxWait)
+	if err != nil {
+		if release != nil {
+			release()
+		}
+		return conn, err
+	}
+	if release != nil {
+		conn = &releaseConn{Conn: conn, release: release}
+	}
+	if timeout := commandTimeout(ctx); timeout > 0 {
+		conn = &timeoutConn{Conn: conn, timeout: timeout}
+	} else if adaptive != nil {
+		conn = &adaptiveConn{Conn: conn, adaptive: adaptive, clock: c.Clock()}
+	}
+	if c.IsReadOnly() {
+		conn = &readOnlyConn{Conn: conn}
+	}
+	if c.RefusesReplicaWrites() {
+		conn = &replicaGuardConn{Conn: conn, client: c}
+	}
+	conn = c.observeConn(conn, c.Clock().Now().Sub(start))
+	if limit != nil || len(shards) > 0 {
+		conn = &dependencyConn{Conn: conn, limit: limit, shards: shards}
 	}
-	return nil, errors.New("redis pool is nil")
+	if c.skipsDependencies(ctx) {
+		conn = &skipDependenciesConn{Conn: conn}
+	}
+	if marker := stickyPrimaryMarker(ctx); marker != nil && !marker.isWritten() {
+		conn = &stickyPrimaryConn{Conn: conn, marker: marker}
+	}
+	return conn, nil
+}
+
+// getPoolConnection returns a connection from the pool, waiting up to the max wait (if set)
+// for a connection when the pool is exhausted
+func getPoolConnection(ctx context.Context, pool nrredis.Pool, maxWait time.Duration) (redis.Conn, error) {
+	if maxWait <= 0 {
+		return pool.GetContext(ctx)
+	}
+
+	// Bound the wait for a vacant connection
+	waitCtx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+	conn, err := pool.GetContext(waitCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w: no connection within %s", ErrPoolExhausted, maxWait)
+	}
+	return conn, err
+}
+
+// wrappedConn is a connection of the client wrapping another connection (IE: timeoutConn)
+type wrappedConn interface {
+	unwrap() redis.Conn
+}
+
+// findConn returns the connection of the type in the chain of wrapped connections, the settings
+// carried by a wrapper are found whatever wraps it
+func findConn[T redis.Conn](conn redis.Conn) (found T, ok bool) {
+	for conn != nil {
+		if found, ok = conn.(T); ok {
+			return
+		}
+		wrapped, isWrapped := conn.(wrappedConn)
+		if !isWrapped {
+			return
+		}
+		conn = wrapped.unwrap()
+	}
+	return
+}
+
+// primaryPool returns the current primary pool (replaced when discovered endpoints change)
+func (c *Client) primaryPool() nrredis.Pool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Pool
 }
 
 // CloseConnection will close a previously open connection
@@ -73,20 +235,76 @@ func Connect(ctx context.Context, redisURL string,
 	maxActiveConnections, idleConnections int,
 	maxConnLifetime, idleTimeout time.Duration,
 	dependencyMode, newRelicEnabled bool, options ...redis.DialOption) (client *Client, err error) {
+	return connect(ctx, Config{
+		DependencyMode:        dependencyMode,
+		IdleTimeout:           idleTimeout,
+		MaxActiveConnections:  maxActiveConnections,
+		MaxConnectionLifetime: maxConnLifetime,
+		MaxIdleConnections:    idleConnections,
+		NewRelicEnabled:       newRelicEnabled,
+		URL:                   redisURL,
+	}, options...)
+}
+
+// connect creates a new connection pool using the configuration
+func connect(ctx context.Context, config Config, options ...redis.DialOption) (client *Client, err error) {
 
 	// Required param for dial
-	if len(redisURL) == 0 {
+	if len(config.URL) == 0 {
 		err = errors.New("missing required parameter: redisURL")
 		return
 	}
 
+	// Create the pool (connections are named when dialed, see: SetConnectionName(), WithClientName())
+	if name, rest := splitClientName(options); len(name) > 0 {
+		config.ClientName, options = name, rest
+	}
+	client = &Client{
+		ScriptsLoaded: nil,
+		maxWait:       config.MaxWait,
+	}
+	if err = client.SetConnectionName(config.ClientName); err != nil {
+		return nil, err
+	}
+	config.onDial = client.nameConnection
+	if client.Pool, err = newPool(config, options...); err != nil {
+		return nil, err
+	}
+	client.newClassPool = func(maxActive, maxIdle int) (nrredis.Pool, error) {
+		classConfig := config
+		classConfig.MaxActiveConnections, classConfig.MaxIdleConnections = maxActive, maxIdle
+		return newPool(classConfig, options...)
+	}
+
+	// Cleanup
+	cleanUp(client.Pool)
+
+	// Register scripts if enabled
+	if config.DependencyMode {
+		if err = client.RegisterScripts(ctx); err != nil {
+			return
+		}
+	}
+
+	// Detect the optional server features (best-effort, detected again on first use if this fails)
+	_, _ = client.DetectCapabilities(ctx)
+
+	return
+}
+
+// newPool creates a new connection pool using the configuration
+// The pool is wrapped with NewRelic support if enabled
+// Connections are named by the dial hook, not by the options of WithClientName()
+func newPool(config Config, options ...redis.DialOption) (nrredis.Pool, error) {
+	_, options = splitClientName(options)
+
 	// Create the pool
-	redisPool := redis.Pool{
-		Dial:            buildDialer(redisURL, options...),
-		IdleTimeout:     idleTimeout,
-		MaxActive:       maxActiveConnections,
-		MaxConnLifetime: maxConnLifetime,
-		MaxIdle:         idleConnections,
+	redisPool := &redis.Pool{
+		Dial:            buildDialer(config.URL, config.onDial, options...),
+		IdleTimeout:     config.IdleTimeout,
+		MaxActive:       config.MaxActiveConnections,
+		MaxConnLifetime: config.MaxConnectionLifetime,
+		MaxIdle:         config.MaxIdleConnections,
 		TestOnBorrow: func(c redis.Conn, t time.Time) error {
 			if time.Since(t) < time.Minute {
 				return nil
@@ -94,40 +312,23 @@ func Connect(ctx context.Context, redisURL string,
 			_, doErr := c.Do(PingCommand)
 			return doErr
 		},
+		Wait: config.MaxWait > 0,
 	}
 
 	// Wrap if NewRelic is enabled
-	if newRelicEnabled {
-		var host, database, port string
-		if host, database, port, err = extractURL(redisURL); err != nil {
-			return
-		}
-
-		client = &Client{
-			Pool: nrredis.Wrap(
-				&redisPool,
-				nrredis.WithDBName(database),
-				nrredis.WithHost(host),
-				nrredis.WithPortPathOrID(port),
-			),
-			ScriptsLoaded: nil,
-		}
-	} else {
-		client = &Client{
-			Pool:          &redisPool,
-			ScriptsLoaded: nil,
-		}
+	if !config.NewRelicEnabled {
+		return redisPool, nil
 	}
-
-	// Cleanup
-	cleanUp(client.Pool)
-
-	// Register scripts if enabled
-	if dependencyMode {
-		err = client.RegisterScripts(ctx)
+	host, database, port, err := extractURL(config.URL)
+	if err != nil {
+		return nil, err
 	}
-
-	return
+	return nrredis.Wrap(
+		redisPool,
+		nrredis.WithDBName(database),
+		nrredis.WithHost(host),
+		nrredis.WithPortPathOrID(port),
+	), nil
 }
 
 // ConnectToURL connects via REDIS_URL and returns a single connection
@@ -168,9 +369,17 @@ func ConnectToURL(connectToURL string, options ...redis.DialOption) (conn redis.
 }
 
 // buildDialer will build a redis connection from URL
-func buildDialer(url string, options ...redis.DialOption) func() (redis.Conn, error) {
+func buildDialer(url string, onDial dialHook, options ...redis.DialOption) func() (redis.Conn, error) {
 	return func() (redis.Conn, error) {
-		return ConnectToURL(url, options...)
+		conn, err := ConnectToURL(url, options...)
+		if err != nil || onDial == nil {
+			return conn, err
+		}
+		if err = onDial(conn); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		return conn, nil
 	}
 }
 
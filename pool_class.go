@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mrz1836/go-cache/nrredis"
+)
+
+// ErrUnknownPoolClass is returned for a context routed to a pool class that was not added
+// (see: WithPoolClass(), AddPoolClass())
+var ErrUnknownPoolClass = errors.New("unknown pool class")
+
+// poolClassKey is the context key for the pool class
+type poolClassKey struct{}
+
+// poolClasses are the pools of the workload classes by name
+type poolClasses map[string]nrredis.Pool
+
+// poolFactory creates a pool to the server of the client with the connection limits
+type poolFactory func(maxActive, maxIdle int) (nrredis.Pool, error)
+
+// WithPoolClass returns a context that routes the commands to the pool of the workload class
+// (IE: "bulk" for a warm-up job), so the class cannot exhaust the connections of the other calls
+// Commands return ErrUnknownPoolClass if the class was not added (see: AddPoolClass())
+func WithPoolClass(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, poolClassKey{}, class)
+}
+
+// poolClass returns the pool class from the context (empty if not set)
+func poolClass(ctx context.Context) string {
+	class, _ := ctx.Value(poolClassKey{}).(string)
+	return class
+}
+
+// AddPoolClass creates a pool for the workload class with its own connection limits, used by
+// the calls with the class in the context (see: WithPoolClass()), adding a class again replaces its pool
+// The pool connects like the primary pool (same url, timeouts and dial options), only clients created
+// by Connect() (or ConnectWithConfig()) can add classes, and class pools are not moved by endpoint discovery
+func (c *Client) AddPoolClass(class string, maxActiveConnections, idleConnections int) error {
+	if len(class) == 0 {
+		return errors.New("missing required parameter: class")
+	}
+
+	c.mu.RLock()
+	factory := c.newClassPool
+	c.mu.RUnlock()
+	if factory == nil {
+		return errors.New("pool classes require a client created by Connect()")
+	}
+
+	pool, err := factory(maxActiveConnections, idleConnections)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.classPools == nil {
+		c.classPools = make(poolClasses)
+	}
+	previous := c.classPools[class]
+	c.classPools[class] = pool
+	c.mu.Unlock()
+
+	if previous != nil {
+		_ = previous.Close()
+	}
+	return nil
+}
+
+// RemovePoolClass closes the pool of the workload class (connections in use are closed when returned)
+func (c *Client) RemovePoolClass(class string) {
+	c.mu.Lock()
+	pool := c.classPools[class]
+	delete(c.classPools, class)
+	c.mu.Unlock()
+
+	if pool != nil {
+		_ = pool.Close()
+	}
+}
+
+// PoolClasses returns the names of the workload classes (sorted)
+func (c *Client) PoolClasses() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	classes := make([]string, 0, len(c.classPools))
+	for class := range c.classPools {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	return classes
+}
+
+// contextPool returns the pool for the context: the pool of the workload class if set, or the primary pool
+func (c *Client) contextPool(ctx context.Context) (nrredis.Pool, error) {
+	class := poolClass(ctx)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(class) == 0 {
+		return c.Pool, nil
+	}
+	pool, ok := c.classPools[class]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPoolClass, class)
+	}
+	return pool, nil
+}
+
+// closePoolClasses closes the pools of all the workload classes
+func (c *Client) closePoolClasses() {
+	c.mu.Lock()
+	classes := c.classPools
+	c.classPools = nil
+	c.mu.Unlock()
+
+	for _, pool := range classes {
+		_ = pool.Close()
+	}
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadMockPoolClasses sets a pool factory on the mocked client, each class pool uses the mocked connection
+func loadMockPoolClasses(client *Client, conn *redigomock.Conn) {
+	client.newClassPool = func(maxActive, maxIdle int) (nrredis.Pool, error) {
+		return &redis.Pool{
+			Dial:      func() (redis.Conn, error) { return conn, nil },
+			MaxActive: maxActive,
+			MaxIdle:   maxIdle,
+		}, nil
+	}
+}
+
+// TestClient_AddPoolClass tests the method AddPoolClass()
+func TestClient_AddPoolClass(t *testing.T) {
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Error(t, client.AddPoolClass("", 1, 1))
+
+		// Not created by Connect()
+		assert.Error(t, client.AddPoolClass("bulk", 1, 1))
+	})
+
+	t.Run("route by class using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		bulkConn := redigomock.NewConn()
+		loadMockPoolClasses(client, bulkConn)
+
+		assert.NoError(t, client.AddPoolClass("bulk", 1, 1))
+		assert.NoError(t, client.AddPoolClass("latency-critical", 10, 5))
+		assert.Equal(t, []string{"bulk", "latency-critical"}, client.PoolClasses())
+
+		primaryCmd := conn.Command(GetCommand, testKey).Expect("primary")
+		bulkCmd := bulkConn.Command(GetCommand, testKey).Expect("bulk")
+
+		val, err := Get(WithPoolClass(context.Background(), "bulk"), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "bulk", val)
+
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", val)
+		assert.Equal(t, 1, conn.Stats(primaryCmd))
+		assert.Equal(t, 1, bulkConn.Stats(bulkCmd))
+
+		// Unknown classes are rejected
+		_, err = Get(WithPoolClass(context.Background(), "missing"), client, testKey)
+		assert.ErrorIs(t, err, ErrUnknownPoolClass)
+
+		client.RemovePoolClass("latency-critical")
+		assert.Equal(t, []string{"bulk"}, client.PoolClasses())
+	})
+
+	t.Run("class limits using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		loadMockPoolClasses(client, redigomock.NewConn())
+		assert.NoError(t, client.AddPoolClass("bulk", 1, 1))
+
+		// The bulk class is exhausted, other calls still get connections
+		ctx := WithPoolClass(context.Background(), "bulk")
+		bulk, err := client.GetConnectionWithContext(ctx)
+		assert.NoError(t, err)
+		defer client.CloseConnection(bulk)
+
+		_, err = client.GetConnectionWithContext(ctx)
+		assert.ErrorIs(t, err, redis.ErrPoolExhausted)
+
+		var primary redis.Conn
+		primary, err = client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		client.CloseConnection(primary)
+	})
+
+	t.Run("class pool using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.AddPoolClass("bulk", 2, 1)
+		assert.NoError(t, err)
+
+		ctx := WithPoolClass(context.Background(), "bulk")
+		err = Set(ctx, client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var val string
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+
+		client.Close()
+		assert.Equal(t, 0, len(client.PoolClasses()))
+	})
+}
+
+// ExampleWithPoolClass is an example of the method WithPoolClass()
+func ExampleWithPoolClass() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Add a pool for the bulk jobs (clients created by Connect() have a pool factory)
+	loadMockPoolClasses(client, conn)
+	_ = client.AddPoolClass("bulk", 2, 1)
+
+	// Mock the write
+	conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+
+	err := Set(WithPoolClass(context.Background(), "bulk"), client, testKey, testStringValue)
+	fmt.Printf("written with the bulk pool: %t", err == nil)
+	// Output:written with the bulk pool: true
+}
@@ -294,6 +294,52 @@ func TestClient_GetConnectionWithContext(t *testing.T) {
 		client.Close()
 		assert.Nil(t, client.Pool)
 	})
+
+	t.Run("exhausted pool waits up to the max wait", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, err := ConnectWithConfig(context.Background(), Config{
+			MaxActiveConnections: 1,
+			MaxIdleConnections:   1,
+			MaxWait:              50 * time.Millisecond,
+			URL:                  testLocalConnectionURL,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		defer client.Close()
+
+		var conn redis.Conn
+		conn, err = client.GetConnectionWithContext(context.Background())
+		assert.NotNil(t, conn)
+		assert.NoError(t, err)
+
+		// No connections are left
+		start := time.Now()
+		_, err = client.GetConnectionWithContext(context.Background())
+		assert.ErrorIs(t, err, ErrPoolExhausted)
+		assert.ErrorIs(t, err, redis.ErrPoolExhausted)
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+		// Canceled contexts are not reported as exhausted
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = client.GetConnectionWithContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NotErrorIs(t, err, ErrPoolExhausted)
+
+		// A connection returned while waiting is used
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			client.CloseConnection(conn)
+		}()
+		var next redis.Conn
+		next, err = client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, next)
+		client.CloseConnection(next)
+	})
 }
 
 // ExampleClient_GetConnectionWithContext is an example of the method GetConnectionWithContext()
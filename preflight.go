@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Preflight checks (see: Preflight())
+const (
+	PreflightAuth    = "auth"    // Authentication (AUTH on dial)
+	PreflightClock   = "clock"   // Clock skew between the client and the server (TIME)
+	PreflightConnect = "connect" // Connectivity (PING)
+	PreflightRESP    = "resp"    // Protocol version
+	PreflightScripts = "scripts" // Registered scripts are loaded (SCRIPT EXISTS)
+	PreflightSelect  = "select"  // Database selection (SELECT on dial)
+	PreflightWrite   = "write"   // Write permission (canary key)
+)
+
+// Preflight defaults
+const (
+	defaultPreflightCanaryKey    = "go-cache:preflight"
+	defaultPreflightCanaryTTL    = 10 * time.Second
+	defaultPreflightMaxClockSkew = time.Second
+)
+
+// ErrPreflightSkipped is the error of the checks that did not run because an earlier check failed
+var ErrPreflightSkipped = errors.New("skipped, an earlier check failed")
+
+// PreflightOptions are the options for Preflight()
+type PreflightOptions struct {
+	CanaryKey    string        // Key written by the write check (default: go-cache:preflight)
+	MaxClockSkew time.Duration // Max clock skew between the client and the server (default: 1 second)
+	SkipWrite    bool          // Skip the write check (IE: read-only services)
+}
+
+// PreflightCheck is the result of a single preflight check
+type PreflightCheck struct {
+	Detail   string        // Details of the check (IE: the measured clock skew)
+	Duration time.Duration // How long the check took
+	Err      error         // Reason the check failed (nil if it passed)
+	Name     string        // Name of the check (IE: PreflightConnect)
+}
+
+// PreflightReport is the result of all the preflight checks (in order)
+type PreflightReport struct {
+	Checks []PreflightCheck
+}
+
+// Err returns the first failed check as an error (nil if all checks passed)
+func (r *PreflightReport) Err() error {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return fmt.Errorf("preflight %s check failed: %w", check.Name, check.Err)
+		}
+	}
+	return nil
+}
+
+// add runs the check and adds the result to the report
+func (r *PreflightReport) add(clock Clock, name string, check func() (string, error)) bool {
+	start := clock.Now()
+	detail, err := check()
+	r.Checks = append(r.Checks, PreflightCheck{
+		Detail:   detail,
+		Duration: clock.Now().Sub(start),
+		Err:      err,
+		Name:     name,
+	})
+	return err == nil
+}
+
+// skip adds the checks as skipped
+func (r *PreflightReport) skip(names ...string) {
+	for _, name := range names {
+		r.Checks = append(r.Checks, PreflightCheck{Err: ErrPreflightSkipped, Name: name})
+	}
+}
+
+// Preflight verifies the client is ready to serve (IE: at boot, to fail fast with a precise reason)
+// Checks connectivity, AUTH, SELECT, the registered scripts, the protocol version, the clock skew
+// and write permission (with a canary key that expires), see: PreflightReport.Err()
+//
+// Commands used:
+// https://redis.io/commands/ping
+// https://redis.io/commands/script-exists
+// https://redis.io/commands/time
+// https://redis.io/commands/set
+// https://redis.io/commands/del
+func (c *Client) Preflight(ctx context.Context, options PreflightOptions) *PreflightReport {
+	if len(options.CanaryKey) == 0 {
+		options.CanaryKey = defaultPreflightCanaryKey
+	}
+	if options.MaxClockSkew <= 0 {
+		options.MaxClockSkew = defaultPreflightMaxClockSkew
+	}
+	report := new(PreflightReport)
+	clock := c.Clock()
+
+	// Connectivity, AUTH and SELECT (run when the connection is dialed)
+	var conn redis.Conn
+	var dialErr error
+	report.add(clock, PreflightConnect, func() (string, error) {
+		if conn, dialErr = c.GetConnectionWithContext(ctx); dialErr == nil {
+			_, dialErr = conn.Do(PingCommand)
+		}
+		if dialErr == nil || preflightStep(dialErr) != PreflightConnect {
+			return "server is reachable", nil
+		}
+		return "", dialErr
+	})
+	if conn != nil {
+		defer c.CloseConnection(conn)
+	}
+	if report.Checks[0].Err != nil {
+		report.skip(PreflightAuth, PreflightSelect, PreflightScripts, PreflightRESP, PreflightClock, PreflightWrite)
+		return report
+	}
+	for _, name := range []string{PreflightAuth, PreflightSelect} {
+		report.add(clock, name, func() (string, error) {
+			if dialErr != nil && preflightStep(dialErr) == name {
+				return "", dialErr
+			}
+			return "", nil
+		})
+	}
+	if dialErr != nil {
+		report.skip(PreflightScripts, PreflightRESP, PreflightClock, PreflightWrite)
+		return report
+	}
+
+	// Registered scripts
+	report.add(clock, PreflightScripts, func() (string, error) {
+		return preflightScripts(conn, c.ScriptsLoaded)
+	})
+
+	// Protocol version
+	report.add(clock, PreflightRESP, func() (string, error) {
+		capabilities, err := c.Capabilities(ctx)
+		if err != nil {
+			return "", err
+		} else if capabilities.RESP3 {
+			return "RESP2 (server supports RESP3)", nil
+		}
+		return "RESP2", nil
+	})
+
+	// Clock skew
+	report.add(clock, PreflightClock, func() (string, error) {
+		skew, err := skewCheck(conn, clock, options.MaxClockSkew)
+		detail := "skew: " + skew.String()
+		if errors.Is(err, ErrClockSkew) {
+			return detail, fmt.Errorf("clock skew of %s exceeds %s", skew, options.MaxClockSkew)
+		} else if err != nil {
+			return "", err
+		}
+		return detail, nil
+	})
+
+	// Write permission
+	if options.SkipWrite {
+		return report
+	}
+	report.add(clock, PreflightWrite, func() (string, error) {
+		if _, err := conn.Do(SetCommand, options.CanaryKey, clock.Now().UnixNano(),
+			ExpireMillisArgument, defaultPreflightCanaryTTL.Milliseconds()); err != nil {
+			return "", err
+		}
+		_, err := conn.Do(DeleteCommand, options.CanaryKey)
+		return options.CanaryKey, err
+	})
+	return report
+}
+
+// preflightStep returns the check a connection error belongs to (AUTH and SELECT run on dial)
+func preflightStep(err error) string {
+	var serverErr redis.Error
+	if !errors.As(err, &serverErr) {
+		return PreflightConnect
+	}
+	message := strings.ToUpper(serverErr.Error())
+	switch {
+	case strings.HasPrefix(message, "NOAUTH"), strings.HasPrefix(message, "WRONGPASS"),
+		strings.Contains(message, "PASSWORD"), strings.Contains(message, "AUTH"):
+		return PreflightAuth
+	case strings.Contains(message, "DB INDEX"):
+		return PreflightSelect
+	}
+	return PreflightConnect
+}
+
+// preflightScripts checks the registered scripts are loaded on the server
+func preflightScripts(conn redis.Conn, scripts []string) (string, error) {
+	if len(scripts) == 0 {
+		return "no scripts registered", nil
+	}
+	loaded, err := ScriptsLoadedRaw(conn, scripts...)
+	if err != nil {
+		return "", err
+	}
+	var missing []string
+	for _, sha := range scripts {
+		if !loaded[sha] {
+			missing = append(missing, sha)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("scripts are not loaded: %s", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("%d scripts loaded", len(scripts)), nil
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Preflight tests the method Preflight()
+func TestClient_Preflight(t *testing.T) {
+
+	t.Run("all checks pass using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		now := time.Now()
+		client.SetClock(NewManualClock(now))
+		client.ScriptsLoaded = []string{killByDependencySha}
+		client.capabilities = &ServerCapabilities{RESP3: true}
+
+		conn.Command(PingCommand).Expect("PONG")
+		conn.Command(ScriptCommand, ExistsArgument, killByDependencySha).Expect([]interface{}{int64(1)})
+		conn.Command(TimeCommand).Expect([]interface{}{
+			[]byte(fmt.Sprint(now.Unix())), []byte(fmt.Sprint(now.Nanosecond() / 1000)),
+		})
+		setCmd := conn.GenericCommand(SetCommand).Expect("OK")
+		conn.Command(DeleteCommand, defaultPreflightCanaryKey).Expect(int64(1))
+
+		report := client.Preflight(context.Background(), PreflightOptions{})
+		assert.NoError(t, report.Err())
+		assert.True(t, setCmd.Called)
+
+		var names []string
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+		}
+		assert.Equal(t, []string{
+			PreflightConnect, PreflightAuth, PreflightSelect, PreflightScripts,
+			PreflightRESP, PreflightClock, PreflightWrite,
+		}, names)
+		assert.Equal(t, "1 scripts loaded", report.Checks[3].Detail)
+		assert.Equal(t, "RESP2 (server supports RESP3)", report.Checks[4].Detail)
+	})
+
+	t.Run("failed checks using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		client.SetClock(NewManualClock(time.Now()))
+		client.ScriptsLoaded = []string{killByDependencySha}
+		client.capabilities = &ServerCapabilities{}
+
+		conn.Command(PingCommand).Expect("PONG")
+		conn.Command(ScriptCommand, ExistsArgument, killByDependencySha).Expect([]interface{}{int64(0)})
+		conn.Command(TimeCommand).Expect([]interface{}{[]byte("1000"), []byte("0")})
+
+		report := client.Preflight(context.Background(), PreflightOptions{SkipWrite: true})
+		assert.Len(t, report.Checks, 6)
+		assert.ErrorContains(t, report.Checks[3].Err, killByDependencySha)
+		assert.Error(t, report.Checks[5].Err)
+		assert.ErrorContains(t, report.Err(), "preflight scripts check failed")
+	})
+
+	t.Run("authentication failure using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(PingCommand).ExpectError(redis.Error("NOAUTH Authentication required."))
+
+		report := client.Preflight(context.Background(), PreflightOptions{})
+		assert.NoError(t, report.Checks[0].Err)
+		assert.Equal(t, PreflightAuth, report.Checks[1].Name)
+		assert.Error(t, report.Checks[1].Err)
+		assert.NoError(t, report.Checks[2].Err)
+		assert.ErrorIs(t, report.Checks[3].Err, ErrPreflightSkipped)
+		assert.ErrorContains(t, report.Err(), "preflight auth check failed")
+	})
+
+	t.Run("connection failure using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(PingCommand).ExpectError(errors.New("connection refused"))
+
+		report := client.Preflight(context.Background(), PreflightOptions{})
+		assert.Len(t, report.Checks, 7)
+		assert.EqualError(t, report.Checks[0].Err, "connection refused")
+		assert.ErrorIs(t, report.Checks[6].Err, ErrPreflightSkipped)
+	})
+
+	t.Run("preflight using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		report := client.Preflight(context.Background(), PreflightOptions{MaxClockSkew: time.Minute})
+		assert.NoError(t, report.Err())
+
+		var found bool
+		found, err = ExistsRaw(conn, defaultPreflightCanaryKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleClient_Preflight is an example of the method Preflight()
+func ExampleClient_Preflight() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the server (authentication fails)
+	conn.Command(PingCommand).ExpectError(redis.Error("WRONGPASS invalid username-password pair"))
+
+	// Fail fast at boot
+	report := client.Preflight(context.Background(), PreflightOptions{})
+	fmt.Println(report.Err())
+	// Output:preflight auth check failed: WRONGPASS invalid username-password pair
+}
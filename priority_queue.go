@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Priority is the priority of the commands of a context when the pool is saturated (see: SetPriorityQueue())
+type Priority int
+
+// Priorities (request-path commands get connections before background work)
+const (
+	PriorityRequest    Priority = iota // Request-path commands (default)
+	PriorityBackground                 // Background work (IE: warm-ups, hot key refreshes, async writes)
+)
+
+// priorityKey is the context key for the priority
+type priorityKey struct{}
+
+// QueueStats are the stats of the priority queue (see: SetPriorityQueue())
+type QueueStats struct {
+	BackgroundDepth  int           `json:"background_depth"`  // Background calls waiting for a connection
+	BackgroundQueued uint64        `json:"background_queued"` // Background calls that had to wait
+	Limit            int           `json:"limit"`             // Max connections in use (0 is disabled)
+	RequestDepth     int           `json:"request_depth"`     // Request-path calls waiting for a connection
+	RequestQueued    uint64        `json:"request_queued"`    // Request-path calls that had to wait
+	WaitDuration     time.Duration `json:"wait_duration"`     // Total time waited in the queue
+}
+
+// WithPriority returns a context whose commands get connections with the priority when the
+// pool is saturated (see: SetPriorityQueue())
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priority returns the priority of the context (background workers default to PriorityBackground)
+func priority(ctx context.Context) Priority {
+	if value, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		if value > PriorityBackground {
+			return PriorityBackground
+		} else if value < PriorityRequest {
+			return PriorityRequest
+		}
+		return value
+	}
+	if background, _ := ctx.Value(backgroundKey{}).(bool); background {
+		return PriorityBackground
+	}
+	return PriorityRequest
+}
+
+// backgroundPriority returns a context with PriorityBackground unless a priority is set
+func backgroundPriority(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, PriorityBackground)
+}
+
+// priorityQueue hands out the connection slots of the pool, request-path calls first
+type priorityQueue struct {
+	active  int
+	clock   Clock
+	limit   int
+	mu      sync.Mutex
+	queued  [PriorityBackground + 1]uint64
+	wait    int64
+	waiters [PriorityBackground + 1][]chan struct{}
+}
+
+// SetPriorityQueue queues the calls for a connection from the primary pool once limit connections
+// are in use (set to the max active connections of the pool), 0 removes the queue
+//
+// Queued calls get the released connections by priority (see: WithPriority()), so background work
+// degrades before user traffic does. Calls using a pool class are not queued (see: WithPoolClass())
+// and the queue depth is reported by Stats()
+func (c *Client) SetPriorityQueue(limit int) error {
+	var queue *priorityQueue
+	if limit < 0 {
+		return errors.New("limit must be zero or more")
+	} else if limit > 0 {
+		queue = &priorityQueue{clock: c.Clock(), limit: limit}
+	}
+	c.mu.Lock()
+	c.priorityQueue = queue
+	c.mu.Unlock()
+	return nil
+}
+
+// acquire waits for a connection slot (up to the max wait if set), the release must be called
+// once the connection is closed
+func (q *priorityQueue) acquire(ctx context.Context, level Priority,
+	maxWait time.Duration) (func(), error) {
+	q.mu.Lock()
+	if q.active < q.limit && q.depth() == 0 {
+		q.active++
+		q.mu.Unlock()
+		return q.releaser(), nil
+	}
+	ready := make(chan struct{})
+	q.waiters[level] = append(q.waiters[level], ready)
+	q.queued[level]++
+	q.mu.Unlock()
+
+	start := q.clock.Now()
+	defer func() {
+		atomic.AddInt64(&q.wait, int64(q.clock.Now().Sub(start)))
+	}()
+
+	waitCtx := ctx
+	if maxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, maxWait)
+		defer cancel()
+	}
+	select {
+	case <-ready:
+		return q.releaser(), nil
+	case <-waitCtx.Done():
+	}
+
+	// The slot may have been handed over while giving up
+	if !q.leave(level, ready) {
+		q.releaser()()
+	}
+	if ctx.Err() == nil {
+		return nil, fmt.Errorf("%w: no connection within %s", ErrPoolExhausted, maxWait)
+	}
+	return nil, ctx.Err()
+}
+
+// leave removes the waiter from the queue, false if it already got a slot
+func (q *priorityQueue) leave(level Priority, ready chan struct{}) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiter := range q.waiters[level] {
+		if waiter == ready {
+			q.waiters[level] = append(q.waiters[level][:i], q.waiters[level][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// releaser returns the release of a slot (safe to call more than once)
+func (q *priorityQueue) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(q.release)
+	}
+}
+
+// release hands the slot to the first waiter with the highest priority
+func (q *priorityQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for level := range q.waiters {
+		if len(q.waiters[level]) > 0 {
+			ready := q.waiters[level][0]
+			q.waiters[level] = q.waiters[level][1:]
+			close(ready)
+			return
+		}
+	}
+	q.active--
+}
+
+// depth returns the number of waiters (the lock must be held)
+func (q *priorityQueue) depth() (count int) {
+	for _, waiters := range q.waiters {
+		count += len(waiters)
+	}
+	return
+}
+
+// stats returns the stats of the queue
+func (q *priorityQueue) stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return QueueStats{
+		BackgroundDepth:  len(q.waiters[PriorityBackground]),
+		BackgroundQueued: q.queued[PriorityBackground],
+		Limit:            q.limit,
+		RequestDepth:     len(q.waiters[PriorityRequest]),
+		RequestQueued:    q.queued[PriorityRequest],
+		WaitDuration:     time.Duration(atomic.LoadInt64(&q.wait)),
+	}
+}
+
+// releaseConn releases the slot of the priority queue (and the load shedding count) when closed
+type releaseConn struct {
+	redis.Conn
+	release func()
+}
+
+// unwrap returns the wrapped connection
+func (c *releaseConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Close closes the connection and releases the slot
+func (c *releaseConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
+
+// DoWithTimeout runs the command with the timeout
+func (c *releaseConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *releaseConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForQueueDepth waits until the priority queue has the waiters
+func waitForQueueDepth(t *testing.T, client *Client, requests, background int) {
+	assert.Eventually(t, func() bool {
+		stats := client.Stats().Queue
+		return stats.RequestDepth == requests && stats.BackgroundDepth == background
+	}, time.Second, time.Millisecond)
+}
+
+// TestClient_SetPriorityQueue tests the method SetPriorityQueue()
+func TestClient_SetPriorityQueue(t *testing.T) {
+
+	t.Run("invalid limit", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.Error(t, client.SetPriorityQueue(-1))
+		assert.NoError(t, client.SetPriorityQueue(0))
+		assert.Equal(t, 0, client.Stats().Queue.Limit)
+	})
+
+	t.Run("request-path calls first using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetPriorityQueue(1))
+
+		held, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+
+		// Queue a background call before a request-path call
+		order := make(chan Priority, 2)
+		get := func(ctx context.Context) {
+			queued, getErr := client.GetConnectionWithContext(ctx)
+			assert.NoError(t, getErr)
+			order <- priority(ctx)
+			client.CloseConnection(queued)
+		}
+		go get(WithPriority(context.Background(), PriorityBackground))
+		waitForQueueDepth(t, client, 0, 1)
+		go get(context.Background())
+		waitForQueueDepth(t, client, 1, 1)
+
+		client.CloseConnection(held)
+		assert.Equal(t, PriorityRequest, <-order)
+		assert.Equal(t, PriorityBackground, <-order)
+
+		stats := client.Stats().Queue
+		assert.Equal(t, 1, stats.Limit)
+		assert.Equal(t, uint64(1), stats.BackgroundQueued)
+		assert.Equal(t, uint64(1), stats.RequestQueued)
+		assert.Equal(t, 0, stats.BackgroundDepth+stats.RequestDepth)
+	})
+
+	t.Run("max wait using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetPriorityQueue(1))
+		client.maxWait = 20 * time.Millisecond
+
+		held, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+
+		_, err = client.GetConnectionWithContext(context.Background())
+		assert.ErrorIs(t, err, ErrPoolExhausted)
+
+		// Canceled waits leave the queue
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = client.GetConnectionWithContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		waitForQueueDepth(t, client, 0, 0)
+
+		// The slot is released once
+		client.CloseConnection(held)
+		client.CloseConnection(held)
+		var next redis.Conn
+		next, err = client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		client.CloseConnection(next)
+	})
+
+	t.Run("command timeout using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		assert.NoError(t, client.SetPriorityQueue(1))
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		ctx := WithCommandTimeout(context.Background(), time.Second)
+		val, err := Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+	})
+
+	t.Run("background workers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, PriorityRequest, priority(context.Background()))
+		assert.Equal(t, PriorityBackground, priority(withBackground(context.Background())))
+		assert.Equal(t, PriorityBackground, priority(backgroundPriority(context.Background())))
+		assert.Equal(t, PriorityRequest, priority(backgroundPriority(
+			WithPriority(context.Background(), PriorityRequest),
+		)))
+	})
+}
+
+// ExampleWithPriority is an example of the method WithPriority()
+func ExampleWithPriority() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Queue the calls once 10 connections are in use
+	_ = client.SetPriorityQueue(10)
+
+	// Mock the write
+	conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+
+	// Re-warming gives way to user traffic when the pool is saturated
+	ctx := WithPriority(context.Background(), PriorityBackground)
+	err := Set(ctx, client, testKey, testStringValue)
+	fmt.Printf("written in the background: %t", err == nil)
+	// Output:written in the background: true
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultReadyTimeout bounds the readiness check if the context has no deadline
+const defaultReadyTimeout = time.Second
+
+// Probe errors (see: Live(), Ready())
+var (
+	ErrNotLive  = errors.New("redis client is not live")
+	ErrNotReady = errors.New("redis client is not ready")
+)
+
+// Live returns ErrNotLive if the client has no connection pool (IE: for a Kubernetes liveness probe)
+// No commands are sent, so an unreachable server does not restart the service
+func (c *Client) Live(_ context.Context) error {
+	if c.primaryPool() == nil {
+		return fmt.Errorf("%w: redis pool is nil", ErrNotLive)
+	}
+	return nil
+}
+
+// Ready returns ErrNotReady (wrapping the reason) if the client can not serve commands
+// (IE: for a Kubernetes readiness probe): the client is shut down, PING fails or a registered
+// script is not loaded
+// The check is bounded to 1 second if the context has no deadline
+//
+// Commands used:
+// https://redis.io/commands/ping
+// https://redis.io/commands/script-exists
+func (c *Client) Ready(ctx context.Context) error {
+	if err := c.Live(ctx); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotReady, err.Error())
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultReadyTimeout)
+		defer cancel()
+	}
+
+	conn, err := c.GetConnectionWithContext(WithCommandTimeout(ctx, defaultReadyTimeout))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotReady, err.Error())
+	}
+	defer c.CloseConnection(conn)
+
+	if _, err = conn.Do(PingCommand); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotReady, err.Error())
+	}
+	if _, err = preflightScripts(conn, c.ScriptsLoaded); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotReady, err.Error())
+	}
+	return nil
+}
+
+// LiveHandler returns a probe handler for Live(), responding 200 if live and 503 if not
+func LiveHandler(client *Client) http.Handler {
+	return probeHandler(client.Live)
+}
+
+// ReadyHandler returns a probe handler for Ready(), responding 200 if ready and 503 if not
+func ReadyHandler(client *Client) http.Handler {
+	return probeHandler(client.Ready)
+}
+
+// probeHandler responds with the result of the probe
+func probeHandler(probe func(ctx context.Context) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if err := probe(req.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+}
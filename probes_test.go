@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Ready tests the methods Live() and Ready()
+func TestClient_Ready(t *testing.T) {
+
+	t.Run("ready using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		client.ScriptsLoaded = []string{killByDependencySha}
+		conn.Command(PingCommand).Expect("PONG")
+		conn.Command(ScriptCommand, ExistsArgument, killByDependencySha).Expect([]interface{}{int64(1)})
+
+		assert.NoError(t, client.Live(context.Background()))
+		assert.NoError(t, client.Ready(context.Background()))
+	})
+
+	t.Run("not ready using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		client.ScriptsLoaded = []string{killByDependencySha}
+		conn.Command(PingCommand).Expect("PONG")
+		conn.Command(ScriptCommand, ExistsArgument, killByDependencySha).Expect([]interface{}{int64(0)})
+
+		err := client.Ready(context.Background())
+		assert.ErrorIs(t, err, ErrNotReady)
+		assert.ErrorContains(t, err, killByDependencySha)
+
+		conn.Clear()
+		conn.Command(PingCommand).ExpectError(errors.New("connection refused"))
+		err = client.Ready(context.Background())
+		assert.ErrorIs(t, err, ErrNotReady)
+
+		// Still live
+		assert.NoError(t, client.Live(context.Background()))
+	})
+
+	t.Run("not live without a pool", func(t *testing.T) {
+		t.Parallel()
+
+		client := new(Client)
+		assert.ErrorIs(t, client.Live(context.Background()), ErrNotLive)
+		assert.ErrorIs(t, client.Ready(context.Background()), ErrNotReady)
+	})
+
+	t.Run("probe handlers", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(PingCommand).ExpectError(errors.New("connection refused"))
+
+		recorder := httptest.NewRecorder()
+		LiveHandler(client).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/live", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		ReadyHandler(client).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "connection refused")
+	})
+}
+
+// ExampleClient_Ready is an example of the method Ready()
+func ExampleClient_Ready() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the ping command
+	conn.Command(PingCommand).Expect("PONG")
+
+	// Use in a readiness probe (or mount ReadyHandler())
+	err := client.Ready(context.Background())
+	fmt.Printf("ready: %v", err == nil)
+	// Output:ready: true
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// TablePrefix is the prefix for the table dependencies of cached query results
+const TablePrefix = "table:"
+
+// QueryScanner runs the query and scans the rows into the result
+type QueryScanner[T any] func(ctx context.Context) (T, error)
+
+// TableDependency returns the dependency key for the table (IE: table:users)
+func TableDependency(table string) string {
+	return TablePrefix + table
+}
+
+// CacheQuery returns the cached result of the query, or runs the scan function and caches the result
+// The result is tagged with each table, use InvalidateTable() on writes to the table
+// Errors from the scan function are returned and never cached, the cache is best-effort
+// No connection is held while the scan function runs (the read and the write each use their own)
+//
+// Uses methods: GetBytes() and SetToJSON()
+// Custom connections use method: CacheQueryRaw()
+func CacheQuery[T any](ctx context.Context, client *Client, key string, ttl time.Duration,
+	tables []string, scanFn QueryScanner[T]) (T, error) {
+	if client.IsBypassed() {
+		return scanFn(ctx)
+	}
+
+	// Cached result
+	var result T
+	if data, err := GetBytes(ctx, client, key); err == nil {
+		if err = json.Unmarshal(data, &result); err == nil {
+			return result, nil
+		}
+		result = *new(T)
+	}
+
+	// Run the query
+	result, err := scanFn(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// Tag the result with each table
+	_ = SetToJSON(ctx, client, key, result, ttl, tableDependencies(tables)...)
+	return result, nil
+}
+
+// CacheQueryRaw returns the cached result of the query, or runs the scan function and caches the result
+// The result is tagged with each table, use InvalidateTable() on writes to the table
+// Errors from the scan function are returned and never cached, the cache is best-effort
+// Uses existing connection (does not close connection)
+//
+// Uses methods: GetBytesRaw() and SetToJSONRaw()
+func CacheQueryRaw[T any](ctx context.Context, conn redis.Conn, key string, ttl time.Duration,
+	tables []string, scanFn QueryScanner[T]) (T, error) {
+
+	// Cached result
+	var result T
+	if data, err := GetBytesRaw(conn, key); err == nil {
+		if err = json.Unmarshal(data, &result); err == nil {
+			return result, nil
+		}
+		result = *new(T)
+	}
+
+	// Run the query
+	result, err := scanFn(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// Tag the result with each table
+	_ = SetToJSONRaw(conn, key, result, ttl, tableDependencies(tables)...)
+	return result, nil
+}
+
+// InvalidateTable removes all cached query results tagged with the table(s)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: InvalidateTableRaw()
+func InvalidateTable(ctx context.Context, client *Client, tables ...string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return InvalidateTableRaw(conn, tables...)
+}
+
+// InvalidateTableRaw removes all cached query results tagged with the table(s)
+// Uses existing connection (does not close connection)
+//
+// Uses methods: KillByDependencyRaw()
+func InvalidateTableRaw(conn redis.Conn, tables ...string) (int, error) {
+	return KillByDependencyRaw(conn, tableDependencies(tables)...)
+}
+
+// tableDependencies returns the dependency key for each table
+func tableDependencies(tables []string) []string {
+	dependencies := make([]string, 0, len(tables))
+	for _, table := range tables {
+		dependencies = append(dependencies, TableDependency(table))
+	}
+	return dependencies
+}
@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testQueryRow is a row used to test query caching
+type testQueryRow struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestCacheQuery tests the method CacheQuery()
+func TestCacheQuery(t *testing.T) {
+
+	t.Run("miss then hit using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(nil).Expect([]byte(`[{"id":2,"name":"cached"}]`))
+		setCmd := conn.Command(SetExpirationCommand, testKey, int64(60), `[{"id":1,"name":"alice"}]`)
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+TableDependency("users"), testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		scans := 0
+		scan := func(_ context.Context) ([]testQueryRow, error) {
+			scans++
+			assert.Equal(t, 0, client.Pool.ActiveCount()-client.Pool.IdleCount()) // No connection is held
+			return []testQueryRow{{ID: 1, Name: "alice"}}, nil
+		}
+
+		rows, err := CacheQuery(context.Background(), client, testKey, time.Minute, []string{"users"}, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, []testQueryRow{{ID: 1, Name: "alice"}}, rows)
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+
+		rows, err = CacheQuery(context.Background(), client, testKey, time.Minute, []string{"users"}, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, []testQueryRow{{ID: 2, Name: "cached"}}, rows)
+		assert.Equal(t, 1, scans)
+	})
+
+	t.Run("scan error is not cached", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(nil)
+
+		_, err := CacheQuery(context.Background(), client, testKey, time.Minute, []string{"users"},
+			func(_ context.Context) ([]testQueryRow, error) {
+				return nil, errors.New("query failed")
+			},
+		)
+		assert.EqualError(t, err, "query failed")
+		assert.Equal(t, 0, conn.Stats(conn.GenericCommand(SetExpirationCommand)))
+	})
+
+	t.Run("cache and invalidate using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		scans := 0
+		scan := func(_ context.Context) (int, error) {
+			scans++
+			return 42, nil
+		}
+
+		// Cache the count
+		var count int
+		count, err = CacheQueryRaw(context.Background(), conn, testKey, 0, []string{"users", "orders"}, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, count)
+
+		count, err = CacheQueryRaw(context.Background(), conn, testKey, 0, []string{"users", "orders"}, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, count)
+		assert.Equal(t, 1, scans)
+
+		// A write to either table invalidates the result
+		var total int
+		total, err = InvalidateTableRaw(conn, "orders")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, total) // The cached result and the dependency set
+
+		_, err = CacheQueryRaw(context.Background(), conn, testKey, 0, []string{"users", "orders"}, scan)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, scans)
+	})
+}
+
+// ExampleCacheQuery is an example of the method CacheQuery()
+func ExampleCacheQuery() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the get and set commands
+	conn.GenericCommand(GetCommand).Expect(nil)
+	conn.GenericCommand(SetExpirationCommand).Expect("OK")
+	conn.Command(MultiCommand)
+	conn.GenericCommand(AddToSetCommand)
+	conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+	// Cache the query result, tagged with the users table
+	names, _ := CacheQuery(context.Background(), client, "active-users", time.Minute, []string{"users"},
+		func(_ context.Context) ([]string, error) {
+			return []string{"alice", "bob"}, nil
+		},
+	)
+	fmt.Printf("names: %v", names)
+	// Output:names: [alice bob]
+}
+
+// TestInvalidateTable tests the method InvalidateTable()
+func TestInvalidateTable(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	dependency := TableDependency("users")
+	conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+dependency).Expect(int64(3))
+	conn.Command(DeleteCommand, dependency).Expect(int64(1))
+
+	total, err := InvalidateTable(context.Background(), client, "users")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, total)
+}
+
+// ExampleInvalidateTable is an example of the method InvalidateTable()
+func ExampleInvalidateTable() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the kill commands
+	conn.GenericCommand(EvalCommand).Expect(int64(2))
+	conn.GenericCommand(DeleteCommand).Expect(int64(1))
+
+	// Invalidate after writing to the table
+	total, _ := InvalidateTable(context.Background(), client, "users")
+	fmt.Printf("removed: %d", total)
+	// Output:removed: 3
+}
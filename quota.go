@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// QuotaPrefix is the prefix for all quota keys
+const QuotaPrefix = "quota:"
+
+// ErrInvalidQuota is returned when the limit or window of a quota is not positive or the amount is negative
+var ErrInvalidQuota = errors.New("quota limit and window must be greater than zero and amount must not be negative")
+
+// consumeQuotaLua is the windowed quota script
+//
+// KEYS[1] = quota key, ARGV[1] = amount, ARGV[2] = limit, ARGV[3] = window (ms)
+// Returns {consumed (1/0), remaining, reset (ms)}
+const consumeQuotaLua = `
+--@begin=lua@
+local amount = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local used = 0
+local reset = redis.call("PTTL", KEYS[1])
+if reset > 0 then
+	used = tonumber(redis.call("GET", KEYS[1])) or 0
+else
+	reset = tonumber(ARGV[3])
+end
+if used + amount > limit then
+	return {0, math.max(0, limit - used), reset}
+end
+if amount > 0 then
+	redis.call("SET", KEYS[1], used + amount, "PX", reset)
+end
+return {1, limit - used - amount, reset}
+--@end=lua@
+`
+
+// consumeQuotaScript is the windowed quota script (EVALSHA with a fallback to EVAL)
+var consumeQuotaScript = newScript("consume_quota", 1, consumeQuotaLua)
+
+// QuotaResult is the result of consuming a quota
+type QuotaResult struct {
+	Consumed  bool          // The amount was consumed (false if it exceeds the remaining quota)
+	Remaining int64         // Quota remaining in the window
+	Reset     time.Duration // Time until the window ends and the quota resets
+	ResetAt   time.Time     // Time the window ends and the quota resets
+}
+
+// ConsumeQuota atomically consumes the amount from the quota of the subject (IE: API calls or
+// tokens per user) if the subject has enough quota remaining in the window
+// The window starts on the first consumption and the quota resets when it ends
+// An amount of zero returns the remaining quota without consuming
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ConsumeQuotaRaw()
+func ConsumeQuota(ctx context.Context, client *Client, subject string, amount, limit int64,
+	window time.Duration) (*QuotaResult, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return consumeQuota(conn, subject, amount, limit, window, client.Clock().Now())
+}
+
+// ConsumeQuotaRaw atomically consumes the amount from the quota of the subject if the subject has
+// enough quota remaining in the window
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/pttl
+// https://redis.io/commands/get
+// https://redis.io/commands/set
+func ConsumeQuotaRaw(conn redis.Conn, subject string, amount, limit int64,
+	window time.Duration) (*QuotaResult, error) {
+	return consumeQuota(conn, subject, amount, limit, window, time.Now())
+}
+
+// consumeQuota runs the quota script, the reset time is relative to now
+func consumeQuota(conn redis.Conn, subject string, amount, limit int64, window time.Duration,
+	now time.Time) (*QuotaResult, error) {
+	if len(subject) == 0 {
+		return nil, errors.New("missing required parameter: subject")
+	} else if limit <= 0 || window < time.Millisecond || amount < 0 {
+		return nil, ErrInvalidQuota
+	}
+
+	reply, err := redis.Int64s(consumeQuotaScript.Do(conn, QuotaPrefix+subject, amount, limit, window.Milliseconds()))
+	if err != nil {
+		return nil, err
+	} else if len(reply) != 3 {
+		return nil, errors.New("unexpected quota reply")
+	}
+	reset := time.Duration(reply[2]) * time.Millisecond
+	return &QuotaResult{
+		Consumed:  reply[0] == 1,
+		Remaining: reply[1],
+		Reset:     reset,
+		ResetAt:   now.Add(reset),
+	}, nil
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConsumeQuota tests the method ConsumeQuota()
+func TestConsumeQuota(t *testing.T) {
+
+	t.Run("invalid quotas", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := ConsumeQuota(context.Background(), client, "", 1, 10, time.Minute)
+		assert.Error(t, err)
+
+		_, err = ConsumeQuota(context.Background(), client, "user-1", 1, 0, time.Minute)
+		assert.ErrorIs(t, err, ErrInvalidQuota)
+
+		_, err = ConsumeQuota(context.Background(), client, "user-1", 1, 10, 0)
+		assert.ErrorIs(t, err, ErrInvalidQuota)
+
+		_, err = ConsumeQuota(context.Background(), client, "user-1", -1, 10, time.Minute)
+		assert.ErrorIs(t, err, ErrInvalidQuota)
+	})
+
+	t.Run("consume using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		now := time.Unix(1700000000, 0)
+		client.SetClock(NewManualClock(now))
+		cmd := conn.Script([]byte(consumeQuotaLua), 1, QuotaPrefix+"user-1", int64(3), int64(10), int64(60000)).
+			Expect([]interface{}{int64(1), int64(7), int64(60000)})
+
+		result, err := ConsumeQuota(context.Background(), client, "user-1", 3, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, cmd.Called)
+		assert.Equal(t, &QuotaResult{
+			Consumed:  true,
+			Remaining: 7,
+			Reset:     time.Minute,
+			ResetAt:   now.Add(time.Minute),
+		}, result)
+	})
+
+	t.Run("unexpected reply", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Script([]byte(consumeQuotaLua), 1, QuotaPrefix+"user-1", int64(1), int64(10), int64(60000)).
+			Expect([]interface{}{int64(1)})
+
+		_, err := ConsumeQuota(context.Background(), client, "user-1", 1, 10, time.Minute)
+		assert.Error(t, err)
+	})
+
+	t.Run("consume using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var result *QuotaResult
+		result, err = ConsumeQuotaRaw(conn, "user-1", 6, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, result.Consumed)
+		assert.Equal(t, int64(4), result.Remaining)
+		assert.Greater(t, result.Reset, 59*time.Second)
+
+		// Exceeds the remaining quota (nothing is consumed)
+		result, err = ConsumeQuotaRaw(conn, "user-1", 5, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, result.Consumed)
+		assert.Equal(t, int64(4), result.Remaining)
+
+		result, err = ConsumeQuotaRaw(conn, "user-1", 4, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, result.Consumed)
+		assert.Equal(t, int64(0), result.Remaining)
+
+		// Check without consuming
+		result, err = ConsumeQuotaRaw(conn, "user-2", 0, 10, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, result.Consumed)
+		assert.Equal(t, int64(10), result.Remaining)
+		var found bool
+		found, err = ExistsRaw(conn, QuotaPrefix+"user-2")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleConsumeQuota is an example of the method ConsumeQuota()
+func ExampleConsumeQuota() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the quota script
+	conn.Script([]byte(consumeQuotaLua), 1, QuotaPrefix+"user-1", int64(1), int64(1000), int64(3600000)).
+		Expect([]interface{}{int64(1), int64(999), int64(3600000)})
+
+	// Consume one of the 1000 calls per hour
+	result, _ := ConsumeQuota(context.Background(), client, "user-1", 1, 1000, time.Hour)
+	fmt.Printf("consumed: %v remaining: %d reset: %s", result.Consumed, result.Remaining, result.Reset)
+	// Output:consumed: true remaining: 999 reset: 1h0m0s
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RateLimitAlgorithm is the algorithm used by a rate limiter
+type RateLimitAlgorithm int
+
+// Rate limiter algorithms
+const (
+	TokenBucket   RateLimitAlgorithm = iota // Bursts up to the limit, refills evenly over the window
+	SlidingWindow                           // At most limit calls in any window (exact, uses a sorted set)
+)
+
+// ErrInvalidRateLimit is returned when the limit or window of a rate limiter is invalid
+var ErrInvalidRateLimit = errors.New("rate limit and window must be greater than zero")
+
+// rateLimitSequence makes sliding window members unique across calls
+var rateLimitSequence uint64
+
+// tokenBucketLua is the token bucket rate limiter script
+//
+// KEYS[1] = bucket key, ARGV[1] = capacity, ARGV[2] = window (ms), ARGV[3] = tokens requested
+// Returns {allowed (1/0), retry after (ms)}
+const tokenBucketLua = `
+--@begin=lua@
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local requested = tonumber(ARGV[3])
+local rate = capacity / window
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= requested then
+	tokens = tokens - requested
+	allowed = 1
+else
+	retry = math.ceil((requested - tokens) / rate)
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], window)
+return {allowed, retry}
+--@end=lua@
+`
+
+// slidingWindowLua is the sliding window rate limiter script
+//
+// KEYS[1] = window key, ARGV[1] = limit, ARGV[2] = window (ms), ARGV[3] = unique member
+// Returns {allowed (1/0), retry after (ms)}
+const slidingWindowLua = `
+--@begin=lua@
+redis.replicate_commands()
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+if redis.call("ZCARD", KEYS[1]) < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], window)
+	return {1, 0}
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return {0, math.max(1, tonumber(oldest[2]) + window - now)}
+--@end=lua@
+`
+
+// Rate limiter scripts (EVALSHA with a fallback to EVAL)
+var (
+	slidingWindowScript = newScript("sliding_window", 1, slidingWindowLua)
+	tokenBucketScript   = newScript("token_bucket", 1, tokenBucketLua)
+)
+
+// RateLimiter allows up to a limit of calls per key within a window of time
+// Each check is a single atomic Lua script
+type RateLimiter struct {
+	algorithm RateLimitAlgorithm
+	client    *Client
+	limit     int64
+	window    time.Duration
+}
+
+// NewRateLimiter creates a new rate limiter (IE: 100 calls per minute)
+func NewRateLimiter(client *Client, algorithm RateLimitAlgorithm, limit int64,
+	window time.Duration) (*RateLimiter, error) {
+	if limit <= 0 || window < time.Millisecond {
+		return nil, ErrInvalidRateLimit
+	}
+	return &RateLimiter{
+		algorithm: algorithm,
+		client:    client,
+		limit:     limit,
+		window:    window,
+	}, nil
+}
+
+// Register will load the rate limiter script (optional, the script is loaded on first use)
+func (r *RateLimiter) Register(ctx context.Context) error {
+	_, err := RegisterScript(ctx, r.client, r.source())
+	return err
+}
+
+// Allow checks if a call for the key is allowed, otherwise how long until the next call is allowed
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: AllowRaw()
+func (r *RateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	conn, err := r.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	defer r.client.CloseConnection(conn)
+	return r.AllowRaw(conn, key)
+}
+
+// AllowRaw checks if a call for the key is allowed, otherwise how long until the next call is allowed
+// Uses existing connection (does not close connection)
+func (r *RateLimiter) AllowRaw(conn redis.Conn, key string) (bool, time.Duration, error) {
+	windowMs := r.window.Milliseconds()
+
+	var reply []int64
+	var err error
+	if r.algorithm == SlidingWindow {
+		member := strconv.FormatInt(r.client.Clock().Now().UnixNano(), 36) + "-" +
+			strconv.FormatUint(atomic.AddUint64(&rateLimitSequence, 1), 36)
+		reply, err = redis.Int64s(slidingWindowScript.Do(conn, key, r.limit, windowMs, member))
+	} else {
+		reply, err = redis.Int64s(tokenBucketScript.Do(conn, key, r.limit, windowMs, 1))
+	}
+	if err != nil {
+		return false, 0, err
+	} else if len(reply) != 2 {
+		return false, 0, errors.New("unexpected rate limiter reply")
+	}
+	return reply[0] == 1, time.Duration(reply[1]) * time.Millisecond, nil
+}
+
+// source returns the script source for the algorithm
+func (r *RateLimiter) source() string {
+	if r.algorithm == SlidingWindow {
+		return slidingWindowLua
+	}
+	return tokenBucketLua
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNewRateLimiter tests the method NewRateLimiter()
+func TestNewRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter, err := NewRateLimiter(nil, TokenBucket, 0, time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidRateLimit)
+	assert.Nil(t, limiter)
+
+	limiter, err = NewRateLimiter(nil, SlidingWindow, 10, time.Microsecond)
+	assert.ErrorIs(t, err, ErrInvalidRateLimit)
+	assert.Nil(t, limiter)
+
+	limiter, err = NewRateLimiter(nil, SlidingWindow, 10, time.Minute)
+	assert.NoError(t, err)
+	assert.NotNil(t, limiter)
+}
+
+// TestRateLimiter_Allow tests the method Allow()
+func TestRateLimiter_Allow(t *testing.T) {
+
+	t.Run("token bucket using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		limiter, err := NewRateLimiter(client, TokenBucket, 10, time.Minute)
+		assert.NoError(t, err)
+
+		cmd := conn.Script([]byte(tokenBucketLua), 1, testKey, int64(10), int64(60000), 1).
+			Expect([]interface{}{int64(1), int64(0)}).
+			Expect([]interface{}{int64(0), int64(1500)})
+
+		var allowed bool
+		var retry time.Duration
+		allowed, retry, err = limiter.Allow(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, time.Duration(0), retry)
+
+		allowed, retry, err = limiter.Allow(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 1500*time.Millisecond, retry)
+		assert.True(t, cmd.Called)
+	})
+
+	t.Run("sliding window using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		limiter, err := NewRateLimiter(client, SlidingWindow, 5, time.Second)
+		assert.NoError(t, err)
+
+		conn.Script([]byte(slidingWindowLua), 1, testKey, int64(5), int64(1000), redigomock.NewAnyData()).
+			Expect([]interface{}{int64(0), int64(250)})
+
+		var allowed bool
+		var retry time.Duration
+		allowed, retry, err = limiter.Allow(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 250*time.Millisecond, retry)
+	})
+
+	t.Run("unexpected reply", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		limiter, err := NewRateLimiter(client, TokenBucket, 10, time.Minute)
+		assert.NoError(t, err)
+
+		conn.Script([]byte(tokenBucketLua), 1, testKey, int64(10), int64(60000), 1).
+			Expect([]interface{}{int64(1)})
+
+		_, _, err = limiter.Allow(context.Background(), testKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("rate limiters using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		for _, algorithm := range []RateLimitAlgorithm{TokenBucket, SlidingWindow} {
+			var limiter *RateLimiter
+			limiter, err = NewRateLimiter(client, algorithm, 2, time.Minute)
+			assert.NoError(t, err)
+			assert.NoError(t, limiter.Register(context.Background()))
+
+			key := fmt.Sprintf("%s-%d", testKey, algorithm)
+			for i := 0; i < 2; i++ {
+				var allowed bool
+				allowed, _, err = limiter.AllowRaw(conn, key)
+				assert.NoError(t, err)
+				assert.True(t, allowed)
+			}
+
+			var allowed bool
+			var retry time.Duration
+			allowed, retry, err = limiter.AllowRaw(conn, key)
+			assert.NoError(t, err)
+			assert.False(t, allowed)
+			assert.Greater(t, retry, time.Duration(0))
+			assert.LessOrEqual(t, retry, time.Minute)
+		}
+	})
+}
+
+// ExampleRateLimiter_Allow is an example of the method Allow()
+func ExampleRateLimiter_Allow() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script
+	conn.Script([]byte(tokenBucketLua), 1, testKey, int64(100), int64(60000), 1).
+		Expect([]interface{}{int64(1), int64(0)})
+
+	// 100 calls per minute
+	limiter, _ := NewRateLimiter(client, TokenBucket, 100, time.Minute)
+	allowed, _, _ := limiter.Allow(context.Background(), testKey)
+	fmt.Printf("allowed: %v", allowed)
+	// Output:allowed: true
+}
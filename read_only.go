@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrReadOnly is returned for commands that modify data while the client is in read-only mode
+var ErrReadOnly = errors.New("redis client is in read-only mode")
+
+// writeCommands are the commands that are rejected in read-only mode
+var writeCommands = map[string]struct{}{
+	AddToSetCommand:           {},
+	AppendCommand:             {},
+	BloomAddCommand:           {},
+	BloomAddManyCommand:       {},
+	BloomReserveCommand:       {},
+	CuckooAddCommand:          {},
+	CuckooAddUniqueCommand:    {},
+	CuckooDeleteCommand:       {},
+	CuckooReserveCommand:      {},
+	DeleteCommand:             {},
+	EvalCommand:               {},
+	ExpireCommand:             {},
+	ExpireMillisCommand:       {},
+	FlushAllCommand:           {},
+	FlushDBCommand:            {},
+	GetDeleteCommand:          {},
+	HashIncrementCommand:      {},
+	HashKeySetCommand:         {},
+	HashMapSetCommand:         {},
+	ListPushCommand:           {},
+	RemoveMemberCommand:       {},
+	SearchCreateCommand:       {},
+	SearchDropIndexCommand:    {},
+	SetCommand:                {},
+	SetExpMillisCommand:       {},
+	SetExpirationCommand:      {},
+	SetIntersectStoreCommand:  {},
+	SetMoveCommand:            {},
+	SetRangeCommand:           {},
+	SortedSetAddCommand:       {},
+	SortedSetIncrementCommand: {},
+	UnlinkCommand:             {},
+	"COPY":                    {},
+	"DECR":                    {},
+	"DECRBY":                  {},
+	"EVAL":                    {},
+	"EXPIREAT":                {},
+	"GETEX":                   {},
+	"GETSET":                  {},
+	"HDEL":                    {},
+	"HINCRBYFLOAT":            {},
+	"HSETNX":                  {},
+	"INCR":                    {},
+	"INCRBY":                  {},
+	"INCRBYFLOAT":             {},
+	"LINSERT":                 {},
+	"LPOP":                    {},
+	"LPUSH":                   {},
+	"LREM":                    {},
+	"LSET":                    {},
+	"LTRIM":                   {},
+	"MSET":                    {},
+	"MSETNX":                  {},
+	PersistCommand:            {},
+	"PEXPIREAT":               {},
+	"PFADD":                   {},
+	RenameCommand:             {},
+	"RENAMENX":                {},
+	"RESTORE":                 {},
+	"RPOP":                    {},
+	"SDIFFSTORE":              {},
+	"SETNX":                   {},
+	"SPOP":                    {},
+	"SUNIONSTORE":             {},
+	"XADD":                    {},
+	"ZREM":                    {},
+	"ZREMRANGEBYRANK":         {},
+	"ZREMRANGEBYSCORE":        {},
+}
+
+// SetReadOnly switches read-only mode on or off (safe to call at any time)
+//
+// In read-only mode every command that modifies data returns ErrReadOnly,
+// while reads (IE: Get(), Exists(), HashGet()) continue to work
+func (c *Client) SetReadOnly(readOnly bool) {
+	var value uint32
+	if readOnly {
+		value = 1
+	}
+	atomic.StoreUint32(&c.readOnly, value)
+}
+
+// IsReadOnly returns true if the client is in read-only mode
+func (c *Client) IsReadOnly() bool {
+	return atomic.LoadUint32(&c.readOnly) == 1
+}
+
+// isWriteCommand returns true if the command modifies data
+func isWriteCommand(commandName string) bool {
+	_, ok := writeCommands[strings.ToUpper(commandName)]
+	return ok
+}
+
+// readOnlyConn rejects the commands that modify data
+type readOnlyConn struct {
+	redis.Conn
+}
+
+// unwrap returns the wrapped connection
+func (c *readOnlyConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs the command (ErrReadOnly if the command modifies data)
+func (c *readOnlyConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if isWriteCommand(commandName) {
+		return nil, ErrReadOnly
+	}
+	return c.Conn.Do(commandName, args...)
+}
+
+// Send sends the command (ErrReadOnly if the command modifies data)
+func (c *readOnlyConn) Send(commandName string, args ...interface{}) error {
+	if isWriteCommand(commandName) {
+		return ErrReadOnly
+	}
+	return c.Conn.Send(commandName, args...)
+}
+
+// DoWithTimeout runs the command with the timeout (ErrReadOnly if the command modifies data)
+func (c *readOnlyConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	if isWriteCommand(commandName) {
+		return nil, ErrReadOnly
+	}
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *readOnlyConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetReadOnly tests the method SetReadOnly()
+func TestClient_SetReadOnly(t *testing.T) {
+
+	t.Run("writes are rejected using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		conn.Command(ExistsCommand, testKey).Expect(int64(1))
+		conn.Command(HashGetCommand, testHashName, testKey).Expect(testStringValue)
+
+		client.SetReadOnly(true)
+		assert.True(t, client.IsReadOnly())
+
+		// Writes are rejected
+		err := Set(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrReadOnly)
+
+		err = HashSet(context.Background(), client, testHashName, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrReadOnly)
+
+		_, err = Delete(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, ErrReadOnly)
+
+		err = Expire(context.Background(), client, testKey, 0)
+		assert.ErrorIs(t, err, ErrReadOnly)
+		assert.False(t, setCmd.Called)
+
+		// Reads continue to work
+		var value string
+		value, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var found bool
+		found, err = Exists(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		value, err = HashGet(context.Background(), client, testHashName, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		// Writes work again once switched off
+		client.SetReadOnly(false)
+		assert.False(t, client.IsReadOnly())
+
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+
+	t.Run("transactions are discarded using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// The set is rejected inside the transaction
+		client.SetReadOnly(true)
+		err = SetChunked(context.Background(), client, testKey, []byte(testStringValue), 0, 0)
+		assert.ErrorIs(t, err, ErrReadOnly)
+
+		// The pooled connection is usable afterwards
+		client.SetReadOnly(false)
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var value string
+		value, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+	})
+}
+
+// ExampleClient_SetReadOnly is an example of the method SetReadOnly()
+func ExampleClient_SetReadOnly() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Switch to read-only mode during an incident
+	client.SetReadOnly(true)
+
+	err := Set(context.Background(), client, "key", "value")
+	fmt.Printf("error: %v", err)
+	// Output:error: redis client is in read-only mode
+}
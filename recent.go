@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RecordRecent records the member as the most recently used in the list (a sorted set scored by
+// time) and keeps only the newest maxItems members, keeping a reference to each dependency
+// Recording an existing member moves it to the front (IE: "recently viewed" items)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: RecordRecentRaw()
+func RecordRecent(ctx context.Context, client *Client, listKey, member string, maxItems int,
+	dependencies ...string) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return recordRecent(conn, listKey, member, maxItems, client.Clock().Now(), dependencies...)
+}
+
+// RecordRecentRaw records the member as the most recently used in the list (a sorted set scored by
+// time) and keeps only the newest maxItems members, keeping a reference to each dependency
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/zadd
+// https://redis.io/commands/zremrangebyrank
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func RecordRecentRaw(conn redis.Conn, listKey, member string, maxItems int, dependencies ...string) error {
+	return recordRecent(conn, listKey, member, maxItems, time.Now(), dependencies...)
+}
+
+// GetRecent returns the n most recently used members of the list (newest first)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: GetRecentRaw()
+func GetRecent(ctx context.Context, client *Client, listKey string, n int) (members []string, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		members, readErr = GetRecentRaw(conn, listKey, n)
+		return
+	})
+	return
+}
+
+// GetRecentRaw returns the n most recently used members of the list (newest first)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/zrevrange
+func GetRecentRaw(conn redis.Conn, listKey string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	return redis.Strings(conn.Do(SortedSetReverseRangeCommand, listKey, 0, n-1))
+}
+
+// recordRecent adds the member scored by the time, trims the list and links the dependencies in one transaction
+func recordRecent(conn redis.Conn, listKey, member string, maxItems int, now time.Time,
+	dependencies ...string) (err error) {
+	if maxItems <= 0 {
+		return ErrInvalidMaxLength
+	} else if err = checkDependencyLimit(conn, dependencies...); err != nil {
+		return
+	}
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	if err = conn.Send(SortedSetAddCommand, listKey, now.UnixNano()/int64(time.Millisecond), member); err != nil {
+		return
+	}
+	if err = conn.Send(SortedSetRemoveRankCommand, listKey, 0, -(maxItems + 1)); err != nil {
+		return
+	}
+	if err = sendLinkDependencies(conn, listKey, dependencies...); err != nil {
+		return
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRecordRecent tests the methods RecordRecent() and GetRecent()
+func TestRecordRecent(t *testing.T) {
+
+	t.Run("invalid max items", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := RecordRecent(context.Background(), client, testKey, "item-1", 0)
+		assert.ErrorIs(t, err, ErrInvalidMaxLength)
+	})
+
+	t.Run("record using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		now := time.Unix(1700000000, 0)
+		client.SetClock(NewManualClock(now))
+		score := now.UnixNano() / int64(time.Millisecond)
+
+		var commands []*redigomock.Cmd
+		commands = append(commands, conn.Command(MultiCommand))
+		commands = append(commands, conn.Command(SortedSetAddCommand, testKey, score, "item-1"))
+		commands = append(commands, conn.Command(SortedSetRemoveRankCommand, testKey, 0, -11))
+		commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey))
+		commands = append(commands, conn.Command(ExecuteCommand))
+
+		err := RecordRecent(context.Background(), client, testKey, "item-1", 10, testDependantKey)
+		assert.NoError(t, err)
+		for _, c := range commands {
+			assert.True(t, c.Called)
+		}
+	})
+
+	t.Run("get recent using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SortedSetReverseRangeCommand, testKey, 0, 1).
+			Expect([]interface{}{[]byte("item-2"), []byte("item-1")})
+
+		members, err := GetRecent(context.Background(), client, testKey, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"item-2", "item-1"}, members)
+
+		members, err = GetRecent(context.Background(), client, testKey, 0)
+		assert.NoError(t, err)
+		assert.Nil(t, members)
+	})
+
+	t.Run("recent using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		clock := NewManualClock(time.Now())
+		client.SetClock(clock)
+		for _, member := range []string{"item-1", "item-2", "item-3", "item-1", "item-4"} {
+			err = RecordRecent(context.Background(), client, testKey, member, 3, testDependantKey)
+			assert.NoError(t, err)
+			clock.Advance(time.Second)
+		}
+
+		var members []string
+		members, err = GetRecentRaw(conn, testKey, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"item-4", "item-1", "item-3"}, members)
+
+		members, err = GetRecentRaw(conn, testKey, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"item-4", "item-1"}, members)
+
+		// Removed with the dependency
+		_, err = DeleteRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleGetRecent is an example of the method GetRecent()
+func ExampleGetRecent() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the range command
+	conn.Command(SortedSetReverseRangeCommand, testKey, 0, 1).
+		Expect([]interface{}{[]byte("item-2"), []byte("item-1")})
+
+	// Get the two most recently viewed items
+	members, _ := GetRecent(context.Background(), client, testKey, 2)
+	fmt.Printf("recent: %v", members)
+	// Output:recent: [item-2 item-1]
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -38,6 +39,9 @@ else
 end
 `
 
+// defaultLockRetryWait is the wait between lock attempts (see: WaitForLock())
+const defaultLockRetryWait = 50 * time.Millisecond
+
 // WriteLock attempts to grab a redis lock
 // Creates a new connection and closes connection at end of function call
 //
@@ -48,13 +52,50 @@ func WriteLock(ctx context.Context, client *Client, name, secret string, ttl int
 		return false, err
 	}
 	defer client.CloseConnection(conn)
-	return WriteLockRaw(conn, name, secret, ttl)
+	locked, err := WriteLockRaw(conn, name, secret, ttl)
+	if errors.Is(err, ErrLockMismatch) {
+		client.statsCollector().addLockContended()
+	}
+	return locked, err
+}
+
+// WaitForLock grabs a redis lock, retrying every retryWait (default: 50ms) while it is held by
+// someone else until the context is done (the context error is returned)
+// The wait time and timeouts are counted in the stats of the client (see: Stats())
+// Creates a new connection for each attempt
+func WaitForLock(ctx context.Context, client *Client, name, secret string, ttl int64,
+	retryWait time.Duration) error {
+	if retryWait <= 0 {
+		retryWait = defaultLockRetryWait
+	}
+
+	start := client.Clock().Now()
+	waited := false
+	for {
+		locked, err := WriteLock(ctx, client, name, secret, ttl)
+		if locked {
+			if waited {
+				client.statsCollector().addLockWait(client.Clock().Now().Sub(start), false)
+			}
+			return nil
+		} else if !errors.Is(err, ErrLockMismatch) {
+			return err
+		}
+		waited = true
+
+		select {
+		case <-ctx.Done():
+			client.statsCollector().addLockWait(client.Clock().Now().Sub(start), true)
+			return ctx.Err()
+		case <-client.Clock().After(retryWait):
+		}
+	}
 }
 
 // WriteLockRaw attempts to grab a redis lock
 // Uses existing connection (does not close connection)
 func WriteLockRaw(conn redis.Conn, name, secret string, ttl int64) (bool, error) {
-	script := redis.NewScript(1, lockScript)
+	script := newScript("lock", 1, lockScript)
 	if resp, err := redis.Int(script.Do(conn, name, secret, ttl)); err != nil {
 		return false, err
 	} else if resp != 0 {
@@ -79,7 +120,7 @@ func ReleaseLock(ctx context.Context, client *Client, name, secret string) (bool
 // ReleaseLockRaw releases the redis lock
 // Uses existing connection (does not close connection)
 func ReleaseLockRaw(conn redis.Conn, name, secret string) (bool, error) {
-	script := redis.NewScript(1, releaseLockScript)
+	script := newScript("release_lock", 1, releaseLockScript)
 	if resp, err := redis.Int(script.Do(conn, name, secret)); err != nil {
 		return false, err
 	} else if resp != 0 {
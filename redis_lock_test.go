@@ -142,6 +142,64 @@ func TestWriteLock(t *testing.T) {
 	})
 }
 
+// TestWaitForLock tests the method WaitForLock()
+func TestWaitForLock(t *testing.T) {
+
+	t.Run("acquired after waiting using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetCommandStats(true)
+
+		lockCmd := conn.Script([]byte(lockScript), 1, "my-lock", "the-secret", int64(10)).
+			Expect(int64(0)).Expect(int64(0)).Expect(int64(1))
+
+		err := WaitForLock(context.Background(), client, "my-lock", "the-secret", int64(10), time.Millisecond)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, conn.Stats(lockCmd))
+
+		herd := client.Stats().Herd
+		assert.Equal(t, uint64(2), herd.LockContended)
+		assert.Equal(t, uint64(1), herd.LockWaits)
+		assert.Equal(t, uint64(0), herd.LockTimeouts)
+		assert.Greater(t, herd.LockWaitDuration, time.Duration(0))
+	})
+
+	t.Run("timeout using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetCommandStats(true)
+
+		conn.Script([]byte(lockScript), 1, "my-lock", "the-secret", int64(10)).Expect(int64(0))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := WaitForLock(ctx, client, "my-lock", "the-secret", int64(10), 5*time.Millisecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		herd := client.Stats().Herd
+		assert.Equal(t, uint64(1), herd.LockWaits)
+		assert.Equal(t, uint64(1), herd.LockTimeouts)
+	})
+
+	t.Run("lock without waiting using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetCommandStats(true)
+
+		conn.Script([]byte(lockScript), 1, "my-lock", "the-secret", int64(10)).Expect(int64(1))
+
+		err := WaitForLock(context.Background(), client, "my-lock", "the-secret", int64(10), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, HerdStats{}, client.Stats().Herd)
+	})
+}
+
 // ExampleWriteLock is an example of the method WriteLock()
 func ExampleWriteLock() {
 
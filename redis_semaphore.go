@@ -0,0 +1,167 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrSemaphoreFull is the error if all the slots of the semaphore are taken
+var ErrSemaphoreFull = errors.New("semaphore has no free slots")
+
+// ErrSemaphoreQueued is the error if all the slots of the semaphore are taken and the secret is
+// waiting in line (see: QueueSemaphore()), release the secret to give up its place in line
+var ErrSemaphoreQueued = errors.New("semaphore has no free slots, waiting in line")
+
+// Suffixes of the keys kept next to the semaphore
+const (
+	semaphoreCounterSuffix = ":counter" // Last ticket handed out
+	semaphoreTicketsSuffix = ":tickets" // Place in line of each secret
+)
+
+// acquireSemaphoreLua is the acquire semaphore script
+//
+// Secrets are stored in a sorted set scored by their expiration (redis server time), so every
+// worker is judged by the same clock and expired secrets free their slot. Each secret takes a
+// ticket from the counter on its first attempt, and holds a slot while its ticket ranks below the
+// limit, so the slots are handed out in the order of the first attempts. A secret that finds no
+// free slot keeps its ticket only if it queues, otherwise it is removed
+//
+// KEYS[1] = semaphore, KEYS[2] = tickets, KEYS[3] = counter
+// ARGV[1] = secret, ARGV[2] = limit, ARGV[3] = ttl (seconds), ARGV[4] = queue (1 or 0)
+// Returns 1 if the secret holds a slot, 0 if it has no slot (waiting in line if it queues)
+const acquireSemaphoreLua = `
+redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[3]) * 1000
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+redis.call("ZINTERSTORE", KEYS[2], 2, KEYS[2], KEYS[1], "WEIGHTS", 1, 0)
+if not redis.call("ZSCORE", KEYS[2], ARGV[1]) then
+	redis.call("ZADD", KEYS[2], redis.call("INCR", KEYS[3]), ARGV[1])
+end
+if redis.call("ZRANK", KEYS[2], ARGV[1]) >= tonumber(ARGV[2]) and ARGV[4] ~= "1" then
+	redis.call("ZREM", KEYS[2], ARGV[1])
+	redis.call("ZREM", KEYS[1], ARGV[1])
+	return 0
+end
+redis.call("ZADD", KEYS[1], now + ttl, ARGV[1])
+for i = 1, 3 do
+	if redis.call("PTTL", KEYS[i]) < ttl then
+		redis.call("PEXPIRE", KEYS[i], ttl)
+	end
+end
+if redis.call("ZRANK", KEYS[2], ARGV[1]) < tonumber(ARGV[2]) then
+	return 1
+end
+return 0
+`
+
+// releaseSemaphoreLua is the release semaphore script (removes the secret and its place in line)
+//
+// KEYS[1] = semaphore, KEYS[2] = tickets, ARGV[1] = secret
+// Returns 1 if the secret was holding a slot or waiting in line
+const releaseSemaphoreLua = `
+redis.call("ZREM", KEYS[2], ARGV[1])
+return redis.call("ZREM", KEYS[1], ARGV[1])
+`
+
+// Semaphore scripts (EVALSHA with a fallback to EVAL)
+var (
+	acquireSemaphoreScript = newScript("acquire_semaphore", 3, acquireSemaphoreLua)
+	releaseSemaphoreScript = newScript("release_semaphore", 2, releaseSemaphoreLua)
+)
+
+// AcquireSemaphore attempts to grab one of the limited slots of a redis semaphore
+// Acquiring again with the same secret refreshes the ttl of the slot
+//
+// A secret that finds no free slot is not kept (ErrSemaphoreFull), nothing needs to be released
+// To keep a place in line for a later attempt use method: QueueSemaphore()
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: AcquireSemaphoreRaw()
+func AcquireSemaphore(ctx context.Context, client *Client, name, secret string,
+	limit, ttl int64) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return AcquireSemaphoreRaw(conn, name, secret, limit, ttl)
+}
+
+// AcquireSemaphoreRaw attempts to grab one of the limited slots of a redis semaphore
+// Uses existing connection (does not close connection)
+func AcquireSemaphoreRaw(conn redis.Conn, name, secret string, limit, ttl int64) (bool, error) {
+	return acquireSemaphore(conn, name, secret, limit, ttl, false)
+}
+
+// QueueSemaphore attempts to grab one of the limited slots of a redis semaphore, and keeps a place
+// in line if there is no free slot
+// Acquiring again with the same secret refreshes the ttl of the slot
+//
+// The slots are fair: a secret that finds no free slot keeps its place in line for the ttl
+// (ErrSemaphoreQueued), and gets a slot on a later attempt before the secrets that came after it
+// Release the secret to give up its place in line (see: ReleaseSemaphore())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: QueueSemaphoreRaw()
+func QueueSemaphore(ctx context.Context, client *Client, name, secret string,
+	limit, ttl int64) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return QueueSemaphoreRaw(conn, name, secret, limit, ttl)
+}
+
+// QueueSemaphoreRaw attempts to grab one of the limited slots of a redis semaphore, and keeps a
+// place in line if there is no free slot
+// Uses existing connection (does not close connection)
+func QueueSemaphoreRaw(conn redis.Conn, name, secret string, limit, ttl int64) (bool, error) {
+	return acquireSemaphore(conn, name, secret, limit, ttl, true)
+}
+
+// acquireSemaphore runs the acquire script, a secret without a slot keeps its place in line if it queues
+func acquireSemaphore(conn redis.Conn, name, secret string, limit, ttl int64, queue bool) (bool, error) {
+	var queued int
+	if queue {
+		queued = 1
+	}
+	if resp, err := redis.Int(acquireSemaphoreScript.Do(
+		conn, name, name+semaphoreTicketsSuffix, name+semaphoreCounterSuffix, secret, limit, ttl, queued,
+	)); err != nil {
+		return false, err
+	} else if resp != 0 {
+		return true, nil
+	} else if queue {
+		return false, ErrSemaphoreQueued
+	}
+	return false, ErrSemaphoreFull
+}
+
+// ReleaseSemaphore releases the slot or the place in line held by the secret
+// Returns false if the secret was not holding a slot or a place in line (IE: it expired)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ReleaseSemaphoreRaw()
+func ReleaseSemaphore(ctx context.Context, client *Client, name, secret string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return ReleaseSemaphoreRaw(conn, name, secret)
+}
+
+// ReleaseSemaphoreRaw releases the slot or the place in line held by the secret
+// Uses existing connection (does not close connection)
+func ReleaseSemaphoreRaw(conn redis.Conn, name, secret string) (bool, error) {
+	resp, err := redis.Int(releaseSemaphoreScript.Do(conn, name, name+semaphoreTicketsSuffix, secret))
+	if err != nil {
+		return false, err
+	}
+	return resp != 0, nil
+}
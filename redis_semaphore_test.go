@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAcquireSemaphore tests the method AcquireSemaphore()
+func TestAcquireSemaphore(t *testing.T) {
+
+	t.Run("acquire semaphore - mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Script([]byte(acquireSemaphoreLua), 3, "my-job", "my-job"+semaphoreTicketsSuffix,
+			"my-job"+semaphoreCounterSuffix, "worker-1", int64(2), int64(10), 0).
+			Expect(int64(1))
+		conn.Script([]byte(acquireSemaphoreLua), 3, "my-job", "my-job"+semaphoreTicketsSuffix,
+			"my-job"+semaphoreCounterSuffix, "worker-3", int64(2), int64(10), 0).
+			Expect(int64(0))
+		conn.Script([]byte(acquireSemaphoreLua), 3, "my-job", "my-job"+semaphoreTicketsSuffix,
+			"my-job"+semaphoreCounterSuffix, "worker-4", int64(2), int64(10), 1).
+			Expect(int64(0))
+
+		acquired, err := AcquireSemaphore(context.Background(), client, "my-job", "worker-1", 2, 10)
+		assert.NoError(t, err)
+		assert.True(t, acquired)
+
+		acquired, err = AcquireSemaphore(context.Background(), client, "my-job", "worker-3", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreFull)
+		assert.False(t, acquired)
+
+		acquired, err = QueueSemaphore(context.Background(), client, "my-job", "worker-4", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreQueued)
+		assert.False(t, acquired)
+	})
+
+	t.Run("try once - real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var acquired bool
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-a", 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		// Refused without a place in line
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-b", 1, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreFull)
+		assert.Equal(t, false, acquired)
+
+		// The free slot goes to the next attempt
+		var released bool
+		released, err = ReleaseSemaphoreRaw(conn, "my-job", "worker-a")
+		assert.NoError(t, err)
+		assert.Equal(t, true, released)
+
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-c", 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		// Nothing was kept for the refused secret
+		released, err = ReleaseSemaphoreRaw(conn, "my-job", "worker-b")
+		assert.NoError(t, err)
+		assert.Equal(t, false, released)
+	})
+
+	t.Run("acquire semaphore - real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Take both slots
+		var acquired bool
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-1", 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-2", 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		// Refresh an existing slot
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-1", 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		// No free slots
+		acquired, err = QueueSemaphoreRaw(conn, "my-job", "worker-3", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreQueued)
+		assert.Equal(t, false, acquired)
+
+		// Waiting in line behind worker-3
+		acquired, err = QueueSemaphoreRaw(conn, "my-job", "worker-4", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreQueued)
+		assert.Equal(t, false, acquired)
+
+		// Free a slot, it goes to the first in line
+		var released bool
+		released, err = ReleaseSemaphoreRaw(conn, "my-job", "worker-1")
+		assert.NoError(t, err)
+		assert.Equal(t, true, released)
+
+		acquired, err = QueueSemaphoreRaw(conn, "my-job", "worker-4", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreQueued)
+		assert.Equal(t, false, acquired)
+
+		// A try-once attempt does not skip the line
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-6", 2, 10)
+		assert.ErrorIs(t, err, ErrSemaphoreFull)
+		assert.Equal(t, false, acquired)
+
+		acquired, err = QueueSemaphoreRaw(conn, "my-job", "worker-3", 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+
+		// Giving up the place in line
+		released, err = ReleaseSemaphoreRaw(conn, "my-job", "worker-4")
+		assert.NoError(t, err)
+		assert.Equal(t, true, released)
+
+		released, err = ReleaseSemaphoreRaw(conn, "my-job", "worker-2")
+		assert.NoError(t, err)
+		assert.Equal(t, true, released)
+
+		acquired, err = AcquireSemaphoreRaw(conn, "my-job", "worker-5", 2, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, true, acquired)
+	})
+}
+
+// ExampleAcquireSemaphore is an example of the method AcquireSemaphore()
+func ExampleAcquireSemaphore() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script
+	conn.Script([]byte(acquireSemaphoreLua), 3, "my-job", "my-job"+semaphoreTicketsSuffix,
+		"my-job"+semaphoreCounterSuffix, "worker-1", int64(5), int64(30), 0).
+		Expect(int64(1))
+
+	// At most 5 workers for 30 seconds
+	acquired, _ := AcquireSemaphore(context.Background(), client, "my-job", "worker-1", 5, 30)
+	fmt.Printf("acquired: %v", acquired)
+	// Output:acquired: true
+}
+
+// ExampleQueueSemaphore is an example of the method QueueSemaphore()
+func ExampleQueueSemaphore() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script
+	conn.Script([]byte(acquireSemaphoreLua), 3, "my-job", "my-job"+semaphoreTicketsSuffix,
+		"my-job"+semaphoreCounterSuffix, "worker-6", int64(5), int64(30), 1).
+		Expect(int64(0))
+
+	// All 5 slots are taken, worker-6 keeps its place in line until it is released
+	_, err := QueueSemaphore(context.Background(), client, "my-job", "worker-6", 5, 30)
+	fmt.Printf("queued: %v", errors.Is(err, ErrSemaphoreQueued))
+	// Output:queued: true
+}
+
+// TestReleaseSemaphore tests the method ReleaseSemaphore()
+func TestReleaseSemaphore(t *testing.T) {
+
+	t.Run("release semaphore - mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Script([]byte(releaseSemaphoreLua), 2, "my-job", "my-job"+semaphoreTicketsSuffix, "worker-1").
+			Expect(int64(1)).
+			Expect(int64(0))
+
+		released, err := ReleaseSemaphore(context.Background(), client, "my-job", "worker-1")
+		assert.NoError(t, err)
+		assert.True(t, released)
+
+		released, err = ReleaseSemaphore(context.Background(), client, "my-job", "worker-1")
+		assert.NoError(t, err)
+		assert.False(t, released)
+	})
+}
+
+// ExampleReleaseSemaphore is an example of the method ReleaseSemaphore()
+func ExampleReleaseSemaphore() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script
+	conn.Script([]byte(releaseSemaphoreLua), 2, "my-job", "my-job"+semaphoreTicketsSuffix, "worker-1").Expect(int64(1))
+
+	// Free the slot
+	released, _ := ReleaseSemaphore(context.Background(), client, "my-job", "worker-1")
+	fmt.Printf("released: %v", released)
+	// Output:released: true
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+)
+
+// primaryReadKey is the context key for requiring reads from the primary
+type primaryReadKey struct{}
+
+// WithPrimaryRead returns a context that routes all reads to the primary (read-your-writes)
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// isPrimaryRead returns true if the context requires reads from the primary
+// (or a write was sent with a sticky primary context, see: WithStickyPrimary())
+func isPrimaryRead(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadKey{}).(bool)
+	return primary || IsStickyPrimary(ctx)
+}
+
+// ConnectReplicas creates a connection pool for each read replica url
+// Read commands (GET, EXISTS, LRANGE, SMEMBERS, HGET) are routed to the replicas (round-robin)
+// and fall back to the primary if the replica cannot be reached
+//
+// Format of URL: redis://localhost:6379
+func (c *Client) ConnectReplicas(replicaURLs []string,
+	maxActiveConnections, idleConnections int,
+	maxConnLifetime, idleTimeout time.Duration,
+	newRelicEnabled bool, options ...redis.DialOption) error {
+
+	// Required param for dial
+	if len(replicaURLs) == 0 {
+		return errors.New("missing required parameter: replicaURLs")
+	}
+
+	// Create a pool per replica
+	replicas := make([]nrredis.Pool, 0, len(replicaURLs))
+	for _, replicaURL := range replicaURLs {
+		pool, err := newPool(Config{
+			IdleTimeout:           idleTimeout,
+			MaxActiveConnections:  maxActiveConnections,
+			MaxConnectionLifetime: maxConnLifetime,
+			MaxIdleConnections:    idleConnections,
+			NewRelicEnabled:       newRelicEnabled,
+			URL:                   replicaURL,
+			onDial:                c.nameConnection,
+		}, options...)
+		if err != nil {
+			closePools(replicas)
+			return err
+		}
+		replicas = append(replicas, pool)
+	}
+
+	c.mu.Lock()
+	previous := c.Replicas
+	c.Replicas = replicas
+	c.mu.Unlock()
+
+	closePools(previous)
+	return nil
+}
+
+// GetReadConnectionWithContext will return a connection from a replica pool, or the primary
+// pool if there are no replicas or the context requires a primary read (see: WithPrimaryRead())
+// The connection must be closed when you're finished
+func (c *Client) GetReadConnectionWithContext(ctx context.Context) (redis.Conn, error) {
+	if pool := c.replicaPool(ctx); pool != nil {
+		conn, err := c.getReplicaConnection(ctx, pool)
+		if err == nil || errors.Is(err, ErrOverloaded) {
+			return conn, err
+		}
+	}
+	return c.GetConnectionWithContext(ctx)
+}
+
+// getReplicaConnection returns a connection from the replica pool (counted by the load shedding,
+// see: SetConcurrencyLimit())
+func (c *Client) getReplicaConnection(ctx context.Context, pool nrredis.Pool) (redis.Conn, error) {
+	release, err := c.admit(ctx, 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	start := c.Clock().Now()
+	var conn redis.Conn
+	if conn, err = pool.GetContext(ctx); err != nil {
+		if release != nil {
+			release()
+		}
+		return nil, err
+	}
+	if release != nil {
+		conn = &releaseConn{Conn: conn, release: release}
+	}
+	conn, _ = withTimeoutConn(ctx)(conn, nil)
+	return c.observeConn(conn, c.Clock().Now().Sub(start)), nil
+}
+
+// replicaPool returns the next replica pool (round-robin) or nil if reads go to the primary
+func (c *Client) replicaPool(ctx context.Context) nrredis.Pool {
+	if isPrimaryRead(ctx) || c.acceptOperation(ctx) != nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.Replicas) == 0 {
+		return nil
+	}
+	next := atomic.AddUint64(&c.replicaIndex, 1)
+	return c.Replicas[next%uint64(len(c.Replicas))]
+}
+
+// read will run the read function against a replica and fall back to the primary
+// if the replica could not be reached
+func (c *Client) read(ctx context.Context, fn func(conn redis.Conn) error) error {
+	if pool := c.replicaPool(ctx); pool != nil {
+		conn, err := c.getReplicaConnection(ctx, pool)
+		if errors.Is(err, ErrOverloaded) {
+			return err
+		} else if err == nil {
+			err = fn(conn)
+			CloseConnection(conn)
+			if !isReplicaFailure(err) {
+				return err
+			}
+		}
+	}
+
+	conn, err := c.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.CloseConnection(conn)
+	return fn(conn)
+}
+
+// isReplicaFailure returns true if the error came from the connection and not from redis
+func isReplicaFailure(err error) bool {
+	if err == nil || errors.Is(err, redis.ErrNil) {
+		return false
+	}
+	var redisErr redis.Error
+	return !errors.As(err, &redisErr)
+}
+
+// closePools will close all the given pools
+func closePools(pools []nrredis.Pool) {
+	for _, pool := range pools {
+		_ = pool.Close()
+	}
+}
@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadMockReplica will load a mocked redis replica pool
+func loadMockReplica() (pool nrredis.Pool, conn *redigomock.Conn) {
+	conn = redigomock.NewConn()
+	pool = &redis.Pool{
+		Dial:            func() (redis.Conn, error) { return conn, nil },
+		IdleTimeout:     testIdleTimeout,
+		MaxActive:       testMaxActiveConnections,
+		MaxConnLifetime: testMaxConnLifetime,
+		MaxIdle:         testMaxIdleConnections,
+	}
+	return
+}
+
+// TestClient_ConnectReplicas tests the method ConnectReplicas()
+func TestClient_ConnectReplicas(t *testing.T) {
+
+	t.Run("missing replica urls", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.ConnectReplicas(
+			nil, testMaxActiveConnections, testMaxIdleConnections,
+			testMaxConnLifetime, testIdleTimeout, false,
+		)
+		assert.Error(t, err)
+		assert.Equal(t, 0, len(client.Replicas))
+	})
+
+	t.Run("valid replica urls", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.ConnectReplicas(
+			[]string{testLocalConnectionURL, testLocalConnectionURL},
+			testMaxActiveConnections, testMaxIdleConnections,
+			testMaxConnLifetime, testIdleTimeout, false,
+			redis.DialConnectTimeout(2*time.Second),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(client.Replicas))
+
+		client.Close()
+		assert.Equal(t, 0, len(client.Replicas))
+	})
+
+	t.Run("bad replica url with new relic", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.ConnectReplicas(
+			[]string{"redis://no-port"},
+			testMaxActiveConnections, testMaxIdleConnections,
+			testMaxConnLifetime, testIdleTimeout, true,
+		)
+		assert.Error(t, err)
+		assert.Equal(t, 0, len(client.Replicas))
+	})
+}
+
+// TestReplicaRouting tests routing reads to the replicas
+func TestReplicaRouting(t *testing.T) {
+
+	t.Run("reads go to the replica", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+
+		primaryCmd := conn.Command(GetCommand, testKey).Expect("primary")
+		replicaCmd := replicaConn.Command(GetCommand, testKey).Expect("replica")
+		replicaConn.Command(ExistsCommand, testKey).Expect(int64(1))
+		replicaConn.Command(HashGetCommand, testHashName, testKey).Expect(testStringValue)
+		replicaConn.Command(MembersCommand, testKey).ExpectStringSlice(testStringValue)
+		replicaConn.Command(ListRangeCommand, testKey, 0, -1).ExpectStringSlice(testStringValue)
+
+		val, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "replica", val)
+		assert.True(t, replicaCmd.Called)
+		assert.False(t, primaryCmd.Called)
+
+		var found bool
+		found, err = Exists(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+
+		val, err = HashGet(context.Background(), client, testHashName, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+
+		var members []string
+		members, err = SetMembers(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testStringValue}, members)
+
+		members, err = GetList(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testStringValue}, members)
+	})
+
+	t.Run("primary read is required", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+
+		primaryCmd := conn.Command(GetCommand, testKey).Expect("primary")
+		replicaCmd := replicaConn.Command(GetCommand, testKey).Expect("replica")
+
+		val, err := Get(WithPrimaryRead(context.Background()), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", val)
+		assert.True(t, primaryCmd.Called)
+		assert.False(t, replicaCmd.Called)
+	})
+
+	t.Run("replica failure falls back to primary", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+
+		conn.Command(GetCommand, testKey).Expect("primary")
+		replicaConn.Command(GetCommand, testKey).ExpectError(errors.New("connection reset"))
+
+		val, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", val)
+	})
+
+	t.Run("replica miss does not fall back", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+
+		primaryCmd := conn.Command(GetCommand, testKey).Expect("primary")
+		replicaConn.Command(GetCommand, testKey).Expect(nil)
+
+		_, err := Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+		assert.False(t, primaryCmd.Called)
+	})
+
+	t.Run("unreachable replica pool falls back to primary", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.Replicas = []nrredis.Pool{&redis.Pool{
+			Dial: func() (redis.Conn, error) { return nil, errors.New("dial failed") },
+		}}
+
+		conn.Command(GetCommand, testKey).Expect("primary")
+
+		val, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", val)
+
+		var readConn redis.Conn
+		readConn, err = client.GetReadConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		assert.NotNil(t, readConn)
+		client.CloseConnection(readConn)
+	})
+}
+
+// ExampleWithPrimaryRead is an example of the method WithPrimaryRead()
+func ExampleWithPrimaryRead() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the primary response
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+	// Read your own writes from the primary
+	value, _ := Get(WithPrimaryRead(context.Background()), client, testKey)
+	fmt.Printf("got value: %s", value)
+	// Output:got value: test-string-value
+}
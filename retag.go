@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// RetagKeys moves the keys matching the pattern (IE: user:*) from the dependency set of the old tag
+// to the dependency set of the new tag, for renaming tags without flushing the cache
+// Returns the number of keys moved
+//
+// Each key is moved atomically (SMOVE), the migration as a whole is not atomic and can be run again
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: RetagKeysRaw()
+func RetagKeys(ctx context.Context, client *Client, pattern, oldTag, newTag string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return RetagKeysRaw(conn, pattern, oldTag, newTag)
+}
+
+// RetagKeysRaw moves the keys matching the pattern from the dependency set of the old tag to the
+// dependency set of the new tag (the matching keys are found with SSCAN, then moved in pipelined
+// batches of SMOVE)
+// Returns the number of keys moved
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/sscan
+// https://redis.io/commands/smove
+func RetagKeysRaw(conn redis.Conn, pattern, oldTag, newTag string) (moved int, err error) {
+	if len(pattern) == 0 {
+		return 0, errors.New("missing required parameter: pattern")
+	} else if len(oldTag) == 0 {
+		return 0, errors.New("missing required parameter: oldTag")
+	} else if len(newTag) == 0 {
+		return 0, errors.New("missing required parameter: newTag")
+	} else if oldTag == newTag {
+		return 0, errors.New("old and new tag must be different")
+	} else if err = checkDependencyLimit(conn, newTag); err != nil {
+		return 0, err
+	}
+
+	for _, set := range dependencySetKeys(conn, oldTag) {
+
+		// Collect the members first (moving while scanning could skip members)
+		var matched []string
+		cursor := int64(0)
+		for {
+			var members []string
+			if cursor, members, err = setScanRaw(conn, set, cursor, pattern, defaultScanCount); err != nil {
+				return
+			}
+			matched = append(matched, members...)
+			if cursor == 0 {
+				break
+			}
+		}
+
+		for start := 0; start < len(matched); start += defaultScanCount {
+			end := start + defaultScanCount
+			if end > len(matched) {
+				end = len(matched)
+			}
+			var count int
+			if count, err = moveMembers(conn, set, newTag, matched[start:end]); err != nil {
+				return
+			}
+			moved += count
+		}
+	}
+	return
+}
+
+// moveMembers moves the members from the set to the dependency set of the tag (pipelined)
+func moveMembers(conn redis.Conn, set, tag string, members []string) (int, error) {
+	for _, member := range members {
+		if err := conn.Send(SetMoveCommand, set, dependencySetKey(conn, tag, member), member); err != nil {
+			return 0, err
+		}
+	}
+	replies, err := flushPipeline(conn)
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, reply := range replies {
+		var count int
+		if count, err = redis.Int(reply, nil); err != nil {
+			return moved, err
+		}
+		moved += count
+	}
+	return moved, nil
+}
+
+// setScanRaw returns the next cursor and the members of the set matching the pattern
+//
+// Spec: https://redis.io/commands/sscan
+func setScanRaw(conn redis.Conn, set string, cursor int64, pattern string, count int) (int64, []string, error) {
+	values, err := redis.Values(conn.Do(SetScanCommand, set, cursor, MatchArgument, pattern, CountArgument, count))
+	if err != nil {
+		return 0, nil, err
+	} else if len(values) != 2 {
+		return 0, nil, fmt.Errorf("unexpected scan reply with %d elements", len(values))
+	}
+	var members []string
+	if cursor, err = redis.Int64(values[0], nil); err != nil {
+		return 0, nil, err
+	}
+	if members, err = redis.Strings(values[1], nil); err != nil {
+		return 0, nil, err
+	}
+	return cursor, members, nil
+}
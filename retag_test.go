@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRetagKeys tests the method RetagKeys()
+func TestRetagKeys(t *testing.T) {
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := RetagKeys(context.Background(), client, "", "old", "new")
+		assert.Error(t, err)
+		_, err = RetagKeys(context.Background(), client, "*", "", "new")
+		assert.Error(t, err)
+		_, err = RetagKeys(context.Background(), client, "*", "old", "")
+		assert.Error(t, err)
+		_, err = RetagKeys(context.Background(), client, "*", "old", "old")
+		assert.Error(t, err)
+	})
+
+	t.Run("retag using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetScanCommand, DependencyPrefix+"old", int64(0), MatchArgument, "user:*",
+			CountArgument, defaultScanCount).Expect([]interface{}{[]byte("7"), []interface{}{[]byte("user:1")}})
+		conn.Command(SetScanCommand, DependencyPrefix+"old", int64(7), MatchArgument, "user:*",
+			CountArgument, defaultScanCount).Expect([]interface{}{[]byte("0"), []interface{}{[]byte("user:2")}})
+		conn.Command(SetMoveCommand, DependencyPrefix+"old", DependencyPrefix+"new", "user:1").Expect(int64(1))
+		conn.Command(SetMoveCommand, DependencyPrefix+"old", DependencyPrefix+"new", "user:2").Expect(int64(0))
+
+		moved, err := RetagKeys(context.Background(), client, "user:*", "old", "new")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, moved)
+	})
+
+	t.Run("retag using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		for i := 0; i < 150; i++ {
+			err = SetRaw(conn, fmt.Sprintf("user:%d", i), testStringValue, "users-v1")
+			assert.NoError(t, err)
+		}
+		err = SetRaw(conn, "order:1", testStringValue, "users-v1")
+		assert.NoError(t, err)
+
+		var moved int
+		moved, err = RetagKeys(context.Background(), client, "user:*", "users-v1", "users-v2")
+		assert.NoError(t, err)
+		assert.Equal(t, 150, moved)
+
+		var members []string
+		members, err = SetMembersRaw(conn, DependencyPrefix+"users-v1")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"order:1"}, members)
+
+		// The new tag removes the keys
+		var total int
+		total, err = KillByDependency(context.Background(), client, "users-v2")
+		assert.NoError(t, err)
+		assert.Equal(t, 151, total)
+	})
+}
+
+// ExampleRetagKeys is an example of the method RetagKeys()
+func ExampleRetagKeys() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the dependency set of the old tag
+	conn.Command(SetScanCommand, DependencyPrefix+"users", int64(0), MatchArgument, "*",
+		CountArgument, defaultScanCount).Expect([]interface{}{[]byte("0"), []interface{}{[]byte("user:1")}})
+	conn.Command(SetMoveCommand, DependencyPrefix+"users", DependencyPrefix+"tenant:1:users", "user:1").
+		Expect(int64(1))
+
+	moved, _ := RetagKeys(context.Background(), client, "*", "users", "tenant:1:users")
+	fmt.Printf("moved %d keys", moved)
+	// Output:moved 1 keys
+}
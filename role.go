@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Server roles (see: Role())
+const (
+	RoleMaster   = "master"
+	RoleReplica  = "replica"
+	RoleSentinel = "sentinel"
+)
+
+// readOnlyErrorPrefix is the prefix of the error for writes against a replica
+const readOnlyErrorPrefix = "READONLY"
+
+// ErrReplicaWrite is returned for writes while the client is connected to a replica
+// (see: SetRefuseReplicaWrites())
+var ErrReplicaWrite = errors.New("redis server is a replica, writes are refused")
+
+// ServerRole is the replication role of the server
+type ServerRole struct {
+	MasterHost string // Host of the master (replica only)
+	MasterPort int    // Port of the master (replica only)
+	Offset     int64  // Replication offset
+	Replicas   int    // Number of connected replicas (master only)
+	Role       string // RoleMaster, RoleReplica or RoleSentinel
+	State      string // Replication state with the master (IE: connected, replica only)
+}
+
+// Role returns the replication role of the server of the primary pool
+// If writes to a replica are refused (see: SetRefuseReplicaWrites()) the role also
+// re-enables or refuses writes (IE: call after a failover)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: RoleRaw()
+func (c *Client) Role(ctx context.Context) (*ServerRole, error) {
+	conn, err := c.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.CloseConnection(conn)
+
+	var role *ServerRole
+	if role, err = RoleRaw(conn); err != nil {
+		return nil, err
+	}
+	if c.RefusesReplicaWrites() {
+		c.setOnReplica(role.Role == RoleReplica)
+	}
+	return role, nil
+}
+
+// RoleRaw returns the replication role of the server
+// INFO replication is used if ROLE is not supported
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/role
+// https://redis.io/commands/info
+func RoleRaw(conn redis.Conn) (*ServerRole, error) {
+	reply, err := redis.Values(conn.Do(RoleCommand))
+	if isServerError(err) {
+		return roleFromInfo(conn)
+	} else if err != nil {
+		return nil, err
+	} else if len(reply) == 0 {
+		return nil, errors.New("empty role reply")
+	}
+
+	role := new(ServerRole)
+	if role.Role, err = redis.String(reply[0], nil); err != nil {
+		return nil, err
+	}
+	switch role.Role {
+	case RoleMaster:
+		// master, offset, [[host, port, offset], ...]
+		if len(reply) > 1 {
+			role.Offset, _ = redis.Int64(reply[1], nil)
+		}
+		if len(reply) > 2 {
+			replicas, _ := redis.Values(reply[2], nil)
+			role.Replicas = len(replicas)
+		}
+	case "slave", RoleReplica:
+		// slave, host, port, state, offset
+		role.Role = RoleReplica
+		if len(reply) > 4 {
+			role.MasterHost, _ = redis.String(reply[1], nil)
+			role.MasterPort, _ = redis.Int(reply[2], nil)
+			role.State, _ = redis.String(reply[3], nil)
+			role.Offset, _ = redis.Int64(reply[4], nil)
+		}
+	}
+	return role, nil
+}
+
+// roleFromInfo returns the replication role from INFO replication
+func roleFromInfo(conn redis.Conn) (*ServerRole, error) {
+	info, err := redis.String(conn.Do(InfoCommand, "replication"))
+	if err != nil {
+		return nil, err
+	}
+
+	role := new(ServerRole)
+	for _, line := range strings.Split(info, "\n") {
+		field, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if !found {
+			continue
+		}
+		switch field {
+		case "role":
+			role.Role = value
+			if value == "slave" {
+				role.Role = RoleReplica
+			}
+		case "master_host":
+			role.MasterHost = value
+		case "master_port":
+			role.MasterPort, _ = strconv.Atoi(value)
+		case "master_link_status":
+			role.State = value
+		case "master_repl_offset", "slave_repl_offset":
+			role.Offset, _ = strconv.ParseInt(value, 10, 64)
+		case "connected_slaves":
+			role.Replicas, _ = strconv.Atoi(value)
+		}
+	}
+	return role, nil
+}
+
+// SetRefuseReplicaWrites switches refusing writes to a replica on or off (safe to call at any time)
+//
+// When on, a READONLY error (IE: the DNS name still points to the old master after a failover)
+// marks the client as connected to a replica, and every following write returns ErrReplicaWrite
+// without being sent, until Role() reports a master again
+func (c *Client) SetRefuseReplicaWrites(refuse bool) {
+	var value uint32
+	if refuse {
+		value = 1
+	}
+	atomic.StoreUint32(&c.refuseReplicaWrite, value)
+	if !refuse {
+		c.setOnReplica(false)
+	}
+}
+
+// RefusesReplicaWrites returns true if writes to a replica are refused (see: SetRefuseReplicaWrites())
+func (c *Client) RefusesReplicaWrites() bool {
+	return atomic.LoadUint32(&c.refuseReplicaWrite) == 1
+}
+
+// IsOnReplica returns true if the primary pool turned out to be connected to a replica
+// (only detected if writes to a replica are refused, see: SetRefuseReplicaWrites())
+func (c *Client) IsOnReplica() bool {
+	return atomic.LoadUint32(&c.onReplica) == 1
+}
+
+// setOnReplica sets if the primary pool is connected to a replica
+func (c *Client) setOnReplica(onReplica bool) {
+	var value uint32
+	if onReplica {
+		value = 1
+	}
+	atomic.StoreUint32(&c.onReplica, value)
+}
+
+// isReplicaWriteError returns true if the error is a write against a replica
+func isReplicaWriteError(err error) bool {
+	var serverErr redis.Error
+	return errors.As(err, &serverErr) && strings.HasPrefix(string(serverErr), readOnlyErrorPrefix)
+}
+
+// replicaGuardConn refuses writes while the client is connected to a replica
+type replicaGuardConn struct {
+	redis.Conn
+	client *Client
+}
+
+// unwrap returns the wrapped connection
+func (c *replicaGuardConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs the command (ErrReplicaWrite for writes while connected to a replica)
+func (c *replicaGuardConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	if err := c.refuse(commandName); err != nil {
+		return nil, err
+	}
+	reply, err := c.Conn.Do(commandName, args...)
+	return reply, c.check(err)
+}
+
+// Send sends the command (ErrReplicaWrite for writes while connected to a replica)
+func (c *replicaGuardConn) Send(commandName string, args ...interface{}) error {
+	if err := c.refuse(commandName); err != nil {
+		return err
+	}
+	return c.Conn.Send(commandName, args...)
+}
+
+// DoWithTimeout runs the command with the timeout (ErrReplicaWrite for writes while connected to a replica)
+func (c *replicaGuardConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	if err := c.refuse(commandName); err != nil {
+		return nil, err
+	}
+	reply, err := redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+	return reply, c.check(err)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *replicaGuardConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	reply, err := redis.ReceiveWithTimeout(c.Conn, timeout)
+	return reply, c.check(err)
+}
+
+// refuse returns ErrReplicaWrite for writes while connected to a replica
+func (c *replicaGuardConn) refuse(commandName string) error {
+	if c.client.IsOnReplica() && isWriteCommand(commandName) {
+		return ErrReplicaWrite
+	}
+	return nil
+}
+
+// check marks the client as connected to a replica on a READONLY error
+func (c *replicaGuardConn) check(err error) error {
+	if !isReplicaWriteError(err) {
+		return err
+	}
+	c.client.setOnReplica(true)
+	return fmt.Errorf("%w: %s", ErrReplicaWrite, err.Error())
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Role tests the method Role()
+func TestClient_Role(t *testing.T) {
+
+	t.Run("master using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(RoleCommand).Expect([]interface{}{
+			[]byte("master"), int64(3129659), []interface{}{
+				[]interface{}{[]byte("127.0.0.1"), []byte("9001"), []byte("3129242")},
+				[]interface{}{[]byte("127.0.0.1"), []byte("9002"), []byte("3129543")},
+			},
+		})
+
+		role, err := client.Role(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, &ServerRole{Offset: 3129659, Replicas: 2, Role: RoleMaster}, role)
+	})
+
+	t.Run("replica using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(RoleCommand).Expect([]interface{}{
+			[]byte("slave"), []byte("127.0.0.1"), int64(6380), []byte("connected"), int64(3167038),
+		})
+
+		role, err := client.Role(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, &ServerRole{
+			MasterHost: "127.0.0.1",
+			MasterPort: 6380,
+			Offset:     3167038,
+			Role:       RoleReplica,
+			State:      "connected",
+		}, role)
+	})
+
+	t.Run("falls back to info using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(RoleCommand).ExpectError(redis.Error("ERR unknown command 'ROLE'"))
+		conn.Command(InfoCommand, "replication").Expect([]byte("# Replication\r\nrole:slave\r\n" +
+			"master_host:10.0.0.1\r\nmaster_port:6379\r\nmaster_link_status:up\r\nslave_repl_offset:42\r\n"))
+
+		role, err := client.Role(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, &ServerRole{
+			MasterHost: "10.0.0.1",
+			MasterPort: 6379,
+			Offset:     42,
+			Role:       RoleReplica,
+			State:      "up",
+		}, role)
+	})
+}
+
+// TestClient_SetRefuseReplicaWrites tests the method SetRefuseReplicaWrites()
+func TestClient_SetRefuseReplicaWrites(t *testing.T) {
+
+	t.Run("writes are refused after a READONLY error", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue).
+			ExpectError(redis.Error("READONLY You can't write against a read only replica."))
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		client.SetRefuseReplicaWrites(true)
+		assert.True(t, client.RefusesReplicaWrites())
+		assert.False(t, client.IsOnReplica())
+
+		// The server reports the replica
+		err := Set(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrReplicaWrite)
+		assert.True(t, client.IsOnReplica())
+		assert.Equal(t, 1, conn.Stats(setCmd))
+
+		// Writes are refused without being sent
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrReplicaWrite)
+		assert.Equal(t, 1, conn.Stats(setCmd))
+
+		// Reads continue to work
+		var value string
+		value, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+	})
+
+	t.Run("role re-enables writes after a failover", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(RoleCommand).Expect([]interface{}{[]byte("master"), int64(0), []interface{}{}})
+
+		client.SetRefuseReplicaWrites(true)
+		client.setOnReplica(true)
+
+		_, err := client.Role(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, client.IsOnReplica())
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, testKey, testStringValue).
+			ExpectError(redis.Error("READONLY You can't write against a read only replica."))
+
+		err := Set(context.Background(), client, testKey, testStringValue)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrReplicaWrite)
+		assert.False(t, client.IsOnReplica())
+	})
+}
+
+// ExampleClient_Role is an example of the method Role()
+func ExampleClient_Role() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the role command
+	conn.Command(RoleCommand).Expect([]interface{}{
+		[]byte("slave"), []byte("127.0.0.1"), int64(6380), []byte("connected"), int64(100),
+	})
+
+	// Check the server before writing
+	role, _ := client.Role(context.Background())
+	fmt.Printf("role: %s master: %s:%d", role.Role, role.MasterHost, role.MasterPort)
+	// Output:role: replica master: 127.0.0.1:6380
+}
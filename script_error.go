@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// scriptLinePattern finds the line of the script in a Redis error (IE: "user_script:3: ...")
+var scriptLinePattern = regexp.MustCompile(`user_script:(\d+):`)
+
+// ScriptError is the error for a Lua script that failed on the server (EVALSHA / EVAL)
+type ScriptError struct {
+	Err      error  // Error from Redis
+	KeyCount int    // Keys given to the script
+	Line     int    // Line of the script reported by Redis (0 if not reported)
+	Name     string // Name of the script
+	SHA      string // SHA1 of the script
+}
+
+// Error returns the error message
+func (e *ScriptError) Error() string {
+	message := "script " + e.Name + " (" + e.SHA + ", " + strconv.Itoa(e.KeyCount) + " keys) failed"
+	if e.Line > 0 {
+		message += " at line " + strconv.Itoa(e.Line)
+	}
+	return message + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// newScriptError wraps an error from Redis in a *ScriptError (other errors are returned as is)
+func newScriptError(err error, name, sha string, keyCount int) error {
+	var redisErr redis.Error
+	if err == nil || !errors.As(err, &redisErr) {
+		return err
+	}
+	scriptErr := &ScriptError{Err: err, KeyCount: keyCount, Name: name, SHA: sha}
+	if match := scriptLinePattern.FindStringSubmatch(redisErr.Error()); match != nil {
+		scriptErr.Line, _ = strconv.Atoi(match[1])
+	}
+	return scriptErr
+}
+
+// namedScript is a Lua script with a name for its errors (see: ScriptError)
+type namedScript struct {
+	*redis.Script
+	keyCount int
+	name     string
+}
+
+// newScript returns a named script, a negative keyCount means the key count is the first argument
+func newScript(name string, keyCount int, src string) *namedScript {
+	return &namedScript{Script: redis.NewScript(keyCount, src), keyCount: keyCount, name: name}
+}
+
+// Do runs the script (EVALSHA with a fallback to EVAL), a failed script returns a *ScriptError
+func (s *namedScript) Do(conn redis.Conn, keysAndArgs ...interface{}) (interface{}, error) {
+	reply, err := s.Script.Do(conn, keysAndArgs...)
+	if err == nil {
+		return reply, nil
+	}
+	keyCount := s.keyCount
+	if keyCount < 0 && len(keysAndArgs) > 0 {
+		keyCount, _ = keysAndArgs[0].(int)
+	}
+	return reply, newScriptError(err, s.name, s.Hash(), keyCount)
+}
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestScriptError tests the errors of failed scripts
+func TestScriptError(t *testing.T) {
+
+	t.Run("script error using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		redisErr := redis.Error("ERR Error running script (call to f_x): @user_script:3: user_script:3: bad argument")
+		conn.Script([]byte(lockScript), 1, "my-lock", "the-secret", int64(10)).ExpectError(redisErr)
+
+		_, err := WriteLock(context.Background(), client, "my-lock", "the-secret", int64(10))
+		var scriptErr *ScriptError
+		assert.ErrorAs(t, err, &scriptErr)
+		assert.ErrorIs(t, err, redisErr)
+		assert.Equal(t, "lock", scriptErr.Name)
+		assert.Equal(t, redis.NewScript(1, lockScript).Hash(), scriptErr.SHA)
+		assert.Equal(t, 1, scriptErr.KeyCount)
+		assert.Equal(t, 3, scriptErr.Line)
+		assert.Contains(t, scriptErr.Error(), "script lock (")
+		assert.Contains(t, scriptErr.Error(), "at line 3")
+	})
+
+	t.Run("no line reported", func(t *testing.T) {
+		t.Parallel()
+
+		script := newScript("test", -1, "return 1")
+		err := newScriptError(redis.Error("ERR failed"), script.name, script.Hash(), 2)
+		var scriptErr *ScriptError
+		assert.ErrorAs(t, err, &scriptErr)
+		assert.Equal(t, 0, scriptErr.Line)
+		assert.NotContains(t, scriptErr.Error(), "at line")
+	})
+
+	t.Run("connection errors are not wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		errConnection := errors.New("connection reset")
+		assert.Equal(t, errConnection, newScriptError(errConnection, "lock", "sha", 1))
+		assert.NoError(t, newScriptError(nil, "lock", "sha", 1))
+	})
+
+	t.Run("kill by dependency using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+"dep-1").
+			ExpectError(redis.Error("NOSCRIPT No matching script"))
+		conn.Command(DeleteCommand, "dep-1").Expect(int64(0))
+
+		_, err := KillByDependency(context.Background(), client, "dep-1")
+		var scriptErr *ScriptError
+		assert.ErrorAs(t, err, &scriptErr)
+		assert.Equal(t, killByDependencyName, scriptErr.Name)
+		assert.Equal(t, killByDependencySha, scriptErr.SHA)
+	})
+
+	t.Run("script error using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// An expiration of zero fails inside the script
+		_, err = WriteLock(context.Background(), client, "my-lock", "the-secret", int64(0))
+		var scriptErr *ScriptError
+		assert.ErrorAs(t, err, &scriptErr)
+		assert.Equal(t, "lock", scriptErr.Name)
+	})
+}
+
+// ExampleScriptError is an example of the error ScriptError
+func ExampleScriptError() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a failed script
+	conn.Script([]byte(lockScript), 1, "my-lock", "the-secret", int64(10)).
+		ExpectError(redis.Error("ERR user_script:5: attempt to compare nil with number"))
+
+	_, err := WriteLock(context.Background(), client, "my-lock", "the-secret", int64(10))
+	var scriptErr *ScriptError
+	if errors.As(err, &scriptErr) {
+		fmt.Printf("script %s failed at line %d", scriptErr.Name, scriptErr.Line)
+	}
+	// Output:script lock failed at line 5
+}
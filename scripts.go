@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gomodule/redigo/redis"
 )
@@ -47,9 +48,79 @@ func RegisterScriptRaw(client *Client, conn redis.Conn, script string) (sha stri
 		return
 	}
 	client.ScriptsLoaded = append(client.ScriptsLoaded, sha)
+	client.mu.Lock()
+	if client.scripts == nil {
+		client.scripts = make(map[string]string)
+	}
+	client.scripts[sha] = script
+	client.mu.Unlock()
 	return
 }
 
+// ScriptsLoaded returns if each registered script (see: Client.ScriptsLoaded) is loaded on the
+// server by SHA (IE: scripts are missing after SCRIPT FLUSH or a failover, see: ReloadScripts())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ScriptsLoadedRaw()
+func ScriptsLoaded(ctx context.Context, client *Client) (map[string]bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return ScriptsLoadedRaw(conn, client.ScriptsLoaded...)
+}
+
+// ScriptsLoadedRaw returns if each script is loaded on the server by SHA
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/script-exists
+func ScriptsLoadedRaw(conn redis.Conn, shas ...string) (map[string]bool, error) {
+	loaded := make(map[string]bool, len(shas))
+	if len(shas) == 0 {
+		return loaded, nil
+	}
+	exists, err := redis.Ints(conn.Do(ScriptCommand, redis.Args{}.Add(ExistsArgument).AddFlat(shas)...))
+	if err != nil {
+		return nil, err
+	}
+	for i, sha := range shas {
+		loaded[sha] = i < len(exists) && exists[i] == 1
+	}
+	return loaded, nil
+}
+
+// ReloadScripts loads every script registered by the client again (IE: after SCRIPT FLUSH or a
+// failover to a server without the scripts), see: ScriptsLoaded()
+//
+// Spec: https://redis.io/commands/script-load
+func (c *Client) ReloadScripts(ctx context.Context) error {
+	c.mu.RLock()
+	scripts := make([]string, 0, len(c.scripts))
+	for _, script := range c.scripts {
+		scripts = append(scripts, script)
+	}
+	c.mu.RUnlock()
+	if len(scripts) == 0 {
+		return nil
+	}
+
+	conn, err := c.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.CloseConnection(conn)
+	for _, script := range scripts {
+		if _, err = conn.Do(ScriptCommand, LoadCommand, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// killByDependencyName is the name of the below script in a ScriptError
+const killByDependencyName = "kill_by_dependency"
+
 // killByDependencySha is the SHA of the below script
 const killByDependencySha = "a648f768f57e73e2497ccaa113d5ad9e731c5cd8"
 
@@ -71,3 +142,74 @@ end
 return redis.call("` + DeleteCommand + `", unpack(all_keys))
 --@end=lua@
 `
+
+// evalScriptName is the name of the scripts run by Eval() in a ScriptError
+const evalScriptName = "eval"
+
+// scriptCache are the scripts by source
+type scriptCache map[string]*namedScript
+
+// Eval runs a Lua script with the keys and args (EVALSHA with a fallback to EVAL if the script
+// is not loaded), the SHA of the script is cached by the client and the script is loaded again
+// by ReloadScripts(). Scripts are cached by source, so use constant scripts and pass values as args.
+// A failed script returns a *ScriptError
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: EvalRaw()
+func Eval(ctx context.Context, client *Client, script string, keys []string,
+	args ...interface{}) (interface{}, error) {
+	if len(script) == 0 {
+		return nil, errors.New("missing required parameter: script")
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return runEval(conn, client.evalScript(script), keys, args)
+}
+
+// EvalRaw runs a Lua script with the keys and args (EVALSHA with a fallback to EVAL if the script
+// is not loaded), the SHA is computed on each call
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/eval
+func EvalRaw(conn redis.Conn, script string, keys []string, args ...interface{}) (interface{}, error) {
+	if len(script) == 0 {
+		return nil, errors.New("missing required parameter: script")
+	}
+	return runEval(conn, newScript(evalScriptName, -1, script), keys, args)
+}
+
+// runEval runs the script with the key count, keys and args
+func runEval(conn redis.Conn, script *namedScript, keys []string, args []interface{}) (interface{}, error) {
+	return script.Do(conn, redis.Args{}.Add(len(keys)).AddFlat(keys).Add(args...)...)
+}
+
+// evalScript returns the cached script of the source, registering it for ReloadScripts()
+func (c *Client) evalScript(source string) *namedScript {
+	c.mu.RLock()
+	script, ok := c.evalScripts[source]
+	c.mu.RUnlock()
+	if ok {
+		return script
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if script, ok = c.evalScripts[source]; ok {
+		return script
+	}
+	if c.evalScripts == nil {
+		c.evalScripts = make(scriptCache)
+	}
+	if c.scripts == nil {
+		c.scripts = make(map[string]string)
+	}
+	script = newScript(evalScriptName, -1, source)
+	c.evalScripts[source] = script
+	c.scripts[script.Hash()] = source
+	return script
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gomodule/redigo/redis"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -201,3 +202,189 @@ func ExampleRegisterScript() {
 	fmt.Printf("registered: %s", testKillDependencyHash)
 	// Output:registered: a648f768f57e73e2497ccaa113d5ad9e731c5cd8
 }
+
+// TestScriptsLoaded tests the methods ScriptsLoaded() and ReloadScripts()
+func TestScriptsLoaded(t *testing.T) {
+
+	t.Run("scripts loaded using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(ScriptCommand, LoadCommand, killByDependencyLua).Expect(testKillDependencyHash)
+		conn.Command(ScriptCommand, ExistsArgument, testKillDependencyHash).Expect([]interface{}{int64(0)})
+
+		_, err := RegisterScript(context.Background(), client, killByDependencyLua)
+		assert.NoError(t, err)
+
+		var loaded map[string]bool
+		loaded, err = ScriptsLoaded(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]bool{testKillDependencyHash: false}, loaded)
+
+		// Loaded again
+		conn.Clear()
+		loadCmd := conn.Command(ScriptCommand, LoadCommand, killByDependencyLua).Expect(testKillDependencyHash)
+		err = client.ReloadScripts(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, loadCmd.Called)
+	})
+
+	t.Run("no scripts registered", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		loaded, err := ScriptsLoaded(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Empty(t, loaded)
+		assert.NoError(t, client.ReloadScripts(context.Background()))
+	})
+
+	t.Run("reload after a flush using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		_, err = RegisterScript(context.Background(), client, killByDependencyLua)
+		assert.NoError(t, err)
+
+		_, err = conn.Do(ScriptCommand, "FLUSH")
+		assert.NoError(t, err)
+
+		var loaded map[string]bool
+		loaded, err = ScriptsLoaded(context.Background(), client)
+		assert.NoError(t, err)
+		assert.False(t, loaded[testKillDependencyHash])
+
+		err = client.ReloadScripts(context.Background())
+		assert.NoError(t, err)
+
+		loaded, err = ScriptsLoaded(context.Background(), client)
+		assert.NoError(t, err)
+		assert.True(t, loaded[testKillDependencyHash])
+	})
+}
+
+// ExampleScriptsLoaded is an example of the method ScriptsLoaded()
+func ExampleScriptsLoaded() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script commands (the script was flushed)
+	conn.Command(ScriptCommand, LoadCommand, killByDependencyLua).Expect(testKillDependencyHash)
+	conn.Command(ScriptCommand, ExistsArgument, testKillDependencyHash).Expect([]interface{}{int64(0)})
+	_, _ = RegisterScript(context.Background(), client, killByDependencyLua)
+
+	// Reload the scripts if any are missing
+	loaded, _ := ScriptsLoaded(context.Background(), client)
+	for sha, ok := range loaded {
+		if !ok {
+			fmt.Printf("reloading: %s", sha)
+			_ = client.ReloadScripts(context.Background())
+		}
+	}
+	// Output:reloading: a648f768f57e73e2497ccaa113d5ad9e731c5cd8
+}
+
+// testEvalScript is a script for the Eval() tests
+const testEvalScript = `return redis.call("SET", KEYS[1], ARGV[1])`
+
+// TestEval tests the methods Eval() and EvalRaw()
+func TestEval(t *testing.T) {
+
+	t.Run("missing script", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := Eval(context.Background(), client, "", nil)
+		assert.Error(t, err)
+		_, err = EvalRaw(conn, "", nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("eval using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		evalCmd := conn.Script([]byte(testEvalScript), 1, testKey, testStringValue).Expect("OK")
+
+		for i := 0; i < 2; i++ {
+			reply, err := redis.String(Eval(context.Background(), client, testEvalScript,
+				[]string{testKey}, testStringValue))
+			assert.NoError(t, err)
+			assert.Equal(t, "OK", reply)
+		}
+		assert.Equal(t, 2, conn.Stats(evalCmd))
+
+		// The SHA is cached and the script is reloaded by ReloadScripts()
+		assert.Equal(t, 1, len(client.evalScripts))
+		assert.Equal(t, testEvalScript, client.scripts[redis.NewScript(1, testEvalScript).Hash()])
+	})
+
+	t.Run("eval using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Not loaded (EVAL), then loaded (EVALSHA)
+		for i := 0; i < 2; i++ {
+			_, err = Eval(context.Background(), client, testEvalScript, []string{testKey}, testStringValue)
+			assert.NoError(t, err)
+		}
+
+		var value string
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var count int
+		count, err = redis.Int(EvalRaw(conn, `return #KEYS + #ARGV`, []string{"a", "b"}, 1, 2, 3))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+
+		_, err = EvalRaw(conn, `return redis.call("INCR", KEYS[1])`, []string{testKey})
+		var scriptErr *ScriptError
+		assert.ErrorAs(t, err, &scriptErr)
+		assert.Equal(t, "eval", scriptErr.Name)
+		assert.Equal(t, 1, scriptErr.KeyCount)
+	})
+}
+
+// ExampleEval is an example of the method Eval()
+func ExampleEval() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script
+	conn.Script([]byte(testEvalScript), 1, testKey, testStringValue).Expect("OK")
+
+	reply, _ := redis.String(Eval(context.Background(), client, testEvalScript, []string{testKey}, testStringValue))
+	fmt.Printf("reply: %s", reply)
+	// Output:reply: OK
+}
@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Package constants (RediSearch module commands)
+const (
+	SearchAggregateCommand string = "FT.AGGREGATE"
+	SearchCommand          string = "FT.SEARCH"
+	SearchCreateCommand    string = "FT.CREATE"
+	SearchDropIndexCommand string = "FT.DROPINDEX"
+)
+
+// SearchFieldType is the type of indexed field
+type SearchFieldType string
+
+// Search field types
+const (
+	SearchFieldNumeric SearchFieldType = "NUMERIC"
+	SearchFieldTag     SearchFieldType = "TAG"
+	SearchFieldText    SearchFieldType = "TEXT"
+)
+
+// SearchField is a field of the index schema
+type SearchField struct {
+	Name     string          // Name of the hash field
+	Sortable bool            // Allows SortBy on the field
+	Type     SearchFieldType // Type of field (text, numeric, tag)
+}
+
+// SearchIndex is the definition of an index on hashes (IE: written via HashSetStruct())
+type SearchIndex struct {
+	Fields   []SearchField // Schema of the index
+	Name     string        // Name of the index
+	Prefixes []string      // Key prefixes of the hashes to index (all hashes if empty)
+}
+
+// SearchOptions are the optional arguments of a search
+type SearchOptions struct {
+	Limit          int      // Max documents to return (default: 10)
+	Offset         int      // Number of documents to skip
+	Return         []string // Fields to return (all fields if empty)
+	SortBy         string   // Sortable field to sort by
+	SortDescending bool     // Sort by the field descending
+}
+
+// SearchDocument is a document found by a search
+type SearchDocument struct {
+	Fields map[string]string // Returned fields of the hash
+	ID     string            // Key of the hash
+}
+
+// SearchResult is the result of a search
+type SearchResult struct {
+	Documents []SearchDocument // Documents of this page
+	Total     int64            // Total number of matching documents
+}
+
+// CreateSearchIndex creates the index on hashes (requires the RediSearch module)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: CreateSearchIndexRaw()
+func CreateSearchIndex(ctx context.Context, client *Client, index *SearchIndex) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return CreateSearchIndexRaw(conn, index)
+}
+
+// CreateSearchIndexRaw creates the index on hashes (requires the RediSearch module)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/ft.create
+func CreateSearchIndexRaw(conn redis.Conn, index *SearchIndex) (err error) {
+	if index == nil || len(index.Name) == 0 {
+		return errors.New("missing required parameter: index name")
+	} else if len(index.Fields) == 0 {
+		return errors.New("missing required parameter: index fields")
+	}
+
+	args := redis.Args{}.Add(index.Name, "ON", "HASH")
+	if len(index.Prefixes) > 0 {
+		args = args.Add("PREFIX", len(index.Prefixes)).AddFlat(index.Prefixes)
+	}
+	args = args.Add("SCHEMA")
+	for _, field := range index.Fields {
+		args = args.Add(field.Name, string(field.Type))
+		if field.Sortable {
+			args = args.Add("SORTABLE")
+		}
+	}
+	_, err = conn.Do(SearchCreateCommand, args...)
+	return
+}
+
+// DropSearchIndex removes the index, and optionally all the indexed hashes
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: DropSearchIndexRaw()
+func DropSearchIndex(ctx context.Context, client *Client, name string, deleteDocuments bool) error {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return DropSearchIndexRaw(conn, name, deleteDocuments)
+}
+
+// DropSearchIndexRaw removes the index, and optionally all the indexed hashes
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/ft.dropindex
+func DropSearchIndexRaw(conn redis.Conn, name string, deleteDocuments bool) (err error) {
+	if deleteDocuments {
+		_, err = conn.Do(SearchDropIndexCommand, name, "DD")
+	} else {
+		_, err = conn.Do(SearchDropIndexCommand, name)
+	}
+	return
+}
+
+// Search runs the query against the index
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SearchRaw()
+func Search(ctx context.Context, client *Client, index, query string, options *SearchOptions) (*SearchResult, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return SearchRaw(conn, index, query, options)
+}
+
+// SearchRaw runs the query against the index
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/ft.search
+func SearchRaw(conn redis.Conn, index, query string, options *SearchOptions) (*SearchResult, error) {
+	args := redis.Args{}.Add(index, query)
+	if options != nil {
+		if len(options.Return) > 0 {
+			args = args.Add("RETURN", len(options.Return)).AddFlat(options.Return)
+		}
+		if len(options.SortBy) > 0 {
+			args = args.Add("SORTBY", options.SortBy)
+			if options.SortDescending {
+				args = args.Add("DESC")
+			}
+		}
+		if options.Limit > 0 || options.Offset > 0 {
+			limit := options.Limit
+			if limit <= 0 {
+				limit = 10
+			}
+			args = args.Add("LIMIT", options.Offset, limit)
+		}
+	}
+
+	values, err := redis.Values(conn.Do(SearchCommand, args...))
+	if err != nil {
+		return nil, err
+	}
+	return parseSearchResult(values)
+}
+
+// SearchAggregate runs the aggregation query (IE: GROUPBY, REDUCE) against the index
+// Each row of the result is a map of the returned fields
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SearchAggregateRaw()
+func SearchAggregate(ctx context.Context, client *Client, index, query string,
+	args ...interface{}) ([]map[string]string, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return SearchAggregateRaw(conn, index, query, args...)
+}
+
+// SearchAggregateRaw runs the aggregation query (IE: GROUPBY, REDUCE) against the index
+// Each row of the result is a map of the returned fields
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/ft.aggregate
+func SearchAggregateRaw(conn redis.Conn, index, query string, args ...interface{}) ([]map[string]string, error) {
+	values, err := redis.Values(conn.Do(SearchAggregateCommand, redis.Args{}.Add(index, query).Add(args...)...))
+	if err != nil {
+		return nil, err
+	} else if len(values) == 0 {
+		return nil, errors.New("invalid search aggregate reply")
+	}
+
+	// The first value is the number of groups, followed by each row
+	rows := make([]map[string]string, 0, len(values)-1)
+	for _, value := range values[1:] {
+		var row map[string]string
+		if row, err = redis.StringMap(value, nil); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseSearchResult parses the reply of FT.SEARCH: total, then the id and fields of each document
+func parseSearchResult(values []interface{}) (*SearchResult, error) {
+	if len(values) == 0 {
+		return nil, errors.New("invalid search reply")
+	}
+	total, err := redis.Int64(values[0], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SearchResult{Total: total}
+	for i := 1; i < len(values); i++ {
+		var document SearchDocument
+		if document.ID, err = redis.String(values[i], nil); err != nil {
+			return nil, fmt.Errorf("invalid search document id: %w", err)
+		}
+
+		// Fields are omitted when the query uses NOCONTENT
+		if i+1 < len(values) {
+			if fields, ok := values[i+1].([]interface{}); ok {
+				if document.Fields, err = redis.StringMap(fields, nil); err != nil {
+					return nil, err
+				}
+				i++
+			}
+		}
+		result.Documents = append(result.Documents, document)
+	}
+	return result, nil
+}
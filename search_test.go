@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testSearchIndex is the index used for testing
+var testSearchIndex = &SearchIndex{
+	Fields: []SearchField{
+		{Name: "name", Type: SearchFieldText},
+		{Name: "price", Type: SearchFieldNumeric, Sortable: true},
+	},
+	Name:     "idx:products",
+	Prefixes: []string{"product:"},
+}
+
+// TestCreateSearchIndex tests the method CreateSearchIndex()
+func TestCreateSearchIndex(t *testing.T) {
+
+	t.Run("missing index name or fields", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := CreateSearchIndex(context.Background(), client, nil)
+		assert.Error(t, err)
+
+		err = CreateSearchIndex(context.Background(), client, &SearchIndex{Name: "idx:empty"})
+		assert.Error(t, err)
+	})
+
+	t.Run("create index using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		createCmd := conn.Command(
+			SearchCreateCommand, "idx:products", "ON", "HASH", "PREFIX", 1, "product:",
+			"SCHEMA", "name", "TEXT", "price", "NUMERIC", "SORTABLE",
+		).Expect("OK")
+
+		err := CreateSearchIndex(context.Background(), client, testSearchIndex)
+		assert.NoError(t, err)
+		assert.True(t, createCmd.Called)
+	})
+}
+
+// ExampleCreateSearchIndex is an example of the method CreateSearchIndex()
+func ExampleCreateSearchIndex() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the create command
+	conn.GenericCommand(SearchCreateCommand).Expect("OK")
+
+	// Index the product hashes
+	err := CreateSearchIndex(context.Background(), client, testSearchIndex)
+	fmt.Printf("created: %v", err == nil)
+	// Output:created: true
+}
+
+// TestDropSearchIndex tests the method DropSearchIndex()
+func TestDropSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	dropCmd := conn.Command(SearchDropIndexCommand, "idx:products").Expect("OK")
+	dropAllCmd := conn.Command(SearchDropIndexCommand, "idx:products", "DD").Expect("OK")
+
+	err := DropSearchIndex(context.Background(), client, "idx:products", false)
+	assert.NoError(t, err)
+	assert.True(t, dropCmd.Called)
+
+	err = DropSearchIndex(context.Background(), client, "idx:products", true)
+	assert.NoError(t, err)
+	assert.True(t, dropAllCmd.Called)
+}
+
+// TestSearch tests the method Search()
+func TestSearch(t *testing.T) {
+
+	t.Run("search with options using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(
+			SearchCommand, "idx:products", "@price:[0 10]",
+			"RETURN", 1, "name", "SORTBY", "price", "DESC", "LIMIT", 0, 2,
+		).Expect([]interface{}{
+			int64(3),
+			[]byte("product:1"), []interface{}{[]byte("name"), []byte("widget")},
+			[]byte("product:2"), []interface{}{[]byte("name"), []byte("gadget")},
+		})
+
+		result, err := Search(context.Background(), client, "idx:products", "@price:[0 10]", &SearchOptions{
+			Limit:          2,
+			Return:         []string{"name"},
+			SortBy:         "price",
+			SortDescending: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), result.Total)
+		assert.Equal(t, []SearchDocument{
+			{ID: "product:1", Fields: map[string]string{"name": "widget"}},
+			{ID: "product:2", Fields: map[string]string{"name": "gadget"}},
+		}, result.Documents)
+	})
+
+	t.Run("search without content", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SearchCommand, "idx:products", "widget").
+			Expect([]interface{}{int64(2), []byte("product:1"), []byte("product:3")})
+
+		result, err := Search(context.Background(), client, "idx:products", "widget", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Total)
+		assert.Equal(t, []SearchDocument{{ID: "product:1"}, {ID: "product:3"}}, result.Documents)
+	})
+
+	t.Run("invalid reply", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SearchCommand, "idx:products", "widget").Expect([]interface{}{})
+
+		_, err := Search(context.Background(), client, "idx:products", "widget", nil)
+		assert.Error(t, err)
+	})
+}
+
+// ExampleSearch is an example of the method Search()
+func ExampleSearch() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the search command
+	conn.GenericCommand(SearchCommand).Expect([]interface{}{
+		int64(1), []byte("product:1"), []interface{}{[]byte("name"), []byte("widget")},
+	})
+
+	// Find the products
+	result, _ := Search(context.Background(), client, "idx:products", "@name:widget", nil)
+	fmt.Printf("found: %d first: %s", result.Total, result.Documents[0].Fields["name"])
+	// Output:found: 1 first: widget
+}
+
+// TestSearchAggregate tests the method SearchAggregate()
+func TestSearchAggregate(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(
+		SearchAggregateCommand, "idx:products", "*", "GROUPBY", 1, "@name", "REDUCE", "COUNT", 0, "AS", "total",
+	).Expect([]interface{}{
+		int64(2),
+		[]interface{}{[]byte("name"), []byte("widget"), []byte("total"), []byte("4")},
+		[]interface{}{[]byte("name"), []byte("gadget"), []byte("total"), []byte("1")},
+	})
+
+	rows, err := SearchAggregate(
+		context.Background(), client, "idx:products", "*", "GROUPBY", 1, "@name", "REDUCE", "COUNT", 0, "AS", "total",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"name": "widget", "total": "4"},
+		{"name": "gadget", "total": "1"},
+	}, rows)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SequencePrefix is the prefix for all sequence keys
+const SequencePrefix = "sequence:"
+
+// ErrInvalidBatchSize is returned when the size of an id batch is not positive
+var ErrInvalidBatchSize = errors.New("batch size must be greater than zero")
+
+// NextID returns the next id of the sequence (starting at 1), unique across all clients
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: NextIDRaw()
+func NextID(ctx context.Context, client *Client, sequenceName string) (int64, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return NextIDRaw(conn, sequenceName)
+}
+
+// NextIDRaw returns the next id of the sequence (starting at 1), unique across all clients
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/incr
+func NextIDRaw(conn redis.Conn, sequenceName string) (int64, error) {
+	if len(sequenceName) == 0 {
+		return 0, errors.New("missing required parameter: sequence name")
+	}
+	return redis.Int64(conn.Do(IncrementCommand, SequencePrefix+sequenceName))
+}
+
+// NextIDBatch reserves the next n ids of the sequence in a single command and returns them in order
+// (IE: for bulk inserts)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: NextIDBatchRaw()
+func NextIDBatch(ctx context.Context, client *Client, sequenceName string, n int) ([]int64, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return NextIDBatchRaw(conn, sequenceName, n)
+}
+
+// NextIDBatchRaw reserves the next n ids of the sequence in a single command and returns them in order
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/incrby
+func NextIDBatchRaw(conn redis.Conn, sequenceName string, n int) ([]int64, error) {
+	if len(sequenceName) == 0 {
+		return nil, errors.New("missing required parameter: sequence name")
+	} else if n <= 0 {
+		return nil, ErrInvalidBatchSize
+	}
+	last, err := redis.Int64(conn.Do(IncrementByCommand, SequencePrefix+sequenceName, n))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = last - int64(n-1-i)
+	}
+	return ids, nil
+}
+
+// NextEpochID returns the next id of the sequence prefixed with the current unix time
+// (see: FormatEpochID()), using the clock of the client
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: NextEpochIDRaw()
+func NextEpochID(ctx context.Context, client *Client, sequenceName string) (string, error) {
+	id, err := NextID(ctx, client, sequenceName)
+	if err != nil {
+		return "", err
+	}
+	return FormatEpochID(client.Clock().Now(), id), nil
+}
+
+// NextEpochIDRaw returns the next id of the sequence prefixed with the current unix time
+// (see: FormatEpochID())
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/incr
+func NextEpochIDRaw(conn redis.Conn, sequenceName string) (string, error) {
+	id, err := NextIDRaw(conn, sequenceName)
+	if err != nil {
+		return "", err
+	}
+	return FormatEpochID(time.Now(), id), nil
+}
+
+// FormatEpochID formats the id prefixed with the unix time in seconds (IE: "1700000000-42")
+func FormatEpochID(t time.Time, id int64) string {
+	return strconv.FormatInt(t.Unix(), 10) + "-" + strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestNextID tests the methods NextID() and NextIDBatch()
+func TestNextID(t *testing.T) {
+
+	t.Run("missing sequence name", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := NextID(context.Background(), client, "")
+		assert.Error(t, err)
+
+		_, err = NextIDBatch(context.Background(), client, "", 2)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid batch size", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		ids, err := NextIDBatch(context.Background(), client, "orders", 0)
+		assert.ErrorIs(t, err, ErrInvalidBatchSize)
+		assert.Nil(t, ids)
+	})
+
+	t.Run("next id using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(IncrementCommand, SequencePrefix+"orders").Expect(int64(42))
+		conn.Command(IncrementByCommand, SequencePrefix+"orders", 3).Expect(int64(45))
+
+		id, err := NextID(context.Background(), client, "orders")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), id)
+
+		var ids []int64
+		ids, err = NextIDBatch(context.Background(), client, "orders", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{43, 44, 45}, ids)
+	})
+
+	t.Run("epoch id using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetClock(NewManualClock(time.Unix(1700000000, 0)))
+
+		conn.Command(IncrementCommand, SequencePrefix+"orders").Expect(int64(7))
+
+		id, err := NextEpochID(context.Background(), client, "orders")
+		assert.NoError(t, err)
+		assert.Equal(t, "1700000000-7", id)
+	})
+
+	t.Run("sequence using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		var id int64
+		id, err = NextIDRaw(conn, "orders")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+
+		var ids []int64
+		ids, err = NextIDBatchRaw(conn, "orders", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{2, 3, 4}, ids)
+
+		var epochID string
+		epochID, err = NextEpochIDRaw(conn, "orders")
+		assert.NoError(t, err)
+		assert.Contains(t, epochID, "-5")
+
+		// Sequences are independent
+		id, err = NextIDRaw(conn, "invoices")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+}
+
+// ExampleNextID is an example of the method NextID()
+func ExampleNextID() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the increment command
+	conn.Command(IncrementCommand, SequencePrefix+"orders").Expect(int64(1))
+
+	// Get the next order id
+	id, _ := NextID(context.Background(), client, "orders")
+	fmt.Printf("order id: %d", id)
+	// Output:order id: 1
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ErrClockSkew is returned when the skew between the local clock and the server exceeds the threshold
+var ErrClockSkew = errors.New("clock skew exceeds the threshold")
+
+// ServerTime returns the current time of the server
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: ServerTimeRaw()
+func ServerTime(ctx context.Context, client *Client) (time.Time, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer client.CloseConnection(conn)
+	return ServerTimeRaw(conn)
+}
+
+// ServerTimeRaw returns the current time of the server
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/time
+func ServerTimeRaw(conn redis.Conn) (time.Time, error) {
+	values, err := redis.Int64s(conn.Do(TimeCommand))
+	if err != nil {
+		return time.Time{}, err
+	} else if len(values) != 2 {
+		return time.Time{}, errors.New("invalid time reply")
+	}
+	return time.Unix(values[0], values[1]*int64(time.Microsecond)), nil
+}
+
+// SkewCheck returns the skew between the server and the clock of the client (positive if the
+// server is ahead), adjusted for half of the round trip
+// Returns ErrClockSkew (with the skew) if the skew exceeds the threshold in either direction
+// (IE: locks and TTL-sensitive logic assume roughly synchronized clocks)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SkewCheckRaw()
+func SkewCheck(ctx context.Context, client *Client, threshold time.Duration) (time.Duration, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return skewCheck(conn, client.Clock(), threshold)
+}
+
+// SkewCheckRaw returns the skew between the server and the local clock (positive if the
+// server is ahead), adjusted for half of the round trip
+// Returns ErrClockSkew (with the skew) if the skew exceeds the threshold in either direction
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/time
+func SkewCheckRaw(conn redis.Conn, threshold time.Duration) (time.Duration, error) {
+	return skewCheck(conn, SystemClock, threshold)
+}
+
+// skewCheck compares the time of the server with the clock at the middle of the round trip
+func skewCheck(conn redis.Conn, clock Clock, threshold time.Duration) (time.Duration, error) {
+	start := clock.Now()
+	serverTime, err := ServerTimeRaw(conn)
+	if err != nil {
+		return 0, err
+	}
+	end := clock.Now()
+	skew := serverTime.Sub(start.Add(end.Sub(start) / 2))
+	if skew > threshold || skew < -threshold {
+		return skew, fmt.Errorf("%w: skew of %s exceeds %s", ErrClockSkew, skew, threshold)
+	}
+	return skew, nil
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestServerTime tests the methods ServerTime() and SkewCheck()
+func TestServerTime(t *testing.T) {
+
+	t.Run("server time using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(TimeCommand).Expect([]interface{}{[]byte("1700000000"), []byte("250000")})
+
+		serverTime, err := ServerTime(context.Background(), client)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Unix(1700000000, int64(250*time.Millisecond)), serverTime)
+	})
+
+	t.Run("invalid time reply", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(TimeCommand).Expect([]interface{}{[]byte("1700000000")})
+
+		_, err := ServerTime(context.Background(), client)
+		assert.Error(t, err)
+	})
+
+	t.Run("skew check using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		client.SetClock(NewManualClock(time.Unix(1700000000, 0)))
+		conn.Command(TimeCommand).Expect([]interface{}{[]byte("1700000002"), []byte("0")})
+
+		skew, err := SkewCheck(context.Background(), client, 5*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*time.Second, skew)
+
+		skew, err = SkewCheck(context.Background(), client, time.Second)
+		assert.ErrorIs(t, err, ErrClockSkew)
+		assert.Equal(t, 2*time.Second, skew)
+	})
+
+	t.Run("skew check using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		var serverTime time.Time
+		serverTime, err = ServerTimeRaw(conn)
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), serverTime, time.Minute)
+
+		_, err = SkewCheckRaw(conn, time.Minute)
+		assert.NoError(t, err)
+	})
+}
+
+// ExampleSkewCheck is an example of the method SkewCheck()
+func ExampleSkewCheck() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock a server one second ahead
+	client.SetClock(NewManualClock(time.Unix(1700000000, 0)))
+	conn.Command(TimeCommand).Expect([]interface{}{[]byte("1700000001"), []byte("0")})
+
+	skew, _ := SkewCheck(context.Background(), client, 5*time.Second)
+	fmt.Printf("skew: %s", skew)
+	// Output:skew: 1s
+}
@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SessionPrefix is the prefix for all session keys
+const SessionPrefix = "session:"
+
+// Session storage settings
+const (
+	emptySessionField = "" // Always stored, so an empty session still exists
+	sessionIDBytes    = 32 // Number of random bytes in a session id
+)
+
+// ErrSessionNotFound is returned when the session does not exist (or has expired)
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is a single session, each value is stored as a field of a redis hash
+type Session struct {
+	ID     string            // Secure random session id
+	Values map[string]string // Encoded values by name
+
+	codec Codec
+}
+
+// Get decodes the named value into the destination
+// redis.ErrNil is returned if the value is not in the session
+func (s *Session) Get(name string, dest interface{}) error {
+	value, ok := s.Values[name]
+	if !ok {
+		return redis.ErrNil
+	}
+	return s.codec.Unmarshal([]byte(value), dest)
+}
+
+// Set encodes the value and sets it in the session (use Save() to store the session)
+func (s *Session) Set(name string, value interface{}) error {
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if s.Values == nil {
+		s.Values = make(map[string]string)
+	}
+	s.Values[name] = string(data)
+	return nil
+}
+
+// Delete removes the named value from the session (use Save() to store the session)
+func (s *Session) Delete(name string) {
+	delete(s.Values, name)
+}
+
+// SessionManager creates, loads and stores sessions with a sliding ttl
+type SessionManager struct {
+	client *Client
+	codec  Codec
+	ttl    time.Duration
+}
+
+// NewSessionManager creates a new session manager, every load or save slides the ttl
+// The codec is optional (default: JSONCodec)
+func NewSessionManager(client *Client, ttl time.Duration, codec Codec) *SessionManager {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	return &SessionManager{
+		client: client,
+		codec:  codec,
+		ttl:    ttl,
+	}
+}
+
+// Create creates and stores a new session with a secure random id
+func (m *SessionManager) Create(ctx context.Context, values map[string]interface{}) (*Session, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return nil, err
+	}
+	session := &Session{ID: id, Values: make(map[string]string), codec: m.codec}
+	for name, value := range values {
+		if err = session.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+	if err = m.Save(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Load loads the session and slides the ttl
+// ErrSessionNotFound is returned if the session does not exist (or has expired)
+//
+// Commands used:
+// https://redis.io/commands/hgetall
+// https://redis.io/commands/expire
+func (m *SessionManager) Load(ctx context.Context, id string) (*Session, error) {
+	conn, err := m.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.client.CloseConnection(conn)
+
+	key := m.key(id)
+	if err = conn.Send(HashGetAllCommand, key); err != nil {
+		return nil, err
+	}
+	if err = conn.Send(ExpireCommand, key, int64(m.ttl.Seconds())); err != nil {
+		return nil, err
+	}
+	var replies []interface{}
+	if replies, err = flushPipeline(conn); err != nil {
+		return nil, err
+	}
+
+	var values map[string]string
+	if values, err = redis.StringMap(replies[0], nil); err != nil {
+		return nil, err
+	} else if len(values) == 0 {
+		return nil, ErrSessionNotFound
+	}
+	delete(values, emptySessionField)
+	return &Session{ID: id, Values: values, codec: m.codec}, nil
+}
+
+// Save replaces the stored session values and slides the ttl
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/del
+// https://redis.io/commands/hmset
+// https://redis.io/commands/expire
+// https://redis.io/commands/exec
+func (m *SessionManager) Save(ctx context.Context, session *Session) error {
+	conn, err := m.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.client.CloseConnection(conn)
+
+	key := m.key(session.ID)
+	if err = conn.Send(MultiCommand); err != nil {
+		return err
+	}
+	if err = conn.Send(DeleteCommand, key); err != nil {
+		return err
+	}
+
+	// The placeholder field keeps an empty session stored (a hash cannot be empty)
+	args := make([]interface{}, 0, 2*len(session.Values)+3)
+	args = append(args, key, emptySessionField, "")
+	for name, value := range session.Values {
+		args = append(args, name, value)
+	}
+	if err = conn.Send(HashMapSetCommand, args...); err != nil {
+		return err
+	}
+	if err = conn.Send(ExpireCommand, key, int64(m.ttl.Seconds())); err != nil {
+		return err
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return err
+}
+
+// Touch slides the ttl of the session without loading it
+// ErrSessionNotFound is returned if the session does not exist (or has expired)
+//
+// Spec: https://redis.io/commands/expire
+func (m *SessionManager) Touch(ctx context.Context, id string) error {
+	conn, err := m.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.client.CloseConnection(conn)
+
+	var found bool
+	if found, err = redis.Bool(conn.Do(ExpireCommand, m.key(id), int64(m.ttl.Seconds()))); err != nil {
+		return err
+	} else if !found {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// Destroy removes the session
+//
+// Spec: https://redis.io/commands/del
+func (m *SessionManager) Destroy(ctx context.Context, id string) error {
+	conn, err := m.client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer m.client.CloseConnection(conn)
+	_, err = conn.Do(DeleteCommand, m.key(id))
+	return err
+}
+
+// key returns the redis key for the session id
+func (m *SessionManager) key(id string) string {
+	return SessionPrefix + id
+}
+
+// newSessionID returns a new secure random session id
+func newSessionID() (string, error) {
+	id := make([]byte, sessionIDBytes)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
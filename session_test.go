@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSessionManager tests the session manager
+func TestSessionManager(t *testing.T) {
+
+	t.Run("load using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		manager := NewSessionManager(client, time.Hour, nil)
+
+		conn.Command(HashGetAllCommand, SessionPrefix+"abc").ExpectStringSlice("", "", "user", `"alice"`)
+		expireCmd := conn.Command(ExpireCommand, SessionPrefix+"abc", int64(3600)).Expect(int64(1))
+
+		session, err := manager.Load(context.Background(), "abc")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"user": `"alice"`}, session.Values)
+		assert.True(t, expireCmd.Called)
+
+		var user string
+		err = session.Get("user", &user)
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user)
+
+		err = session.Get("missing", &user)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("session not found using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		manager := NewSessionManager(client, time.Hour, nil)
+
+		conn.Command(HashGetAllCommand, SessionPrefix+"abc").ExpectStringSlice()
+		conn.Command(ExpireCommand, SessionPrefix+"abc", int64(3600)).Expect(int64(0))
+
+		_, err := manager.Load(context.Background(), "abc")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+
+		err = manager.Touch(context.Background(), "abc")
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+
+	t.Run("session lifecycle using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		manager := NewSessionManager(client, time.Hour, JSONCodec{})
+
+		// Create a session
+		var session *Session
+		session, err = manager.Create(ctx, map[string]interface{}{"user_id": 42})
+		assert.NoError(t, err)
+		assert.Equal(t, sessionIDBytes*2, len(session.ID))
+
+		// Load and update the session
+		var loaded *Session
+		loaded, err = manager.Load(ctx, session.ID)
+		assert.NoError(t, err)
+
+		var userID int
+		err = loaded.Get("user_id", &userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 42, userID)
+
+		loaded.Delete("user_id")
+		err = loaded.Set("theme", "dark")
+		assert.NoError(t, err)
+		err = manager.Save(ctx, loaded)
+		assert.NoError(t, err)
+
+		loaded, err = manager.Load(ctx, session.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"theme": `"dark"`}, loaded.Values)
+
+		// An empty session still exists
+		loaded.Delete("theme")
+		err = manager.Save(ctx, loaded)
+		assert.NoError(t, err)
+		err = manager.Touch(ctx, session.ID)
+		assert.NoError(t, err)
+
+		// Destroy the session
+		err = manager.Destroy(ctx, session.ID)
+		assert.NoError(t, err)
+		_, err = manager.Load(ctx, session.ID)
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+	})
+}
+
+// TestNewSessionID tests the method newSessionID()
+func TestNewSessionID(t *testing.T) {
+	t.Parallel()
+
+	first, err := newSessionID()
+	assert.NoError(t, err)
+	var second string
+	second, err = newSessionID()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, 64, len(first))
+}
+
+// ExampleSessionManager_Load is an example of the method Load()
+func ExampleSessionManager_Load() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the commands
+	conn.Command(HashGetAllCommand, SessionPrefix+"abc").ExpectStringSlice("user", `"alice"`)
+	conn.Command(ExpireCommand, SessionPrefix+"abc", int64(1800)).Expect(int64(1))
+
+	// Load the session
+	session, _ := NewSessionManager(client, 30*time.Minute, nil).Load(context.Background(), "abc")
+
+	var user string
+	_ = session.Get("user", &user)
+	fmt.Printf("session user: %s", user)
+	// Output:session user: alice
+}
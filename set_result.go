@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// SetResult is the result of SetWithResult()
+type SetResult struct {
+	Existed  bool   // The key existed before the write
+	Previous string // Previous value (empty if the key did not exist)
+	Written  bool   // The value was written (false if the condition was not met)
+}
+
+// SetOption sets a condition, expiration or dependencies for SetWithResult()
+type SetOption func(*setConfig)
+
+// setConfig holds the options for SetWithResult()
+type setConfig struct {
+	condition    string
+	dependencies []string
+	ttl          time.Duration
+}
+
+// SetIfNotExists only writes the value if the key does not exist (NX, redis 7+)
+func SetIfNotExists() SetOption {
+	return func(c *setConfig) {
+		c.condition = SetIfNotExistsArgument
+	}
+}
+
+// SetIfExists only writes the value if the key already exists (XX)
+func SetIfExists() SetOption {
+	return func(c *setConfig) {
+		c.condition = SetIfExistsArgument
+	}
+}
+
+// SetWithTTL sets the expiration of the value
+// The ttl uses millisecond precision if it is not a whole number of seconds
+func SetWithTTL(ttl time.Duration) SetOption {
+	return func(c *setConfig) {
+		c.ttl = ttl
+	}
+}
+
+// SetWithDependencies keeps a reference to each dependency (only if the value was written)
+func SetWithDependencies(dependencies ...string) SetOption {
+	return func(c *setConfig) {
+		c.dependencies = append(c.dependencies, dependencies...)
+	}
+}
+
+// SetWithResult will set the key and return the previous value and whether the value was written
+// in a single round trip (no GET before the SET)
+// Options set a condition (IE: SetIfNotExists()), an expiration or dependencies
+// Requires redis 6.2+ (redis 7+ for SetIfNotExists())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetWithResultRaw()
+func SetWithResult(ctx context.Context, client *Client, key string, value interface{},
+	options ...SetOption) (*SetResult, error) {
+	if client.IsBypassed() {
+		return &SetResult{}, nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseConnection(conn)
+	return SetWithResultRaw(conn, key, value, options...)
+}
+
+// SetWithResultRaw will set the key and return the previous value and whether the value was written
+// in a single round trip (no GET before the SET)
+// Options set a condition (IE: SetIfNotExists()), an expiration or dependencies
+// Requires redis 6.2+ (redis 7+ for SetIfNotExists())
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func SetWithResultRaw(conn redis.Conn, key string, value interface{}, options ...SetOption) (*SetResult, error) {
+	config := new(setConfig)
+	for _, opt := range options {
+		opt(config)
+	}
+	if err := checkDependencyLimit(conn, config.dependencies...); err != nil {
+		return nil, err
+	}
+
+	// Build the arguments
+	args := redis.Args{}.Add(key, value)
+	if len(config.condition) > 0 {
+		args = args.Add(config.condition)
+	}
+	args = args.Add(GetArgument)
+	if config.ttl > 0 {
+		if isWholeSeconds(config.ttl) {
+			args = args.Add(ExpireSecondsArgument, int64(config.ttl.Seconds()))
+		} else {
+			args = args.Add(ExpireMillisArgument, config.ttl.Milliseconds())
+		}
+	}
+
+	// The previous value is returned (nil if the key did not exist)
+	result := new(SetResult)
+	previous, err := redis.String(conn.Do(SetCommand, args...))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return nil, err
+	}
+	result.Existed = err == nil
+	result.Previous = previous
+
+	switch config.condition {
+	case SetIfNotExistsArgument:
+		result.Written = !result.Existed
+	case SetIfExistsArgument:
+		result.Written = result.Existed
+	default:
+		result.Written = true
+	}
+
+	if result.Written {
+		if err = linkDependencies(conn, key, config.dependencies...); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetWithResult tests the method SetWithResult()
+func TestSetWithResult(t *testing.T) {
+
+	t.Run("set with result using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var tests = []struct {
+			testCase string
+			options  []SetOption
+			args     []interface{}
+			reply    interface{}
+			expected *SetResult
+		}{
+			{
+				"new key",
+				nil,
+				[]interface{}{testKey, testStringValue, GetArgument},
+				nil,
+				&SetResult{Written: true},
+			},
+			{
+				"existing key",
+				nil,
+				[]interface{}{testKey, testStringValue, GetArgument},
+				[]byte("previous"),
+				&SetResult{Existed: true, Previous: "previous", Written: true},
+			},
+			{
+				"if not exists (key exists)",
+				[]SetOption{SetIfNotExists()},
+				[]interface{}{testKey, testStringValue, SetIfNotExistsArgument, GetArgument},
+				[]byte("previous"),
+				&SetResult{Existed: true, Previous: "previous"},
+			},
+			{
+				"if exists (key is missing)",
+				[]SetOption{SetIfExists()},
+				[]interface{}{testKey, testStringValue, SetIfExistsArgument, GetArgument},
+				nil,
+				&SetResult{},
+			},
+			{
+				"ttl in seconds",
+				[]SetOption{SetWithTTL(time.Minute)},
+				[]interface{}{testKey, testStringValue, GetArgument, ExpireSecondsArgument, int64(60)},
+				nil,
+				&SetResult{Written: true},
+			},
+			{
+				"ttl in milliseconds",
+				[]SetOption{SetIfExists(), SetWithTTL(1500 * time.Millisecond)},
+				[]interface{}{testKey, testStringValue, SetIfExistsArgument, GetArgument, ExpireMillisArgument, int64(1500)},
+				[]byte("previous"),
+				&SetResult{Existed: true, Previous: "previous", Written: true},
+			},
+		}
+		for _, test := range tests {
+			t.Run(test.testCase, func(t *testing.T) {
+				conn.Clear()
+				setCmd := conn.Command(SetCommand, test.args...).Expect(test.reply)
+
+				result, err := SetWithResult(context.Background(), client, testKey, testStringValue, test.options...)
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, result)
+				assert.True(t, setCmd.Called)
+			})
+		}
+	})
+
+	t.Run("dependencies are linked if written", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, testKey, testStringValue, SetIfNotExistsArgument, GetArgument).Expect(nil)
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		result, err := SetWithResult(context.Background(), client, testKey, testStringValue,
+			SetIfNotExists(), SetWithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.True(t, result.Written)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("set with result using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		var result *SetResult
+		result, err = SetWithResult(ctx, client, testKey, "first", SetWithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.Equal(t, &SetResult{Written: true}, result)
+
+		result, err = SetWithResult(ctx, client, testKey, "second", SetIfNotExists())
+		assert.NoError(t, err)
+		assert.Equal(t, &SetResult{Existed: true, Previous: "first"}, result)
+
+		result, err = SetWithResult(ctx, client, testKey, "third", SetIfExists(), SetWithTTL(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, &SetResult{Existed: true, Previous: "first", Written: true}, result)
+
+		var value string
+		value, err = Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "third", value)
+
+		var found bool
+		found, err = SetIsMember(ctx, client, DependencyPrefix+testDependantKey, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+}
+
+// ExampleSetWithResult is an example of the method SetWithResult()
+func ExampleSetWithResult() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command (the key holds a previous value)
+	conn.Command(SetCommand, testKey, testStringValue, SetIfNotExistsArgument, GetArgument).Expect([]byte("previous"))
+
+	// Only write if the key does not exist
+	result, _ := SetWithResult(context.Background(), client, testKey, testStringValue, SetIfNotExists())
+	fmt.Printf("written: %v previous: %s", result.Written, result.Previous)
+	// Output:written: false previous: previous
+}
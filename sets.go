@@ -2,15 +2,22 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/gomodule/redigo/redis"
 )
 
 // SetAdd will add the member to the Set and link a reference to each dependency for the entire Set
+// Per-call options (IE: WithTTL()) use method: SetAddWith()
 // Creates a new connection and closes connection at end of function call
 //
 // Custom connections use method: SetAddRaw()
 func SetAdd(ctx context.Context, client *Client, setName, member interface{}, dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
@@ -24,6 +31,9 @@ func SetAdd(ctx context.Context, client *Client, setName, member interface{}, de
 //
 // Spec: https://redis.io/commands/sadd
 func SetAddRaw(conn redis.Conn, setName, member interface{}, dependencies ...string) error {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return err
+	}
 	if _, err := conn.Do(AddToSetCommand, setName, member); err != nil {
 		return err
 	}
@@ -36,6 +46,9 @@ func SetAddRaw(conn redis.Conn, setName, member interface{}, dependencies ...str
 //
 // Custom connections use method: SetAddManyRaw()
 func SetAddMany(ctx context.Context, client *Client, setName string, members ...interface{}) error {
+	if client.IsBypassed() {
+		return nil
+	}
 	conn, err := client.GetConnectionWithContext(ctx)
 	if err != nil {
 		return err
@@ -50,17 +63,14 @@ func SetAddMany(ctx context.Context, client *Client, setName string, members ...
 // Spec: https://redis.io/commands/sadd
 func SetAddManyRaw(conn redis.Conn, setName string, members ...interface{}) (err error) {
 
-	// Create the arguments
-	args := make([]interface{}, len(members)+1)
-	args[0] = setName
-
-	// Loop members
-	for i, key := range members {
-		args[i+1] = key
-	}
+	// Create the arguments (pooled)
+	args := getArgs()
+	defer putArgs(args)
+	*args = append(*args, setName)
+	*args = append(*args, members...)
 
-	// Fire the delete command
-	_, err = conn.Do(AddToSetCommand, args...)
+	// Fire the add command
+	_, err = conn.Do(AddToSetCommand, *args...)
 	return
 }
 
@@ -109,15 +119,23 @@ func SetRemoveMemberRaw(conn redis.Conn, set, member interface{}) (err error) {
 
 // SetMembers will fetch all members in the list
 // Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Sets with a tombstone are empty if set (see: SetInvalidationTombstones())
 //
 // Custom connections use method: SetMembersRaw()
-func SetMembers(ctx context.Context, client *Client, set interface{}) ([]string, error) {
-	conn, err := client.GetConnectionWithContext(ctx)
-	if err != nil {
-		return nil, err
+func SetMembers(ctx context.Context, client *Client, set interface{}) (members []string, err error) {
+	if client.IsBypassed() {
+		return nil, nil
 	}
-	defer client.CloseConnection(conn)
-	return SetMembersRaw(conn, set)
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		var tombstone bool
+		if tombstone, readErr = client.hasTombstone(conn, fmt.Sprint(set)); readErr != nil || tombstone {
+			return
+		}
+		members, readErr = SetMembersRaw(conn, set)
+		return
+	})
+	return
 }
 
 // SetMembersRaw will fetch all members in the list
@@ -127,3 +145,113 @@ func SetMembers(ctx context.Context, client *Client, set interface{}) ([]string,
 func SetMembersRaw(conn redis.Conn, set interface{}) ([]string, error) {
 	return redis.Strings(conn.Do(MembersCommand, set))
 }
+
+// SetIntersectCard returns the number of members in the intersection of the sets
+// The count stops at the limit (0 is no limit), SINTER is used if SINTERCARD is not supported (redis < 7)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: SetIntersectCardRaw()
+func SetIntersectCard(ctx context.Context, client *Client, limit int, keys ...string) (count int, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		count, readErr = SetIntersectCardRaw(conn, limit, keys...)
+		return
+	})
+	return
+}
+
+// SetIntersectCardRaw returns the number of members in the intersection of the sets
+// The count stops at the limit (0 is no limit), SINTER is used if SINTERCARD is not supported (redis < 7)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/sintercard
+// https://redis.io/commands/sinter
+func SetIntersectCardRaw(conn redis.Conn, limit int, keys ...string) (int, error) {
+	if len(keys) == 0 {
+		return 0, errors.New("missing required parameter: keys")
+	}
+
+	args := redis.Args{}.Add(len(keys)).AddFlat(keys)
+	if limit > 0 {
+		args = args.Add(LimitArgument, limit)
+	}
+	count, err := redis.Int(conn.Do(SetIntersectCardCommand, args...))
+	if !isServerError(err) {
+		return count, err
+	}
+
+	// Fallback for older servers
+	var members []interface{}
+	if members, err = redis.Values(conn.Do(SetIntersectCommand, redis.Args{}.AddFlat(keys)...)); err != nil {
+		return 0, err
+	}
+	if limit > 0 && len(members) > limit {
+		return limit, nil
+	}
+	return len(members), nil
+}
+
+// SetIntersectStore stores the intersection of the sets in the destination with an expiration
+// (0 is no expiration) and returns the number of members stored
+// The destination is linked to each source as a dependency, removing a source (see: Delete())
+// also removes the cached intersection
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetIntersectStoreRaw()
+func SetIntersectStore(ctx context.Context, client *Client, destination string, keys []string,
+	ttl time.Duration) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return SetIntersectStoreRaw(conn, destination, keys, ttl)
+}
+
+// SetIntersectStoreRaw stores the intersection of the sets in the destination with an expiration
+// (0 is no expiration) and returns the number of members stored
+// The destination is linked to each source as a dependency in the same transaction
+// The ttl uses millisecond precision if it is not a whole number of seconds
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/multi
+// https://redis.io/commands/sinterstore
+// https://redis.io/commands/expire
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/sadd
+// https://redis.io/commands/exec
+func SetIntersectStoreRaw(conn redis.Conn, destination string, keys []string, ttl time.Duration) (int, error) {
+	if len(keys) == 0 {
+		return 0, errors.New("missing required parameter: keys")
+	} else if ttl < 0 {
+		return 0, ErrInvalidTTL
+	} else if err := checkDependencyLimit(conn, keys...); err != nil {
+		return 0, err
+	}
+
+	if err := conn.Send(MultiCommand); err != nil {
+		return 0, err
+	}
+	if err := conn.Send(SetIntersectStoreCommand, redis.Args{}.Add(destination).AddFlat(keys)...); err != nil {
+		return 0, err
+	}
+	if ttl > 0 {
+		command, args := expireCommand(destination, ttl)
+		if err := conn.Send(command, args...); err != nil {
+			return 0, err
+		}
+	}
+	if err := sendLinkDependencies(conn, destination, keys...); err != nil {
+		return 0, err
+	}
+
+	replies, err := redis.Values(conn.Do(ExecuteCommand))
+	if err != nil {
+		return 0, err
+	} else if len(replies) == 0 {
+		return 0, redis.ErrNil
+	}
+	return redis.Int(replies[0], nil)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gomodule/redigo/redis"
 	"github.com/rafaeljusto/redigomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -425,3 +427,136 @@ func ExampleSetMembers() {
 	fmt.Printf("found members: [%v]", testStringValue)
 	// Output:found members: [test-string-value]
 }
+
+// TestSetIntersectCard will test the method SetIntersectCard()
+func TestSetIntersectCard(t *testing.T) {
+
+	t.Run("intersect card using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetIntersectCardCommand, 2, "perms:a", "perms:b", LimitArgument, 5).Expect(int64(3))
+
+		count, err := SetIntersectCard(context.Background(), client, 5, "perms:a", "perms:b")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+
+		_, err = SetIntersectCard(context.Background(), client, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("falls back to sinter using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetIntersectCardCommand, 2, "perms:a", "perms:b", LimitArgument, 2).
+			ExpectError(redis.Error("ERR unknown command 'SINTERCARD'"))
+		conn.Command(SetIntersectCommand, "perms:a", "perms:b").
+			Expect([]interface{}{[]byte("read"), []byte("write"), []byte("admin")})
+
+		count, err := SetIntersectCard(context.Background(), client, 2, "perms:a", "perms:b")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
+
+// TestSetIntersectStore will test the method SetIntersectStore()
+func TestSetIntersectStore(t *testing.T) {
+
+	t.Run("intersect store using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var commands []*redigomock.Cmd
+		commands = append(commands, conn.Command(MultiCommand))
+		commands = append(commands, conn.Command(SetIntersectStoreCommand, testKey, "perms:a", "perms:b"))
+		commands = append(commands, conn.Command(ExpireCommand, testKey, int64(60)))
+		commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+"perms:a", testKey))
+		commands = append(commands, conn.Command(AddToSetCommand, DependencyPrefix+"perms:b", testKey))
+		commands = append(commands, conn.Command(ExecuteCommand).
+			Expect([]interface{}{int64(2), int64(1), int64(1), int64(1)}))
+
+		count, err := SetIntersectStore(context.Background(), client, testKey, []string{"perms:a", "perms:b"}, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+		for _, c := range commands {
+			assert.True(t, c.Called)
+		}
+
+		_, err = SetIntersectStore(context.Background(), client, testKey, []string{"perms:a"}, -time.Second)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("intersect using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetAddManyRaw(conn, "perms:a", "read", "write", "admin")
+		assert.NoError(t, err)
+		err = SetAddManyRaw(conn, "perms:b", "read", "write")
+		assert.NoError(t, err)
+
+		var count int
+		count, err = SetIntersectCardRaw(conn, 0, "perms:a", "perms:b")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		count, err = SetIntersectCardRaw(conn, 1, "perms:a", "perms:b")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = SetIntersectStoreRaw(conn, testKey, []string{"perms:a", "perms:b"}, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var members []string
+		members, err = SetMembersRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"read", "write"}, members)
+
+		// Removing a source removes the cached intersection
+		_, err = DeleteRaw(conn, "perms:b")
+		assert.NoError(t, err)
+		var found bool
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleSetIntersectStore is an example of the method SetIntersectStore()
+func ExampleSetIntersectStore() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the transaction (two members in common)
+	conn.Command(MultiCommand)
+	conn.GenericCommand(SetIntersectStoreCommand)
+	conn.GenericCommand(ExpireCommand)
+	conn.GenericCommand(AddToSetCommand)
+	conn.Command(ExecuteCommand).Expect([]interface{}{int64(2)})
+
+	// Cache the permissions the user has in both roles
+	count, _ := SetIntersectStore(context.Background(), client, "perms:user-1",
+		[]string{"perms:role-a", "perms:role-b"}, time.Minute)
+	fmt.Printf("members: %d", count)
+	// Output:members: 2
+}
@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Default shadow read settings
+const (
+	defaultShadowReadMaxInFlight = 100
+	defaultShadowReadTimeout     = 5 * time.Second
+)
+
+// ErrMissingShadowClient is returned when shadow reads are enabled without a secondary client
+var ErrMissingShadowClient = errors.New("missing required secondary client for shadow reads")
+
+// ShadowReadHandler is fired when a shadow read does not match the primary read
+type ShadowReadHandler func(result *ShadowReadResult)
+
+// ShadowReadConfig is the configuration for shadow-read verification
+type ShadowReadConfig struct {
+	MaxInFlight int               // Max concurrent shadow reads, extra reads are skipped (default: 100)
+	OnMismatch  ShadowReadHandler // Fired on a mismatch or a failed shadow read
+	Secondary   *Client           // Client that receives the shadow reads
+	Timeout     time.Duration     // Timeout for each shadow read (default: 5s)
+}
+
+// ShadowReadResult is the comparison of a primary read and the matching shadow read
+type ShadowReadResult struct {
+	Key            string        // Key that was read
+	PrimaryErr     error         // Error from the primary (redis.ErrNil is a miss)
+	PrimaryValue   string        // Value from the primary
+	SecondaryErr   error         // Error from the secondary (redis.ErrNil is a miss)
+	SecondaryValue string        // Value from the secondary
+	ShadowDuration time.Duration // How long the shadow read took
+}
+
+// ShadowReadStats are the running totals for shadow reads
+type ShadowReadStats struct {
+	Errors     uint64 // Shadow reads that failed on the secondary
+	Matches    uint64 // Shadow reads that matched the primary
+	Mismatches uint64 // Shadow reads that did not match the primary
+	Reads      uint64 // Shadow reads issued
+	Skipped    uint64 // Shadow reads skipped (too many in-flight)
+}
+
+// shadowReader issues and compares the shadow reads for a client
+type shadowReader struct {
+	clock   Clock
+	config  ShadowReadConfig
+	slots   chan struct{}
+	stats   ShadowReadStats
+	waiting sync.WaitGroup
+}
+
+// EnableShadowReads will issue every Get() and GetBytes() against the secondary client in the
+// background, compare the results and report any mismatches
+//
+// Shadow reads never change the value or error returned to the caller
+func (c *Client) EnableShadowReads(config *ShadowReadConfig) error {
+	if config == nil || config.Secondary == nil {
+		return ErrMissingShadowClient
+	}
+	reader := &shadowReader{clock: c.Clock(), config: *config}
+	if reader.config.MaxInFlight <= 0 {
+		reader.config.MaxInFlight = defaultShadowReadMaxInFlight
+	}
+	if reader.config.Timeout <= 0 {
+		reader.config.Timeout = defaultShadowReadTimeout
+	}
+	reader.slots = make(chan struct{}, reader.config.MaxInFlight)
+
+	c.DisableShadowReads()
+	c.mu.Lock()
+	c.shadow = reader
+	c.mu.Unlock()
+	return nil
+}
+
+// DisableShadowReads will stop issuing shadow reads and wait for any in-flight shadow reads
+func (c *Client) DisableShadowReads() {
+	c.mu.Lock()
+	reader := c.shadow
+	c.shadow = nil
+	c.mu.Unlock()
+
+	if reader != nil {
+		reader.waiting.Wait()
+	}
+}
+
+// ShadowReadStats returns the running totals for the current shadow reads
+func (c *Client) ShadowReadStats() ShadowReadStats {
+	reader := c.shadowReader()
+	if reader == nil {
+		return ShadowReadStats{}
+	}
+	return ShadowReadStats{
+		Errors:     atomic.LoadUint64(&reader.stats.Errors),
+		Matches:    atomic.LoadUint64(&reader.stats.Matches),
+		Mismatches: atomic.LoadUint64(&reader.stats.Mismatches),
+		Reads:      atomic.LoadUint64(&reader.stats.Reads),
+		Skipped:    atomic.LoadUint64(&reader.stats.Skipped),
+	}
+}
+
+// shadowReader returns the current shadow reader (if enabled)
+func (c *Client) shadowReader() *shadowReader {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.shadow
+}
+
+// shadowGet will fire a shadow read for the key (if enabled) and compare it to the primary result
+func (c *Client) shadowGet(key, primaryValue string, primaryErr error) {
+
+	// Nothing to compare against if the primary failed
+	if primaryErr != nil && !errors.Is(primaryErr, redis.ErrNil) {
+		return
+	}
+
+	// Reserve a slot while holding the lock (DisableShadowReads() waits on in-flight reads)
+	c.mu.RLock()
+	reader := c.shadow
+	if reader == nil {
+		c.mu.RUnlock()
+		return
+	}
+	select {
+	case reader.slots <- struct{}{}:
+		reader.waiting.Add(1)
+		c.mu.RUnlock()
+	default:
+		c.mu.RUnlock()
+		atomic.AddUint64(&reader.stats.Skipped, 1)
+		return
+	}
+
+	go func() {
+		defer func() {
+			<-reader.slots
+			reader.waiting.Done()
+		}()
+		reader.compare(key, primaryValue, primaryErr)
+	}()
+}
+
+// compare will read the key from the secondary and compare it to the primary result
+func (r *shadowReader) compare(key, primaryValue string, primaryErr error) {
+	atomic.AddUint64(&r.stats.Reads, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.config.Timeout)
+	defer cancel()
+
+	start := r.clock.Now()
+	result := &ShadowReadResult{
+		Key:          key,
+		PrimaryErr:   primaryErr,
+		PrimaryValue: primaryValue,
+	}
+	result.SecondaryValue, result.SecondaryErr = getString(ctx, r.config.Secondary, key)
+	result.ShadowDuration = r.clock.Now().Sub(start)
+
+	// A match is both sides missing or both sides returning the same value
+	primaryMiss := errors.Is(primaryErr, redis.ErrNil)
+	secondaryMiss := errors.Is(result.SecondaryErr, redis.ErrNil)
+	switch {
+	case result.SecondaryErr != nil && !secondaryMiss:
+		atomic.AddUint64(&r.stats.Errors, 1)
+	case primaryMiss == secondaryMiss && primaryValue == result.SecondaryValue:
+		atomic.AddUint64(&r.stats.Matches, 1)
+		return
+	default:
+		atomic.AddUint64(&r.stats.Mismatches, 1)
+	}
+
+	if r.config.OnMismatch != nil {
+		r.config.OnMismatch(result)
+	}
+}
+
+// getString gets a key in string format without firing any shadow reads
+func getString(ctx context.Context, client *Client, key string) (string, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.CloseConnection(conn)
+	return GetRaw(conn, key)
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_EnableShadowReads tests the method EnableShadowReads()
+func TestClient_EnableShadowReads(t *testing.T) {
+
+	t.Run("missing config or secondary", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.EnableShadowReads(nil)
+		assert.ErrorIs(t, err, ErrMissingShadowClient)
+
+		err = client.EnableShadowReads(&ShadowReadConfig{})
+		assert.ErrorIs(t, err, ErrMissingShadowClient)
+	})
+
+	t.Run("shadow read matches the primary", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		secondary, secondaryConn := loadMockRedis()
+		defer secondary.CloseAll(secondaryConn)
+
+		var mismatches []*ShadowReadResult
+		var mu sync.Mutex
+		err := client.EnableShadowReads(&ShadowReadConfig{
+			Secondary: secondary,
+			OnMismatch: func(result *ShadowReadResult) {
+				mu.Lock()
+				mismatches = append(mismatches, result)
+				mu.Unlock()
+			},
+		})
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		secondaryConn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		var val string
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+
+		// Wait for the in-flight shadow reads
+		client.DisableShadowReads()
+		assert.Equal(t, 0, len(mismatches))
+	})
+
+	t.Run("shadow read mismatch is reported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		secondary, secondaryConn := loadMockRedis()
+		defer secondary.CloseAll(secondaryConn)
+
+		results := make(chan *ShadowReadResult, 1)
+		err := client.EnableShadowReads(&ShadowReadConfig{
+			Secondary: secondary,
+			OnMismatch: func(result *ShadowReadResult) {
+				results <- result
+			},
+		})
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		secondaryConn.Command(GetCommand, testKey).Expect("stale-value")
+
+		var val []byte
+		val, err = GetBytes(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, string(val))
+
+		result := <-results
+		assert.Equal(t, testKey, result.Key)
+		assert.Equal(t, testStringValue, result.PrimaryValue)
+		assert.Equal(t, "stale-value", result.SecondaryValue)
+		assert.NoError(t, result.SecondaryErr)
+
+		stats := client.ShadowReadStats()
+		assert.Equal(t, uint64(1), stats.Reads)
+		assert.Equal(t, uint64(1), stats.Mismatches)
+		client.DisableShadowReads()
+	})
+
+	t.Run("shadow read miss on secondary is a mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		secondary, secondaryConn := loadMockRedis()
+		defer secondary.CloseAll(secondaryConn)
+
+		results := make(chan *ShadowReadResult, 1)
+		err := client.EnableShadowReads(&ShadowReadConfig{
+			Secondary: secondary,
+			OnMismatch: func(result *ShadowReadResult) {
+				results <- result
+			},
+		})
+		assert.NoError(t, err)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		secondaryConn.Command(GetCommand, testKey).Expect(nil)
+
+		_, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+
+		result := <-results
+		assert.Error(t, result.SecondaryErr)
+		client.DisableShadowReads()
+	})
+
+	t.Run("disabled shadow reads", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		_, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, ShadowReadStats{}, client.ShadowReadStats())
+	})
+}
+
+// ExampleClient_EnableShadowReads is an example of the method EnableShadowReads()
+func ExampleClient_EnableShadowReads() {
+	// Load a mocked redis for testing/examples
+	client, _ := loadMockRedis()
+	secondary, _ := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.Close()
+	defer secondary.Close()
+
+	// Compare every Get() against the secondary
+	_ = client.EnableShadowReads(&ShadowReadConfig{
+		Secondary: secondary,
+		OnMismatch: func(result *ShadowReadResult) {
+			fmt.Printf("mismatch for key: %s", result.Key)
+		},
+	})
+	defer client.DisableShadowReads()
+
+	fmt.Print("shadow reads enabled")
+	// Output:shadow reads enabled
+}
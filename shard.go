@@ -0,0 +1,448 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"hash/crc32"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// shardVirtualNodes is the number of points each shard has on the hash ring
+const shardVirtualNodes = 160
+
+// ErrNoShards is returned when a sharded client has no shards
+var ErrNoShards = errors.New("sharded client has no shards")
+
+// ShardedClient distributes keys across standalone redis servers using consistent hashing
+//
+// Keys with a hash tag (IE: "{user:1}:profile") are hashed by the tag only, so related keys
+// can be co-located on the same server. Dependencies are always linked on the shard of the
+// key that is being set, and dependency kills are broadcast to every shard.
+type ShardedClient struct {
+	mu     sync.RWMutex
+	names  []string
+	ring   []ringPoint
+	shards map[string]*Client
+}
+
+// ringPoint is a single point on the hash ring
+type ringPoint struct {
+	hash  uint32
+	shard string
+}
+
+// NewShardedClient creates a new (empty) sharded client, use AddShard() to add servers
+func NewShardedClient() *ShardedClient {
+	return &ShardedClient{shards: make(map[string]*Client)}
+}
+
+// ConnectSharded creates a sharded client with a connection pool for each of the urls
+//
+// Format of URL: redis://localhost:6379
+func ConnectSharded(ctx context.Context, redisURLs []string,
+	maxActiveConnections, idleConnections int,
+	maxConnLifetime, idleTimeout time.Duration,
+	dependencyMode, newRelicEnabled bool, options ...redis.DialOption) (*ShardedClient, error) {
+
+	// Required param for dial
+	if len(redisURLs) == 0 {
+		return nil, errors.New("missing required parameter: redisURLs")
+	}
+
+	sharded := NewShardedClient()
+	for _, redisURL := range redisURLs {
+		client, err := Connect(
+			ctx, redisURL, maxActiveConnections, idleConnections,
+			maxConnLifetime, idleTimeout, dependencyMode, newRelicEnabled, options...,
+		)
+		if err != nil {
+			sharded.Close()
+			return nil, err
+		}
+		sharded.AddShard(shardName(redisURL), client)
+	}
+	return sharded, nil
+}
+
+// AddShard adds (or replaces) a named shard on the hash ring
+//
+// The name (not the order of shards) decides the placement of keys on the ring
+func (s *ShardedClient) AddShard(name string, client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.shards[name]; !ok {
+		s.names = append(s.names, name)
+		sort.Strings(s.names)
+	}
+	s.shards[name] = client
+	s.buildRing()
+}
+
+// RemoveShard removes a shard from the hash ring and returns its client (does not close the client)
+func (s *ShardedClient) RemoveShard(name string) *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client, ok := s.shards[name]
+	if !ok {
+		return nil
+	}
+	delete(s.shards, name)
+	for i, shard := range s.names {
+		if shard == name {
+			s.names = append(s.names[:i], s.names[i+1:]...)
+			break
+		}
+	}
+	s.buildRing()
+	return client
+}
+
+// Shards returns all the shard clients (sorted by shard name)
+func (s *ShardedClient) Shards() []*Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	clients := make([]*Client, 0, len(s.names))
+	for _, name := range s.names {
+		clients = append(clients, s.shards[name])
+	}
+	return clients
+}
+
+// ClientFor returns the shard client that owns the key (nil if there are no shards)
+func (s *ShardedClient) ClientFor(key string) *Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.ring) == 0 {
+		return nil
+	}
+	hash := crc32.ChecksumIEEE([]byte(HashTag(key)))
+	i := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= hash })
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.shards[s.ring[i].shard]
+}
+
+// Close closes all the shard connection pools
+func (s *ShardedClient) Close() {
+	for _, client := range s.Shards() {
+		client.Close()
+	}
+}
+
+// buildRing rebuilds the hash ring (lock must be held)
+func (s *ShardedClient) buildRing() {
+	s.ring = make([]ringPoint, 0, len(s.names)*shardVirtualNodes)
+	for _, name := range s.names {
+		for i := 0; i < shardVirtualNodes; i++ {
+			s.ring = append(s.ring, ringPoint{
+				hash:  crc32.ChecksumIEEE([]byte(name + "-" + strconv.Itoa(i))),
+				shard: name,
+			})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool { return s.ring[i].hash < s.ring[j].hash })
+}
+
+// clientFor returns the shard client that owns the key or an error if there are no shards
+func (s *ShardedClient) clientFor(key string) (*Client, error) {
+	if client := s.ClientFor(key); client != nil {
+		return client, nil
+	}
+	return nil, ErrNoShards
+}
+
+// HashTag returns the part of the key that is used for hashing
+//
+// If the key contains a non-empty "{...}" section, only that section is hashed (same as Redis Cluster)
+func HashTag(key string) string {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+// shardName returns the name of the shard for the redis url (without credentials)
+func shardName(redisURL string) string {
+	if u, err := url.Parse(redisURL); err == nil && len(u.Host) > 0 {
+		return u.Host + u.Path
+	}
+	return redisURL
+}
+
+// Get gets a key from the shard that owns the key
+func (s *ShardedClient) Get(ctx context.Context, key string) (string, error) {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return "", err
+	}
+	return Get(ctx, client, key)
+}
+
+// GetBytes gets a key (in bytes) from the shard that owns the key
+func (s *ShardedClient) GetBytes(ctx context.Context, key string) ([]byte, error) {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return nil, err
+	}
+	return GetBytes(ctx, client, key)
+}
+
+// GetList returns a []string stored in a redis list on the shard that owns the key
+func (s *ShardedClient) GetList(ctx context.Context, key string) ([]string, error) {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return nil, err
+	}
+	return GetList(ctx, client, key)
+}
+
+// SetList saves a slice as a redis list (appends) on the shard that owns the key
+func (s *ShardedClient) SetList(ctx context.Context, key string, slice []string) error {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return err
+	}
+	return SetList(ctx, client, key, slice)
+}
+
+// GetAllKeys returns the keys from every shard
+func (s *ShardedClient) GetAllKeys(ctx context.Context) (keys []string, err error) {
+	for _, client := range s.Shards() {
+		var shardKeys []string
+		if shardKeys, err = GetAllKeys(ctx, client); err != nil {
+			return
+		}
+		keys = append(keys, shardKeys...)
+	}
+	return
+}
+
+// Set will set the key and link the dependencies on the shard that owns the key
+func (s *ShardedClient) Set(ctx context.Context, key string, value interface{}, dependencies ...string) error {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return err
+	}
+	return Set(ctx, client, key, value, dependencies...)
+}
+
+// SetExp will set the key with a ttl and link the dependencies on the shard that owns the key
+func (s *ShardedClient) SetExp(ctx context.Context, key string, value interface{},
+	ttl time.Duration, dependencies ...string) error {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return err
+	}
+	return SetExp(ctx, client, key, value, ttl, dependencies...)
+}
+
+// SetToJSON stores the struct data (Struct->JSON) on the shard that owns the key
+func (s *ShardedClient) SetToJSON(ctx context.Context, keyName string, modelData interface{},
+	ttl time.Duration, dependencies ...string) error {
+	client, err := s.clientFor(keyName)
+	if err != nil {
+		return err
+	}
+	return SetToJSON(ctx, client, keyName, modelData, ttl, dependencies...)
+}
+
+// Exists checks if a key is present on the shard that owns the key
+func (s *ShardedClient) Exists(ctx context.Context, key string) (bool, error) {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return false, err
+	}
+	return Exists(ctx, client, key)
+}
+
+// Expire sets the expiration for a given key on the shard that owns the key
+func (s *ShardedClient) Expire(ctx context.Context, key string, duration time.Duration,
+	options ...ExpireOption) error {
+	client, err := s.clientFor(key)
+	if err != nil {
+		return err
+	}
+	return Expire(ctx, client, key, duration, options...)
+}
+
+// Delete is an alias for KillByDependency()
+func (s *ShardedClient) Delete(ctx context.Context, keys ...string) (int, error) {
+	return s.KillByDependency(ctx, keys...)
+}
+
+// KillByDependency removes all keys which are listed as depending on the key(s) from every shard
+func (s *ShardedClient) KillByDependency(ctx context.Context, keys ...string) (total int, err error) {
+	if len(keys) == 0 {
+		return
+	}
+	for _, client := range s.Shards() {
+		var deleted int
+		if deleted, err = KillByDependency(ctx, client, keys...); err != nil {
+			return
+		}
+		total += deleted
+	}
+	return
+}
+
+// DeleteWithoutDependency will remove keys (from the shards that own them) without using dependency script
+func (s *ShardedClient) DeleteWithoutDependency(ctx context.Context, keys ...string) (total int, err error) {
+	var client *Client
+	for _, key := range keys {
+		if client, err = s.clientFor(key); err != nil {
+			return
+		}
+		var deleted int
+		if deleted, err = DeleteWithoutDependency(ctx, client, key); err != nil {
+			return
+		}
+		total += deleted
+	}
+	return
+}
+
+// DestroyCache will flush every shard
+func (s *ShardedClient) DestroyCache(ctx context.Context) error {
+	for _, client := range s.Shards() {
+		if err := DestroyCache(ctx, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HashSet will set the hashKey to the value in the specified hashName on the shard that owns the hash
+func (s *ShardedClient) HashSet(ctx context.Context, hashName, hashKey string,
+	value interface{}, dependencies ...string) error {
+	client, err := s.clientFor(hashName)
+	if err != nil {
+		return err
+	}
+	return HashSet(ctx, client, hashName, hashKey, value, dependencies...)
+}
+
+// HashGet gets a key from a hash on the shard that owns the hash
+func (s *ShardedClient) HashGet(ctx context.Context, hash, key string) (string, error) {
+	client, err := s.clientFor(hash)
+	if err != nil {
+		return "", err
+	}
+	return HashGet(ctx, client, hash, key)
+}
+
+// HashMapGet gets values from a hash map on the shard that owns the hash
+func (s *ShardedClient) HashMapGet(ctx context.Context, hashName string, keys ...interface{}) ([]string, error) {
+	client, err := s.clientFor(hashName)
+	if err != nil {
+		return nil, err
+	}
+	return HashMapGet(ctx, client, hashName, keys...)
+}
+
+// HashMapSet will set the pairs in the specified hashName on the shard that owns the hash
+func (s *ShardedClient) HashMapSet(ctx context.Context, hashName string,
+	pairs [][2]interface{}, dependencies ...string) error {
+	client, err := s.clientFor(hashName)
+	if err != nil {
+		return err
+	}
+	return HashMapSet(ctx, client, hashName, pairs, dependencies...)
+}
+
+// HashMapSetExp will set the pairs with a ttl in the specified hashName on the shard that owns the hash
+func (s *ShardedClient) HashMapSetExp(ctx context.Context, hashName string, pairs [][2]interface{},
+	ttl time.Duration, dependencies ...string) error {
+	client, err := s.clientFor(hashName)
+	if err != nil {
+		return err
+	}
+	return HashMapSetExp(ctx, client, hashName, pairs, ttl, dependencies...)
+}
+
+// SetAdd will add the member to the Set on the shard that owns the set
+func (s *ShardedClient) SetAdd(ctx context.Context, setName string, member interface{},
+	dependencies ...string) error {
+	client, err := s.clientFor(setName)
+	if err != nil {
+		return err
+	}
+	return SetAdd(ctx, client, setName, member, dependencies...)
+}
+
+// SetAddMany will add many values to an existing set on the shard that owns the set
+func (s *ShardedClient) SetAddMany(ctx context.Context, setName string, members ...interface{}) error {
+	client, err := s.clientFor(setName)
+	if err != nil {
+		return err
+	}
+	return SetAddMany(ctx, client, setName, members...)
+}
+
+// SetIsMember returns if the member is part of the set on the shard that owns the set
+func (s *ShardedClient) SetIsMember(ctx context.Context, set string, member interface{}) (bool, error) {
+	client, err := s.clientFor(set)
+	if err != nil {
+		return false, err
+	}
+	return SetIsMember(ctx, client, set, member)
+}
+
+// SetRemoveMember removes the member from the set on the shard that owns the set
+func (s *ShardedClient) SetRemoveMember(ctx context.Context, set string, member interface{}) error {
+	client, err := s.clientFor(set)
+	if err != nil {
+		return err
+	}
+	return SetRemoveMember(ctx, client, set, member)
+}
+
+// SetMembers will fetch all members of the set on the shard that owns the set
+func (s *ShardedClient) SetMembers(ctx context.Context, set string) ([]string, error) {
+	client, err := s.clientFor(set)
+	if err != nil {
+		return nil, err
+	}
+	return SetMembers(ctx, client, set)
+}
+
+// WriteLock attempts to grab a redis lock on the shard that owns the lock name
+func (s *ShardedClient) WriteLock(ctx context.Context, name, secret string, ttl int64) (bool, error) {
+	client, err := s.clientFor(name)
+	if err != nil {
+		return false, err
+	}
+	return WriteLock(ctx, client, name, secret, ttl)
+}
+
+// ReleaseLock releases the redis lock on the shard that owns the lock name
+func (s *ShardedClient) ReleaseLock(ctx context.Context, name, secret string) (bool, error) {
+	client, err := s.clientFor(name)
+	if err != nil {
+		return false, err
+	}
+	return ReleaseLock(ctx, client, name, secret)
+}
+
+// Ping will ping every shard
+func (s *ShardedClient) Ping(ctx context.Context) error {
+	clients := s.Shards()
+	if len(clients) == 0 {
+		return ErrNoShards
+	}
+	for _, client := range clients {
+		if err := Ping(ctx, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
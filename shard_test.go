@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHashTag tests the method HashTag()
+func TestHashTag(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		key      string
+		expected string
+	}{
+		{"user:1", "user:1"},
+		{"{user:1}:profile", "user:1"},
+		{"depend:{user:1}", "user:1"},
+		{"prefix{user:1}suffix", "user:1"},
+		{"{}:empty", "{}:empty"},
+		{"{open", "{open"},
+		{"close}", "close}"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, HashTag(test.key), test.key)
+	}
+}
+
+// TestShardedClient_ClientFor tests the method ClientFor()
+func TestShardedClient_ClientFor(t *testing.T) {
+
+	t.Run("no shards", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		assert.Nil(t, sharded.ClientFor(testKey))
+
+		_, err := sharded.Get(context.Background(), testKey)
+		assert.ErrorIs(t, err, ErrNoShards)
+		assert.ErrorIs(t, sharded.Ping(context.Background()), ErrNoShards)
+	})
+
+	t.Run("keys are distributed and stable", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		clients := map[*Client]int{}
+		for i := 0; i < 3; i++ {
+			client, conn := loadMockRedis()
+			defer client.CloseAll(conn)
+			sharded.AddShard("shard-"+strconv.Itoa(i), client)
+			clients[client] = 0
+		}
+		assert.Equal(t, 3, len(sharded.Shards()))
+
+		for i := 0; i < 3000; i++ {
+			key := "key-" + strconv.Itoa(i)
+			client := sharded.ClientFor(key)
+			assert.Equal(t, client, sharded.ClientFor(key))
+			clients[client]++
+		}
+		for _, count := range clients {
+			assert.Greater(t, count, 500)
+		}
+	})
+
+	t.Run("hash tags are co-located", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		for i := 0; i < 5; i++ {
+			client, conn := loadMockRedis()
+			defer client.CloseAll(conn)
+			sharded.AddShard("shard-"+strconv.Itoa(i), client)
+		}
+
+		owner := sharded.ClientFor("{user:1}")
+		assert.Equal(t, owner, sharded.ClientFor("{user:1}:profile"))
+		assert.Equal(t, owner, sharded.ClientFor("{user:1}:settings"))
+		assert.Equal(t, owner, sharded.ClientFor(DependencyPrefix+"{user:1}"))
+	})
+
+	t.Run("removing a shard only moves its keys", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		for i := 0; i < 4; i++ {
+			client, conn := loadMockRedis()
+			defer client.CloseAll(conn)
+			sharded.AddShard("shard-"+strconv.Itoa(i), client)
+		}
+
+		before := map[string]*Client{}
+		for i := 0; i < 1000; i++ {
+			key := "key-" + strconv.Itoa(i)
+			before[key] = sharded.ClientFor(key)
+		}
+
+		removed := sharded.RemoveShard("shard-2")
+		assert.NotNil(t, removed)
+		assert.Nil(t, sharded.RemoveShard("shard-2"))
+
+		for key, client := range before {
+			if client != removed {
+				assert.Equal(t, client, sharded.ClientFor(key))
+			} else {
+				assert.NotEqual(t, removed, sharded.ClientFor(key))
+			}
+		}
+	})
+}
+
+// TestShardedClient_Commands tests routing the commands to the shards
+func TestShardedClient_Commands(t *testing.T) {
+
+	t.Run("set and get on the owning shard", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		first, firstConn := loadMockRedis()
+		defer first.CloseAll(firstConn)
+		second, secondConn := loadMockRedis()
+		defer second.CloseAll(secondConn)
+		sharded.AddShard("first", first)
+		sharded.AddShard("second", second)
+
+		conn := firstConn
+		if sharded.ClientFor(testKey) == second {
+			conn = secondConn
+		}
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		err := sharded.Set(context.Background(), testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+
+		var val string
+		val, err = sharded.Get(context.Background(), testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+	})
+
+	t.Run("kill by dependency is broadcast", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		first, firstConn := loadMockRedis()
+		defer first.CloseAll(firstConn)
+		second, secondConn := loadMockRedis()
+		defer second.CloseAll(secondConn)
+		sharded.AddShard("first", first)
+		sharded.AddShard("second", second)
+
+		firstConn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+testDependantKey).Expect(int64(2))
+		firstConn.Command(DeleteCommand, testDependantKey).Expect(int64(0))
+		secondConn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+testDependantKey).Expect(int64(1))
+		secondConn.Command(DeleteCommand, testDependantKey).Expect(int64(1))
+
+		total, err := sharded.Delete(context.Background(), testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, total)
+
+		total, err = sharded.KillByDependency(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, total)
+	})
+
+	t.Run("ping every shard", func(t *testing.T) {
+		t.Parallel()
+
+		sharded := NewShardedClient()
+		first, firstConn := loadMockRedis()
+		defer first.CloseAll(firstConn)
+		second, secondConn := loadMockRedis()
+		defer second.CloseAll(secondConn)
+		sharded.AddShard("first", first)
+		sharded.AddShard("second", second)
+
+		firstPing := firstConn.Command(PingCommand).Expect("PONG")
+		secondPing := secondConn.Command(PingCommand).Expect("PONG")
+
+		assert.NoError(t, sharded.Ping(context.Background()))
+		assert.True(t, firstPing.Called)
+		assert.True(t, secondPing.Called)
+	})
+}
+
+// ExampleShardedClient_ClientFor is an example of the method ClientFor()
+func ExampleShardedClient_ClientFor() {
+	sharded := NewShardedClient()
+	for _, name := range []string{"redis-a:6379", "redis-b:6379", "redis-c:6379"} {
+		// Load a mocked redis for testing/examples
+		client, _ := loadMockRedis()
+		sharded.AddShard(name, client)
+	}
+
+	// Close connections at end of request
+	defer sharded.Close()
+
+	// Keys with the same hash tag are stored on the same shard
+	same := sharded.ClientFor("{user:1}:profile") == sharded.ClientFor("{user:1}:settings")
+	fmt.Printf("co-located: %v", same)
+	// Output:co-located: true
+}
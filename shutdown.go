@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// shutdownPollInterval is the time between checking for in-flight commands during Shutdown()
+const shutdownPollInterval = 10 * time.Millisecond
+
+// ErrClientShutdown is returned for new operations after Shutdown() was called
+var ErrClientShutdown = errors.New("redis client is shut down")
+
+// backgroundKey is the context key for operations from the background workers
+type backgroundKey struct{}
+
+// withBackground returns a context for background workers (allowed while draining in Shutdown())
+func withBackground(ctx context.Context) context.Context {
+	return context.WithValue(ctx, backgroundKey{}, true)
+}
+
+// Shutdown gracefully closes the client: new operations are rejected with ErrClientShutdown, the
+// background workers (async writes and persists, hot keys, discovery, standby) are stopped, and the
+// in-flight commands are drained before the connection pool (and any replica and class pools) are closed
+//
+// The pools are always closed, the context error is returned if the drain did not finish in time
+func (c *Client) Shutdown(ctx context.Context) error {
+	atomic.StoreUint32(&c.shutdown, 1)
+
+	// Stop the background workers (pending async writes and persists are still written)
+	c.StopHotKeys()
+	c.StopDiscovery()
+	c.StopStandby()
+	err := c.StopAsyncWriter(ctx)
+	if persistErr := c.StopWriteThrough(ctx); err == nil {
+		err = persistErr
+	}
+
+	// Wait for the in-flight commands
+	if err == nil {
+		err = c.drain(ctx)
+	}
+
+	c.Close()
+	return err
+}
+
+// IsShutdown returns true if Shutdown() was called
+func (c *Client) IsShutdown() bool {
+	return atomic.LoadUint32(&c.shutdown) == 1
+}
+
+// acceptOperation returns ErrClientShutdown if the client is shut down (background workers are allowed)
+func (c *Client) acceptOperation(ctx context.Context) error {
+	if !c.IsShutdown() {
+		return nil
+	}
+	if background, _ := ctx.Value(backgroundKey{}).(bool); background {
+		return nil
+	}
+	return ErrClientShutdown
+}
+
+// drain waits until there are no connections in use or the context is done
+func (c *Client) drain(ctx context.Context) error {
+	for c.inFlight() > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.Clock().After(shutdownPollInterval):
+		}
+	}
+	return nil
+}
+
+// inFlight returns the number of connections in use across the primary, replica and class pools
+func (c *Client) inFlight() (count int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.Pool != nil {
+		count += c.Pool.ActiveCount() - c.Pool.IdleCount()
+	}
+	for _, replica := range c.Replicas {
+		count += replica.ActiveCount() - replica.IdleCount()
+	}
+	for _, pool := range c.classPools {
+		count += pool.ActiveCount() - pool.IdleCount()
+	}
+	return
+}
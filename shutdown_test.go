@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Shutdown tests the method Shutdown()
+func TestClient_Shutdown(t *testing.T) {
+
+	t.Run("new operations are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.Shutdown(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, client.IsShutdown())
+		assert.Nil(t, client.Pool)
+
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.ErrorIs(t, err, ErrClientShutdown)
+
+		_, err = Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, ErrClientShutdown)
+	})
+
+	t.Run("in-flight commands are drained", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// Command in progress
+		inFlight, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			done <- client.Shutdown(context.Background())
+		}()
+
+		// Shutdown waits for the connection to be returned
+		select {
+		case <-done:
+			t.Fatal("shutdown did not wait for the in-flight command")
+		case <-time.After(50 * time.Millisecond):
+		}
+		assert.True(t, client.IsShutdown())
+
+		_, err = client.GetConnectionWithContext(context.Background())
+		assert.ErrorIs(t, err, ErrClientShutdown)
+
+		client.CloseConnection(inFlight)
+		select {
+		case err = <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("shutdown did not finish")
+		}
+		assert.Nil(t, client.Pool)
+	})
+
+	t.Run("in-flight commands of pool classes are drained", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		loadMockPoolClasses(client, conn)
+		assert.NoError(t, client.AddPoolClass("bulk", 1, 1))
+
+		// Command in progress on the class pool
+		inFlight, err := client.GetConnectionWithContext(WithPoolClass(context.Background(), "bulk"))
+		assert.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			done <- client.Shutdown(context.Background())
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("shutdown did not wait for the in-flight command of the pool class")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		client.CloseConnection(inFlight)
+		select {
+		case err = <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("shutdown did not finish")
+		}
+		assert.Equal(t, 0, len(client.PoolClasses()))
+	})
+
+	t.Run("drain stops at the deadline", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		inFlight, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		defer client.CloseConnection(inFlight)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err = client.Shutdown(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, client.Pool)
+	})
+
+	t.Run("pending async writes are written", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+
+		err := client.StartAsyncWriter(&AsyncWriterConfig{Workers: 1})
+		assert.NoError(t, err)
+
+		err = SetAsync(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		err = client.Shutdown(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+	})
+}
+
+// ExampleClient_Shutdown is an example of the method Shutdown()
+func ExampleClient_Shutdown() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Wait up to five seconds for in-flight commands
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := client.Shutdown(ctx)
+
+	fmt.Printf("shutdown: %v", err == nil)
+	// Output:shutdown: true
+}
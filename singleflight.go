@@ -0,0 +1,50 @@
+package cache
+
+import "sync"
+
+// flightCall is an in-flight (or completed) call of a flightGroup
+type flightCall struct {
+	dups  int
+	err   error
+	value interface{}
+	wg    sync.WaitGroup
+}
+
+// flightGroup suppresses duplicate concurrent calls for the same key
+// The shared calls are counted in the stats of the client (if set, see: Stats())
+type flightGroup struct {
+	calls  map[string]*flightCall
+	client *Client
+	mu     sync.Mutex
+}
+
+// do runs fn once per key at a time, duplicate callers wait and share the result
+// shared is true if the caller waited for the result of another caller
+func (g *flightGroup) do(key string, fn func() (interface{}, error)) (value interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		call.dups++
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err, true
+	}
+	call := new(flightCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.value, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	dups := call.dups
+	g.mu.Unlock()
+	if g.client != nil {
+		g.client.statsCollector().addFetch(dups)
+	}
+	return call.value, call.err, false
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFlightGroup tests the method do()
+func TestFlightGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("concurrent calls share one result", func(t *testing.T) {
+		group := new(flightGroup)
+		release := make(chan struct{})
+		var calls, shared int64
+
+		var started, wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			started.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				started.Done()
+				value, err, isShared := group.do(testKey, func() (interface{}, error) {
+					atomic.AddInt64(&calls, 1)
+					<-release
+					return testStringValue, nil
+				})
+				assert.NoError(t, err)
+				assert.Equal(t, testStringValue, value)
+				if isShared {
+					atomic.AddInt64(&shared, 1)
+				}
+			}()
+		}
+		started.Wait()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int64(10), calls+shared)
+		assert.Less(t, calls, int64(10))
+	})
+
+	t.Run("sequential calls are not shared", func(t *testing.T) {
+		group := new(flightGroup)
+		for i := 0; i < 2; i++ {
+			_, _, shared := group.do(testKey, func() (interface{}, error) {
+				return nil, nil
+			})
+			assert.False(t, shared)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// ValueSizePolicy is what happens to a value that is larger than the max value size
+type ValueSizePolicy int
+
+// Value size policies
+const (
+	ValueSizeReject   ValueSizePolicy = iota // Return ErrValueTooLarge
+	ValueSizeTruncate                        // Store the first MaxSize bytes of the value
+	ValueSizeCompress                        // Store the value gzipped (rejected if still too large)
+	ValueSizeChunk                           // Store the value in chunks of MaxSize (see: SetChunked())
+)
+
+// encodedValuePrefix starts every compressed or chunked value (see: decodeValue())
+const encodedValuePrefix = "\x00go-cache:"
+
+// compressedValueHeader starts a value that was compressed by the value size guard
+var compressedValueHeader = []byte(encodedValuePrefix + "gzip\x00")
+
+// ErrValueTooLarge is returned when a value is larger than the max value size
+var ErrValueTooLarge = errors.New("value is larger than the max value size")
+
+// ErrInvalidMaxValueSize is returned when the max value size is not positive
+var ErrInvalidMaxValueSize = errors.New("max value size must be greater than zero")
+
+// ValueSizeHandler is fired when a value is larger than the max value size
+type ValueSizeHandler func(key string, size int, policy ValueSizePolicy)
+
+// ValueSizeConfig is the configuration for the value size guard
+type ValueSizeConfig struct {
+	MaxSize     int              // Max size of a value in bytes
+	OnViolation ValueSizeHandler // Fired for each value above the max size (optional)
+	Policy      ValueSizePolicy  // What happens to a value above the max size (default: reject)
+}
+
+// ValueSizeStats are the running totals for the value size guard
+type ValueSizeStats struct {
+	Chunked    uint64 // Values stored in chunks
+	Compressed uint64 // Values stored compressed
+	Rejected   uint64 // Values rejected with ErrValueTooLarge
+	Truncated  uint64 // Values truncated to the max size
+	Violations uint64 // Values above the max size
+}
+
+// valueSizeGuard applies the max value size for a client
+type valueSizeGuard struct {
+	config ValueSizeConfig
+	stats  ValueSizeStats
+}
+
+// SetValueSizeGuard applies a max value size to Set(), SetExp() and HashSet() (nil removes the guard)
+//
+// Get(), GetBytes() and HashGet() transparently read compressed and chunked values
+func (c *Client) SetValueSizeGuard(config *ValueSizeConfig) error {
+	var guard *valueSizeGuard
+	if config != nil {
+		if config.MaxSize <= 0 {
+			return ErrInvalidMaxValueSize
+		}
+		guard = &valueSizeGuard{config: *config}
+	}
+
+	c.mu.Lock()
+	c.sizeGuard = guard
+	c.mu.Unlock()
+	return nil
+}
+
+// ValueSizeStats returns the running totals for the value size guard
+func (c *Client) ValueSizeStats() ValueSizeStats {
+	c.mu.RLock()
+	guard := c.sizeGuard
+	c.mu.RUnlock()
+	if guard == nil {
+		return ValueSizeStats{}
+	}
+	return ValueSizeStats{
+		Chunked:    atomic.LoadUint64(&guard.stats.Chunked),
+		Compressed: atomic.LoadUint64(&guard.stats.Compressed),
+		Rejected:   atomic.LoadUint64(&guard.stats.Rejected),
+		Truncated:  atomic.LoadUint64(&guard.stats.Truncated),
+		Violations: atomic.LoadUint64(&guard.stats.Violations),
+	}
+}
+
+// guardValue applies the value size guard (if set) to the value
+// A chunk size is returned if the value must be stored in chunks (only if chunks are allowed)
+func (c *Client) guardValue(key string, value interface{}, allowChunks bool) (interface{}, int, error) {
+	c.mu.RLock()
+	guard := c.sizeGuard
+	c.mu.RUnlock()
+	if guard == nil {
+		return value, 0, nil
+	}
+
+	// Only strings and bytes can be too large (bytes are not copied)
+	var data []byte
+	maxSize := guard.config.MaxSize
+	switch v := value.(type) {
+	case string:
+		if len(v) <= maxSize {
+			return value, 0, nil
+		}
+		data = []byte(v)
+	case []byte:
+		if len(v) <= maxSize {
+			return value, 0, nil
+		}
+		data = v
+	default:
+		return value, 0, nil
+	}
+
+	atomic.AddUint64(&guard.stats.Violations, 1)
+	if guard.config.OnViolation != nil {
+		guard.config.OnViolation(key, len(data), guard.config.Policy)
+	}
+
+	switch guard.config.Policy {
+	case ValueSizeTruncate:
+		atomic.AddUint64(&guard.stats.Truncated, 1)
+		return data[:maxSize], 0, nil
+	case ValueSizeCompress:
+		if compressed, err := compressValue(data); err != nil {
+			return nil, 0, err
+		} else if len(compressed) <= maxSize {
+			atomic.AddUint64(&guard.stats.Compressed, 1)
+			return compressed, 0, nil
+		}
+	case ValueSizeChunk:
+		if allowChunks {
+			atomic.AddUint64(&guard.stats.Chunked, 1)
+			return data, maxSize, nil
+		}
+	case ValueSizeReject:
+	}
+
+	atomic.AddUint64(&guard.stats.Rejected, 1)
+	return nil, 0, fmt.Errorf("%w: key %s is %d bytes (max %d)", ErrValueTooLarge, key, len(data), maxSize)
+}
+
+// compressValue gzips the value and adds the compressed value header
+func compressValue(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(compressedValueHeader)
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressValue returns the original value if it was compressed by the value size guard
+func decompressValue(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, compressedValueHeader) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data[len(compressedValueHeader):]))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+	return io.ReadAll(reader)
+}
+
+// decodeValue returns the original value if it was compressed or chunked by the value size guard
+func decodeValue(conn redis.Conn, key string, data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, chunkManifestHeader) {
+		return GetChunkedRaw(conn, key)
+	}
+	return decompressValue(data)
+}
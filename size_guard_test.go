@@ -0,0 +1,169 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_SetValueSizeGuard is testing the method SetValueSizeGuard()
+func TestClient_SetValueSizeGuard(t *testing.T) {
+
+	t.Run("invalid max size", func(t *testing.T) {
+		client := &Client{}
+		err := client.SetValueSizeGuard(&ValueSizeConfig{})
+		assert.ErrorIs(t, err, ErrInvalidMaxValueSize)
+	})
+
+	t.Run("reject using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var violations []string
+		err := client.SetValueSizeGuard(&ValueSizeConfig{
+			MaxSize: 5,
+			OnViolation: func(key string, size int, policy ValueSizePolicy) {
+				violations = append(violations, fmt.Sprintf("%s:%d:%d", key, size, policy))
+			},
+		})
+		assert.NoError(t, err)
+
+		setCmd := conn.GenericCommand(SetCommand)
+
+		// Small values are not changed
+		err = Set(context.Background(), client, testKey, "small")
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+
+		// Large values are rejected
+		err = Set(context.Background(), client, testKey, "too large")
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+
+		err = HashSet(context.Background(), client, testHashName, testKey, []byte("too large"))
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+
+		// Other types are not checked
+		err = Set(context.Background(), client, testKey, 123456789)
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{testKey + ":9:0", testHashName + ":" + testKey + ":9:0"}, violations)
+		assert.Equal(t, ValueSizeStats{Rejected: 2, Violations: 2}, client.ValueSizeStats())
+
+		// Removing the guard
+		err = client.SetValueSizeGuard(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ValueSizeStats{}, client.ValueSizeStats())
+
+		err = Set(context.Background(), client, testKey, "too large")
+		assert.NoError(t, err)
+	})
+
+	t.Run("truncate using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetValueSizeGuard(&ValueSizeConfig{MaxSize: 3, Policy: ValueSizeTruncate})
+		assert.NoError(t, err)
+
+		setCmd := conn.Command(SetCommand, testKey, []byte("too"))
+
+		err = Set(context.Background(), client, testKey, "too large")
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.Equal(t, ValueSizeStats{Truncated: 1, Violations: 1}, client.ValueSizeStats())
+	})
+
+	t.Run("policies using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		value := strings.Repeat("go-cache ", 20)
+
+		// Compressed values are read back transparently
+		err = client.SetValueSizeGuard(&ValueSizeConfig{MaxSize: 100, Policy: ValueSizeCompress})
+		assert.NoError(t, err)
+
+		err = Set(ctx, client, testKey, value, testDependantKey)
+		assert.NoError(t, err)
+
+		var stored string
+		stored, err = Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, value, stored)
+
+		err = HashSet(ctx, client, testHashName, testKey, value)
+		assert.NoError(t, err)
+
+		stored, err = HashGet(ctx, client, testHashName, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, value, stored)
+
+		// Values that do not compress enough are rejected
+		err = Set(ctx, client, testKey, "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"+
+			"zyxwvutsrqponmlkjihgfedcba9876543210ZYXWVUTSRQPONMLKJIHGFEDCBA")
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+
+		// Chunked values are read back transparently
+		err = client.SetValueSizeGuard(&ValueSizeConfig{MaxSize: 50, Policy: ValueSizeChunk})
+		assert.NoError(t, err)
+
+		err = SetExp(ctx, client, testKey, value, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+
+		var storedBytes []byte
+		storedBytes, err = GetBytes(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(value), storedBytes)
+
+		// Hashes cannot be chunked
+		err = HashSet(ctx, client, testHashName, testKey, value)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		assert.Equal(t, ValueSizeStats{Chunked: 1, Rejected: 1, Violations: 2}, client.ValueSizeStats())
+
+		// All chunks are removed by the dependency
+		_, err = KillByDependencyRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+
+		var keys []string
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testHashName}, keys)
+	})
+}
+
+// ExampleClient_SetValueSizeGuard is an example of the method SetValueSizeGuard()
+func ExampleClient_SetValueSizeGuard() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Reject values larger than 1KB
+	_ = client.SetValueSizeGuard(&ValueSizeConfig{MaxSize: 1024, Policy: ValueSizeReject})
+
+	// Try to set a 2KB value
+	err := Set(context.Background(), client, "large-key", make([]byte, 2048))
+	fmt.Printf("rejected: %v", err != nil)
+	// Output:rejected: true
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// skipDependenciesKey is the context key for skipping the dependency bookkeeping
+type skipDependenciesKey struct{}
+
+// WithSkipDependencies returns a context that skips the dependency bookkeeping for each write using
+// a connection from the client (the MULTI/SADD/EXEC round trips), see: SetSkipDependencies()
+func WithSkipDependencies(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipDependenciesKey{}, true)
+}
+
+// SetSkipDependencies switches skipping the dependency bookkeeping for all writes on or off
+// (safe to call at any time)
+//
+// Use for hot write paths that never need invalidation by dependency (see: KillByDependency()),
+// keys written while skipping are not removed with their dependencies
+func (c *Client) SetSkipDependencies(skip bool) {
+	var value uint32
+	if skip {
+		value = 1
+	}
+	atomic.StoreUint32(&c.skipDependencies, value)
+}
+
+// SkipsDependencies returns true if the dependency bookkeeping is skipped for all writes
+func (c *Client) SkipsDependencies() bool {
+	return atomic.LoadUint32(&c.skipDependencies) == 1
+}
+
+// skipsDependencies returns true if the dependency bookkeeping is skipped for the client or context
+func (c *Client) skipsDependencies(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipDependenciesKey{}).(bool)
+	return skip || c.SkipsDependencies()
+}
+
+// skipDependenciesConn marks a connection that skips the dependency bookkeeping
+type skipDependenciesConn struct {
+	redis.Conn
+}
+
+// unwrap returns the wrapped connection
+func (c *skipDependenciesConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// DoWithTimeout runs the command with the timeout
+func (c *skipDependenciesConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *skipDependenciesConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
+
+// skipsDependencies returns true if the connection skips the dependency bookkeeping
+func skipsDependencies(conn redis.Conn) bool {
+	_, skip := findConn[*skipDependenciesConn](conn)
+	return skip
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetSkipDependencies tests the method SetSkipDependencies()
+func TestSetSkipDependencies(t *testing.T) {
+
+	t.Run("skip for the client using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		assert.False(t, client.SkipsDependencies())
+		client.SetSkipDependencies(true)
+		assert.True(t, client.SkipsDependencies())
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		multiCmd := conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+
+		err := Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, setCmd.Called)
+		assert.False(t, multiCmd.Called)
+		assert.False(t, depCmd.Called)
+
+		// Switched back on
+		client.SetSkipDependencies(false)
+		assert.False(t, client.SkipsDependencies())
+		conn.Command(ExecuteCommand)
+
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("skip for the context using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+
+		err := Set(WithSkipDependencies(context.Background()), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.False(t, depCmd.Called)
+	})
+
+	t.Run("skip for the write using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+
+		written, err := SetWith(context.Background(), client, testKey, testStringValue,
+			WithDependencies(testDependantKey), SkipDependencies())
+		assert.NoError(t, err)
+		assert.True(t, written)
+		assert.True(t, setCmd.Called)
+		assert.False(t, depCmd.Called)
+	})
+
+	t.Run("skip using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := WithSkipDependencies(context.Background())
+		err = Set(ctx, client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+
+		var found bool
+		found, err = ExistsRaw(conn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		found, err = ExistsRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.True(t, found)
+	})
+}
+
+// ExampleClient_SetSkipDependencies is an example of the method SetSkipDependencies()
+func ExampleClient_SetSkipDependencies() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Skip the dependency bookkeeping for all writes
+	client.SetSkipDependencies(true)
+
+	// Only the set command is sent
+	conn.Command(SetCommand, testKey, testStringValue)
+	err := Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+	fmt.Printf("error: %v skipping: %v", err, client.SkipsDependencies())
+	// Output:error: <nil> skipping: true
+}
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"log"
+	"time"
+)
+
+// SlowCommand is a command that exceeded the slow command threshold (see: SetSlowCommandThreshold())
+type SlowCommand struct {
+	Command  string        // Command name (IE: GET)
+	Duration time.Duration // Round trip of the command (0 for pipelined commands)
+	Key      string        // First key of the command (if any)
+	PoolWait time.Duration // Time spent waiting for a connection from the pool (first command only)
+}
+
+// SlowCommandHandler is fired for each slow command
+type SlowCommandHandler func(command SlowCommand)
+
+// slowLog is the slow command threshold and handler
+type slowLog struct {
+	handler   SlowCommandHandler
+	threshold time.Duration
+}
+
+// SetSlowCommandThreshold reports each command that takes longer than the threshold, including
+// the time spent waiting for a connection from the pool (which SLOWLOG on the server misses)
+// The handler is optional (default: log), a threshold of 0 switches the slow command log off
+func (c *Client) SetSlowCommandThreshold(threshold time.Duration, handler SlowCommandHandler) {
+	var slow *slowLog
+	if threshold > 0 {
+		if handler == nil {
+			handler = logSlowCommand
+		}
+		slow = &slowLog{handler: handler, threshold: threshold}
+	}
+	c.mu.Lock()
+	c.slowLog = slow
+	c.mu.Unlock()
+}
+
+// logSlowCommand is the default handler for slow commands
+func logSlowCommand(command SlowCommand) {
+	log.Printf("go-cache: slow command %s %s took %s (pool wait: %s)",
+		command.Command, command.Key, command.Duration, command.PoolWait)
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stepClock is a clock that moves forward by the step on every call of Now()
+type stepClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// After returns a channel that fires right away
+func (c *stepClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+// Now returns the time and moves the clock forward
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+// TestClient_SetSlowCommandThreshold tests the method SetSlowCommandThreshold()
+func TestClient_SetSlowCommandThreshold(t *testing.T) {
+
+	t.Run("reports slow commands using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		conn.Command(MultiCommand)
+		conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		// Each command takes 10ms, as does getting a connection
+		client.SetClock(&stepClock{now: time.Now(), step: 10 * time.Millisecond})
+
+		var slow []SlowCommand
+		client.SetSlowCommandThreshold(10*time.Millisecond, func(command SlowCommand) {
+			slow = append(slow, command)
+		})
+
+		// Slow including the pool wait
+		_, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []SlowCommand{{
+			Command:  GetCommand,
+			Duration: 10 * time.Millisecond,
+			Key:      testKey,
+			PoolWait: 10 * time.Millisecond,
+		}}, slow)
+
+		// The pool wait is only added to the first command (pipelined commands have no duration)
+		slow = nil
+		err = LinkDependencies(context.Background(), client, testKey, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []SlowCommand{
+			{Command: MultiCommand, PoolWait: 10 * time.Millisecond},
+			{Command: ExecuteCommand, Duration: 10 * time.Millisecond},
+		}, slow)
+
+		// Switched off
+		slow = nil
+		client.SetSlowCommandThreshold(0, nil)
+		_, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Empty(t, slow)
+	})
+
+	t.Run("fast commands are not reported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		client.SetSlowCommandThreshold(time.Hour, func(command SlowCommand) {
+			t.Fatalf("unexpected slow command: %s", command.Command)
+		})
+		_, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+	})
+}
+
+// ExampleClient_SetSlowCommandThreshold is an example of the method SetSlowCommandThreshold()
+func ExampleClient_SetSlowCommandThreshold() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Report every command (IE: use 100ms in production)
+	client.SetSlowCommandThreshold(time.Nanosecond, func(command SlowCommand) {
+		fmt.Printf("slow command: %s %s", command.Command, command.Key)
+	})
+
+	// Mock and run a command
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+	_, _ = Get(context.Background(), client, testKey)
+	// Output:slow command: GET test-key-name
+}
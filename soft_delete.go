@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// TrashPrefix is the prefix for soft deleted keys (see: SoftDelete())
+const TrashPrefix = "trash:"
+
+// trashMetaPrefix is the prefix for the original TTL and dependencies of soft deleted keys
+const trashMetaPrefix = "trash-meta:"
+
+// ErrRestoreConflict is returned when a soft deleted key is restored but the key was written again
+var ErrRestoreConflict = errors.New("key already exists, soft deleted key was not restored")
+
+// softDeleteLua moves the key into the trash and unlinks it from the dependencies
+//
+// KEYS[1] = key, KEYS[2] = trash key, KEYS[3] = meta key
+// ARGV[1] = retention (ms), ARGV[2] = dependencies (JSON), ARGV[3...] = dependency sets
+// Returns 1 if the key was moved, 0 if the key does not exist
+const softDeleteLua = `
+--@begin=lua@
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl == -2 then
+	return 0
+end
+redis.call("` + RenameCommand + `", KEYS[1], KEYS[2])
+redis.call("` + ExpireMillisCommand + `", KEYS[2], ARGV[1])
+redis.call("` + DeleteCommand + `", KEYS[3])
+redis.call("` + HashKeySetCommand + `", KEYS[3], "ttl", ttl, "dependencies", ARGV[2])
+redis.call("` + ExpireMillisCommand + `", KEYS[3], ARGV[1])
+for i = 3, #ARGV do
+	redis.call("` + RemoveMemberCommand + `", ARGV[i], KEYS[1])
+end
+return 1
+--@end=lua@
+`
+
+// restoreKeyLua moves the key out of the trash with its original TTL and links it to the dependencies
+//
+// KEYS[1] = key, KEYS[2] = trash key, KEYS[3] = meta key, ARGV = dependency sets
+// Returns 1 if the key was restored, 0 if the key is not in the trash, -1 if the key exists
+const restoreKeyLua = `
+--@begin=lua@
+if redis.call("` + ExistsCommand + `", KEYS[2]) == 0 then
+	return 0
+elseif redis.call("` + ExistsCommand + `", KEYS[1]) == 1 then
+	return -1
+end
+local ttl = tonumber(redis.call("` + HashGetCommand + `", KEYS[3], "ttl")) or -1
+redis.call("` + RenameCommand + `", KEYS[2], KEYS[1])
+if ttl > 0 then
+	redis.call("` + ExpireMillisCommand + `", KEYS[1], ttl)
+else
+	redis.call("` + PersistCommand + `", KEYS[1])
+end
+for i = 1, #ARGV do
+	redis.call("` + AddToSetCommand + `", ARGV[i], KEYS[1])
+end
+redis.call("` + DeleteCommand + `", KEYS[3])
+return 1
+--@end=lua@
+`
+
+// Soft delete scripts (EVALSHA with a fallback to EVAL)
+var (
+	restoreKeyScript = newScript("restore_key", 3, restoreKeyLua)
+	softDeleteScript = newScript("soft_delete", 3, softDeleteLua)
+)
+
+// SoftDelete moves the key into the trash (TrashPrefix) for the retention, and unlinks it from the
+// dependencies, giving an undo window on risky deletes (see: RestoreKey())
+// Returns false if the key does not exist
+//
+// The dependencies are the ones the key is linked to (they are linked again on restore), the
+// remaining TTL of the key is kept for the restore (time spent in the trash is not counted)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SoftDeleteRaw()
+func SoftDelete(ctx context.Context, client *Client, key string, retention time.Duration,
+	dependencies ...string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	deleted, err := SoftDeleteRaw(conn, key, retention, dependencies...)
+	if deleted {
+		client.fireRemoveHooks(ctx, OperationDelete, []string{key}, 1, err)
+	}
+	return deleted, err
+}
+
+// SoftDeleteRaw moves the key into the trash for the retention, and unlinks it from the dependencies
+// Returns false if the key does not exist
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/pttl
+// https://redis.io/commands/rename
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/hset
+// https://redis.io/commands/srem
+func SoftDeleteRaw(conn redis.Conn, key string, retention time.Duration, dependencies ...string) (bool, error) {
+	if len(key) == 0 {
+		return false, errors.New("missing required parameter: key")
+	} else if retention < time.Millisecond {
+		return false, ErrInvalidTTL
+	}
+	if dependencies == nil {
+		dependencies = []string{}
+	}
+	encoded, err := json.Marshal(dependencies)
+	if err != nil {
+		return false, err
+	}
+
+	args := redis.Args{}.Add(key, TrashPrefix+key, trashMetaPrefix+key, retention.Milliseconds(), encoded)
+	for _, dependency := range dependencies {
+		args = args.Add(dependencySetKey(conn, dependency, key))
+	}
+	return redis.Bool(softDeleteScript.Do(conn, args...))
+}
+
+// RestoreKey moves a soft deleted key out of the trash with its remaining TTL, and links it to
+// its dependencies again (see: SoftDelete())
+// Returns false if the key is not in the trash (IE: the retention ended), and ErrRestoreConflict if
+// the key was written again since it was deleted
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: RestoreKeyRaw()
+func RestoreKey(ctx context.Context, client *Client, key string) (bool, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return RestoreKeyRaw(conn, key)
+}
+
+// RestoreKeyRaw moves a soft deleted key out of the trash with its remaining TTL, and links it to
+// its dependencies again
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/hget
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/rename
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/sadd
+func RestoreKeyRaw(conn redis.Conn, key string) (bool, error) {
+	if len(key) == 0 {
+		return false, errors.New("missing required parameter: key")
+	}
+
+	// Dependencies recorded by the soft delete
+	var dependencies []string
+	encoded, err := redis.Bytes(conn.Do(HashGetCommand, trashMetaPrefix+key, "dependencies"))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return false, err
+	} else if len(encoded) > 0 {
+		if err = json.Unmarshal(encoded, &dependencies); err != nil {
+			return false, err
+		}
+	}
+
+	args := redis.Args{}.Add(key, TrashPrefix+key, trashMetaPrefix+key)
+	for _, dependency := range dependencies {
+		args = args.Add(dependencySetKey(conn, dependency, key))
+	}
+	var restored int
+	if restored, err = redis.Int(restoreKeyScript.Do(conn, args...)); err != nil {
+		return false, err
+	} else if restored < 0 {
+		return false, ErrRestoreConflict
+	}
+	return restored == 1, nil
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSoftDelete tests the methods SoftDelete() and RestoreKey()
+func TestSoftDelete(t *testing.T) {
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := SoftDelete(context.Background(), client, "", time.Hour)
+		assert.Error(t, err)
+		_, err = SoftDelete(context.Background(), client, testKey, 0)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+		_, err = RestoreKey(context.Background(), client, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("soft delete using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		deleteCmd := conn.Script([]byte(softDeleteLua), 3, testKey, TrashPrefix+testKey, trashMetaPrefix+testKey,
+			int64(60000), []byte(`["`+testDependantKey+`"]`), DependencyPrefix+testDependantKey).Expect(int64(1))
+
+		deleted, err := SoftDelete(context.Background(), client, testKey, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, true, deleted)
+		assert.Equal(t, 1, conn.Stats(deleteCmd))
+	})
+
+	t.Run("restore conflict using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(HashGetCommand, trashMetaPrefix+testKey, "dependencies").Expect([]byte(`[]`))
+		conn.Script([]byte(restoreKeyLua), 3, testKey, TrashPrefix+testKey, trashMetaPrefix+testKey).
+			Expect(int64(-1))
+
+		restored, err := RestoreKey(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, ErrRestoreConflict)
+		assert.Equal(t, false, restored)
+	})
+
+	t.Run("soft delete and restore using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetExpRaw(conn, testKey, testStringValue, time.Hour, testDependantKey)
+		assert.NoError(t, err)
+
+		// Missing keys are not deleted
+		var deleted bool
+		deleted, err = SoftDelete(context.Background(), client, "missing", time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, false, deleted)
+
+		deleted, err = SoftDelete(context.Background(), client, testKey, time.Minute, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, true, deleted)
+
+		var value string
+		value, err = GetRaw(conn, testKey)
+		assert.Error(t, err)
+		assert.Equal(t, "", value)
+
+		value, err = GetRaw(conn, TrashPrefix+testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var members []string
+		members, err = SetMembersRaw(conn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(members))
+
+		var restored bool
+		restored, err = RestoreKey(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, true, restored)
+
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		var info []KeyInfo
+		info, err = KeyInfoMultiRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(info))
+		assert.Greater(t, info[0].TTL, 59*time.Minute)
+
+		members, err = SetMembersRaw(conn, DependencyPrefix+testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{testKey}, members)
+
+		// Nothing left in the trash
+		restored, err = RestoreKey(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, false, restored)
+
+		// A key written after the soft delete is not overwritten
+		_, err = SoftDelete(context.Background(), client, testKey, time.Minute)
+		assert.NoError(t, err)
+		err = SetRaw(conn, testKey, "new-value")
+		assert.NoError(t, err)
+
+		_, err = RestoreKey(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, ErrRestoreConflict)
+
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-value", value)
+	})
+}
+
+// ExampleSoftDelete is an example of the method SoftDelete()
+func ExampleSoftDelete() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the soft delete script
+	conn.Script([]byte(softDeleteLua), 3, "user:1", TrashPrefix+"user:1", trashMetaPrefix+"user:1",
+		int64(3600000), []byte(`[]`)).Expect(int64(1))
+
+	deleted, _ := SoftDelete(context.Background(), client, "user:1", time.Hour)
+	fmt.Printf("soft deleted: %t", deleted)
+	// Output:soft deleted: true
+}
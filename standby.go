@@ -0,0 +1,215 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/mrz1836/go-cache/nrredis"
+)
+
+// Default standby settings
+const (
+	defaultStandbyFailures  = 3
+	defaultStandbyInterval  = 5 * time.Second
+	defaultStandbyRecovered = 3
+)
+
+// StandbyConfig is the configuration for ConnectWithStandby()
+type StandbyConfig struct {
+	FailureThreshold  int             // Failed health checks in a row before promoting the standby (default: 3)
+	Interval          time.Duration   // Time between health checks of the primary (default: 5s)
+	OnError           func(err error) // Fired when a health check fails or the scripts can't be loaded (optional)
+	OnPromote         func()          // Fired when the traffic is switched to the standby (optional)
+	OnRecover         func()          // Fired when the traffic is switched back to the primary (optional)
+	ReadOnly          bool            // Commands that modify data are rejected while on the standby
+	RecoveryThreshold int             // Healthy checks in a row before switching back (default: 3)
+	URL               string          // Url of the standby (required)
+}
+
+// standby checks the primary in the background and switches the traffic to the standby
+type standby struct {
+	cancel    context.CancelFunc
+	config    StandbyConfig
+	dependent bool
+	done      chan struct{}
+	failures  int
+	primary   nrredis.Pool
+	promoted  bool
+	readOnly  bool
+	recovered int
+	standby   nrredis.Pool
+}
+
+// ConnectWithStandby creates a new connection pool to the primary (config URL) and a warm standby
+// pool (standby URL, same settings), the primary is checked (PING) in the background
+//
+// On sustained primary failure the traffic is switched to the standby (read-only if set) and
+// switched back once the primary recovers, firing the hooks. Simpler than Sentinel for small
+// deployments, the data is not copied between the primary and the standby
+// The health checks are stopped via Close()
+func ConnectWithStandby(ctx context.Context, config Config, standbyConfig StandbyConfig,
+	options ...redis.DialOption) (*Client, error) {
+
+	// Required param for the standby
+	if len(standbyConfig.URL) == 0 {
+		return nil, errors.New("missing required parameter: standby url")
+	}
+	if standbyConfig.FailureThreshold <= 0 {
+		standbyConfig.FailureThreshold = defaultStandbyFailures
+	}
+	if standbyConfig.Interval <= 0 {
+		standbyConfig.Interval = defaultStandbyInterval
+	}
+	if standbyConfig.RecoveryThreshold <= 0 {
+		standbyConfig.RecoveryThreshold = defaultStandbyRecovered
+	}
+
+	client, err := ConnectWithConfig(ctx, config, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The standby pool is warm, but it is not used until the standby is promoted
+	standbyPoolConfig := config
+	standbyPoolConfig.URL = standbyConfig.URL
+	standbyPoolConfig.onDial = client.nameConnection
+	var pool nrredis.Pool
+	if pool, err = newPool(standbyPoolConfig, standbyPoolConfig.dialOptions(options...)...); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	// Start checking the primary in the background
+	standbyCtx, cancel := context.WithCancel(context.Background())
+	s := &standby{
+		cancel:    cancel,
+		config:    standbyConfig,
+		dependent: config.DependencyMode,
+		done:      make(chan struct{}),
+		primary:   client.primaryPool(),
+		standby:   pool,
+	}
+	client.mu.Lock()
+	client.standby = s
+	client.mu.Unlock()
+
+	go client.runStandby(standbyCtx, s)
+	return client, nil
+}
+
+// IsOnStandby returns true if the traffic was switched to the standby (see: ConnectWithStandby())
+func (c *Client) IsOnStandby() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.standby != nil && c.standby.promoted
+}
+
+// StopStandby stops checking the primary in the background and closes the pool that is not in use
+// (the traffic stays on the current pool)
+func (c *Client) StopStandby() {
+	c.mu.Lock()
+	s := c.standby
+	c.standby = nil
+	c.mu.Unlock()
+
+	if s == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	if s.promoted {
+		_ = s.primary.Close()
+	} else {
+		_ = s.standby.Close()
+	}
+}
+
+// runStandby checks the primary until the context is canceled
+func (c *Client) runStandby(ctx context.Context, s *standby) {
+	defer close(s.done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.Clock().After(s.config.Interval):
+		}
+
+		err := checkPool(ctx, s.primary)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+		c.updateStandby(ctx, s, err == nil)
+	}
+}
+
+// updateStandby counts the health check and switches the traffic once a threshold is reached
+func (c *Client) updateStandby(ctx context.Context, s *standby, healthy bool) {
+	if healthy {
+		s.failures = 0
+		s.recovered++
+	} else {
+		s.recovered = 0
+		s.failures++
+	}
+
+	var pool nrredis.Pool
+	if !s.promoted && s.failures >= s.config.FailureThreshold {
+		pool = s.standby
+	} else if s.promoted && s.recovered >= s.config.RecoveryThreshold {
+		pool = s.primary
+	} else {
+		return
+	}
+
+	// The dependency script must be loaded on the new pool
+	if s.dependent {
+		if err := loadDependencyScript(ctx, pool); err != nil && s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+	}
+
+	c.mu.Lock()
+	c.Pool = pool
+	c.capabilities = nil
+	s.promoted = !s.promoted
+	c.mu.Unlock()
+
+	if s.promoted {
+		if s.config.ReadOnly {
+			s.readOnly = c.IsReadOnly()
+			c.SetReadOnly(true)
+		}
+		if s.config.OnPromote != nil {
+			s.config.OnPromote()
+		}
+		return
+	}
+	if s.config.ReadOnly {
+		c.SetReadOnly(s.readOnly)
+	}
+	if s.config.OnRecover != nil {
+		s.config.OnRecover()
+	}
+}
+
+// checkPool sends a PING using a connection from the pool (bounded to 1 second)
+//
+// Spec: https://redis.io/commands/ping
+func checkPool(ctx context.Context, pool nrredis.Pool) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultReadyTimeout)
+	defer cancel()
+
+	conn, err := pool.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer CloseConnection(conn)
+	_, err = redis.DoWithTimeout(conn, defaultReadyTimeout, PingCommand)
+	return err
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/rafaeljusto/redigomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadMockStandby sets a standby with a mocked pool on the mocked client
+func loadMockStandby(client *Client, config StandbyConfig) (*standby, *redigomock.Conn) {
+	standbyConn := redigomock.NewConn()
+	s := &standby{
+		cancel:  func() {},
+		config:  config,
+		done:    make(chan struct{}),
+		primary: client.Pool,
+		standby: &redis.Pool{Dial: func() (redis.Conn, error) { return standbyConn, nil }},
+	}
+	close(s.done)
+	client.standby = s
+	return s, standbyConn
+}
+
+// TestConnectWithStandby tests the method ConnectWithStandby()
+func TestConnectWithStandby(t *testing.T) {
+
+	t.Run("missing standby url", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := ConnectWithStandby(context.Background(), Config{URL: testLocalConnectionURL}, StandbyConfig{})
+		assert.Error(t, err)
+		assert.Nil(t, client)
+	})
+
+	t.Run("promote and recover using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var promoted, recovered int
+		s, standbyConn := loadMockStandby(client, StandbyConfig{
+			FailureThreshold:  2,
+			OnPromote:         func() { promoted++ },
+			OnRecover:         func() { recovered++ },
+			ReadOnly:          true,
+			RecoveryThreshold: 2,
+		})
+		primary := client.Pool
+
+		// A single failure is not sustained
+		client.updateStandby(context.Background(), s, false)
+		client.updateStandby(context.Background(), s, true)
+		client.updateStandby(context.Background(), s, false)
+		assert.False(t, client.IsOnStandby())
+
+		client.updateStandby(context.Background(), s, false)
+		assert.True(t, client.IsOnStandby())
+		assert.True(t, client.IsReadOnly())
+		assert.Equal(t, 1, promoted)
+
+		// The traffic goes to the standby
+		getCmd := standbyConn.Command(GetCommand, testKey).Expect(testStringValue)
+		val, err := Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+		assert.Equal(t, 1, standbyConn.Stats(getCmd))
+
+		// The primary recovered
+		client.updateStandby(context.Background(), s, true)
+		assert.True(t, client.IsOnStandby())
+		client.updateStandby(context.Background(), s, true)
+		assert.False(t, client.IsOnStandby())
+		assert.False(t, client.IsReadOnly())
+		assert.Equal(t, 1, recovered)
+		assert.Equal(t, primary, client.Pool)
+	})
+
+	t.Run("health check using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(PingCommand).Expect("PONG")
+		assert.NoError(t, checkPool(context.Background(), client.Pool))
+
+		failing := &redis.Pool{Dial: func() (redis.Conn, error) { return nil, errors.New("connection refused") }}
+		assert.Error(t, checkPool(context.Background(), failing))
+	})
+
+	t.Run("promote the standby using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		promoted := make(chan struct{})
+		client, err := ConnectWithStandby(context.Background(), Config{
+			ConnectTimeout:       100 * time.Millisecond,
+			MaxActiveConnections: testMaxActiveConnections,
+			MaxIdleConnections:   testMaxIdleConnections,
+			URL:                  "redis://localhost:1",
+		}, StandbyConfig{
+			FailureThreshold: 2,
+			Interval:         10 * time.Millisecond,
+			OnPromote:        func() { close(promoted) },
+			URL:              testLocalConnectionURL,
+		})
+		assert.NoError(t, err)
+		defer client.Close()
+
+		select {
+		case <-promoted:
+		case <-time.After(5 * time.Second):
+			t.Fatal("standby was not promoted")
+		}
+		assert.True(t, client.IsOnStandby())
+
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var val string
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, val)
+	})
+}
+
+// ExampleConnectWithStandby is an example of the method ConnectWithStandby()
+func ExampleConnectWithStandby() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mocked standby (use ConnectWithStandby() with the standby url)
+	s, _ := loadMockStandby(client, StandbyConfig{
+		FailureThreshold: 1,
+		OnPromote:        func() { fmt.Print("standby promoted, ") },
+		ReadOnly:         true,
+	})
+
+	// The primary failed its health check
+	client.updateStandby(context.Background(), s, false)
+	fmt.Printf("read-only: %t", client.IsReadOnly())
+	// Output:standby promoted, read-only: true
+}
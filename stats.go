@@ -0,0 +1,244 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CommandStats are the running totals for a single command
+type CommandStats struct {
+	Calls    uint64        `json:"calls"`    // Commands sent
+	Duration time.Duration `json:"duration"` // Total round trip (pipelined commands are not timed)
+	Errors   uint64        `json:"errors"`   // Commands that returned an error
+}
+
+// PoolStats are the connection pool stats of the primary pool
+type PoolStats struct {
+	ActiveCount  int           `json:"active_count"`  // Connections in the pool (in use or idle)
+	IdleCount    int           `json:"idle_count"`    // Idle connections in the pool
+	WaitCount    int64         `json:"wait_count"`    // Connections waited for
+	WaitDuration time.Duration `json:"wait_duration"` // Total time waited for connections
+}
+
+// HerdStats are the running totals of the herd protection: fetches shared by concurrent misses
+// (IE: Memoize(), ObjectCache, RegisterLoader()) and lock contention (WriteLock(), WaitForLock())
+type HerdStats struct {
+	Fetches          uint64        `json:"fetches"`            // Fetches that called the backend
+	LockContended    uint64        `json:"lock_contended"`     // Lock attempts that found the lock held by someone else
+	LockTimeouts     uint64        `json:"lock_timeouts"`      // Lock waits that ended without the lock (context done)
+	LockWaitDuration time.Duration `json:"lock_wait_duration"` // Total time waited for locks
+	LockWaits        uint64        `json:"lock_waits"`         // Lock waits (acquired after waiting or timed out)
+	SavedCalls       uint64        `json:"saved_calls"`        // Callers that shared a fetch (backend calls saved)
+	SharedFetches    uint64        `json:"shared_fetches"`     // Fetches that were shared with other callers
+}
+
+// ClientStats are the running totals of the client (see: SetCommandStats())
+type ClientStats struct {
+	Commands map[string]CommandStats `json:"commands"` // Totals by command name
+	Herd     HerdStats               `json:"herd"`     // Shared fetches and lock contention
+	Hits     uint64                  `json:"hits"`     // Reads that found the key or field (GET, HGET, MGET, GETDEL)
+	Load     LoadStats               `json:"load"`     // Load shedding stats (see: SetConcurrencyLimit())
+	Misses   uint64                  `json:"misses"`   // Reads that did not find the key or field
+	Pool     PoolStats               `json:"pool"`     // Primary pool stats
+	Queue    QueueStats              `json:"queue"`    // Priority queue stats (see: SetPriorityQueue())
+}
+
+// hitCommands are the reads counted as hits or misses
+var hitCommands = map[string]struct{}{
+	GetCommand:       {},
+	GetDeleteCommand: {},
+	HashGetCommand:   {},
+	MultiGetCommand:  {},
+}
+
+// commandStats collects the running totals of the commands
+type commandStats struct {
+	commands      map[string]*CommandStats
+	fetches       uint64
+	hits          uint64
+	lockContended uint64
+	lockTimeouts  uint64
+	lockWait      int64
+	lockWaits     uint64
+	misses        uint64
+	mu            sync.Mutex
+	saved         uint64
+	sharedFetches uint64
+}
+
+// add adds the command to the totals
+func (s *commandStats) add(commandName string, duration time.Duration, err error) {
+	s.mu.Lock()
+	stats, ok := s.commands[commandName]
+	if !ok {
+		stats = new(CommandStats)
+		s.commands[commandName] = stats
+	}
+	stats.Calls++
+	stats.Duration += duration
+	if err != nil {
+		stats.Errors++
+	}
+	s.mu.Unlock()
+}
+
+// addReply counts the reply of a read as hits or misses (nil replies are misses)
+func (s *commandStats) addReply(commandName string, reply interface{}, err error) {
+	if s == nil || err != nil {
+		return
+	} else if _, ok := hitCommands[commandName]; !ok {
+		return
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		values = []interface{}{reply}
+	}
+	for _, value := range values {
+		if value == nil {
+			atomic.AddUint64(&s.misses, 1)
+		} else {
+			atomic.AddUint64(&s.hits, 1)
+		}
+	}
+}
+
+// addFetch counts a fetch and the callers that shared it
+func (s *commandStats) addFetch(saved int) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.fetches, 1)
+	if saved > 0 {
+		atomic.AddUint64(&s.sharedFetches, 1)
+		atomic.AddUint64(&s.saved, uint64(saved))
+	}
+}
+
+// addLockContended counts a lock attempt that found the lock held by someone else
+func (s *commandStats) addLockContended() {
+	if s != nil {
+		atomic.AddUint64(&s.lockContended, 1)
+	}
+}
+
+// addLockWait counts a lock wait and if it ended without the lock
+func (s *commandStats) addLockWait(duration time.Duration, timedOut bool) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.lockWaits, 1)
+	atomic.AddInt64(&s.lockWait, int64(duration))
+	if timedOut {
+		atomic.AddUint64(&s.lockTimeouts, 1)
+	}
+}
+
+// statsCollector returns the running totals (nil if not collected, see: SetCommandStats())
+func (c *Client) statsCollector() *commandStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stats
+}
+
+// SetCommandStats switches collecting the running totals of the commands on or off
+// (see: Stats(), PublishExpvar(), StatsHandler()), switching it off resets the totals
+func (c *Client) SetCommandStats(enabled bool) {
+	var stats *commandStats
+	if enabled {
+		stats = &commandStats{commands: make(map[string]*CommandStats)}
+	}
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+}
+
+// Stats returns the running totals of the client and the primary pool stats
+// The command totals are empty if they are not collected (see: SetCommandStats())
+func (c *Client) Stats() ClientStats {
+	result := ClientStats{Commands: make(map[string]CommandStats)}
+	if pool := c.primaryPool(); pool != nil {
+		poolStats := pool.Stats()
+		result.Pool = PoolStats{
+			ActiveCount:  poolStats.ActiveCount,
+			IdleCount:    poolStats.IdleCount,
+			WaitCount:    poolStats.WaitCount,
+			WaitDuration: poolStats.WaitDuration,
+		}
+	}
+
+	c.mu.RLock()
+	queue, shedder := c.priorityQueue, c.loadShedder
+	c.mu.RUnlock()
+	if queue != nil {
+		result.Queue = queue.stats()
+	}
+	if shedder != nil {
+		result.Load = shedder.stats()
+	}
+
+	stats := c.statsCollector()
+	if stats == nil {
+		return result
+	}
+
+	stats.mu.Lock()
+	for name, command := range stats.commands {
+		result.Commands[name] = *command
+	}
+	stats.mu.Unlock()
+	result.Hits = atomic.LoadUint64(&stats.hits)
+	result.Misses = atomic.LoadUint64(&stats.misses)
+	result.Herd = HerdStats{
+		Fetches:          atomic.LoadUint64(&stats.fetches),
+		LockContended:    atomic.LoadUint64(&stats.lockContended),
+		LockTimeouts:     atomic.LoadUint64(&stats.lockTimeouts),
+		LockWaitDuration: time.Duration(atomic.LoadInt64(&stats.lockWait)),
+		LockWaits:        atomic.LoadUint64(&stats.lockWaits),
+		SavedCalls:       atomic.LoadUint64(&stats.saved),
+		SharedFetches:    atomic.LoadUint64(&stats.sharedFetches),
+	}
+	return result
+}
+
+// PublishExpvar publishes the stats of the client (see: Stats()) as an expvar under the name
+// (IE: picked up from /debug/vars), collecting the command totals if they are not collected yet
+// An error is returned if the name is already published
+func (c *Client) PublishExpvar(name string) error {
+	if len(name) == 0 {
+		return errors.New("missing required parameter: name")
+	} else if expvar.Get(name) != nil {
+		return errors.New("expvar is already published: " + name)
+	}
+
+	c.mu.RLock()
+	collecting := c.stats != nil
+	c.mu.RUnlock()
+	if !collecting {
+		c.SetCommandStats(true)
+	}
+
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.Stats()
+	}))
+	return nil
+}
+
+// StatsHandler returns an admin handler that responds with the stats of the client as JSON
+// The handler has no authentication, only mount it on an internal admin router
+func StatsHandler(client *Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(client.Stats())
+	})
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestClient_Stats tests the method Stats()
+func TestClient_Stats(t *testing.T) {
+
+	t.Run("command totals using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+		conn.Command(GetCommand, "missing").Expect(nil)
+		conn.Command(GetCommand, "broken").ExpectError(errors.New("connection lost"))
+		conn.Command(MultiGetCommand, testKey, "missing").Expect([]interface{}{[]byte(testStringValue), nil})
+
+		// Off by default
+		ctx := context.Background()
+		_, _ = Get(ctx, client, testKey)
+		assert.Empty(t, client.Stats().Commands)
+
+		client.SetCommandStats(true)
+		_, _ = Get(ctx, client, testKey)
+		_, _ = Get(ctx, client, "missing")
+		_, _ = Get(ctx, client, "broken")
+		custom, _ := client.GetConnectionWithContext(ctx)
+		_, _ = custom.Do(MultiGetCommand, testKey, "missing")
+		client.CloseConnection(custom)
+
+		stats := client.Stats()
+		assert.Equal(t, uint64(3), stats.Commands[GetCommand].Calls)
+		assert.Equal(t, uint64(1), stats.Commands[GetCommand].Errors)
+		assert.Equal(t, uint64(1), stats.Commands[MultiGetCommand].Calls)
+		assert.Equal(t, uint64(2), stats.Hits)
+		assert.Equal(t, uint64(2), stats.Misses)
+		assert.Equal(t, 1, stats.Pool.ActiveCount)
+
+		// Switched off
+		client.SetCommandStats(false)
+		assert.Empty(t, client.Stats().Commands)
+	})
+
+	t.Run("shared fetches", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		client.SetCommandStats(true)
+
+		group := &flightGroup{client: client}
+		release := make(chan struct{})
+		var started, wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			started.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				started.Done()
+				_, _, _ = group.do(testKey, func() (interface{}, error) {
+					<-release
+					return testStringValue, nil
+				})
+			}()
+		}
+		started.Wait()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		herd := client.Stats().Herd
+		assert.Equal(t, uint64(5), herd.Fetches+herd.SavedCalls)
+		assert.Greater(t, herd.SavedCalls, uint64(0))
+		assert.Greater(t, herd.SharedFetches, uint64(0))
+	})
+
+	t.Run("publish expvar", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		err := client.PublishExpvar("go-cache-stats-test")
+		assert.NoError(t, err)
+		_, _ = Get(context.Background(), client, testKey)
+
+		var stats ClientStats
+		err = json.Unmarshal([]byte(expvar.Get("go-cache-stats-test").String()), &stats)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), stats.Hits)
+
+		// Already published
+		err = client.PublishExpvar("go-cache-stats-test")
+		assert.Error(t, err)
+		err = client.PublishExpvar("")
+		assert.Error(t, err)
+	})
+
+	t.Run("stats handler", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		handler := StatsHandler(client)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/stats", nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), `"pool":`)
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/stats", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	})
+}
+
+// ExampleClient_Stats is an example of the method Stats()
+func ExampleClient_Stats() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Collect the command totals
+	client.SetCommandStats(true)
+
+	// Mock and run a hit and a miss
+	conn.Command(GetCommand, testKey).Expect(testStringValue)
+	conn.Command(GetCommand, "missing").Expect(nil)
+	_, _ = Get(context.Background(), client, testKey)
+	_, _ = Get(context.Background(), client, "missing")
+
+	stats := client.Stats()
+	fmt.Printf("calls: %d hits: %d misses: %d", stats.Commands[GetCommand].Calls, stats.Hits, stats.Misses)
+	// Output:calls: 2 hits: 1 misses: 1
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// stickyPrimaryKey is the context key for the sticky primary marker
+type stickyPrimaryKey struct{}
+
+// stickyPrimary is marked by the first write sent with the context
+type stickyPrimary struct {
+	written uint32
+}
+
+// WithStickyPrimary returns a context that routes reads to the primary once a write was sent
+// with it (read-your-writes for a request), reads before the first write still use the replicas
+//
+// Use one context per request, the marker is shared by every context derived from it
+func WithStickyPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stickyPrimaryKey{}, &stickyPrimary{})
+}
+
+// IsStickyPrimary returns true if a write was sent with the context (see: WithStickyPrimary())
+func IsStickyPrimary(ctx context.Context) bool {
+	marker := stickyPrimaryMarker(ctx)
+	return marker != nil && marker.isWritten()
+}
+
+// stickyPrimaryMarker returns the sticky primary marker of the context (nil if not set)
+func stickyPrimaryMarker(ctx context.Context) *stickyPrimary {
+	marker, _ := ctx.Value(stickyPrimaryKey{}).(*stickyPrimary)
+	return marker
+}
+
+// isWritten returns true if a write was sent
+func (s *stickyPrimary) isWritten() bool {
+	return atomic.LoadUint32(&s.written) == 1
+}
+
+// mark records a write if the command modifies data
+func (s *stickyPrimary) mark(commandName string) {
+	if isWriteCommand(commandName) {
+		atomic.StoreUint32(&s.written, 1)
+	}
+}
+
+// stickyPrimaryConn marks the context after a command that modifies data
+type stickyPrimaryConn struct {
+	redis.Conn
+	marker *stickyPrimary
+}
+
+// unwrap returns the wrapped connection
+func (c *stickyPrimaryConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs the command
+func (c *stickyPrimaryConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	c.marker.mark(commandName)
+	return c.Conn.Do(commandName, args...)
+}
+
+// Send sends the command
+func (c *stickyPrimaryConn) Send(commandName string, args ...interface{}) error {
+	c.marker.mark(commandName)
+	return c.Conn.Send(commandName, args...)
+}
+
+// DoWithTimeout runs the command with the timeout
+func (c *stickyPrimaryConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	c.marker.mark(commandName)
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the timeout
+func (c *stickyPrimaryConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mrz1836/go-cache/nrredis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithStickyPrimary tests the method WithStickyPrimary()
+func TestWithStickyPrimary(t *testing.T) {
+
+	t.Run("no marker", func(t *testing.T) {
+		t.Parallel()
+
+		assert.False(t, IsStickyPrimary(context.Background()))
+		assert.False(t, IsStickyPrimary(WithStickyPrimary(context.Background())))
+	})
+
+	t.Run("reads go to the primary after a write", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		replica, replicaConn := loadMockReplica()
+		client.Replicas = []nrredis.Pool{replica}
+
+		primaryCmd := conn.Command(GetCommand, testKey).Expect("primary")
+		replicaCmd := replicaConn.Command(GetCommand, testKey).Expect("replica")
+		conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+
+		ctx := WithStickyPrimary(context.Background())
+
+		// Reads before the first write use the replica
+		val, err := Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "replica", val)
+		assert.False(t, IsStickyPrimary(ctx))
+
+		err = Set(ctx, client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.True(t, IsStickyPrimary(ctx))
+
+		val, err = Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "primary", val)
+		assert.Equal(t, 1, conn.Stats(primaryCmd))
+		assert.Equal(t, 1, replicaConn.Stats(replicaCmd))
+
+		// Other requests still read from the replica
+		val, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "replica", val)
+	})
+
+	t.Run("reads do not mark the context", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		ctx := WithStickyPrimary(context.Background())
+		_, err := Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.False(t, IsStickyPrimary(ctx))
+	})
+
+	t.Run("writes keep the dependency shards using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetDependencyShards(testDependantKey, 4)
+		assert.NoError(t, err)
+
+		shard := dependencyShardKey(testDependantKey, dependencyShard(testKey, 4))
+		conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+		conn.Command(MultiCommand)
+		addCmd := conn.Command(AddToSetCommand, shard, testKey)
+		unshardedCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		ctx := WithStickyPrimary(context.Background())
+		err = Set(ctx, client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, conn.Stats(addCmd))
+		assert.Equal(t, 0, conn.Stats(unshardedCmd))
+	})
+
+	t.Run("writes keep skipping dependencies using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+		multiCmd := conn.Command(MultiCommand)
+
+		ctx := WithSkipDependencies(WithStickyPrimary(context.Background()))
+		err := Set(ctx, client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, conn.Stats(setCmd))
+		assert.Equal(t, 0, conn.Stats(multiCmd))
+	})
+}
+
+// ExampleWithStickyPrimary is an example of the method WithStickyPrimary()
+func ExampleWithStickyPrimary() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the write
+	conn.Command(SetCommand, testKey, testStringValue).Expect("OK")
+
+	// One context per request, reads after the write go to the primary
+	ctx := WithStickyPrimary(context.Background())
+	_ = Set(ctx, client, testKey, testStringValue)
+	fmt.Printf("read from primary: %t", IsStickyPrimary(ctx))
+	// Output:read from primary: true
+}
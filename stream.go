@@ -0,0 +1,248 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// StreamChunkSize is the number of bytes read or written per command when streaming a value (512KB)
+const StreamChunkSize = 512 * 1024
+
+// ErrShortStream is returned when the reader ends before the expected size of the value
+var ErrShortStream = errors.New("stream ended before the expected size")
+
+// ErrInvalidStreamSize is returned when the size of a streamed value is negative
+var ErrInvalidStreamSize = errors.New("stream size must not be negative")
+
+// GetReader returns a reader for the value of the key, read in chunks (see: StreamChunkSize)
+// The value is never buffered entirely in memory (IE: io.Copy() into an HTTP response)
+// Returns redis.ErrNil if the key is missing
+//
+// The connection is held until the reader is closed (always close the reader)
+// Values changed while reading are not a consistent snapshot
+// Values stored compressed or chunked by the value size guard are not decoded
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: GetReaderRaw()
+func GetReader(ctx context.Context, client *Client, key string) (io.ReadCloser, error) {
+	if client.IsBypassed() {
+		return nil, redis.ErrNil
+	}
+	conn, err := client.GetReadConnectionWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var reader *valueReader
+	if reader, err = newValueReader(conn, key); err != nil {
+		client.CloseConnection(conn)
+		return nil, err
+	}
+	reader.close = func() {
+		client.CloseConnection(conn)
+	}
+	return reader, nil
+}
+
+// GetReaderRaw returns a reader for the value of the key, read in chunks (see: StreamChunkSize)
+// Returns redis.ErrNil if the key is missing
+// Uses existing connection (does not close connection, closing the reader is optional)
+//
+// Commands used:
+// https://redis.io/commands/strlen
+// https://redis.io/commands/getrange
+func GetReaderRaw(conn redis.Conn, key string) (io.ReadCloser, error) {
+	return newValueReader(conn, key)
+}
+
+// SetFromReader will set the key in redis to the size bytes read from the reader and keep a
+// reference to each dependency
+// The value is written in chunks to a temporary key and renamed into place when complete,
+// so readers never see a partial value
+// Returns ErrShortStream if the reader ends before the size (the key is not changed)
+//
+// The value size guard is not applied (see: SetValueSizeGuard())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetFromReaderRaw()
+func SetFromReader(ctx context.Context, client *Client, key string, reader io.Reader, size int64,
+	dependencies ...string) error {
+	if client.IsBypassed() {
+		return nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.CloseConnection(conn)
+	return SetFromReaderRaw(conn, key, reader, size, dependencies...)
+}
+
+// SetFromReaderRaw will set the key in redis to the size bytes read from the reader and keep a
+// reference to each dependency
+// Returns ErrShortStream if the reader ends before the size (the key is not changed)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/set
+// https://redis.io/commands/setrange
+// https://redis.io/commands/rename
+func SetFromReaderRaw(conn redis.Conn, key string, reader io.Reader, size int64,
+	dependencies ...string) (err error) {
+	if size < 0 {
+		return ErrInvalidStreamSize
+	} else if err = checkDependencyLimit(conn, dependencies...); err != nil {
+		return
+	}
+
+	// Write to a unique temporary key (removed on any error)
+	var temp string
+	if temp, err = streamTempKey(key); err != nil {
+		return
+	}
+	if _, err = conn.Do(SetCommand, temp, ""); err != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			_, _ = conn.Do(DeleteCommand, temp)
+		}
+	}()
+
+	// Write each chunk at the offset
+	bufSize := size
+	if bufSize > StreamChunkSize {
+		bufSize = StreamChunkSize
+	}
+	buf := make([]byte, bufSize)
+	var offset int64
+	for offset < size {
+		chunk := buf
+		if remaining := size - offset; remaining < bufSize {
+			chunk = buf[:remaining]
+		}
+		var n int
+		if n, err = io.ReadFull(reader, chunk); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				err = fmt.Errorf("%w: read %d of %d bytes", ErrShortStream, offset+int64(n), size)
+			}
+			return
+		}
+		if _, err = conn.Do(SetRangeCommand, temp, offset, chunk); err != nil {
+			return
+		}
+		offset += int64(n)
+	}
+
+	// Move the complete value into place
+	if _, err = conn.Do(RenameCommand, temp, key); err != nil {
+		return
+	}
+	return linkDependencies(conn, key, dependencies...)
+}
+
+// streamTempKey returns a unique temporary key for a streamed write of the key
+func streamTempKey(key string) (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return key + ":stream:" + hex.EncodeToString(id), nil
+}
+
+// valueReader reads a value in chunks using GETRANGE
+type valueReader struct {
+	close   func()
+	conn    redis.Conn
+	key     string
+	offset  int64
+	pending []byte
+	size    int64
+}
+
+// newValueReader returns a reader for the value of the key (redis.ErrNil if the key is missing)
+func newValueReader(conn redis.Conn, key string) (*valueReader, error) {
+	size, err := redis.Int64(conn.Do(StringLengthCommand, key))
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty value or a missing key
+	if size == 0 {
+		var found bool
+		if found, err = ExistsRaw(conn, key); err != nil {
+			return nil, err
+		} else if !found {
+			return nil, redis.ErrNil
+		}
+	}
+	return &valueReader{conn: conn, key: key, size: size}, nil
+}
+
+// next reads the next chunk of the value
+func (r *valueReader) next() ([]byte, error) {
+	if r.offset >= r.size {
+		return nil, io.EOF
+	}
+	end := r.offset + StreamChunkSize - 1
+	if end >= r.size {
+		end = r.size - 1
+	}
+	chunk, err := redis.Bytes(r.conn.Do(GetRangeCommand, r.key, r.offset, end))
+	if err != nil {
+		return nil, err
+	} else if len(chunk) == 0 {
+		// The value was changed while reading
+		return nil, io.ErrUnexpectedEOF
+	}
+	r.offset += int64(len(chunk))
+	return chunk, nil
+}
+
+// Read reads the value into p
+func (r *valueReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		chunk, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		r.pending = chunk
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// WriteTo writes the rest of the value to w without an extra copy (used by io.Copy())
+func (r *valueReader) WriteTo(w io.Writer) (total int64, err error) {
+	for {
+		if len(r.pending) == 0 {
+			if r.pending, err = r.next(); errors.Is(err, io.EOF) {
+				return total, nil
+			} else if err != nil {
+				return
+			}
+		}
+		var n int
+		n, err = w.Write(r.pending)
+		total += int64(n)
+		r.pending = r.pending[n:]
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Close releases the connection (if it was created for the reader)
+func (r *valueReader) Close() error {
+	if r.close != nil {
+		r.close()
+		r.close = nil
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGetReader tests the method GetReader()
+func TestGetReader(t *testing.T) {
+
+	t.Run("read using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(StringLengthCommand, testKey).Expect(int64(3))
+		conn.Command(GetRangeCommand, testKey, int64(0), int64(2)).Expect([]byte("abc"))
+
+		reader, err := GetReader(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		defer func() {
+			_ = reader.Close()
+		}()
+
+		var data []byte
+		data, err = io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("abc"), data)
+	})
+
+	t.Run("missing key using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(StringLengthCommand, testKey).Expect(int64(0))
+		conn.Command(ExistsCommand, testKey).Expect(int64(0))
+
+		reader, err := GetReader(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+		assert.Nil(t, reader)
+	})
+
+	t.Run("value changed while reading using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(StringLengthCommand, testKey).Expect(int64(3))
+		conn.Command(GetRangeCommand, testKey, int64(0), int64(2)).Expect([]byte(""))
+
+		reader, err := GetReaderRaw(conn, testKey)
+		assert.NoError(t, err)
+		_, err = io.ReadAll(reader)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+}
+
+// TestSetFromReader tests the method SetFromReader()
+func TestSetFromReader(t *testing.T) {
+
+	t.Run("invalid size", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := SetFromReader(context.Background(), client, testKey, strings.NewReader("abc"), -1)
+		assert.ErrorIs(t, err, ErrInvalidStreamSize)
+	})
+
+	t.Run("short stream using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.GenericCommand(SetCommand)
+		conn.GenericCommand(SetRangeCommand)
+		delCmd := conn.GenericCommand(DeleteCommand)
+		renameCmd := conn.GenericCommand(RenameCommand)
+
+		err := SetFromReader(context.Background(), client, testKey, strings.NewReader("abc"), 5)
+		assert.ErrorIs(t, err, ErrShortStream)
+		assert.True(t, delCmd.Called)
+		assert.False(t, renameCmd.Called)
+	})
+
+	t.Run("stream using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Larger than two chunks
+		value := bytes.Repeat([]byte("0123456789"), StreamChunkSize/4)
+		ctx := context.Background()
+		err = SetFromReader(ctx, client, testKey, bytes.NewReader(value), int64(len(value)), testDependantKey)
+		assert.NoError(t, err)
+
+		var reader io.ReadCloser
+		reader, err = GetReader(ctx, client, testKey)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		var total int64
+		total, err = io.Copy(&buf, reader)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(value)), total)
+		assert.Equal(t, value, buf.Bytes())
+		assert.NoError(t, reader.Close())
+
+		// A short stream leaves the value
+		err = SetFromReader(ctx, client, testKey, strings.NewReader("abc"), 10)
+		assert.ErrorIs(t, err, ErrShortStream)
+
+		var keys []string
+		keys, err = GetAllKeysRaw(conn)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{testKey, DependencyPrefix + testDependantKey}, keys)
+
+		// Empty values
+		err = SetFromReaderRaw(conn, testKey, strings.NewReader(""), 0)
+		assert.NoError(t, err)
+		reader, err = GetReaderRaw(conn, testKey)
+		assert.NoError(t, err)
+		var data []byte
+		data, err = io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Len(t, data, 0)
+	})
+}
+
+// ExampleGetReader is an example of the method GetReader()
+func ExampleGetReader() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the length and range commands
+	conn.Command(StringLengthCommand, testKey).Expect(int64(5))
+	conn.Command(GetRangeCommand, testKey, int64(0), int64(4)).Expect([]byte("hello"))
+
+	// Stream the value (IE: into an HTTP response)
+	reader, _ := GetReader(context.Background(), client, testKey)
+	defer func() {
+		_ = reader.Close()
+	}()
+	_, _ = io.Copy(os.Stdout, reader)
+	// Output:hello
+}
@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Append appends the value to the end of the key (the key is created if it does not exist)
+// Returns the length of the value after the append
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: AppendRaw()
+func Append(ctx context.Context, client *Client, key string, value interface{},
+	dependencies ...string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return AppendRaw(conn, key, value, dependencies...)
+}
+
+// AppendRaw appends the value to the end of the key (the key is created if it does not exist)
+// Returns the length of the value after the append
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/append
+func AppendRaw(conn redis.Conn, key string, value interface{}, dependencies ...string) (int, error) {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return 0, err
+	}
+	length, err := redis.Int(conn.Do(AppendCommand, key, value))
+	if err != nil {
+		return 0, err
+	}
+	return length, linkDependencies(conn, key, dependencies...)
+}
+
+// GetRange gets the part of the value between the start and end offsets (both inclusive)
+// Negative offsets start from the end of the value (IE: -1 is the last byte)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: GetRangeRaw()
+func GetRange(ctx context.Context, client *Client, key string, start, end int) (value string, err error) {
+	if client.IsBypassed() {
+		return "", nil
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		var tombstone bool
+		if tombstone, readErr = client.hasTombstone(conn, key); readErr != nil || tombstone {
+			return
+		}
+		value, readErr = GetRangeRaw(conn, key, start, end)
+		return
+	})
+	return
+}
+
+// GetRangeRaw gets the part of the value between the start and end offsets (both inclusive)
+// Negative offsets start from the end of the value (IE: -1 is the last byte)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/getrange
+func GetRangeRaw(conn redis.Conn, key string, start, end int) (string, error) {
+	return redis.String(conn.Do(GetRangeCommand, key, start, end))
+}
+
+// SetRange overwrites part of the value starting at the offset (the value is padded with zero bytes if needed)
+// Returns the length of the value after the change
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetRangeRaw()
+func SetRange(ctx context.Context, client *Client, key string, offset int, value interface{},
+	dependencies ...string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	return SetRangeRaw(conn, key, offset, value, dependencies...)
+}
+
+// SetRangeRaw overwrites part of the value starting at the offset (the value is padded with zero bytes if needed)
+// Returns the length of the value after the change
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/setrange
+func SetRangeRaw(conn redis.Conn, key string, offset int, value interface{}, dependencies ...string) (int, error) {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return 0, err
+	}
+	length, err := redis.Int(conn.Do(SetRangeCommand, key, offset, value))
+	if err != nil {
+		return 0, err
+	}
+	return length, linkDependencies(conn, key, dependencies...)
+}
+
+// StrLen gets the length of the value (0 if the key does not exist)
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+//
+// Custom connections use method: StrLenRaw()
+func StrLen(ctx context.Context, client *Client, key string) (length int, err error) {
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		length, readErr = StrLenRaw(conn, key)
+		return
+	})
+	return
+}
+
+// StrLenRaw gets the length of the value (0 if the key does not exist)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/strlen
+func StrLenRaw(conn redis.Conn, key string) (int, error) {
+	return redis.Int(conn.Do(StringLengthCommand, key))
+}
@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAppend is testing the method Append()
+func TestAppend(t *testing.T) {
+
+	t.Run("append using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(AppendCommand, testKey, "line-1\n").Expect(int64(7))
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		length, err := Append(context.Background(), client, testKey, "line-1\n", testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, length)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("string operations using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		// Load redis
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		// Start with a fresh db
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Missing key
+		var length int
+		length, err = StrLenRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, length)
+
+		// Log-style appends
+		length, err = AppendRaw(conn, testKey, "hello")
+		assert.NoError(t, err)
+		assert.Equal(t, 5, length)
+
+		length, err = AppendRaw(conn, testKey, " world")
+		assert.NoError(t, err)
+		assert.Equal(t, 11, length)
+
+		// Partial reads
+		var value string
+		value, err = GetRangeRaw(conn, testKey, 0, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello", value)
+
+		value, err = GetRangeRaw(conn, testKey, -5, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, "world", value)
+
+		// Partial writes
+		length, err = SetRangeRaw(conn, testKey, 6, "redis")
+		assert.NoError(t, err)
+		assert.Equal(t, 11, length)
+
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello redis", value)
+
+		length, err = StrLenRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 11, length)
+	})
+}
+
+// ExampleAppend is an example of the method Append()
+func ExampleAppend() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the append command
+	conn.Command(AppendCommand, "log", "entry\n").Expect(int64(6))
+
+	// Append to the log
+	length, _ := Append(context.Background(), client, "log", "entry\n")
+	fmt.Printf("length: %d", length)
+	// Output:length: 6
+}
+
+// TestGetRange is testing the method GetRange()
+func TestGetRange(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(GetRangeCommand, testKey, 0, 3).Expect([]byte("test"))
+
+	value, err := GetRange(context.Background(), client, testKey, 0, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", value)
+}
+
+// ExampleGetRange is an example of the method GetRange()
+func ExampleGetRange() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the get range command
+	conn.Command(GetRangeCommand, testKey, 0, 3).Expect([]byte("test"))
+
+	// Read the first four bytes
+	value, _ := GetRange(context.Background(), client, testKey, 0, 3)
+	fmt.Printf("value: %s", value)
+	// Output:value: test
+}
+
+// TestSetRange is testing the method SetRange()
+func TestSetRange(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(SetRangeCommand, testKey, 5, "value").Expect(int64(10))
+
+	length, err := SetRange(context.Background(), client, testKey, 5, "value")
+	assert.NoError(t, err)
+	assert.Equal(t, 10, length)
+}
+
+// TestStrLen is testing the method StrLen()
+func TestStrLen(t *testing.T) {
+	t.Parallel()
+
+	client, conn := loadMockRedis()
+	defer client.CloseAll(conn)
+
+	conn.Command(StringLengthCommand, testKey).Expect(int64(17))
+
+	length, err := StrLen(context.Background(), client, testKey)
+	assert.NoError(t, err)
+	assert.Equal(t, 17, length)
+}
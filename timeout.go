@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// commandTimeoutKey is the context key for the command timeout
+type commandTimeoutKey struct{}
+
+// WithCommandTimeout returns a context that overrides the read timeout of each command using a
+// connection from the client (IE: a longer timeout for KillByDependency() on huge sets)
+//
+// The default timeouts are set with the dial options (see: Config.ReadTimeout)
+func WithCommandTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, commandTimeoutKey{}, timeout)
+}
+
+// commandTimeout returns the command timeout from the context (0 if not set)
+func commandTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(commandTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// withTimeoutConn returns a function that wraps the connection with the command timeout
+// from the context (the connection is returned as-is if there is no timeout or an error)
+func withTimeoutConn(ctx context.Context) func(conn redis.Conn, err error) (redis.Conn, error) {
+	return func(conn redis.Conn, err error) (redis.Conn, error) {
+		timeout := commandTimeout(ctx)
+		if err != nil || timeout <= 0 {
+			return conn, err
+		}
+		return &timeoutConn{Conn: conn, timeout: timeout}, nil
+	}
+}
+
+// timeoutConn runs each command on the connection with the timeout
+type timeoutConn struct {
+	redis.Conn
+	timeout time.Duration
+}
+
+// unwrap returns the wrapped connection
+func (c *timeoutConn) unwrap() redis.Conn {
+	return c.Conn
+}
+
+// Do runs the command with the timeout
+func (c *timeoutConn) Do(commandName string, args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, c.timeout, commandName, args...)
+}
+
+// Receive receives a reply with the timeout
+func (c *timeoutConn) Receive() (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, c.timeout)
+}
+
+// DoWithTimeout runs the command with the given timeout
+func (c *timeoutConn) DoWithTimeout(timeout time.Duration, commandName string,
+	args ...interface{}) (interface{}, error) {
+	return redis.DoWithTimeout(c.Conn, timeout, commandName, args...)
+}
+
+// ReceiveWithTimeout receives a reply with the given timeout
+func (c *timeoutConn) ReceiveWithTimeout(timeout time.Duration) (interface{}, error) {
+	return redis.ReceiveWithTimeout(c.Conn, timeout)
+}
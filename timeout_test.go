@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithCommandTimeout tests the method WithCommandTimeout()
+func TestWithCommandTimeout(t *testing.T) {
+
+	t.Run("connections are wrapped with the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		// No timeout
+		plain, err := client.GetConnectionWithContext(context.Background())
+		assert.NoError(t, err)
+		_, ok := plain.(*timeoutConn)
+		assert.False(t, ok)
+		client.CloseConnection(plain)
+
+		// With a timeout
+		var wrapped redis.Conn
+		wrapped, err = client.GetConnectionWithContext(WithCommandTimeout(context.Background(), time.Second))
+		assert.NoError(t, err)
+		assert.IsType(t, &timeoutConn{}, wrapped)
+		assert.Equal(t, time.Second, wrapped.(*timeoutConn).timeout)
+		client.CloseConnection(wrapped)
+	})
+
+	t.Run("command timeout using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, err := ConnectWithConfig(context.Background(), Config{
+			MaxIdleConnections: testMaxIdleConnections,
+			ReadTimeout:        5 * time.Second,
+			URL:                testLocalConnectionURL,
+		})
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+		defer client.Close()
+
+		// Commands within the timeout work as usual
+		ctx := WithCommandTimeout(context.Background(), time.Second)
+		err = Set(ctx, client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		var value string
+		value, err = Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+
+		// A blocking command is stopped by the shorter timeout
+		var conn redis.Conn
+		conn, err = client.GetConnectionWithContext(WithCommandTimeout(context.Background(), 50*time.Millisecond))
+		assert.NoError(t, err)
+		defer client.CloseConnection(conn)
+
+		start := time.Now()
+		_, err = conn.Do("BLPOP", "empty-list", 2)
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+// ExampleWithCommandTimeout is an example of the method WithCommandTimeout()
+func ExampleWithCommandTimeout() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Allow up to a minute for removing a huge dependency set
+	ctx := WithCommandTimeout(context.Background(), time.Minute)
+	fmt.Printf("timeout: %s", commandTimeout(ctx))
+	// Output:timeout: 1m0s
+}
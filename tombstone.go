@@ -0,0 +1,240 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// TombstonePrefix is the prefix for the tombstones of invalidated keys
+const TombstonePrefix = "tombstone:"
+
+// killWithTombstonesLua removes the dependency sets and their members, writing a tombstone for each member
+//
+// KEYS = dependency sets, ARGV[1] = tombstone window (ms), ARGV[2...] = dependency keys to remove
+// Returns the number of keys removed
+const killWithTombstonesLua = `
+--@begin=lua@
+local window = ARGV[1]
+local all_keys = {}
+for _, key in ipairs(KEYS) do
+	table.insert(all_keys, key)
+	for _, member in ipairs(redis.call("` + MembersCommand + `", key)) do
+		redis.call("` + SetCommand + `", "` + TombstonePrefix + `" .. member, 1, "PX", window)
+		table.insert(all_keys, member)
+	end
+end
+for i = 2, #ARGV do
+	redis.call("` + SetCommand + `", "` + TombstonePrefix + `" .. ARGV[i], 1, "PX", window)
+	table.insert(all_keys, ARGV[i])
+end
+if #all_keys == 0 then
+	return 0
+end
+return redis.call("` + DeleteCommand + `", unpack(all_keys))
+--@end=lua@
+`
+
+// setUnlessTombstonedLua writes the key unless it has a tombstone
+//
+// KEYS[1] = key, KEYS[2] = tombstone key, ARGV[1] = value, ARGV[2...] = SET options (IE: NX, PX 1500)
+// Returns 1 if the key was written, 0 if it has a tombstone (or the NX condition failed)
+const setUnlessTombstonedLua = `
+--@begin=lua@
+if redis.call("` + ExistsCommand + `", KEYS[2]) == 1 then
+	return 0
+end
+if redis.call("` + SetCommand + `", KEYS[1], unpack(ARGV)) then
+	return 1
+end
+return 0
+--@end=lua@
+`
+
+// Tombstone scripts (EVALSHA with a fallback to EVAL)
+var (
+	killWithTombstonesScript  = newScript("kill_with_tombstones", -1, killWithTombstonesLua)
+	setUnlessTombstonedScript = newScript("set_unless_tombstoned", 2, setUnlessTombstonedLua)
+)
+
+// SetInvalidationTombstones switches two-phase invalidation on (a window of at least 1ms) or off (0)
+//
+// KillByDependency() and Delete() write a short-lived tombstone for each key before removing it,
+// the string writers (IE: Set(), SetExp(), SetWith(), SetToJSON()) skip the write of a key with a
+// tombstone (checked in the same script), the hash writers (IE: HashSet(), HashMapSet()) skip the
+// write of a hash with a tombstone (checked first), and the
+// string, hash, list and set readers (IE: Get(), GetMulti(), HashGet()) treat a key with a tombstone
+// as a miss (one extra round trip per read), so a racing writer can not resurrect stale data right
+// after an invalidation; readers re-fetch from the source until the window ends
+func (c *Client) SetInvalidationTombstones(window time.Duration) error {
+	if window != 0 && window < time.Millisecond {
+		return ErrInvalidTTL
+	}
+	atomic.StoreInt64(&c.tombstoneWindow, int64(window))
+	return nil
+}
+
+// InvalidationTombstones returns the tombstone window (0 if off, see: SetInvalidationTombstones())
+func (c *Client) InvalidationTombstones() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.tombstoneWindow))
+}
+
+// KillByDependencyWithTombstones removes all keys which are listed as depending on the key(s), and
+// writes a tombstone for each removed key that expires after the window (see: SetInvalidationTombstones())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: KillByDependencyWithTombstonesRaw()
+func KillByDependencyWithTombstones(ctx context.Context, client *Client, window time.Duration,
+	keys ...string) (int, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer client.CloseConnection(conn)
+	total, err := KillByDependencyWithTombstonesRaw(conn, window, keys...)
+	client.fireRemoveHooks(ctx, OperationInvalidate, keys, total, err)
+	return total, err
+}
+
+// KillByDependencyWithTombstonesRaw removes all keys which are listed as depending on the key(s), and
+// writes a tombstone for each removed key that expires after the window
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/smembers
+// https://redis.io/commands/set
+// https://redis.io/commands/del
+func KillByDependencyWithTombstonesRaw(conn redis.Conn, window time.Duration, keys ...string) (total int, err error) {
+	if window < time.Millisecond {
+		return 0, ErrInvalidTTL
+	} else if len(keys) == 0 {
+		return
+	}
+
+	// Sharded dependencies are removed one shard at a time
+	var sets, shards, shardKeys []string
+	for _, key := range keys {
+		if keySets := dependencySetKeys(conn, key); len(keySets) == 1 {
+			sets = append(sets, keySets[0])
+		} else {
+			for _, set := range keySets {
+				shards = append(shards, set)
+				shardKeys = append(shardKeys, key)
+			}
+		}
+	}
+
+	// Totals are the DEL replies, a failure does not stop the other shards
+	var failures deleteFailures
+	args := redis.Args{}.Add(len(sets)).AddFlat(sets).Add(window.Milliseconds()).AddFlat(keys)
+	if total, err = redis.Int(killWithTombstonesScript.Do(conn, args...)); err != nil {
+		failures.add(err, keys...)
+	}
+	for i, shard := range shards {
+		var removed int
+		if removed, err = redis.Int(killWithTombstonesScript.Do(conn, 1, shard, window.Milliseconds())); err != nil {
+			failures.add(err, shardKeys[i])
+		}
+		total += removed
+	}
+	return total, failures.error(total)
+}
+
+// checkTombstone returns redis.ErrNil if tombstones are on and the key has a tombstone
+func (c *Client) checkTombstone(conn redis.Conn, key string) error {
+	if tombstone, err := c.hasTombstone(conn, key); err != nil {
+		return err
+	} else if tombstone {
+		return redis.ErrNil
+	}
+	return nil
+}
+
+// hasTombstone returns true if tombstones are on and the key has a tombstone
+//
+// Spec: https://redis.io/commands/exists
+func (c *Client) hasTombstone(conn redis.Conn, key string) (bool, error) {
+	if c.InvalidationTombstones() == 0 {
+		return false, nil
+	}
+	return ExistsRaw(conn, TombstonePrefix+key)
+}
+
+// hideTombstones marks the keys of the result with a tombstone as missing if tombstones are on
+//
+// Spec: https://redis.io/commands/exists
+func (c *Client) hideTombstones(conn redis.Conn, result *MultiResult) error {
+	if c.InvalidationTombstones() == 0 || len(result.Keys) == 0 {
+		return nil
+	}
+	tombstones := make([]string, len(result.Keys))
+	for i, key := range result.Keys {
+		tombstones[i] = TombstonePrefix + key
+	}
+	_, found, err := ExistsMultiRaw(conn, tombstones...)
+	if err != nil {
+		return err
+	}
+	for i, tombstone := range found {
+		if tombstone {
+			result.Found[i] = false
+			result.Values[i] = ""
+		}
+	}
+	return nil
+}
+
+// setValue writes the value (chunked if the chunk size is set) with the ttl (0 is no ttl) and links
+// the dependencies, the write of a key with a tombstone is skipped if tombstones are on
+// Returns false if the write was skipped
+//
+// Commands used:
+// https://redis.io/commands/evalsha
+// https://redis.io/commands/exists
+// https://redis.io/commands/set
+// https://redis.io/commands/sadd
+func (c *Client) setValue(conn redis.Conn, key string, value interface{}, chunkSize int,
+	ttl time.Duration, dependencies []string) (bool, error) {
+	if c.InvalidationTombstones() > 0 {
+		if chunkSize == 0 {
+			return setUnlessTombstoned(conn, key, value, &writeConfig{dependencies: dependencies, ttl: ttl})
+		}
+
+		// Chunks are too large for the script, the tombstone is checked first
+		if tombstone, err := c.hasTombstone(conn, key); err != nil || tombstone {
+			return false, err
+		}
+	}
+
+	switch {
+	case chunkSize > 0:
+		return true, SetChunkedRaw(conn, key, value.([]byte), chunkSize, ttl, dependencies...)
+	case ttl > 0:
+		return true, SetExpRaw(conn, key, value, ttl, dependencies...)
+	default:
+		return true, SetRaw(conn, key, value, dependencies...)
+	}
+}
+
+// setUnlessTombstoned writes the key with the options (IE: WithTTL(), WithNX()) unless it has a
+// tombstone, and links the dependencies of a written key
+// A ttl under 1ms is rejected (the script sets the expiration in milliseconds)
+func setUnlessTombstoned(conn redis.Conn, key string, value interface{}, config *writeConfig) (bool, error) {
+	if config.ttl != 0 && config.ttl < time.Millisecond {
+		return false, ErrInvalidTTL
+	}
+	if !skipsDependencies(conn) {
+		if err := checkDependencyLimit(conn, config.dependencies...); err != nil {
+			return false, err
+		}
+	}
+	args := redis.Args{}.Add(key, TombstonePrefix+key, value).AddFlat(config.setArguments())
+	written, err := redis.Bool(setUnlessTombstonedScript.Do(conn, args...))
+	if err != nil || !written {
+		return false, err
+	}
+	return true, linkDependencies(conn, key, config.dependencies...)
+}
@@ -0,0 +1,314 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetInvalidationTombstones tests the method SetInvalidationTombstones()
+func TestSetInvalidationTombstones(t *testing.T) {
+
+	t.Run("invalid window", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetInvalidationTombstones(time.Microsecond)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+
+		err = client.SetInvalidationTombstones(-time.Second)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+
+		_, err = KillByDependencyWithTombstones(context.Background(), client, 0, testDependantKey)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("kill and get using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetInvalidationTombstones(2 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 2*time.Second, client.InvalidationTombstones())
+
+		killCmd := conn.Script([]byte(killWithTombstonesLua), 1, DependencyPrefix+testDependantKey,
+			int64(2000), testDependantKey).Expect(int64(3))
+
+		var total int
+		total, err = KillByDependency(context.Background(), client, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+		assert.True(t, killCmd.Called)
+
+		// A racing writer stored a stale value, the tombstone hides it
+		conn.Command(ExistsCommand, TombstonePrefix+testKey).Expect(int64(1))
+		getCmd := conn.Command(GetCommand, testKey).Expect(testStringValue)
+
+		_, err = Get(context.Background(), client, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+		assert.False(t, getCmd.Called)
+
+		// Off
+		err = client.SetInvalidationTombstones(0)
+		assert.NoError(t, err)
+		var value string
+		value, err = Get(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+	})
+
+	t.Run("writes of keys with a tombstone are skipped using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetInvalidationTombstones(time.Second)
+		assert.NoError(t, err)
+		var sets int
+		client.OnSet(func(context.Context, KeyEvent) { sets++ })
+
+		// The key has a tombstone
+		skipCmd := conn.Script([]byte(setUnlessTombstonedLua), 2, testKey, TombstonePrefix+testKey,
+			testStringValue).Expect(int64(0))
+		err = Set(context.Background(), client, testKey, testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, skipCmd.Called)
+		assert.Equal(t, 0, sets)
+
+		// The tombstone expired, the key is written and linked
+		writeCmd := conn.Script([]byte(setUnlessTombstonedLua), 2, testKey, TombstonePrefix+testKey,
+			testStringValue, ExpireMillisArgument, int64(1500)).Expect(int64(1))
+		conn.Command(MultiCommand)
+		linkCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+		err = SetExp(context.Background(), client, testKey, testStringValue, 1500*time.Millisecond, testDependantKey)
+		assert.NoError(t, err)
+		assert.True(t, writeCmd.Called)
+		assert.True(t, linkCmd.Called)
+		assert.Equal(t, 1, sets)
+
+		err = SetExp(context.Background(), client, testKey, testStringValue, 0)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+
+		// A ttl under 1ms would be stored without an expiration
+		err = SetExp(context.Background(), client, testKey, testStringValue, time.Microsecond)
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+		_, err = SetWith(context.Background(), client, testKey, testStringValue, WithTTL(time.Microsecond))
+		assert.ErrorIs(t, err, ErrInvalidTTL)
+	})
+
+	t.Run("options, json and hash writes are skipped using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetInvalidationTombstones(time.Second)
+		assert.NoError(t, err)
+		var sets int
+		client.OnSet(func(context.Context, KeyEvent) { sets++ })
+
+		// The key has a tombstone
+		withCmd := conn.Script([]byte(setUnlessTombstonedLua), 2, testKey, TombstonePrefix+testKey,
+			testStringValue, SetIfNotExistsArgument, ExpireSecondsArgument, int64(60)).Expect(int64(0))
+		jsonCmd := conn.Script([]byte(setUnlessTombstonedLua), 2, testKey, TombstonePrefix+testKey,
+			`{"name":"stale"}`, ExpireSecondsArgument, int64(60)).Expect(int64(0))
+
+		var written bool
+		written, err = SetWith(context.Background(), client, testKey, testStringValue,
+			WithNX(), WithTTL(time.Minute))
+		assert.NoError(t, err)
+		assert.False(t, written)
+		assert.True(t, withCmd.Called)
+
+		err = SetToJSON(context.Background(), client, testKey, map[string]string{"name": "stale"}, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, jsonCmd.Called)
+
+		// The hash has a tombstone
+		conn.Command(ExistsCommand, TombstonePrefix+testHashName).Expect(int64(1))
+		hashCmd := conn.GenericCommand(HashKeySetCommand)
+		hashMapCmd := conn.GenericCommand(HashMapSetCommand)
+
+		err = HashSet(context.Background(), client, testHashName, "field", "stale")
+		assert.NoError(t, err)
+		written, err = HashSetWith(context.Background(), client, testHashName, "field", "stale")
+		assert.NoError(t, err)
+		assert.False(t, written)
+		pairs := [][2]interface{}{{"field", "stale"}}
+		err = HashMapSet(context.Background(), client, testHashName, pairs)
+		assert.NoError(t, err)
+		err = HashMapSetExp(context.Background(), client, testHashName, pairs, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, hashCmd.Called)
+		assert.False(t, hashMapCmd.Called)
+		assert.Equal(t, 0, sets)
+	})
+
+	t.Run("multi and hash reads using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.SetInvalidationTombstones(time.Second)
+		assert.NoError(t, err)
+
+		// The first key has a tombstone
+		conn.Command(MultiGetCommand, "key-1", "key-2").Expect([]interface{}{[]byte("stale"), []byte("fresh")})
+		conn.Command(ExistsCommand, TombstonePrefix+"key-1").Expect(int64(1))
+		conn.Command(ExistsCommand, TombstonePrefix+"key-2").Expect(int64(0))
+		result, err := GetMulti(context.Background(), client, "key-1", "key-2")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, true}, result.Found)
+		assert.Equal(t, []string{"", "fresh"}, result.Values)
+
+		// The hash has a tombstone
+		conn.Command(ExistsCommand, TombstonePrefix+testHashName).Expect(int64(1))
+		hashCmd := conn.GenericCommand(HashGetCommand).Expect("stale")
+		hashMapCmd := conn.GenericCommand(HashMapGetCommand).Expect([]interface{}{[]byte("stale")})
+		hashAllCmd := conn.GenericCommand(HashGetAllCommand).Expect([]interface{}{[]byte("field"), []byte("stale")})
+
+		_, err = HashGet(context.Background(), client, testHashName, "field")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		var values []string
+		values, err = HashMapGet(context.Background(), client, testHashName, "field")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{""}, values)
+
+		result, err = HashMapGetFound(context.Background(), client, testHashName, "field")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false}, result.Found)
+
+		var dest struct {
+			Field string `redis:"field"`
+		}
+		err = HashGetStruct(context.Background(), client, testHashName, &dest)
+		assert.ErrorIs(t, err, redis.ErrNil)
+		assert.False(t, hashCmd.Called)
+		assert.False(t, hashMapCmd.Called)
+		assert.False(t, hashAllCmd.Called)
+
+		// The list has a tombstone
+		conn.Command(ExistsCommand, TombstonePrefix+testKey).Expect(int64(1))
+		listCmd := conn.GenericCommand(ListRangeCommand).Expect([]interface{}{[]byte("stale")})
+		var list []string
+		list, err = GetList(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Empty(t, list)
+		assert.False(t, listCmd.Called)
+	})
+
+	t.Run("kill and get using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = client.SetInvalidationTombstones(time.Minute)
+		assert.NoError(t, err)
+		err = client.SetDependencyShards("products", 2)
+		assert.NoError(t, err)
+
+		err = Set(context.Background(), client, "user:1", testStringValue, testDependantKey)
+		assert.NoError(t, err)
+		for i := 0; i < 4; i++ {
+			err = Set(context.Background(), client, fmt.Sprintf("product:%d", i), testStringValue, "products")
+			assert.NoError(t, err)
+		}
+
+		var total int
+		total, err = Delete(context.Background(), client, testDependantKey, "products")
+		assert.NoError(t, err)
+		assert.Equal(t, 8, total)
+
+		// Writers of the client are skipped
+		err = Set(context.Background(), client, "product:0", "stale", "products")
+		assert.NoError(t, err)
+		err = SetToJSON(context.Background(), client, "product:1", testStringValue, time.Minute)
+		assert.NoError(t, err)
+		err = HashSet(context.Background(), client, "product:2", "field", "stale")
+		assert.NoError(t, err)
+		var exists bool
+		for _, key := range []string{"product:0", "product:1", "product:2"} {
+			exists, err = ExistsRaw(conn, key)
+			assert.NoError(t, err)
+			assert.False(t, exists)
+		}
+
+		// Keys without a tombstone keep the options
+		var written bool
+		written, err = SetWith(context.Background(), client, "product:9", testStringValue,
+			WithNX(), WithTTL(1500*time.Millisecond))
+		assert.NoError(t, err)
+		assert.True(t, written)
+		written, err = SetWith(context.Background(), client, "product:9", "other", WithNX())
+		assert.NoError(t, err)
+		assert.False(t, written)
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do(TTLMillisCommand, "product:9"))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(1000))
+
+		// A racing writer resurrects the keys
+		err = SetRaw(conn, "user:1", "stale")
+		assert.NoError(t, err)
+		err = HashSetRaw(conn, "product:1", "field", "stale")
+		assert.NoError(t, err)
+
+		var found bool
+		_, found, err = GetWithFound(context.Background(), client, "user:1")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		_, found, err = GetBytesWithFound(context.Background(), client, "product:3")
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		var result *MultiResult
+		result, err = GetMulti(context.Background(), client, "user:1", "product:3")
+		assert.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, result.Found)
+		_, err = HashGet(context.Background(), client, "product:1", "field")
+		assert.ErrorIs(t, err, redis.ErrNil)
+
+		ttl, err = redis.Int64(conn.Do(TTLMillisCommand, TombstonePrefix+"product:3"))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(50000))
+	})
+}
+
+// ExampleClient_SetInvalidationTombstones is an example of the method SetInvalidationTombstones()
+func ExampleClient_SetInvalidationTombstones() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Keys removed by an invalidation miss for 5 seconds (even if a racing writer stores them again)
+	_ = client.SetInvalidationTombstones(5 * time.Second)
+
+	// Mock the tombstone
+	conn.Command(ExistsCommand, TombstonePrefix+testKey).Expect(int64(1))
+
+	_, found, _ := GetWithFound(context.Background(), client, testKey)
+	fmt.Printf("found: %t", found)
+	// Output:found: false
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// maxUpdateRetries is the number of attempts of UpdateKey() when the key is modified concurrently
+const maxUpdateRetries = 10
+
+// ErrUpdateConflict is returned when the key was modified concurrently on every attempt of UpdateKey()
+var ErrUpdateConflict = errors.New("key was modified concurrently, update retries exhausted")
+
+// UpdateFunc returns the new value for the current value ("" if the key does not exist)
+// Returning an error cancels the update (the error is returned by UpdateKey())
+type UpdateFunc func(current string) (string, error)
+
+// UpdateKey applies the transformation to the value of the key and keeps a reference to each dependency
+// The write only happens if the key was not modified since it was read (WATCH/MULTI), otherwise
+// the value is read and transformed again (the function may be called more than once)
+// Returns the new value, or ErrUpdateConflict if the key kept changing
+// The ttl of the key is kept
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: UpdateKeyRaw()
+func UpdateKey(ctx context.Context, client *Client, key string, fn UpdateFunc,
+	dependencies ...string) (string, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.CloseConnection(conn)
+	return UpdateKeyRaw(conn, key, fn, dependencies...)
+}
+
+// UpdateKeyRaw applies the transformation to the value of the key and keeps a reference to each dependency
+// The write only happens if the key was not modified since it was read (WATCH/MULTI)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/watch
+// https://redis.io/commands/get
+// https://redis.io/commands/set
+func UpdateKeyRaw(conn redis.Conn, key string, fn UpdateFunc, dependencies ...string) (string, error) {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return "", err
+	}
+	for attempt := 0; attempt < maxUpdateRetries; attempt++ {
+		if _, err := conn.Do(WatchCommand, key); err != nil {
+			return "", err
+		}
+
+		// Read and transform the current value
+		current, err := GetRaw(conn, key)
+		if err != nil && !errors.Is(err, redis.ErrNil) {
+			_, _ = conn.Do(UnwatchCommand)
+			return "", err
+		}
+		var value string
+		if value, err = fn(current); err != nil {
+			_, _ = conn.Do(UnwatchCommand)
+			return "", err
+		}
+
+		// Write if the key was not modified
+		if err = conn.Send(MultiCommand); err != nil {
+			return "", err
+		}
+		if err = conn.Send(SetCommand, key, value, KeepTTLArgument); err != nil {
+			return "", err
+		}
+		if err = sendLinkDependencies(conn, key, dependencies...); err != nil {
+			return "", err
+		}
+		var replies []interface{}
+		if replies, err = redis.Values(conn.Do(ExecuteCommand)); errors.Is(err, redis.ErrNil) {
+			continue // The key was modified, try again
+		} else if err != nil {
+			return "", err
+		} else if len(replies) > 0 {
+			if replyErr, ok := replies[0].(redis.Error); ok {
+				return "", replyErr
+			}
+		}
+		return value, nil
+	}
+	return "", ErrUpdateConflict
+}
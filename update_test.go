@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUpdateKey tests the method UpdateKey()
+func TestUpdateKey(t *testing.T) {
+
+	t.Run("update using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(WatchCommand, testKey)
+		conn.Command(GetCommand, testKey).Expect([]byte("1"))
+		conn.Command(MultiCommand)
+		setCmd := conn.Command(SetCommand, testKey, "2", KeepTTLArgument)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{"OK", int64(1)})
+
+		value, err := UpdateKey(context.Background(), client, testKey, func(current string) (string, error) {
+			count, _ := strconv.Atoi(current)
+			return strconv.Itoa(count + 1), nil
+		}, testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", value)
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("conflict on every attempt using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(WatchCommand, testKey)
+		conn.Command(GetCommand, testKey).Expect(nil)
+		conn.Command(MultiCommand)
+		conn.Command(SetCommand, testKey, "new", KeepTTLArgument)
+		conn.Command(ExecuteCommand).Expect(nil)
+
+		var calls int
+		value, err := UpdateKey(context.Background(), client, testKey, func(current string) (string, error) {
+			calls++
+			assert.Equal(t, "", current)
+			return "new", nil
+		})
+		assert.ErrorIs(t, err, ErrUpdateConflict)
+		assert.Equal(t, "", value)
+		assert.Equal(t, maxUpdateRetries, calls)
+	})
+
+	t.Run("function error cancels the update using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(WatchCommand, testKey)
+		conn.Command(GetCommand, testKey).Expect([]byte("invalid"))
+		unwatchCmd := conn.Command(UnwatchCommand)
+		setCmd := conn.GenericCommand(SetCommand)
+
+		_, err := UpdateKey(context.Background(), client, testKey, func(current string) (string, error) {
+			return "", errors.New("invalid value")
+		})
+		assert.Error(t, err)
+		assert.True(t, unwatchCmd.Called)
+		assert.False(t, setCmd.Called)
+	})
+
+	t.Run("concurrent updates using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		err = SetExpRaw(conn, testKey, "0", time.Minute)
+		assert.NoError(t, err)
+
+		// No lost writes (up to the retries)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, updateErr := UpdateKey(context.Background(), client, testKey, func(current string) (string, error) {
+					count, _ := strconv.Atoi(current)
+					return strconv.Itoa(count + 1), nil
+				})
+				assert.NoError(t, updateErr)
+			}()
+		}
+		wg.Wait()
+
+		var value string
+		value, err = GetRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "5", value)
+
+		// The ttl is kept
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do("TTL", testKey))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+	})
+}
+
+// ExampleUpdateKey is an example of the method UpdateKey()
+func ExampleUpdateKey() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the transaction
+	conn.Command(WatchCommand, testKey)
+	conn.Command(GetCommand, testKey).Expect([]byte("a,b"))
+	conn.Command(MultiCommand)
+	conn.Command(SetCommand, testKey, "a,b,c", KeepTTLArgument)
+	conn.Command(ExecuteCommand).Expect([]interface{}{"OK"})
+
+	// Append to the list stored in the string (no lost writes)
+	value, _ := UpdateKey(context.Background(), client, testKey, func(current string) (string, error) {
+		return current + ",c", nil
+	})
+	fmt.Printf("value: %s", value)
+	// Output:value: a,b,c
+}
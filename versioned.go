@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// Fields of the hash holding a versioned value
+const (
+	versionedValueField   = "value"
+	versionedVersionField = "version"
+)
+
+// ErrVersionConflict is returned when the version of the stored value does not match (IE: another
+// writer changed the value since it was read)
+var ErrVersionConflict = errors.New("version of the value does not match")
+
+// setIfVersionScript writes the value only if the version matches ("" if the key must not exist)
+// and returns the new version
+const setIfVersionScript = `
+local current = redis.call("HGET", KEYS[1], "` + versionedVersionField + `")
+if (current or "") ~= ARGV[2]
+then
+	return false
+end
+local version = redis.call("HINCRBY", KEYS[1], "` + versionedVersionField + `", 1)
+redis.call("HSET", KEYS[1], "` + versionedValueField + `", ARGV[1])
+return tostring(version)
+`
+
+// GetWithVersion gets the versioned value of the key and its version (see: SetIfVersion())
+// Returns redis.ErrNil if the key does not exist
+// Creates a new connection and closes connection at end of function call
+// Reads from a replica if configured (see: ConnectReplicas())
+// Keys with a tombstone are a miss if set (see: SetInvalidationTombstones())
+//
+// Custom connections use method: GetWithVersionRaw()
+func GetWithVersion(ctx context.Context, client *Client, key string) (value, version string, err error) {
+	if client.IsBypassed() {
+		return "", "", redis.ErrNil
+	}
+	err = client.read(ctx, func(conn redis.Conn) (readErr error) {
+		if readErr = client.checkTombstone(conn, key); readErr != nil {
+			return
+		}
+		value, version, readErr = GetWithVersionRaw(conn, key)
+		return
+	})
+	return
+}
+
+// GetWithVersionRaw gets the versioned value of the key and its version (see: SetIfVersion())
+// Returns redis.ErrNil if the key does not exist
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/hmget
+func GetWithVersionRaw(conn redis.Conn, key string) (value, version string, err error) {
+	var values []interface{}
+	if values, err = redis.Values(
+		conn.Do(HashMapGetCommand, key, versionedValueField, versionedVersionField),
+	); err != nil {
+		return
+	} else if len(values) != 2 || values[1] == nil {
+		return "", "", redis.ErrNil
+	}
+	if value, err = redis.String(values[0], nil); errors.Is(err, redis.ErrNil) {
+		err = nil
+	} else if err != nil {
+		return
+	}
+	version, err = redis.String(values[1], nil)
+	return
+}
+
+// SetIfVersion will set the versioned value of the key only if the stored version matches, and keep
+// a reference to each dependency
+// Use the version from GetWithVersion(), or "" to only write if the key does not exist
+// Returns the new version, or ErrVersionConflict if the version does not match
+// The value is stored in a hash with its version (read it with GetWithVersion())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetIfVersionRaw()
+func SetIfVersion(ctx context.Context, client *Client, key string, value interface{}, version string,
+	dependencies ...string) (string, error) {
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer client.CloseConnection(conn)
+	return SetIfVersionRaw(conn, key, value, version, dependencies...)
+}
+
+// SetIfVersionRaw will set the versioned value of the key only if the stored version matches, and keep
+// a reference to each dependency
+// Returns the new version, or ErrVersionConflict if the version does not match
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/eval
+func SetIfVersionRaw(conn redis.Conn, key string, value interface{}, version string,
+	dependencies ...string) (string, error) {
+	if err := checkDependencyLimit(conn, dependencies...); err != nil {
+		return "", err
+	}
+	script := newScript("set_if_version", 1, setIfVersionScript)
+	newVersion, err := redis.String(script.Do(conn, key, value, version))
+	if errors.Is(err, redis.ErrNil) {
+		return "", ErrVersionConflict
+	} else if err != nil {
+		return "", err
+	}
+	return newVersion, linkDependencies(conn, key, dependencies...)
+}
@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetIfVersion tests the methods GetWithVersion() and SetIfVersion()
+func TestSetIfVersion(t *testing.T) {
+
+	t.Run("get with version using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(HashMapGetCommand, testKey, versionedValueField, versionedVersionField).
+			Expect([]interface{}{[]byte(testStringValue), []byte("3")})
+		conn.Command(HashMapGetCommand, "missing", versionedValueField, versionedVersionField).
+			Expect([]interface{}{nil, nil})
+
+		value, version, err := GetWithVersion(context.Background(), client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, value)
+		assert.Equal(t, "3", version)
+
+		_, _, err = GetWithVersion(context.Background(), client, "missing")
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+
+	t.Run("set if version using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Script([]byte(setIfVersionScript), 1, testKey, testStringValue, "3").Expect([]byte("4"))
+		conn.Script([]byte(setIfVersionScript), 1, testKey, testStringValue, "2").Expect(nil)
+
+		version, err := SetIfVersion(context.Background(), client, testKey, testStringValue, "3")
+		assert.NoError(t, err)
+		assert.Equal(t, "4", version)
+
+		_, err = SetIfVersion(context.Background(), client, testKey, testStringValue, "2")
+		assert.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("versioned values using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		// Create (the key must not exist)
+		var version string
+		version, err = SetIfVersionRaw(conn, testKey, "draft", "", testDependantKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", version)
+
+		_, err = SetIfVersionRaw(conn, testKey, "other", "")
+		assert.ErrorIs(t, err, ErrVersionConflict)
+
+		// Two workers read the same version
+		var value, first, second string
+		value, first, err = GetWithVersionRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "draft", value)
+		_, second, err = GetWithVersionRaw(conn, testKey)
+		assert.NoError(t, err)
+
+		version, err = SetIfVersionRaw(conn, testKey, "edit-1", first)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", version)
+
+		// The second edit conflicts
+		_, err = SetIfVersionRaw(conn, testKey, "edit-2", second)
+		assert.ErrorIs(t, err, ErrVersionConflict)
+
+		value, version, err = GetWithVersionRaw(conn, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "edit-1", value)
+		assert.Equal(t, "2", version)
+
+		// Removed with the dependency
+		_, err = DeleteRaw(conn, testDependantKey)
+		assert.NoError(t, err)
+		_, _, err = GetWithVersionRaw(conn, testKey)
+		assert.ErrorIs(t, err, redis.ErrNil)
+	})
+}
+
+// ExampleSetIfVersion is an example of the method SetIfVersion()
+func ExampleSetIfVersion() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the script (the version was changed by another writer)
+	conn.Script([]byte(setIfVersionScript), 1, testKey, testStringValue, "1").Expect(nil)
+
+	_, err := SetIfVersion(context.Background(), client, testKey, testStringValue, "1")
+	fmt.Printf("error: %v", err)
+	// Output:error: version of the value does not match
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultWarmerWorkers is the default number of concurrent warm-up workers
+const defaultWarmerWorkers = 10
+
+// WarmupLoader describes a single key to populate during a warm-up
+type WarmupLoader struct {
+	Dependencies []string                                       // Dependencies to link to the key
+	Fetch        func(ctx context.Context) (interface{}, error) // Fetches the value for the key
+	Key          string                                         // Key to populate
+	TTL          time.Duration                                  // Expiration of the key (0 = no expiration)
+}
+
+// WarmupProgress is reported after each key in a warm-up has been processed
+type WarmupProgress struct {
+	Completed int    // Keys populated so far
+	Err       error  // Error for this key (if any)
+	Failed    int    // Keys that failed so far
+	Key       string // Key that was just processed
+	Total     int    // Total keys in the warm-up
+}
+
+// WarmupResult is the outcome of a warm-up
+type WarmupResult struct {
+	Completed int              // Keys populated
+	Duration  time.Duration    // How long the warm-up took
+	Errors    map[string]error // Errors by key
+	Failed    int              // Keys that failed
+	Total     int              // Total keys in the warm-up
+}
+
+// Warmer populates the cache concurrently from a set of loaders, for use at deploy
+// time or after DestroyCache()
+type Warmer struct {
+	client     *Client
+	loaders    []*WarmupLoader
+	onProgress func(progress WarmupProgress)
+	workers    int
+}
+
+// NewWarmer creates a new warmer with a bounded number of workers (default: 10)
+func NewWarmer(client *Client, workers int) *Warmer {
+	if workers <= 0 {
+		workers = defaultWarmerWorkers
+	}
+	return &Warmer{client: client, workers: workers}
+}
+
+// Add will add loaders to the warm-up
+func (w *Warmer) Add(loaders ...*WarmupLoader) *Warmer {
+	w.loaders = append(w.loaders, loaders...)
+	return w
+}
+
+// OnProgress sets the handler that is fired after each key is processed
+//
+// The handler is never fired concurrently
+func (w *Warmer) OnProgress(handler func(progress WarmupProgress)) *Warmer {
+	w.onProgress = handler
+	return w
+}
+
+// Run will fetch and store all the keys using the worker pool
+//
+// Failed keys do not stop the warm-up, they are returned in the result. The context
+// error is returned if the warm-up was canceled before all keys were processed.
+func (w *Warmer) Run(ctx context.Context) (*WarmupResult, error) {
+	start := w.client.Clock().Now()
+	result := &WarmupResult{
+		Errors: make(map[string]error),
+		Total:  len(w.loaders),
+	}
+
+	// Feed the loaders to the workers
+	jobs := make(chan *WarmupLoader)
+	go func() {
+		defer close(jobs)
+		for _, loader := range w.loaders {
+			select {
+			case jobs <- loader:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Process the loaders
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for loader := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				err := w.warm(ctx, loader)
+
+				mu.Lock()
+				if err != nil {
+					result.Failed++
+					result.Errors[loader.Key] = err
+				} else {
+					result.Completed++
+				}
+				if w.onProgress != nil {
+					w.onProgress(WarmupProgress{
+						Completed: result.Completed,
+						Err:       err,
+						Failed:    result.Failed,
+						Key:       loader.Key,
+						Total:     result.Total,
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	result.Duration = w.client.Clock().Now().Sub(start)
+	if result.Completed+result.Failed < result.Total {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
+// warm will fetch and store a single key (stored with PriorityBackground unless a priority is set)
+func (w *Warmer) warm(ctx context.Context, loader *WarmupLoader) error {
+	value, err := loader.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+	ctx = backgroundPriority(ctx)
+	if loader.TTL > 0 {
+		return SetExp(ctx, w.client, loader.Key, value, loader.TTL, loader.Dependencies...)
+	}
+	return Set(ctx, w.client, loader.Key, value, loader.Dependencies...)
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWarmer_Run tests the method Run()
+func TestWarmer_Run(t *testing.T) {
+
+	t.Run("keys are populated", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+		setExpCmd := conn.Command(SetExpirationCommand, testKey+"-exp", int64(60), testStringValue)
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey+"-exp")
+		conn.Command(ExecuteCommand).Expect([]interface{}{})
+
+		var progress []WarmupProgress
+		result, err := NewWarmer(client, 1).Add(
+			&WarmupLoader{
+				Key: testKey,
+				Fetch: func(ctx context.Context) (interface{}, error) {
+					return testStringValue, nil
+				},
+			},
+			&WarmupLoader{
+				Dependencies: []string{testDependantKey},
+				Key:          testKey + "-exp",
+				TTL:          time.Minute,
+				Fetch: func(ctx context.Context) (interface{}, error) {
+					return testStringValue, nil
+				},
+			},
+		).OnProgress(func(p WarmupProgress) {
+			progress = append(progress, p)
+		}).Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, 2, result.Completed)
+		assert.Equal(t, 0, result.Failed)
+		assert.True(t, setCmd.Called)
+		assert.True(t, setExpCmd.Called)
+		assert.True(t, depCmd.Called)
+
+		assert.Equal(t, 2, len(progress))
+		assert.Equal(t, 2, progress[1].Completed)
+		assert.Equal(t, 2, progress[1].Total)
+	})
+
+	t.Run("failed keys are reported", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		result, err := NewWarmer(client, 0).Add(&WarmupLoader{
+			Key: testKey,
+			Fetch: func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("fetch failed")
+			},
+		}).Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+		assert.Error(t, result.Errors[testKey])
+	})
+
+	t.Run("workers are bounded", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		var running, maxRunning int32
+		warmer := NewWarmer(client, 3)
+		for i := 0; i < 20; i++ {
+			warmer.Add(&WarmupLoader{
+				Key: testKey + strconv.Itoa(i),
+				Fetch: func(ctx context.Context) (interface{}, error) {
+					current := atomic.AddInt32(&running, 1)
+					for {
+						seen := atomic.LoadInt32(&maxRunning)
+						if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					return nil, errors.New("skip the write")
+				},
+			})
+		}
+
+		result, err := warmer.Run(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 20, result.Failed)
+		assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+	})
+
+	t.Run("canceled warm-up", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result, err := NewWarmer(client, 1).Add(&WarmupLoader{
+			Key: testKey,
+			Fetch: func(ctx context.Context) (interface{}, error) {
+				return testStringValue, nil
+			},
+		}).Run(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 0, result.Completed)
+	})
+}
+
+// ExampleWarmer_Run is an example of the method Run()
+func ExampleWarmer_Run() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command
+	conn.Command(SetCommand, testKey, testStringValue)
+
+	// Warm the cache
+	result, _ := NewWarmer(client, 5).Add(&WarmupLoader{
+		Key: testKey,
+		Fetch: func(ctx context.Context) (interface{}, error) {
+			return testStringValue, nil
+		},
+	}).Run(context.Background())
+	fmt.Printf("warmed keys: %d", result.Completed)
+	// Output:warmed keys: 1
+}
@@ -0,0 +1,266 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default invalidation webhook settings
+const (
+	defaultWebhookBatchSize = 100
+	defaultWebhookBatchWait = time.Second
+	defaultWebhookQueueSize = 1000
+	defaultWebhookRetries   = 3
+	defaultWebhookRetryWait = time.Second
+	defaultWebhookTimeout   = 5 * time.Second
+)
+
+// WebhookSignatureHeader is the header with the signature of the payload ("sha256=" and the
+// hex HMAC-SHA256 of the body using the secret, see: VerifyWebhookSignature())
+const WebhookSignatureHeader = "X-Cache-Signature"
+
+// ErrWebhookDelivery is returned (to the error handler) when a batch could not be delivered
+var ErrWebhookDelivery = errors.New("webhook delivery failed")
+
+// WebhookConfig is the configuration for an invalidation webhook
+type WebhookConfig struct {
+	BatchSize    int             // Max events per delivery (default: 100)
+	BatchWait    time.Duration   // Max wait for a batch to fill after the first event (default: 1s)
+	ErrorHandler func(err error) // Fired for each failed delivery (optional)
+	HTTPClient   *http.Client    // Client for the deliveries (default: 5s timeout)
+	QueueSize    int             // Max pending events, new events are dropped if full (default: 1000)
+	Retries      int             // Retries of a failed delivery (default: 3)
+	RetryWait    time.Duration   // Wait before the first retry, doubled after each retry (default: 1s)
+	Secret       []byte          // Key used to sign the payloads (required)
+	URL          string          // Target of the deliveries (required)
+}
+
+// WebhookEvent is a removed key (delete) or an invalidated dependency (invalidate)
+type WebhookEvent struct {
+	Key       string       `json:"key"`
+	Operation KeyOperation `json:"operation"`
+	Removed   int          `json:"removed"`
+	Time      time.Time    `json:"time"`
+}
+
+// WebhookPayload is the signed JSON body of each delivery
+type WebhookPayload struct {
+	Events []WebhookEvent `json:"events"`
+	SentAt time.Time      `json:"sent_at"`
+}
+
+// WebhookStats are the running totals for an invalidation webhook
+type WebhookStats struct {
+	Delivered uint64 // Events delivered
+	Dropped   uint64 // Events dropped because the queue was full
+	Failed    uint64 // Events in deliveries that failed after all retries
+	Pending   int    // Events waiting in the queue
+}
+
+// Webhook delivers the invalidations of a client to an HTTP target in signed batches
+type Webhook struct {
+	clock   Clock
+	config  WebhookConfig
+	done    chan struct{}
+	mu      sync.RWMutex
+	queue   chan WebhookEvent
+	stats   WebhookStats
+	stopped bool
+}
+
+// AddInvalidationWebhook starts delivering every key removed by Delete(), DeleteWithoutDependency()
+// and every dependency invalidated by KillByDependency() to the URL (IE: to purge a CDN)
+//
+// Events are delivered asynchronously in batches (see: WebhookPayload) as a POST with the
+// signature in the WebhookSignatureHeader, failed deliveries are retried.
+// Stop() delivers the pending events.
+func (c *Client) AddInvalidationWebhook(config WebhookConfig) (*Webhook, error) {
+	if len(config.URL) == 0 {
+		return nil, errors.New("missing required parameter: url")
+	} else if len(config.Secret) == 0 {
+		return nil, errors.New("missing required parameter: secret")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultWebhookBatchSize
+	}
+	if config.BatchWait <= 0 {
+		config.BatchWait = defaultWebhookBatchWait
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultWebhookQueueSize
+	}
+	if config.Retries <= 0 {
+		config.Retries = defaultWebhookRetries
+	}
+	if config.RetryWait <= 0 {
+		config.RetryWait = defaultWebhookRetryWait
+	}
+
+	webhook := &Webhook{
+		clock:  c.Clock(),
+		config: config,
+		done:   make(chan struct{}),
+		queue:  make(chan WebhookEvent, config.QueueSize),
+	}
+	go webhook.run()
+
+	hook := func(_ context.Context, event KeyEvent) {
+		webhook.add(WebhookEvent{
+			Key:       event.Key,
+			Operation: event.Operation,
+			Removed:   event.Removed,
+			Time:      c.Clock().Now(),
+		})
+	}
+	c.OnDelete(hook)
+	c.OnInvalidate(hook)
+	return webhook, nil
+}
+
+// Stop stops accepting events and waits for the pending events to be delivered
+// or the context to be done (pending events continue in the background)
+func (w *Webhook) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.stopped {
+		w.stopped = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the running totals for the webhook
+func (w *Webhook) Stats() WebhookStats {
+	return WebhookStats{
+		Delivered: atomic.LoadUint64(&w.stats.Delivered),
+		Dropped:   atomic.LoadUint64(&w.stats.Dropped),
+		Failed:    atomic.LoadUint64(&w.stats.Failed),
+		Pending:   len(w.queue),
+	}
+}
+
+// SignWebhookPayload returns the signature of the body ("sha256=" and the hex HMAC-SHA256)
+func SignWebhookPayload(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature returns true if the signature of the body is valid (for the receivers)
+func VerifyWebhookSignature(secret, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(signature))
+}
+
+// add queues the event (dropped if the queue is full or the webhook is stopped)
+func (w *Webhook) add(event WebhookEvent) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.stopped {
+		return
+	}
+	select {
+	case w.queue <- event:
+	default:
+		atomic.AddUint64(&w.stats.Dropped, 1)
+	}
+}
+
+// run batches the queued events until the queue is closed
+func (w *Webhook) run() {
+	defer close(w.done)
+
+	var batch []WebhookEvent
+	var flush <-chan time.Time // Set while a batch is waiting
+	for {
+		select {
+		case event, ok := <-w.queue:
+			if !ok {
+				w.deliver(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) == 1 {
+				flush = w.clock.After(w.config.BatchWait)
+			}
+			if len(batch) >= w.config.BatchSize {
+				flush = nil
+				w.deliver(batch)
+				batch = nil
+			}
+		case <-flush:
+			flush = nil
+			w.deliver(batch)
+			batch = nil
+		}
+	}
+}
+
+// deliver posts the batch, retrying failed deliveries
+func (w *Webhook) deliver(batch []WebhookEvent) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := json.Marshal(&WebhookPayload{Events: batch, SentAt: w.clock.Now().UTC()})
+	if err == nil {
+		wait := w.config.RetryWait
+		for attempt := 0; ; attempt++ {
+			var retry bool
+			if retry, err = w.post(body); err == nil || !retry || attempt >= w.config.Retries {
+				break
+			}
+			<-w.clock.After(wait)
+			wait *= 2
+		}
+	}
+
+	if err == nil {
+		atomic.AddUint64(&w.stats.Delivered, uint64(len(batch)))
+		return
+	}
+	atomic.AddUint64(&w.stats.Failed, uint64(len(batch)))
+	if w.config.ErrorHandler != nil {
+		w.config.ErrorHandler(err)
+	}
+}
+
+// post sends the body and returns whether a failed delivery can be retried
+func (w *Webhook) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", ErrWebhookDelivery, err.Error())
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.config.Secret, body))
+
+	var resp *http.Response
+	if resp, err = w.config.HTTPClient.Do(req); err != nil {
+		return true, fmt.Errorf("%w: %s", ErrWebhookDelivery, err.Error())
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("%w: %s", ErrWebhookDelivery, resp.Status)
+}
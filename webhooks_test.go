@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testWebhookSecret is the secret for the webhook tests
+var testWebhookSecret = []byte("test-webhook-secret")
+
+// webhookReceiver records the payloads delivered to a test server
+type webhookReceiver struct {
+	sync.Mutex
+	payloads []*WebhookPayload
+	statuses []int // Replied in order (then 200)
+	requests int32
+}
+
+// ServeHTTP verifies and records the payload
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+	atomic.AddInt32(&r.requests, 1)
+
+	if len(r.statuses) > 0 {
+		status := r.statuses[0]
+		r.statuses = r.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if !VerifyWebhookSignature(testWebhookSecret, body, req.Header.Get(WebhookSignatureHeader)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	payload := new(WebhookPayload)
+	_ = json.Unmarshal(body, payload)
+	r.payloads = append(r.payloads, payload)
+}
+
+// TestAddInvalidationWebhook tests the method AddInvalidationWebhook()
+func TestAddInvalidationWebhook(t *testing.T) {
+
+	t.Run("missing parameters", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		_, err := client.AddInvalidationWebhook(WebhookConfig{Secret: testWebhookSecret})
+		assert.Error(t, err)
+		_, err = client.AddInvalidationWebhook(WebhookConfig{URL: "http://localhost"})
+		assert.Error(t, err)
+	})
+
+	t.Run("signed batches using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		receiver := new(webhookReceiver)
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		webhook, err := client.AddInvalidationWebhook(WebhookConfig{
+			BatchSize: 2,
+			BatchWait: time.Minute,
+			Secret:    testWebhookSecret,
+			URL:       server.URL,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+testDependantKey).Expect(int64(2))
+		conn.Command(DeleteCommand, testDependantKey).Expect(int64(1))
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+
+		ctx := context.Background()
+		_, err = KillByDependency(ctx, client, testDependantKey)
+		assert.NoError(t, err)
+		_, err = DeleteWithoutDependency(ctx, client, "key-1")
+		assert.NoError(t, err)
+		_, err = DeleteWithoutDependency(ctx, client, "key-1")
+		assert.NoError(t, err)
+
+		// The last event is delivered on stop
+		err = webhook.Stop(ctx)
+		assert.NoError(t, err)
+
+		receiver.Lock()
+		defer receiver.Unlock()
+		assert.Len(t, receiver.payloads, 2)
+		assert.Len(t, receiver.payloads[0].Events, 2)
+		assert.Equal(t, testDependantKey, receiver.payloads[0].Events[0].Key)
+		assert.Equal(t, OperationInvalidate, receiver.payloads[0].Events[0].Operation)
+		assert.Equal(t, 3, receiver.payloads[0].Events[0].Removed)
+		assert.Equal(t, OperationDelete, receiver.payloads[0].Events[1].Operation)
+		assert.Len(t, receiver.payloads[1].Events, 1)
+		assert.Equal(t, WebhookStats{Delivered: 3}, webhook.Stats())
+
+		// Stopped webhooks ignore new events
+		_, err = DeleteWithoutDependency(ctx, client, "key-1")
+		assert.NoError(t, err)
+		assert.Equal(t, WebhookStats{Delivered: 3}, webhook.Stats())
+	})
+
+	t.Run("retries failed deliveries", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		receiver := &webhookReceiver{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		webhook, err := client.AddInvalidationWebhook(WebhookConfig{
+			RetryWait: time.Millisecond,
+			Secret:    testWebhookSecret,
+			URL:       server.URL,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+		_, err = DeleteWithoutDependency(context.Background(), client, "key-1")
+		assert.NoError(t, err)
+
+		err = webhook.Stop(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&receiver.requests))
+		assert.Equal(t, WebhookStats{Delivered: 1}, webhook.Stats())
+	})
+
+	t.Run("batches wait on the client clock", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := NewManualClock(start)
+		client.SetClock(clock)
+
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		webhook, err := client.AddInvalidationWebhook(WebhookConfig{
+			BatchWait: time.Minute,
+			Secret:    testWebhookSecret,
+			URL:       server.URL,
+		})
+		assert.NoError(t, err)
+		defer func() { _ = webhook.Stop(context.Background()) }()
+
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+		_, err = DeleteWithoutDependency(context.Background(), client, "key-1")
+		assert.NoError(t, err)
+
+		// The batch is delivered once the clock passes the batch wait
+		waitForWaiters(t, clock, 1)
+		clock.Advance(time.Minute)
+		for i := 0; i < 1000 && webhook.Stats().Delivered == 0; i++ {
+			time.Sleep(time.Millisecond)
+		}
+		assert.Equal(t, WebhookStats{Delivered: 1}, webhook.Stats())
+
+		receiver.Lock()
+		defer receiver.Unlock()
+		assert.Len(t, receiver.payloads, 1)
+		assert.True(t, start.Add(time.Minute).Equal(receiver.payloads[0].SentAt))
+	})
+
+	t.Run("rejected deliveries are not retried", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		receiver := &webhookReceiver{statuses: []int{http.StatusBadRequest}}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		var failures []error
+		webhook, err := client.AddInvalidationWebhook(WebhookConfig{
+			ErrorHandler: func(err error) {
+				failures = append(failures, err)
+			},
+			RetryWait: time.Millisecond,
+			Secret:    testWebhookSecret,
+			URL:       server.URL,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(DeleteCommand, "key-1").Expect(int64(1))
+		_, err = DeleteWithoutDependency(context.Background(), client, "key-1")
+		assert.NoError(t, err)
+
+		err = webhook.Stop(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&receiver.requests))
+		assert.Equal(t, WebhookStats{Failed: 1}, webhook.Stats())
+		assert.Len(t, failures, 1)
+		assert.ErrorIs(t, failures[0], ErrWebhookDelivery)
+	})
+
+	t.Run("verify signature", func(t *testing.T) {
+		t.Parallel()
+
+		body := []byte(`{"events":[]}`)
+		signature := SignWebhookPayload(testWebhookSecret, body)
+		assert.True(t, VerifyWebhookSignature(testWebhookSecret, body, signature))
+		assert.False(t, VerifyWebhookSignature([]byte("other"), body, signature))
+		assert.False(t, VerifyWebhookSignature(testWebhookSecret, []byte(`{}`), signature))
+	})
+}
+
+// ExampleClient_AddInvalidationWebhook is an example of the method AddInvalidationWebhook()
+func ExampleClient_AddInvalidationWebhook() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Receive the invalidations (IE: a CDN purge service)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		payload := new(WebhookPayload)
+		_ = json.Unmarshal(body, payload)
+		fmt.Printf("invalidated: %s", payload.Events[0].Key)
+	}))
+	defer server.Close()
+
+	webhook, _ := client.AddInvalidationWebhook(WebhookConfig{Secret: []byte("secret"), URL: server.URL})
+
+	// Mock the dependency commands
+	conn.Command(EvalCommand, killByDependencySha, 0, DependencyPrefix+"user-1").Expect(int64(2))
+	conn.Command(DeleteCommand, "user-1").Expect(int64(0))
+
+	_, _ = KillByDependency(context.Background(), client, "user-1")
+	_ = webhook.Stop(context.Background())
+	// Output:invalidated: user-1
+}
@@ -0,0 +1,289 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+// WriteOption sets per-call behavior for SetWith(), HashSetWith() and SetAddWith()
+type WriteOption func(*writeConfig)
+
+// writeConfig holds the options of a single write
+type writeConfig struct {
+	codec            Codec
+	dependencies     []string
+	nx               bool
+	skipDependencies bool
+	ttl              time.Duration
+}
+
+// WithDependencies keeps a reference to each dependency (only if the value was written)
+func WithDependencies(dependencies ...string) WriteOption {
+	return func(c *writeConfig) {
+		c.dependencies = append(c.dependencies, dependencies...)
+	}
+}
+
+// SkipDependencies skips the dependency bookkeeping for the write (IE: hot write paths),
+// any dependencies are ignored (see: SetSkipDependencies())
+func SkipDependencies() WriteOption {
+	return func(c *writeConfig) {
+		c.skipDependencies = true
+	}
+}
+
+// WithTTL sets the expiration of the key (for hashes and sets: the entire hash or set)
+// The ttl uses millisecond precision if it is not a whole number of seconds
+func WithTTL(ttl time.Duration) WriteOption {
+	return func(c *writeConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithNX only writes the value if it does not exist (for hashes: the field, sets are unchanged
+// as members are unique)
+func WithNX() WriteOption {
+	return func(c *writeConfig) {
+		c.nx = true
+	}
+}
+
+// WithCodec encodes the value with the codec before it is written (IE: JSONCodec{})
+func WithCodec(codec Codec) WriteOption {
+	return func(c *writeConfig) {
+		c.codec = codec
+	}
+}
+
+// newWriteConfig applies the options and encodes the value (if a codec is set)
+func newWriteConfig(value interface{}, options []WriteOption) (*writeConfig, interface{}, error) {
+	config := new(writeConfig)
+	for _, opt := range options {
+		opt(config)
+	}
+	if config.skipDependencies {
+		config.dependencies = nil
+	}
+	if config.codec == nil {
+		return config, value, nil
+	}
+	data, err := config.codec.Marshal(value)
+	return config, data, err
+}
+
+// SetWith will set the key in redis with the options (IE: WithTTL(), WithNX(), WithDependencies())
+// and return whether the value was written (false if WithNX() and the key exists)
+// Applies the value size guard if set, values are never chunked (see: SetValueSizeGuard())
+// Persists the written key if the write-through is started (see: StartWriteThrough())
+// Writes of keys with a tombstone are skipped if set (see: SetInvalidationTombstones())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetWithRaw()
+func SetWith(ctx context.Context, client *Client, key string, value interface{},
+	options ...WriteOption) (bool, error) {
+	config, value, err := newWriteConfig(value, options)
+	if err != nil {
+		return false, err
+	} else if client.IsBypassed() {
+		return false, client.persist(ctx, key, value)
+	}
+	encoded := value
+	if value, _, err = client.guardValue(key, value, false); err != nil {
+		return false, err
+	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	var written bool
+	if client.InvalidationTombstones() > 0 {
+		written, err = setUnlessTombstoned(conn, key, value, config)
+	} else {
+		written, err = setWithConfig(conn, key, value, config)
+	}
+	if written {
+		client.fireHooks(ctx, KeyEvent{
+			Dependencies: config.dependencies, Key: key, Operation: OperationSet, TTL: config.ttl,
+		})
+		if persistErr := client.persist(ctx, key, encoded); persistErr != nil {
+			return written, persistErr
+		}
+	}
+	return written, err
+}
+
+// SetWithRaw will set the key in redis with the options (IE: WithTTL(), WithNX(), WithDependencies())
+// and return whether the value was written (false if WithNX() and the key exists)
+// Uses existing connection (does not close connection)
+//
+// Spec: https://redis.io/commands/set
+func SetWithRaw(conn redis.Conn, key string, value interface{}, options ...WriteOption) (bool, error) {
+	config, value, err := newWriteConfig(value, options)
+	if err != nil {
+		return false, err
+	}
+	return setWithConfig(conn, key, value, config)
+}
+
+// setWithConfig sets the key with the condition and expiration, then links the dependencies
+func setWithConfig(conn redis.Conn, key string, value interface{}, config *writeConfig) (bool, error) {
+	if err := checkDependencyLimit(conn, config.dependencies...); err != nil {
+		return false, err
+	}
+	args := redis.Args{}.Add(key, value).AddFlat(config.setArguments())
+	if _, err := redis.String(conn.Do(SetCommand, args...)); errors.Is(err, redis.ErrNil) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, linkDependencies(conn, key, config.dependencies...)
+}
+
+// setArguments returns the SET arguments of the condition and expiration (IE: NX, EX 60)
+func (c *writeConfig) setArguments() redis.Args {
+	args := redis.Args{}
+	if c.nx {
+		args = args.Add(SetIfNotExistsArgument)
+	}
+	if c.ttl > 0 {
+		if isWholeSeconds(c.ttl) {
+			args = args.Add(ExpireSecondsArgument, int64(c.ttl.Seconds()))
+		} else {
+			args = args.Add(ExpireMillisArgument, c.ttl.Milliseconds())
+		}
+	}
+	return args
+}
+
+// HashSetWith will set the hashKey to the value in the specified hashName with the options
+// (IE: WithTTL(), WithNX(), WithDependencies()) and return whether the value was written
+// (false if WithNX() and the field exists)
+// Applies the value size guard if set, values are never chunked (see: SetValueSizeGuard())
+// Writes of hashes with a tombstone are skipped if set (see: SetInvalidationTombstones())
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: HashSetWithRaw()
+func HashSetWith(ctx context.Context, client *Client, hashName, hashKey string, value interface{},
+	options ...WriteOption) (bool, error) {
+	if client.IsBypassed() {
+		return false, nil
+	}
+	config, value, err := newWriteConfig(value, options)
+	if err != nil {
+		return false, err
+	}
+	if value, _, err = client.guardValue(hashName+":"+hashKey, value, false); err != nil {
+		return false, err
+	}
+	var conn redis.Conn
+	if conn, err = client.GetConnectionWithContext(ctx); err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	if tombstone, tombstoneErr := client.hasTombstone(conn, hashName); tombstoneErr != nil || tombstone {
+		return false, tombstoneErr
+	}
+	return hashSetWithConfig(conn, hashName, hashKey, value, config)
+}
+
+// HashSetWithRaw will set the hashKey to the value in the specified hashName with the options
+// (IE: WithTTL(), WithNX(), WithDependencies()) and return whether the value was written
+// (false if WithNX() and the field exists)
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/hset
+// https://redis.io/commands/hsetnx
+// https://redis.io/commands/expire
+// https://redis.io/commands/pexpire
+// https://redis.io/commands/sadd
+func HashSetWithRaw(conn redis.Conn, hashName, hashKey string, value interface{},
+	options ...WriteOption) (bool, error) {
+	config, value, err := newWriteConfig(value, options)
+	if err != nil {
+		return false, err
+	}
+	return hashSetWithConfig(conn, hashName, hashKey, value, config)
+}
+
+// hashSetWithConfig sets the field (if it does not exist for NX), then sets the expiration
+// and links the dependencies
+func hashSetWithConfig(conn redis.Conn, hashName, hashKey string, value interface{},
+	config *writeConfig) (bool, error) {
+	if err := checkDependencyLimit(conn, config.dependencies...); err != nil {
+		return false, err
+	}
+	if config.nx {
+		written, err := redis.Bool(conn.Do(HashSetNXCommand, hashName, hashKey, value))
+		if err != nil || !written {
+			return false, err
+		}
+	} else if _, err := conn.Do(HashKeySetCommand, hashName, hashKey, value); err != nil {
+		return false, err
+	}
+	return true, expireAndLink(conn, hashName, config)
+}
+
+// SetAddWith will add the member to the set with the options (IE: WithTTL(), WithDependencies())
+// and return whether the member was added (false if it was already a member)
+// Creates a new connection and closes connection at end of function call
+//
+// Custom connections use method: SetAddWithRaw()
+func SetAddWith(ctx context.Context, client *Client, setName string, member interface{},
+	options ...WriteOption) (bool, error) {
+	if client.IsBypassed() {
+		return false, nil
+	}
+	conn, err := client.GetConnectionWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer client.CloseConnection(conn)
+	return SetAddWithRaw(conn, setName, member, options...)
+}
+
+// SetAddWithRaw will add the member to the set with the options (IE: WithTTL(), WithDependencies())
+// and return whether the member was added (false if it was already a member)
+// The expiration and dependencies are set even if the member was already in the set
+// Uses existing connection (does not close connection)
+//
+// Commands used:
+// https://redis.io/commands/sadd
+// https://redis.io/commands/expire
+// https://redis.io/commands/pexpire
+func SetAddWithRaw(conn redis.Conn, setName string, member interface{}, options ...WriteOption) (bool, error) {
+	config, member, err := newWriteConfig(member, options)
+	if err != nil {
+		return false, err
+	} else if err = checkDependencyLimit(conn, config.dependencies...); err != nil {
+		return false, err
+	}
+	var added bool
+	if added, err = redis.Bool(conn.Do(AddToSetCommand, setName, member)); err != nil {
+		return false, err
+	}
+	return added, expireAndLink(conn, setName, config)
+}
+
+// expireAndLink sets the expiration (if set) and links the dependencies in one transaction
+func expireAndLink(conn redis.Conn, key string, config *writeConfig) (err error) {
+	if config.ttl <= 0 {
+		return linkDependencies(conn, key, config.dependencies...)
+	}
+	if err = conn.Send(MultiCommand); err != nil {
+		return
+	}
+	command, args := expireCommand(key, config.ttl)
+	if err = conn.Send(command, args...); err != nil {
+		return
+	}
+	if err = sendLinkDependencies(conn, key, config.dependencies...); err != nil {
+		return
+	}
+	_, err = conn.Do(ExecuteCommand)
+	return
+}
@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSetWith tests the method SetWith()
+func TestSetWith(t *testing.T) {
+
+	t.Run("set with options using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		setCmd := conn.Command(SetCommand, testKey, []byte(`{"id":1}`), SetIfNotExistsArgument,
+			ExpireMillisArgument, int64(1500)).Expect("OK")
+		conn.Command(MultiCommand)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+		conn.Command(ExecuteCommand).Expect([]interface{}{int64(1)})
+
+		written, err := SetWith(context.Background(), client, testKey, map[string]int{"id": 1},
+			WithCodec(JSONCodec{}), WithNX(), WithTTL(1500*time.Millisecond), WithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.True(t, written)
+		assert.True(t, setCmd.Called)
+		assert.True(t, depCmd.Called)
+	})
+
+	t.Run("not written using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		conn.Command(SetCommand, testKey, testStringValue, SetIfNotExistsArgument).Expect(nil)
+		depCmd := conn.Command(AddToSetCommand, DependencyPrefix+testDependantKey, testKey)
+
+		written, err := SetWith(context.Background(), client, testKey, testStringValue,
+			WithNX(), WithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.False(t, written)
+		assert.False(t, depCmd.Called)
+	})
+
+	t.Run("options using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		ctx := context.Background()
+		var written bool
+		written, err = SetWith(ctx, client, testKey, "first", WithNX(), WithTTL(time.Minute))
+		assert.NoError(t, err)
+		assert.True(t, written)
+		written, err = SetWith(ctx, client, testKey, "second", WithNX())
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		var value string
+		value, err = Get(ctx, client, testKey)
+		assert.NoError(t, err)
+		assert.Equal(t, "first", value)
+
+		// Hash fields
+		written, err = HashSetWith(ctx, client, testHashName, "field", 1,
+			WithNX(), WithTTL(time.Minute), WithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.True(t, written)
+		written, err = HashSetWith(ctx, client, testHashName, "field", 2, WithNX())
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		var ttl int64
+		ttl, err = redis.Int64(conn.Do("TTL", testHashName))
+		assert.NoError(t, err)
+		assert.Greater(t, ttl, int64(0))
+
+		// Set members
+		written, err = SetAddWith(ctx, client, "set", "member", WithTTL(time.Minute), WithDependencies(testDependantKey))
+		assert.NoError(t, err)
+		assert.True(t, written)
+		written, err = SetAddWith(ctx, client, "set", "member")
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		// Removed with the dependency
+		_, err = Delete(ctx, client, testDependantKey)
+		assert.NoError(t, err)
+		var found bool
+		found, err = Exists(ctx, client, testHashName)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		found, err = Exists(ctx, client, "set")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+// ExampleSetWith is an example of the method SetWith()
+func ExampleSetWith() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Mock the set command (the key exists)
+	conn.Command(SetCommand, testKey, testStringValue, SetIfNotExistsArgument, ExpireSecondsArgument, int64(60)).
+		Expect(nil)
+
+	// Only write the value if the key does not exist
+	written, _ := SetWith(context.Background(), client, testKey, testStringValue, WithNX(), WithTTL(time.Minute))
+	fmt.Printf("written: %v", written)
+	// Output:written: false
+}
@@ -0,0 +1,287 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default write-through settings
+const (
+	defaultPersistQueueSize  = 1000
+	defaultPersistRetries    = 3
+	defaultPersistRetryWait  = 100 * time.Millisecond
+	defaultPersistTimeout    = 5 * time.Second
+	defaultPersistWorkers    = 4
+	maxPersistRetryWaitShift = 10
+)
+
+// Write-through errors
+var (
+	ErrPersistQueueFull    = errors.New("write-through persist queue is full")
+	ErrWriteThroughStarted = errors.New("write-through is already started")
+)
+
+// PersistFunc writes the value of the key to the slower store (see: StartWriteThrough())
+type PersistFunc func(ctx context.Context, key string, value []byte) error
+
+// PersistError is the error for a failed persist
+type PersistError struct {
+	Attempts int    // Attempts made (1 for a synchronous persist)
+	Err      error  // Error from the persist function
+	Key      string // Key that failed to be persisted
+}
+
+// Error returns the error message
+func (e *PersistError) Error() string {
+	return "persist failed for key " + e.Key + " after " + strconv.Itoa(e.Attempts) + " attempt(s): " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *PersistError) Unwrap() error {
+	return e.Err
+}
+
+// WriteThroughConfig is the configuration for the write-through persistence (see: StartWriteThrough())
+type WriteThroughConfig struct {
+	Async        bool                    // Persist in the background with retries (default: before the write returns)
+	ErrorHandler func(err *PersistError) // Fired for each async persist that failed all retries (optional)
+	MaxRetries   int                     // Retries of a failed async persist (default: 3, negative: none)
+	Patterns     []string                // Keys to persist (path.Match syntax), all keys if empty
+	Persist      PersistFunc             // Writes the value to the store (required)
+	QueueSize    int                     // Max pending async persists (default: 1000)
+	RetryWait    time.Duration           // Wait before the first retry, doubled for each retry (default: 100ms)
+	Timeout      time.Duration           // Timeout for each async attempt (default: 5s)
+	Workers      int                     // Concurrent async persists (default: 4)
+}
+
+// WriteThroughStats are the running totals for the write-through persistence
+type WriteThroughStats struct {
+	Failed    uint64 // Persists that failed (after all retries if async)
+	Pending   int    // Async persists waiting in the queue
+	Persisted uint64 // Persists that succeeded
+	Retried   uint64 // Async attempts that were retried
+}
+
+// persistWrite is a single pending persist
+type persistWrite struct {
+	key   string
+	value []byte
+}
+
+// writeThrough runs the persistence for a client
+type writeThrough struct {
+	clock   Clock
+	config  WriteThroughConfig
+	queue   chan *persistWrite
+	stats   WriteThroughStats
+	stop    chan struct{}
+	workers sync.WaitGroup
+}
+
+// StartWriteThrough persists each key written with Set(), SetExp() or SetWith() (and matching a
+// pattern) to a slower store, so the cache is the front of the write path (write-through)
+//
+// Synchronous persists run after the cache write, and their error (a *PersistError) is returned by
+// the write so the caller can retry; async persists are queued and retried with a backoff, giving
+// at-least-once delivery while the process runs (a full queue returns ErrPersistQueueFull)
+// Keys are persisted even if the client is bypassed (see: SetBypass()), Raw functions on custom
+// connections are not persisted
+func (c *Client) StartWriteThrough(config *WriteThroughConfig) error {
+	if config == nil || config.Persist == nil {
+		return errors.New("missing required parameter: config.Persist")
+	}
+	for _, pattern := range config.Patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return err
+		}
+	}
+	persister := &writeThrough{clock: c.Clock(), config: *config, stop: make(chan struct{})}
+	if persister.config.MaxRetries == 0 {
+		persister.config.MaxRetries = defaultPersistRetries
+	} else if persister.config.MaxRetries < 0 {
+		persister.config.MaxRetries = 0
+	}
+	if persister.config.QueueSize <= 0 {
+		persister.config.QueueSize = defaultPersistQueueSize
+	}
+	if persister.config.RetryWait <= 0 {
+		persister.config.RetryWait = defaultPersistRetryWait
+	}
+	if persister.config.Timeout <= 0 {
+		persister.config.Timeout = defaultPersistTimeout
+	}
+	if persister.config.Workers <= 0 {
+		persister.config.Workers = defaultPersistWorkers
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writeThrough != nil {
+		return ErrWriteThroughStarted
+	}
+	c.writeThrough = persister
+
+	if persister.config.Async {
+		persister.queue = make(chan *persistWrite, persister.config.QueueSize)
+		for i := 0; i < persister.config.Workers; i++ {
+			persister.workers.Add(1)
+			go persister.run()
+		}
+	}
+	return nil
+}
+
+// StopWriteThrough stops persisting writes and waits for the pending async persists (with their
+// retries) to finish or the context to be done (pending persists continue in the background)
+// Retries of the pending persists no longer wait for their backoff
+func (c *Client) StopWriteThrough(ctx context.Context) error {
+	c.mu.Lock()
+	persister := c.writeThrough
+	c.writeThrough = nil
+	if persister != nil {
+		close(persister.stop)
+		if persister.queue != nil {
+			close(persister.queue)
+		}
+	}
+	c.mu.Unlock()
+
+	if persister == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		persister.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WriteThroughStats returns the running totals for the write-through persistence
+func (c *Client) WriteThroughStats() WriteThroughStats {
+	c.mu.RLock()
+	persister := c.writeThrough
+	c.mu.RUnlock()
+	if persister == nil {
+		return WriteThroughStats{}
+	}
+	return WriteThroughStats{
+		Failed:    atomic.LoadUint64(&persister.stats.Failed),
+		Pending:   len(persister.queue),
+		Persisted: atomic.LoadUint64(&persister.stats.Persisted),
+		Retried:   atomic.LoadUint64(&persister.stats.Retried),
+	}
+}
+
+// persist persists the written key if the write-through is started and the key matches
+func (c *Client) persist(ctx context.Context, key string, value interface{}) error {
+	c.mu.RLock()
+	persister := c.writeThrough
+	if persister == nil || !persister.matches(key) {
+		c.mu.RUnlock()
+		return nil
+	}
+	write := &persistWrite{key: key, value: persistValue(value)}
+
+	// Queued while holding the lock (the queue is closed under the lock)
+	if persister.config.Async {
+		defer c.mu.RUnlock()
+		select {
+		case persister.queue <- write:
+			return nil
+		default:
+			return ErrPersistQueueFull
+		}
+	}
+	c.mu.RUnlock()
+
+	if err := persister.config.Persist(ctx, write.key, write.value); err != nil {
+		atomic.AddUint64(&persister.stats.Failed, 1)
+		return &PersistError{Attempts: 1, Err: err, Key: key}
+	}
+	atomic.AddUint64(&persister.stats.Persisted, 1)
+	return nil
+}
+
+// matches returns true if the key matches a pattern (or there are no patterns)
+func (w *writeThrough) matches(key string) bool {
+	if len(w.config.Patterns) == 0 {
+		return true
+	}
+	for _, pattern := range w.config.Patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// run processes the queued persists until the queue is closed
+func (w *writeThrough) run() {
+	defer w.workers.Done()
+	for write := range w.queue {
+		attempts, err := w.attempt(write)
+		if err == nil {
+			atomic.AddUint64(&w.stats.Persisted, 1)
+			continue
+		}
+		atomic.AddUint64(&w.stats.Failed, 1)
+		if w.config.ErrorHandler != nil {
+			w.config.ErrorHandler(&PersistError{Attempts: attempts, Err: err, Key: write.key})
+		}
+	}
+}
+
+// attempt persists the write, retrying with a backoff (cut short once stopped), and returns the attempts made
+func (w *writeThrough) attempt(write *persistWrite) (attempts int, err error) {
+	for attempts < w.config.MaxRetries+1 {
+		if attempts > 0 {
+			atomic.AddUint64(&w.stats.Retried, 1)
+			shift := attempts - 1
+			if shift > maxPersistRetryWaitShift {
+				shift = maxPersistRetryWaitShift
+			}
+			select {
+			case <-w.stop:
+			case <-w.clock.After(w.config.RetryWait << uint(shift)):
+			}
+		}
+		attempts++
+
+		ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+		err = w.config.Persist(ctx, write.key, write.value)
+		cancel()
+		if err == nil {
+			return attempts, nil
+		}
+	}
+	return attempts, err
+}
+
+// persistValue returns the value as bytes (as it is written to redis)
+func persistValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case []byte:
+		data := make([]byte, len(v))
+		copy(data, v)
+		return data
+	case string:
+		return []byte(v)
+	case nil:
+		return []byte{}
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
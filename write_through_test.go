@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// persistRecorder records the persisted keys, failing the first failures calls
+type persistRecorder struct {
+	calls    int
+	failures int
+	mu       sync.Mutex
+	values   map[string]string
+}
+
+// persist records the key and value
+func (r *persistRecorder) persist(_ context.Context, key string, value []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.calls <= r.failures {
+		return errors.New("store is unavailable")
+	}
+	if r.values == nil {
+		r.values = make(map[string]string)
+	}
+	r.values[key] = string(value)
+	return nil
+}
+
+// get returns the persisted value of the key
+func (r *persistRecorder) get(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.values[key]
+}
+
+// TestStartWriteThrough tests the method StartWriteThrough()
+func TestStartWriteThrough(t *testing.T) {
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		err := client.StartWriteThrough(nil)
+		assert.Error(t, err)
+
+		recorder := new(persistRecorder)
+		err = client.StartWriteThrough(&WriteThroughConfig{Patterns: []string{"user:["}, Persist: recorder.persist})
+		assert.Error(t, err)
+
+		err = client.StartWriteThrough(&WriteThroughConfig{Persist: recorder.persist})
+		assert.NoError(t, err)
+		err = client.StartWriteThrough(&WriteThroughConfig{Persist: recorder.persist})
+		assert.ErrorIs(t, err, ErrWriteThroughStarted)
+	})
+
+	t.Run("sync persist using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := &persistRecorder{failures: 1}
+		err := client.StartWriteThrough(&WriteThroughConfig{Patterns: []string{"user:*"}, Persist: recorder.persist})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, "user:1", testStringValue)
+		conn.Command(SetExpirationCommand, "user:2", int64(60), testStringValue)
+		conn.Command(SetCommand, "order:1", testStringValue)
+
+		// First persist fails (the cache is written)
+		err = Set(context.Background(), client, "user:1", testStringValue)
+		var persistErr *PersistError
+		assert.ErrorAs(t, err, &persistErr)
+		assert.Equal(t, "user:1", persistErr.Key)
+		assert.Equal(t, 1, persistErr.Attempts)
+
+		err = SetExp(context.Background(), client, "user:2", testStringValue, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, recorder.get("user:2"))
+
+		// Not matching the patterns
+		err = Set(context.Background(), client, "order:1", testStringValue)
+		assert.NoError(t, err)
+		assert.Equal(t, "", recorder.get("order:1"))
+
+		stats := client.WriteThroughStats()
+		assert.Equal(t, uint64(1), stats.Failed)
+		assert.Equal(t, uint64(1), stats.Persisted)
+	})
+
+	t.Run("bypassed writes are persisted", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := new(persistRecorder)
+		err := client.StartWriteThrough(&WriteThroughConfig{Persist: recorder.persist})
+		assert.NoError(t, err)
+
+		client.SetBypass(true)
+		setCmd := conn.Command(SetCommand, testKey, testStringValue)
+
+		_, err = SetWith(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+		assert.False(t, setCmd.Called)
+		assert.Equal(t, testStringValue, recorder.get(testKey))
+	})
+
+	t.Run("async persist with retries using mocked redis", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := &persistRecorder{failures: 2}
+		var failed []*PersistError
+		err := client.StartWriteThrough(&WriteThroughConfig{
+			Async:        true,
+			ErrorHandler: func(err *PersistError) { failed = append(failed, err) },
+			Persist:      recorder.persist,
+			RetryWait:    time.Millisecond,
+			Workers:      1,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		// Pending persists are finished on stop
+		err = client.StopWriteThrough(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, recorder.get(testKey))
+		assert.Equal(t, 3, recorder.calls)
+		assert.Empty(t, failed)
+	})
+
+	t.Run("stop cuts the retry backoff short", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+		clock := NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		client.SetClock(clock)
+
+		recorder := &persistRecorder{failures: 1}
+		err := client.StartWriteThrough(&WriteThroughConfig{
+			Async:     true,
+			Persist:   recorder.persist,
+			RetryWait: time.Hour,
+			Workers:   1,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		// The retry waits on the client clock, stop retries right away
+		waitForWaiters(t, clock, 1)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = client.StopWriteThrough(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, testStringValue, recorder.get(testKey))
+	})
+
+	t.Run("async persist gives up", func(t *testing.T) {
+		t.Parallel()
+
+		client, conn := loadMockRedis()
+		defer client.CloseAll(conn)
+
+		recorder := &persistRecorder{failures: 10}
+		errs := make(chan *PersistError, 1)
+		err := client.StartWriteThrough(&WriteThroughConfig{
+			Async:        true,
+			ErrorHandler: func(err *PersistError) { errs <- err },
+			MaxRetries:   2,
+			Persist:      recorder.persist,
+			RetryWait:    time.Millisecond,
+		})
+		assert.NoError(t, err)
+
+		conn.Command(SetCommand, testKey, testStringValue)
+		err = Set(context.Background(), client, testKey, testStringValue)
+		assert.NoError(t, err)
+
+		persistErr := <-errs
+		assert.Equal(t, 3, persistErr.Attempts)
+		assert.Equal(t, testKey, persistErr.Key)
+		assert.Equal(t, uint64(2), client.WriteThroughStats().Retried)
+	})
+
+	t.Run("persist using real redis", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping live local redis tests")
+		}
+
+		client, conn, err := loadRealRedis()
+		assert.NotNil(t, client)
+		assert.NoError(t, err)
+		defer client.CloseAll(conn)
+
+		err = clearRealRedis(conn)
+		assert.NoError(t, err)
+
+		recorder := new(persistRecorder)
+		err = client.StartWriteThrough(&WriteThroughConfig{Async: true, Persist: recorder.persist})
+		assert.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			err = Set(context.Background(), client, fmt.Sprintf("key-%d", i), fmt.Sprintf("value-%d", i))
+			assert.NoError(t, err)
+		}
+		err = client.StopWriteThrough(context.Background())
+		assert.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, fmt.Sprintf("value-%d", i), recorder.get(fmt.Sprintf("key-%d", i)))
+		}
+		var value string
+		value, err = GetRaw(conn, "key-0")
+		assert.NoError(t, err)
+		assert.Equal(t, "value-0", value)
+	})
+}
+
+// ExampleClient_StartWriteThrough is an example of the method StartWriteThrough()
+func ExampleClient_StartWriteThrough() {
+	// Load a mocked redis for testing/examples
+	client, conn := loadMockRedis()
+
+	// Close connections at end of request
+	defer client.CloseAll(conn)
+
+	// Persist users to the database after each cache write
+	_ = client.StartWriteThrough(&WriteThroughConfig{
+		Patterns: []string{"user:*"},
+		Persist: func(_ context.Context, key string, value []byte) error {
+			fmt.Printf("persisted %s: %s", key, value)
+			return nil
+		},
+	})
+
+	conn.Command(SetCommand, "user:1", "Jane")
+	_ = Set(context.Background(), client, "user:1", "Jane")
+	// Output:persisted user:1: Jane
+}